@@ -0,0 +1,341 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	analyticsDateLayout   = "2006-01-02"
+	analyticsDefaultDays  = 28
+	analyticsMaxDays      = 366
+	analyticsTopItemLimit = 5
+)
+
+type analyticsServiceImpl struct {
+	analyticsRepo repositories.AnalyticsRepository
+	videoRepo     repositories.VideoRepository
+	topicRepo     repositories.TopicRepository
+	location      *time.Location
+}
+
+func NewAnalyticsService(
+	analyticsRepo repositories.AnalyticsRepository,
+	videoRepo repositories.VideoRepository,
+	topicRepo repositories.TopicRepository,
+	location *time.Location,
+) services.AnalyticsService {
+	return &analyticsServiceImpl{
+		analyticsRepo: analyticsRepo,
+		videoRepo:     videoRepo,
+		topicRepo:     topicRepo,
+		location:      location,
+	}
+}
+
+func (s *analyticsServiceImpl) GetCreatorAnalytics(ctx context.Context, userID uuid.UUID, params *dto.AnalyticsQueryParams) (*dto.CreatorAnalyticsResponse, error) {
+	from, to, err := s.parseRange(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repositories.ContentStatFilter{OwnerID: userID, From: from, To: to}
+
+	series, err := s.analyticsRepo.GetContentSeries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.analyticsRepo.GetShareSeries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := s.analyticsRepo.GetFollowerSeries(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	topVideos, err := s.topContent(ctx, filter, models.AnalyticsContentVideo)
+	if err != nil {
+		return nil, err
+	}
+
+	topTopics, err := s.topContent(ctx, filter, models.AnalyticsContentTopic)
+	if err != nil {
+		return nil, err
+	}
+
+	points, totals := s.buildSeries(series, from, to)
+	shareSeries, sharesByPlatform := buildShareSeries(shares)
+
+	return &dto.CreatorAnalyticsResponse{
+		From:             from.Format(analyticsDateLayout),
+		To:               to.Format(analyticsDateLayout),
+		Totals:           totals,
+		Series:           points,
+		SharesByPlatform: sharesByPlatform,
+		ShareSeries:      shareSeries,
+		FollowerGrowth:   s.buildFollowerGrowth(followers, from, to),
+		TopVideos:        topVideos,
+		TopTopics:        topTopics,
+	}, nil
+}
+
+func (s *analyticsServiceImpl) GetVideoAnalytics(ctx context.Context, userID, videoID uuid.UUID, params *dto.AnalyticsQueryParams) (*dto.ContentAnalyticsResponse, error) {
+	video, err := s.videoRepo.FindByIDIncludingInactive(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.UserID != userID {
+		return nil, errors.New("you don't have permission to view analytics for this video")
+	}
+
+	resp, err := s.contentAnalytics(ctx, userID, models.AnalyticsContentVideo, videoID, params)
+	if err != nil {
+		return nil, err
+	}
+	resp.Title = video.Title
+
+	return resp, nil
+}
+
+func (s *analyticsServiceImpl) GetTopicAnalytics(ctx context.Context, userID, topicID uuid.UUID, params *dto.AnalyticsQueryParams) (*dto.ContentAnalyticsResponse, error) {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return nil, errors.New("topic not found")
+	}
+
+	if topic.UserID != userID {
+		return nil, errors.New("you don't have permission to view analytics for this topic")
+	}
+
+	resp, err := s.contentAnalytics(ctx, userID, models.AnalyticsContentTopic, topicID, params)
+	if err != nil {
+		return nil, err
+	}
+	resp.Title = topic.Title
+
+	return resp, nil
+}
+
+// RollupDay recomputes the stats of one calendar day in the reporting timezone
+func (s *analyticsServiceImpl) RollupDay(ctx context.Context, day time.Time) error {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, s.location)
+	end := start.AddDate(0, 0, 1)
+
+	// Topic views come from the live counter, so they can only be captured while the day is running
+	now := time.Now()
+	snapshot := !now.Before(start) && now.Before(end)
+
+	return s.analyticsRepo.RollupDay(ctx, start, start, end, snapshot)
+}
+
+// RollupRecent refreshes today and yesterday; it is safe to run repeatedly
+func (s *analyticsServiceImpl) RollupRecent(ctx context.Context) error {
+	today := time.Now().In(s.location)
+	if err := s.RollupDay(ctx, today.AddDate(0, 0, -1)); err != nil {
+		return err
+	}
+	return s.RollupDay(ctx, today)
+}
+
+// Helper methods
+func (s *analyticsServiceImpl) contentAnalytics(ctx context.Context, userID uuid.UUID, contentType models.AnalyticsContentType, contentID uuid.UUID, params *dto.AnalyticsQueryParams) (*dto.ContentAnalyticsResponse, error) {
+	from, to, err := s.parseRange(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repositories.ContentStatFilter{
+		OwnerID:     userID,
+		ContentType: contentType,
+		ContentID:   &contentID,
+		From:        from,
+		To:          to,
+	}
+
+	series, err := s.analyticsRepo.GetContentSeries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	points, totals := s.buildSeries(series, from, to)
+	resp := &dto.ContentAnalyticsResponse{
+		ContentType: string(contentType),
+		ContentID:   contentID,
+		From:        from.Format(analyticsDateLayout),
+		To:          to.Format(analyticsDateLayout),
+		Totals:      totals,
+		Series:      points,
+	}
+
+	// Shares exist for videos only
+	if contentType == models.AnalyticsContentVideo {
+		shares, err := s.analyticsRepo.GetShareSeries(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		resp.ShareSeries, resp.SharesByPlatform = buildShareSeries(shares)
+	}
+
+	return resp, nil
+}
+
+func (s *analyticsServiceImpl) topContent(ctx context.Context, filter repositories.ContentStatFilter, contentType models.AnalyticsContentType) ([]dto.TopContentItem, error) {
+	filter.ContentType = contentType
+	stats, err := s.analyticsRepo.GetTopContent(ctx, filter, analyticsTopItemLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TopContentItem, 0, len(stats))
+	for _, stat := range stats {
+		item := dto.TopContentItem{
+			ContentType: string(contentType),
+			ContentID:   stat.ContentID,
+			Views:       stat.Views,
+			Likes:       stat.Likes,
+			Comments:    stat.Comments,
+			Shares:      stat.Shares,
+		}
+
+		// Deleted content still counts in the period but has no title anymore
+		if contentType == models.AnalyticsContentVideo {
+			if video, err := s.videoRepo.FindByIDIncludingInactive(ctx, stat.ContentID); err == nil {
+				item.Title = video.Title
+			}
+		} else if topic, err := s.topicRepo.GetByID(ctx, stat.ContentID); err == nil {
+			item.Title = topic.Title
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *analyticsServiceImpl) parseRange(params *dto.AnalyticsQueryParams) (time.Time, time.Time, error) {
+	now := time.Now().In(s.location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+	if params.To != "" {
+		parsed, err := time.ParseInLocation(analyticsDateLayout, params.To, s.location)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to date")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(analyticsDefaultDays - 1))
+	if params.From != "" {
+		parsed, err := time.ParseInLocation(analyticsDateLayout, params.From, s.location)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from date")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	if to.Sub(from) > analyticsMaxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("date range is too large")
+	}
+
+	return from, to, nil
+}
+
+// buildSeries fills days without activity with zeros and sums the totals
+func (s *analyticsServiceImpl) buildSeries(stats []models.ContentDailyStat, from, to time.Time) ([]dto.AnalyticsPoint, dto.AnalyticsTotals) {
+	byDate := make(map[string]models.ContentDailyStat, len(stats))
+	for _, stat := range stats {
+		byDate[stat.Date.Format(analyticsDateLayout)] = stat
+	}
+
+	var totals dto.AnalyticsTotals
+	var completed int64
+	points := make([]dto.AnalyticsPoint, 0)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(analyticsDateLayout)
+		stat := byDate[date]
+
+		points = append(points, dto.AnalyticsPoint{
+			Date:           date,
+			Views:          stat.Views,
+			WatchTime:      stat.WatchTime,
+			CompletedViews: stat.CompletedViews,
+			Likes:          stat.Likes,
+			Comments:       stat.Comments,
+			Shares:         stat.Shares,
+		})
+
+		totals.Views += stat.Views
+		totals.WatchTime += stat.WatchTime
+		totals.Likes += stat.Likes
+		totals.Comments += stat.Comments
+		totals.Shares += stat.Shares
+		completed += stat.CompletedViews
+	}
+
+	if totals.Views > 0 {
+		totals.AvgWatchTime = float64(totals.WatchTime) / float64(totals.Views)
+		totals.CompletionRate = float64(completed) / float64(totals.Views)
+	}
+
+	return points, totals
+}
+
+func (s *analyticsServiceImpl) buildFollowerGrowth(stats []models.FollowerDailyStat, from, to time.Time) []dto.FollowerGrowthPoint {
+	fromDate := from.Format(analyticsDateLayout)
+
+	// Days without new followers carry the previous total forward, starting from the last total before the range
+	var total int64
+	byDate := make(map[string]models.FollowerDailyStat, len(stats))
+	for _, stat := range stats {
+		date := stat.Date.Format(analyticsDateLayout)
+		if date < fromDate {
+			total = stat.TotalFollowers
+			continue
+		}
+		byDate[date] = stat
+	}
+
+	points := make([]dto.FollowerGrowthPoint, 0)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(analyticsDateLayout)
+		stat, ok := byDate[date]
+		if ok {
+			total = stat.TotalFollowers
+		}
+
+		points = append(points, dto.FollowerGrowthPoint{
+			Date:           date,
+			NewFollowers:   stat.NewFollowers,
+			TotalFollowers: total,
+		})
+	}
+
+	return points
+}
+
+func buildShareSeries(stats []models.ShareDailyStat) ([]dto.SharePlatformPoint, map[string]int64) {
+	points := make([]dto.SharePlatformPoint, 0, len(stats))
+	byPlatform := make(map[string]int64)
+	for _, stat := range stats {
+		points = append(points, dto.SharePlatformPoint{
+			Date:     stat.Date.Format(analyticsDateLayout),
+			Platform: stat.Platform,
+			Shares:   stat.Shares,
+		})
+		byPlatform[stat.Platform] += stat.Shares
+	}
+	return points, byPlatform
+}
@@ -0,0 +1,366 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+
+	"github.com/google/uuid"
+)
+
+type bookmarkServiceImpl struct {
+	bookmarkRepo repositories.BookmarkRepository
+	topicRepo    repositories.TopicRepository
+	replyRepo    repositories.ReplyRepository
+	videoRepo    repositories.VideoRepository
+	commentRepo  repositories.CommentRepository
+	forumService services.ForumService
+	videoService services.VideoService
+}
+
+func NewBookmarkService(
+	bookmarkRepo repositories.BookmarkRepository,
+	topicRepo repositories.TopicRepository,
+	replyRepo repositories.ReplyRepository,
+	videoRepo repositories.VideoRepository,
+	commentRepo repositories.CommentRepository,
+	forumService services.ForumService,
+	videoService services.VideoService,
+) services.BookmarkService {
+	return &bookmarkServiceImpl{
+		bookmarkRepo: bookmarkRepo,
+		topicRepo:    topicRepo,
+		replyRepo:    replyRepo,
+		videoRepo:    videoRepo,
+		commentRepo:  commentRepo,
+		forumService: forumService,
+		videoService: videoService,
+	}
+}
+
+func (s *bookmarkServiceImpl) AddBookmark(ctx context.Context, userID uuid.UUID, req *dto.CreateBookmarkRequest) (*dto.BookmarkResponse, error) {
+	targetType := models.BookmarkTargetType(req.TargetType)
+	if err := s.checkTarget(ctx, userID, targetType, req.TargetID); err != nil {
+		return nil, err
+	}
+
+	if req.FolderID != nil {
+		if _, err := s.getOwnedFolder(ctx, userID, *req.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	bookmark := &models.Bookmark{
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   req.TargetID,
+		FolderID:   req.FolderID,
+	}
+
+	created, err := s.bookmarkRepo.Create(ctx, bookmark)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errors.New(req.TargetType + " already bookmarked")
+	}
+
+	return dto.BookmarkToBookmarkResponse(bookmark), nil
+}
+
+func (s *bookmarkServiceImpl) RemoveBookmark(ctx context.Context, userID uuid.UUID, targetType string, targetID uuid.UUID) error {
+	// Removing doesn't check the target, so bookmarks on deleted content can still be cleared
+	deleted, err := s.bookmarkRepo.Delete(ctx, userID, models.BookmarkTargetType(targetType), targetID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("bookmark not found")
+	}
+	return nil
+}
+
+func (s *bookmarkServiceImpl) GetBookmarkStatus(ctx context.Context, userID uuid.UUID, targetType string, targetID uuid.UUID) (*dto.BookmarkStatusResponse, error) {
+	bookmark, err := s.bookmarkRepo.GetByTarget(ctx, userID, models.BookmarkTargetType(targetType), targetID)
+	if err != nil {
+		return &dto.BookmarkStatusResponse{IsBookmarked: false}, nil
+	}
+
+	return &dto.BookmarkStatusResponse{
+		IsBookmarked: true,
+		FolderID:     bookmark.FolderID,
+	}, nil
+}
+
+func (s *bookmarkServiceImpl) MoveBookmark(ctx context.Context, userID, bookmarkID uuid.UUID, req *dto.MoveBookmarkRequest) (*dto.BookmarkResponse, error) {
+	bookmark, err := s.bookmarkRepo.GetByID(ctx, bookmarkID)
+	if err != nil {
+		return nil, err
+	}
+	if bookmark.UserID != userID {
+		return nil, errors.New("bookmark not found")
+	}
+
+	if req.FolderID != nil {
+		if _, err := s.getOwnedFolder(ctx, userID, *req.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.bookmarkRepo.SetFolder(ctx, bookmarkID, req.FolderID); err != nil {
+		return nil, err
+	}
+
+	bookmark.FolderID = req.FolderID
+	return dto.BookmarkToBookmarkResponse(bookmark), nil
+}
+
+func (s *bookmarkServiceImpl) GetBookmarks(ctx context.Context, userID uuid.UUID, params *dto.BookmarkQueryParams) (*dto.BookmarkListResponse, error) {
+	hiddenForumIDs, err := s.forumService.GetHiddenForumIDs(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repositories.BookmarkFilter{
+		TargetType:      models.BookmarkTargetType(params.Type),
+		ExcludeForumIDs: hiddenForumIDs,
+	}
+	if params.FolderID == "none" {
+		filter.Unsorted = true
+	} else if params.FolderID != "" {
+		folderID, err := uuid.Parse(params.FolderID)
+		if err != nil {
+			return nil, errors.New("invalid folder ID")
+		}
+		if _, err := s.getOwnedFolder(ctx, userID, folderID); err != nil {
+			return nil, err
+		}
+		filter.FolderID = &folderID
+	}
+
+	page, limit := dto.NormalizePage(params.Page, params.Limit)
+	offset := (page - 1) * limit
+	bookmarks, total, err := s.bookmarkRepo.FindByUserID(ctx, userID, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.resolveBookmarks(ctx, userID, bookmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BookmarkListResponse{
+		Bookmarks: responses,
+		Meta:      dto.NewPaginationMeta(total, offset, limit),
+	}, nil
+}
+
+func (s *bookmarkServiceImpl) CreateFolder(ctx context.Context, userID uuid.UUID, req *dto.CreateBookmarkFolderRequest) (*dto.BookmarkFolderResponse, error) {
+	exists, err := s.bookmarkRepo.FolderNameExists(ctx, userID, req.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("a bookmark folder with this name already exists")
+	}
+
+	folder := &models.BookmarkFolder{
+		UserID: userID,
+		Name:   req.Name,
+	}
+	if err := s.bookmarkRepo.CreateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	return dto.BookmarkFolderToBookmarkFolderResponse(folder), nil
+}
+
+func (s *bookmarkServiceImpl) GetFolders(ctx context.Context, userID uuid.UUID) ([]dto.BookmarkFolderResponse, error) {
+	folders, err := s.bookmarkRepo.FindFoldersByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.BookmarkFolderResponse, len(folders))
+	for i, folder := range folders {
+		responses[i] = *dto.BookmarkFolderToBookmarkFolderResponse(folder)
+	}
+
+	return responses, nil
+}
+
+func (s *bookmarkServiceImpl) UpdateFolder(ctx context.Context, userID, folderID uuid.UUID, req *dto.UpdateBookmarkFolderRequest) (*dto.BookmarkFolderResponse, error) {
+	folder, err := s.getOwnedFolder(ctx, userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.bookmarkRepo.FolderNameExists(ctx, userID, req.Name, &folderID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("a bookmark folder with this name already exists")
+	}
+
+	folder.Name = req.Name
+	if err := s.bookmarkRepo.UpdateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	return dto.BookmarkFolderToBookmarkFolderResponse(folder), nil
+}
+
+func (s *bookmarkServiceImpl) DeleteFolder(ctx context.Context, userID, folderID uuid.UUID) error {
+	if _, err := s.getOwnedFolder(ctx, userID, folderID); err != nil {
+		return err
+	}
+
+	return s.bookmarkRepo.DeleteFolder(ctx, folderID)
+}
+
+// Helper methods
+
+// checkTarget makes sure the content exists and the user can currently see it
+func (s *bookmarkServiceImpl) checkTarget(ctx context.Context, userID uuid.UUID, targetType models.BookmarkTargetType, targetID uuid.UUID) error {
+	switch targetType {
+	case models.BookmarkTargetTopic:
+		topic, err := s.topicRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return errors.New("topic not found")
+		}
+		return s.forumService.CheckAccess(ctx, topic.ForumID, &userID, models.ForumActionView)
+	case models.BookmarkTargetReply:
+		reply, err := s.replyRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return errors.New("reply not found")
+		}
+		topic, err := s.topicRepo.GetByID(ctx, reply.TopicID)
+		if err != nil {
+			return errors.New("reply not found")
+		}
+		return s.forumService.CheckAccess(ctx, topic.ForumID, &userID, models.ForumActionView)
+	case models.BookmarkTargetVideo:
+		if _, err := s.videoRepo.FindByID(ctx, targetID); err != nil {
+			return errors.New("video not found")
+		}
+		return nil
+	case models.BookmarkTargetComment:
+		comment, err := s.commentRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return errors.New("comment not found")
+		}
+		if _, err := s.videoRepo.FindByID(ctx, comment.VideoID); err != nil {
+			return errors.New("comment not found")
+		}
+		return nil
+	default:
+		return errors.New("invalid bookmark target type")
+	}
+}
+
+func (s *bookmarkServiceImpl) getOwnedFolder(ctx context.Context, userID, folderID uuid.UUID) (*models.BookmarkFolder, error) {
+	folder, err := s.bookmarkRepo.GetFolderByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other users' folders look like they don't exist
+	if folder.UserID != userID {
+		return nil, errors.New("bookmark folder not found")
+	}
+
+	return folder, nil
+}
+
+// resolveBookmarks loads the saved content for a page of bookmarks in one query per type;
+// items whose content vanished between the two queries are dropped
+func (s *bookmarkServiceImpl) resolveBookmarks(ctx context.Context, userID uuid.UUID, bookmarks []*models.Bookmark) ([]dto.BookmarkResponse, error) {
+	idsByType := make(map[models.BookmarkTargetType][]uuid.UUID)
+	for _, bookmark := range bookmarks {
+		idsByType[bookmark.TargetType] = append(idsByType[bookmark.TargetType], bookmark.TargetID)
+	}
+
+	topics := make(map[uuid.UUID]*dto.TopicResponse)
+	if ids := idsByType[models.BookmarkTargetTopic]; len(ids) > 0 {
+		found, err := s.topicRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, topic := range found {
+			topics[topic.ID] = dto.TopicToTopicResponse(topic)
+		}
+	}
+
+	replies := make(map[uuid.UUID]*dto.ReplyResponse)
+	if ids := idsByType[models.BookmarkTargetReply]; len(ids) > 0 {
+		found, err := s.replyRepo.GetByIDsIncludingDeleted(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, reply := range found {
+			if reply.DeletedAt == nil {
+				replies[reply.ID] = dto.ReplyToReplyResponse(reply, false)
+			}
+		}
+	}
+
+	videos := make(map[uuid.UUID]dto.VideoResponse)
+	if ids := idsByType[models.BookmarkTargetVideo]; len(ids) > 0 {
+		found, err := s.videoService.GetVideosByIDs(ctx, ids, &userID)
+		if err != nil {
+			return nil, err
+		}
+		videos = found
+	}
+
+	comments := make(map[uuid.UUID]*dto.CommentResponse)
+	if ids := idsByType[models.BookmarkTargetComment]; len(ids) > 0 {
+		found, err := s.commentRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range found {
+			if comment.User != nil {
+				comments[comment.ID] = dto.CommentToCommentResponse(comment, comment.User)
+			}
+		}
+	}
+
+	responses := make([]dto.BookmarkResponse, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		resp := dto.BookmarkToBookmarkResponse(bookmark)
+		switch bookmark.TargetType {
+		case models.BookmarkTargetTopic:
+			resp.Topic = topics[bookmark.TargetID]
+			if resp.Topic == nil {
+				continue
+			}
+		case models.BookmarkTargetReply:
+			resp.Reply = replies[bookmark.TargetID]
+			if resp.Reply == nil {
+				continue
+			}
+		case models.BookmarkTargetVideo:
+			video, ok := videos[bookmark.TargetID]
+			if !ok {
+				continue
+			}
+			resp.Video = &video
+		case models.BookmarkTargetComment:
+			resp.Comment = comments[bookmark.TargetID]
+			if resp.Comment == nil {
+				continue
+			}
+		default:
+			continue
+		}
+		responses = append(responses, *resp)
+	}
+
+	return responses, nil
+}
@@ -7,29 +7,31 @@ import (
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/websocket"
 	"math"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type commentServiceImpl struct {
-	commentRepo         repositories.CommentRepository
-	videoRepo           repositories.VideoRepository
-	userRepo            repositories.UserRepository
-	notificationService services.NotificationService
+	commentRepo     repositories.CommentRepository
+	videoRepo       repositories.VideoRepository
+	userRepo        repositories.UserRepository
+	queueService    services.QueueService
 }
 
 func NewCommentService(
 	commentRepo repositories.CommentRepository,
 	videoRepo repositories.VideoRepository,
 	userRepo repositories.UserRepository,
-	notificationService services.NotificationService,
+	queueService services.QueueService,
 ) services.CommentService {
 	return &commentServiceImpl{
-		commentRepo:         commentRepo,
-		videoRepo:           videoRepo,
-		userRepo:            userRepo,
-		notificationService: notificationService,
+		commentRepo:     commentRepo,
+		videoRepo:       videoRepo,
+		userRepo:        userRepo,
+		queueService:    queueService,
 	}
 }
 
@@ -53,34 +55,25 @@ func (s *commentServiceImpl) CreateComment(ctx context.Context, userID uuid.UUID
 
 	// Create comment
 	comment := &models.Comment{
+		ID:       uuid.New(),
 		UserID:   userID,
 		VideoID:  req.VideoID,
 		ParentID: req.ParentID,
 		Content:  req.Content,
 	}
 
-	if err := s.commentRepo.Create(ctx, comment); err != nil {
+	// Create notification for video comment or comment reply, and update the comment count
+	// in video table in the background
+	tasks, err := newTasks(s.queueService,
+		queuedTask{dto.NotifyCommentTask{CommentID: comment.ID}, "comment:" + comment.ID.String()},
+		queuedTask{dto.SyncCommentCountTask{VideoID: req.VideoID}, ""},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.commentRepo.Create(ctx, comment, tasks...); err != nil {
 		return nil, err
 	}
-
-	// Create notification for video comment or comment reply
-	go func() {
-		if req.ParentID != nil {
-			// This is a reply to a comment
-			_ = s.notificationService.CreateCommentReplyNotification(context.Background(), *req.ParentID, userID)
-		} else {
-			// This is a comment on a video
-			_ = s.notificationService.CreateVideoCommentNotification(context.Background(), req.VideoID, userID)
-		}
-	}()
-
-	// Update comment count in video table asynchronously
-	go func() {
-		count, err := s.commentRepo.CountByVideoID(context.Background(), req.VideoID)
-		if err == nil {
-			_ = s.videoRepo.UpdateCommentCount(context.Background(), req.VideoID, int(count))
-		}
-	}()
 
 	// Load user for response
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -88,7 +81,10 @@ func (s *commentServiceImpl) CreateComment(ctx context.Context, userID uuid.UUID
 		return nil, err
 	}
 
-	return s.toCommentResponse(comment, user), nil
+	resp := s.toCommentResponse(comment, user)
+	broadcastComment(resp, websocket.EventCommentCreated)
+
+	return resp, nil
 }
 
 func (s *commentServiceImpl) GetCommentsByVideoID(ctx context.Context, videoID uuid.UUID, page, limit int) (*dto.CommentListResponse, error) {
@@ -159,10 +155,29 @@ func (s *commentServiceImpl) UpdateComment(ctx context.Context, userID uuid.UUID
 		return nil, errors.New("you don't have permission to update this comment")
 	}
 
-	// Update content
+	// Update content; saving the same text again is not an edit
+	previous := comment.Content
+	edited := previous != req.Content
 	comment.Content = req.Content
-
-	if err := s.commentRepo.Update(ctx, comment); err != nil {
+	if edited {
+		now := time.Now()
+		comment.EditCount++
+		comment.EditedAt = &now
+	}
+
+	var revision *repositories.RevisionEdit
+	if edited {
+		revision = revisionEdit(&dto.ContentEdit{
+			ContentType: models.RevisionContentComment,
+			ContentID:   commentID,
+			AuthorID:    comment.UserID,
+			PostedAt:    comment.CreatedAt,
+			EditorID:    userID,
+			Previous:    dto.RevisionSnapshot{Content: previous},
+			Current:     dto.RevisionSnapshot{Content: comment.Content},
+		})
+	}
+	if err := s.commentRepo.Edit(ctx, comment, revision); err != nil {
 		return nil, err
 	}
 
@@ -172,7 +187,12 @@ func (s *commentServiceImpl) UpdateComment(ctx context.Context, userID uuid.UUID
 		return nil, err
 	}
 
-	return s.toCommentResponse(comment, user), nil
+	resp := s.toCommentResponse(comment, user)
+	if edited {
+		broadcastComment(resp, websocket.EventCommentUpdated)
+	}
+
+	return resp, nil
 }
 
 func (s *commentServiceImpl) DeleteComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) error {
@@ -187,18 +207,15 @@ func (s *commentServiceImpl) DeleteComment(ctx context.Context, userID uuid.UUID
 		return errors.New("you don't have permission to delete this comment")
 	}
 
-	// Delete comment
-	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
+	// Delete comment and update comment count in video table in the background
+	tasks, err := newTasks(s.queueService, queuedTask{dto.SyncCommentCountTask{VideoID: comment.VideoID}, ""})
+	if err != nil {
 		return err
 	}
-
-	// Update comment count in video table asynchronously
-	go func() {
-		count, err := s.commentRepo.CountByVideoID(context.Background(), comment.VideoID)
-		if err == nil {
-			_ = s.videoRepo.UpdateCommentCount(context.Background(), comment.VideoID, int(count))
-		}
-	}()
+	if err := s.commentRepo.Delete(ctx, commentID, tasks...); err != nil {
+		return err
+	}
+	broadcastCommentDeleted(comment)
 
 	return nil
 }
@@ -210,39 +227,20 @@ func (s *commentServiceImpl) DeleteCommentByAdmin(ctx context.Context, commentID
 		return errors.New("comment not found")
 	}
 
-	// Delete comment
-	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
+	// Delete comment and update comment count in video table in the background
+	tasks, err := newTasks(s.queueService, queuedTask{dto.SyncCommentCountTask{VideoID: comment.VideoID}, ""})
+	if err != nil {
 		return err
 	}
-
-	// Update comment count in video table asynchronously
-	go func() {
-		count, err := s.commentRepo.CountByVideoID(context.Background(), comment.VideoID)
-		if err == nil {
-			_ = s.videoRepo.UpdateCommentCount(context.Background(), comment.VideoID, int(count))
-		}
-	}()
+	if err := s.commentRepo.Delete(ctx, commentID, tasks...); err != nil {
+		return err
+	}
+	broadcastCommentDeleted(comment)
 
 	return nil
 }
 
 // Helper method to convert Comment to CommentResponse
 func (s *commentServiceImpl) toCommentResponse(comment *models.Comment, user *models.User) *dto.CommentResponse {
-	return &dto.CommentResponse{
-		ID:       comment.ID,
-		UserID:   comment.UserID,
-		User: dto.UserSummaryComment{
-			ID:        user.ID,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Avatar:    user.Avatar,
-		},
-		VideoID:   comment.VideoID,
-		ParentID:  comment.ParentID,
-		Content:   comment.Content,
-		CreatedAt: comment.CreatedAt,
-		UpdatedAt: comment.UpdatedAt,
-		Replies:   []dto.CommentResponse{}, // Initialize empty slice
-	}
+	return dto.CommentToCommentResponse(comment, user)
 }
@@ -0,0 +1,289 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/unfurl"
+	"gofiber-social/pkg/markdown"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxUnfurlLinksPerPost = 5
+	unfurlFetchTimeout    = 15 * time.Second
+)
+
+type contentServiceImpl struct {
+	fileRepo        repositories.FileRepository
+	videoRepo       repositories.VideoRepository
+	linkPreviewRepo repositories.LinkPreviewRepository
+	linkResolver    unfurl.LinkResolver // nil disables unfurling
+	refreshAfter    time.Duration
+
+	mu       sync.Mutex
+	inFlight map[string]bool
+}
+
+func NewContentService(
+	fileRepo repositories.FileRepository,
+	videoRepo repositories.VideoRepository,
+	linkPreviewRepo repositories.LinkPreviewRepository,
+	linkResolver unfurl.LinkResolver,
+	refreshAfter time.Duration,
+) services.ContentService {
+	return &contentServiceImpl{
+		fileRepo:        fileRepo,
+		videoRepo:       videoRepo,
+		linkPreviewRepo: linkPreviewRepo,
+		linkResolver:    linkResolver,
+		refreshAfter:    refreshAfter,
+		inFlight:        make(map[string]bool),
+	}
+}
+
+func (s *contentServiceImpl) Render(ctx context.Context, authorID uuid.UUID, source string) (*dto.RenderedContent, error) {
+	doc := markdown.Parse(source)
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	refs := doc.Refs()
+
+	embeds, err := s.resolveEmbeds(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	// รูปต้องเป็นไฟล์รูปภาพที่ผู้เขียนอัปโหลดเอง
+	for _, fileID := range refs.FileIDs {
+		if _, ok := embeds.Images[fileID]; !ok {
+			return nil, errors.New("embedded image not found or not a public image: " + fileID.String())
+		}
+	}
+	for _, videoID := range refs.VideoIDs {
+		if _, ok := embeds.Videos[videoID]; !ok {
+			return nil, errors.New("embedded video not found: " + videoID.String())
+		}
+	}
+
+	images, err := s.fileRepo.GetByIDs(ctx, refs.FileIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range images {
+		if file.UserID != authorID {
+			return nil, errors.New("you can only embed images you uploaded")
+		}
+	}
+
+	return &dto.RenderedContent{
+		HTML:    doc.HTML(embeds),
+		FileIDs: refs.FileIDs,
+		Links:   refs.Links,
+	}, nil
+}
+
+func (s *contentServiceImpl) RenderStored(ctx context.Context, source string) string {
+	doc := markdown.Parse(source)
+	embeds, err := s.resolveEmbeds(ctx, doc.Refs())
+	if err != nil {
+		embeds = markdown.Embeds{}
+	}
+	return doc.HTML(embeds)
+}
+
+func (s *contentServiceImpl) AttachEmbeds(ctx context.Context, authorID uuid.UUID, content *dto.RenderedContent, resourceType models.FileReferenceType, resourceID uuid.UUID) {
+	// References are only added: older revisions may still show images removed by an edit
+	for _, fileID := range content.FileIDs {
+		err := s.fileRepo.CreateReference(ctx, &models.FileReference{
+			FileID:       fileID,
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to attach embedded file %s to %s %s: %v", fileID, resourceType, resourceID, err)
+		}
+	}
+}
+
+func (s *contentServiceImpl) GetLinkPreviews(ctx context.Context, urls []string) map[string]dto.LinkPreviewResponse {
+	result := make(map[string]dto.LinkPreviewResponse)
+	if s.linkResolver == nil || len(urls) == 0 {
+		return result
+	}
+
+	previews, err := s.linkPreviewRepo.GetByURLs(ctx, urls)
+	if err != nil {
+		return result
+	}
+
+	fresh := make(map[string]bool, len(previews))
+	for _, preview := range previews {
+		if time.Since(preview.FetchedAt) < s.refreshAfter {
+			fresh[preview.URL] = true
+		}
+		if !preview.Failed {
+			result[preview.URL] = dto.LinkPreviewResponse{
+				URL:         preview.URL,
+				Title:       preview.Title,
+				Description: preview.Description,
+				ImageURL:    preview.ImageURL,
+				SiteName:    preview.SiteName,
+			}
+		}
+	}
+
+	var missing []string
+	for _, url := range urls {
+		if !fresh[url] {
+			missing = append(missing, url)
+		}
+	}
+	s.PrefetchLinkPreviews(missing)
+
+	return result
+}
+
+func (s *contentServiceImpl) PrefetchLinkPreviews(urls []string) {
+	if s.linkResolver == nil || len(urls) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	var pending []string
+	for _, url := range urls {
+		if !s.inFlight[url] {
+			s.inFlight[url] = true
+			pending = append(pending, url)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	go func() {
+		for _, url := range pending {
+			s.fetchPreview(url)
+		}
+	}()
+}
+
+// Helper methods
+func (s *contentServiceImpl) fetchPreview(url string) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.inFlight, url)
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), unfurlFetchTimeout)
+	defer cancel()
+
+	preview := &models.LinkPreview{URL: url, FetchedAt: time.Now()}
+	meta, err := s.linkResolver.Resolve(ctx, url)
+	if err != nil || meta.Title == "" {
+		preview.Failed = true
+	} else {
+		preview.Title = truncate(meta.Title, 300)
+		preview.Description = truncate(meta.Description, 1000)
+		preview.SiteName = truncate(meta.SiteName, 200)
+		if len(meta.ImageURL) <= 2048 {
+			preview.ImageURL = meta.ImageURL
+		}
+	}
+
+	if err := s.linkPreviewRepo.Upsert(ctx, preview); err != nil {
+		log.Printf("Warning: Failed to store link preview for %s: %v", url, err)
+	}
+}
+
+func (s *contentServiceImpl) resolveEmbeds(ctx context.Context, refs markdown.Refs) (markdown.Embeds, error) {
+	embeds := markdown.Embeds{
+		Images: make(map[uuid.UUID]string, len(refs.FileIDs)),
+		Videos: make(map[uuid.UUID]markdown.Video, len(refs.VideoIDs)),
+	}
+
+	files, err := s.fileRepo.GetByIDs(ctx, refs.FileIDs)
+	if err != nil {
+		return embeds, err
+	}
+	for _, file := range files {
+		// Stored HTML keeps the URL, so only public images can be embedded: protected files
+		// are served through expiring signed URLs
+		if strings.HasPrefix(file.MimeType, "image/") && !file.Protected {
+			embeds.Images[file.ID] = file.URL
+		}
+	}
+
+	videos, err := s.videoRepo.FindByIDs(ctx, refs.VideoIDs)
+	if err != nil {
+		return embeds, err
+	}
+	for _, video := range videos {
+		embeds.Videos[video.ID] = markdown.Video{Title: video.Title}
+	}
+
+	return embeds, nil
+}
+
+// previewTarget is a response field to fill with the previews of its links
+type previewTarget struct {
+	previews *[]dto.LinkPreviewResponse
+	links    []string
+}
+
+func topicPreviewTarget(topic *dto.TopicResponse) previewTarget {
+	return previewTarget{previews: &topic.LinkPreviews, links: linksToPreview(markdown.Links(topic.Content))}
+}
+
+// replyPreviewTargets collects a reply and its nested replies
+func replyPreviewTargets(reply *dto.ReplyResponse, targets []previewTarget) []previewTarget {
+	targets = append(targets, previewTarget{previews: &reply.LinkPreviews, links: linksToPreview(markdown.Links(reply.Content))})
+	for i := range reply.Replies {
+		targets = replyPreviewTargets(&reply.Replies[i], targets)
+	}
+	return targets
+}
+
+// fillLinkPreviews looks up the previews of all targets with one query
+func fillLinkPreviews(ctx context.Context, contentService services.ContentService, targets []previewTarget) {
+	var urls []string
+	for _, target := range targets {
+		urls = append(urls, target.links...)
+	}
+
+	previews := contentService.GetLinkPreviews(ctx, urls)
+	for _, target := range targets {
+		for _, url := range target.links {
+			if preview, ok := previews[url]; ok {
+				*target.previews = append(*target.previews, preview)
+			}
+		}
+	}
+}
+
+// linksToPreview limits how many links of one post are unfurled
+func linksToPreview(links []string) []string {
+	if len(links) > maxUnfurlLinksPerPost {
+		return links[:maxUnfurlLinksPerPost]
+	}
+	return links
+}
+
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
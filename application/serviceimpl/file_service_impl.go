@@ -10,6 +10,7 @@ import (
 	"gofiber-social/domain/services"
 	"gofiber-social/infrastructure/storage"
 	"gofiber-social/pkg/utils"
+	"log"
 	"mime/multipart"
 	"path/filepath"
 	"strings"
@@ -18,26 +19,53 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	orphanCleanupBatchSize = 500 // limits how many files a single GC run removes
+	protectMediaBatchSize  = 100 // files moved to the secure zone per run
+)
+
+// protectedReferenceTypes are the uses that keep a file in the secure zone
+var protectedReferenceTypes = []models.FileReferenceType{
+	models.FileReferenceVideo,
+	models.FileReferenceVideoThumbnail,
+	models.FileReferenceMessage,
+}
+
 type FileServiceImpl struct {
-	fileRepo repositories.FileRepository
-	userRepo repositories.UserRepository
-	storage  storage.BunnyStorage
+	fileRepo          repositories.FileRepository
+	userRepo          repositories.UserRepository
+	storage           storage.BunnyStorage
+	defaultQuota      int64
+	orphanGracePeriod time.Duration
+	signedURLTTL      time.Duration
 }
 
-func NewFileService(fileRepo repositories.FileRepository, userRepo repositories.UserRepository, storage storage.BunnyStorage) services.FileService {
+func NewFileService(fileRepo repositories.FileRepository, userRepo repositories.UserRepository, storage storage.BunnyStorage, defaultQuota int64, orphanGracePeriod, signedURLTTL time.Duration) services.FileService {
 	return &FileServiceImpl{
-		fileRepo: fileRepo,
-		userRepo: userRepo,
-		storage:  storage,
+		fileRepo:          fileRepo,
+		userRepo:          userRepo,
+		storage:           storage,
+		defaultQuota:      defaultQuota,
+		orphanGracePeriod: orphanGracePeriod,
+		signedURLTTL:      signedURLTTL,
 	}
 }
 
 func (s *FileServiceImpl) UploadFile(ctx context.Context, userID uuid.UUID, fileHeader *multipart.FileHeader, options *dto.UploadFileRequest) (*models.File, error) {
-	_, err := s.userRepo.GetByID(ctx, userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
 
+	// ตรวจสอบโควต้าพื้นที่จัดเก็บก่อนอัปโหลด
+	used, err := s.fileRepo.SumSizeByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if used+fileHeader.Size > s.quotaFor(user) {
+		return nil, errors.New("storage quota exceeded")
+	}
+
 	file, err := fileHeader.Open()
 	if err != nil {
 		return nil, err
@@ -82,7 +110,8 @@ func (s *FileServiceImpl) UploadFile(ctx context.Context, userID uuid.UUID, file
 	// Normalize path separators for storage
 	cdnPath = strings.ReplaceAll(cdnPath, "\\", "/")
 
-	url, err := s.storage.UploadFile(file, cdnPath, mimeType)
+	protected := strings.HasPrefix(mimeType, "video/") || (options != nil && options.Protected)
+	url, err := s.storage.UploadFile(file, cdnPath, mimeType, protected)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +123,7 @@ func (s *FileServiceImpl) UploadFile(ctx context.Context, userID uuid.UUID, file
 		MimeType:  mimeType,
 		URL:       url,
 		CDNPath:   cdnPath,
+		Protected: protected,
 		UserID:    userID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -101,7 +131,7 @@ func (s *FileServiceImpl) UploadFile(ctx context.Context, userID uuid.UUID, file
 
 	err = s.fileRepo.Create(ctx, fileModel)
 	if err != nil {
-		s.storage.DeleteFile(cdnPath)
+		s.storage.DeleteFile(cdnPath, protected)
 		return nil, err
 	}
 
@@ -136,7 +166,15 @@ func (s *FileServiceImpl) DeleteFile(ctx context.Context, fileID uuid.UUID) erro
 		return errors.New("file not found")
 	}
 
-	err = s.storage.DeleteFile(file.CDNPath)
+	refCount, err := s.fileRepo.CountReferences(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	if refCount > 0 {
+		return errors.New("file is in use and cannot be deleted")
+	}
+
+	err = s.storage.DeleteFile(file.CDNPath, file.Protected)
 	if err != nil {
 		return err
 	}
@@ -158,6 +196,197 @@ func (s *FileServiceImpl) ListFiles(ctx context.Context, offset, limit int) ([]*
 	return files, count, nil
 }
 
+func (s *FileServiceImpl) AttachFile(ctx context.Context, fileID, ownerID uuid.UUID, resourceType models.FileReferenceType, resourceID uuid.UUID) error {
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return errors.New("file not found")
+	}
+
+	if file.UserID != ownerID {
+		return errors.New("you don't have permission to use this file")
+	}
+
+	if isProtectedReference(resourceType) {
+		if err := s.ProtectFile(ctx, file); err != nil {
+			return fmt.Errorf("failed to protect file: %v", err)
+		}
+	}
+
+	return s.fileRepo.CreateReference(ctx, &models.FileReference{
+		FileID:       fileID,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+	})
+}
+
+// AttachFileByURL attaches the uploaded file behind a URL (e.g. avatar, topic thumbnail).
+// URLs that do not point to one of our uploads are ignored.
+func (s *FileServiceImpl) AttachFileByURL(ctx context.Context, ownerID uuid.UUID, url string, resourceType models.FileReferenceType, resourceID uuid.UUID) error {
+	if url == "" {
+		return nil
+	}
+
+	file, err := s.fileRepo.GetByURL(ctx, url)
+	if err != nil || file.UserID != ownerID {
+		return nil
+	}
+
+	return s.fileRepo.CreateReference(ctx, &models.FileReference{
+		FileID:       file.ID,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+	})
+}
+
+// ReleaseFiles drops the references held by a resource and deletes any file
+// that is no longer used by anything else.
+func (s *FileServiceImpl) ReleaseFiles(ctx context.Context, resourceType models.FileReferenceType, resourceID uuid.UUID) error {
+	fileIDs, err := s.fileRepo.DeleteReferencesByResource(ctx, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+
+	for _, fileID := range fileIDs {
+		refCount, err := s.fileRepo.CountReferences(ctx, fileID)
+		if err != nil || refCount > 0 {
+			continue
+		}
+
+		file, err := s.fileRepo.GetByID(ctx, fileID)
+		if err != nil {
+			continue
+		}
+
+		if err := s.removeFile(ctx, file); err != nil {
+			log.Printf("Warning: Failed to delete released file %s: %v", fileID, err)
+		}
+	}
+
+	return nil
+}
+
+// CleanupOrphanedFiles removes files that were never attached to anything
+// once they are older than the configured grace period.
+func (s *FileServiceImpl) CleanupOrphanedFiles(ctx context.Context) (int, error) {
+	files, err := s.fileRepo.FindOrphaned(ctx, time.Now().Add(-s.orphanGracePeriod), orphanCleanupBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, file := range files {
+		if err := s.removeFile(ctx, file); err != nil {
+			log.Printf("Warning: Failed to delete orphaned file %s: %v", file.ID, err)
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (s *FileServiceImpl) GetStorageUsage(ctx context.Context, userID uuid.UUID) (*dto.StorageUsageResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	used, err := s.fileRepo.SumSizeByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := s.quotaFor(user)
+	remaining := quota - used
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	var usedPercent float64
+	if quota > 0 {
+		usedPercent = float64(used) / float64(quota) * 100
+	}
+
+	return &dto.StorageUsageResponse{
+		UsedBytes:      used,
+		QuotaBytes:     quota,
+		RemainingBytes: remaining,
+		UsedPercent:    usedPercent,
+	}, nil
+}
+
+func (s *FileServiceImpl) quotaFor(user *models.User) int64 {
+	if user.StorageQuota > 0 {
+		return user.StorageQuota
+	}
+	return s.defaultQuota
+}
+
+// ProtectFile moves a public file into the secure zone; its public URL stops working
+func (s *FileServiceImpl) ProtectFile(ctx context.Context, file *models.File) error {
+	if file.Protected {
+		return nil
+	}
+
+	url, err := s.storage.MoveToSecure(file.CDNPath)
+	if err != nil {
+		return err
+	}
+	if err := s.fileRepo.MarkProtected(ctx, file.ID, url); err != nil {
+		return err
+	}
+
+	file.URL = url
+	file.Protected = true
+	return nil
+}
+
+// ProtectLegacyMedia moves video media and message attachments uploaded before the secure zone
+// existed into it, a batch per run
+func (s *FileServiceImpl) ProtectLegacyMedia(ctx context.Context) (int, error) {
+	files, err := s.fileRepo.FindUnprotectedReferenced(ctx, protectedReferenceTypes, protectMediaBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	protected := 0
+	for _, file := range files {
+		if err := s.ProtectFile(ctx, file); err != nil {
+			log.Printf("Warning: Failed to move file %s to the secure zone: %v", file.ID, err)
+			continue
+		}
+		protected++
+	}
+	return protected, nil
+}
+
+func (s *FileServiceImpl) FileURL(file *models.File) string {
+	if file == nil {
+		return ""
+	}
+	if !file.Protected {
+		return file.URL
+	}
+	return s.storage.GetSignedURL(file.CDNPath, time.Now().Add(s.signedURLTTL))
+}
+
+func isProtectedReference(resourceType models.FileReferenceType) bool {
+	for _, protectedType := range protectedReferenceTypes {
+		if resourceType == protectedType {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFile deletes the CDN object first so a failed delete leaves the row for the next GC run
+func (s *FileServiceImpl) removeFile(ctx context.Context, file *models.File) error {
+	if err := s.storage.DeleteFile(file.CDNPath, file.Protected); err != nil {
+		return err
+	}
+	return s.fileRepo.Delete(ctx, file.ID)
+}
+
 func (s *FileServiceImpl) getMimeTypeFromExtension(ext string) string {
 	ext = strings.ToLower(ext)
 	mimeTypes := map[string]string{
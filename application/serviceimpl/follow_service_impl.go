@@ -14,17 +14,20 @@ import (
 
 type followServiceImpl struct {
 	followRepo          repositories.FollowRepository
+	blockRepo           repositories.BlockRepository
 	userRepo            repositories.UserRepository
 	notificationService services.NotificationService
 }
 
 func NewFollowService(
 	followRepo repositories.FollowRepository,
+	blockRepo repositories.BlockRepository,
 	userRepo repositories.UserRepository,
 	notificationService services.NotificationService,
 ) services.FollowService {
 	return &followServiceImpl{
 		followRepo:          followRepo,
+		blockRepo:           blockRepo,
 		userRepo:            userRepo,
 		notificationService: notificationService,
 	}
@@ -42,6 +45,14 @@ func (s *followServiceImpl) FollowUser(ctx context.Context, followerID, followin
 		return nil, errors.New("following user not found")
 	}
 
+	blocked, err := s.blockRepo.IsBlockedEither(ctx, followerID, followingID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("you can't follow this user")
+	}
+
 	// Follow
 	if err := s.followRepo.Follow(ctx, followerID, followingID); err != nil {
 		return nil, err
@@ -53,15 +64,7 @@ func (s *followServiceImpl) FollowUser(ctx context.Context, followerID, followin
 	}()
 
 	// Update counts asynchronously
-	go func() {
-		// Update follower's following count
-		followingCount, _ := s.followRepo.GetFollowingCount(context.Background(), followerID)
-		_ = s.userRepo.UpdateFollowingCount(context.Background(), followerID, int(followingCount))
-
-		// Update following's follower count
-		followerCount, _ := s.followRepo.GetFollowerCount(context.Background(), followingID)
-		_ = s.userRepo.UpdateFollowerCount(context.Background(), followingID, int(followerCount))
-	}()
+	go s.updateFollowCounts(followerID, followingID)
 
 	return &dto.FollowResponse{
 		FollowerID:  followerID,
@@ -76,15 +79,7 @@ func (s *followServiceImpl) UnfollowUser(ctx context.Context, followerID, follow
 	}
 
 	// Update counts asynchronously
-	go func() {
-		// Update follower's following count
-		followingCount, _ := s.followRepo.GetFollowingCount(context.Background(), followerID)
-		_ = s.userRepo.UpdateFollowingCount(context.Background(), followerID, int(followingCount))
-
-		// Update following's follower count
-		followerCount, _ := s.followRepo.GetFollowerCount(context.Background(), followingID)
-		_ = s.userRepo.UpdateFollowerCount(context.Background(), followingID, int(followerCount))
-	}()
+	go s.updateFollowCounts(followerID, followingID)
 
 	return nil
 }
@@ -207,3 +202,73 @@ func (s *followServiceImpl) GetUserStats(ctx context.Context, userID uuid.UUID)
 		FollowingCount: followingCount,
 	}, nil
 }
+
+func (s *followServiceImpl) BlockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	if blockerID == blockedID {
+		return errors.New("users cannot block themselves")
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, blockedID); err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.blockRepo.Block(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	// Drop follows both ways; either may not exist
+	_ = s.followRepo.Unfollow(ctx, blockerID, blockedID)
+	_ = s.followRepo.Unfollow(ctx, blockedID, blockerID)
+
+	go func() {
+		s.updateFollowCounts(blockerID, blockedID)
+		s.updateFollowCounts(blockedID, blockerID)
+	}()
+
+	return nil
+}
+
+func (s *followServiceImpl) UnblockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return s.blockRepo.Unblock(ctx, blockerID, blockedID)
+}
+
+func (s *followServiceImpl) GetBlockedUsers(ctx context.Context, userID uuid.UUID, page, limit int) (*dto.BlockListResponse, error) {
+	page, limit = dto.NormalizePage(page, limit)
+	offset := (page - 1) * limit
+
+	blocks, totalCount, err := s.blockRepo.FindBlocked(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]dto.BlockedUserResponse, 0, len(blocks))
+	for _, block := range blocks {
+		if block.Blocked == nil {
+			continue
+		}
+		users = append(users, dto.BlockedUserResponse{
+			ID:        block.Blocked.ID,
+			Username:  block.Blocked.Username,
+			FullName:  block.Blocked.FullName,
+			Avatar:    block.Blocked.Avatar,
+			BlockedAt: block.CreatedAt,
+		})
+	}
+
+	return &dto.BlockListResponse{
+		Users:      users,
+		TotalCount: totalCount,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int(math.Ceil(float64(totalCount) / float64(limit))),
+	}, nil
+}
+
+// updateFollowCounts recounts the follower's following count and the followed user's follower count
+func (s *followServiceImpl) updateFollowCounts(followerID, followingID uuid.UUID) {
+	followingCount, _ := s.followRepo.GetFollowingCount(context.Background(), followerID)
+	_ = s.userRepo.UpdateFollowingCount(context.Background(), followerID, int(followingCount))
+
+	followerCount, _ := s.followRepo.GetFollowerCount(context.Background(), followingID)
+	_ = s.userRepo.UpdateFollowerCount(context.Background(), followingID, int(followerCount))
+}
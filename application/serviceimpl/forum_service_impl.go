@@ -13,13 +13,23 @@ import (
 	"github.com/google/uuid"
 )
 
+// ความลึกสูงสุดของ forum (forum หลัก = 1)
+const maxForumDepth = 5
+
+// viewerBatchSize caps how many users FilterViewers loads per query
+const viewerBatchSize = 1000
+
 type ForumServiceImpl struct {
 	forumRepo repositories.ForumRepository
+	userRepo  repositories.UserRepository
+	readRepo  repositories.ReadRepository
 }
 
-func NewForumService(forumRepo repositories.ForumRepository) services.ForumService {
+func NewForumService(forumRepo repositories.ForumRepository, userRepo repositories.UserRepository, readRepo repositories.ReadRepository) services.ForumService {
 	return &ForumServiceImpl{
 		forumRepo: forumRepo,
+		userRepo:  userRepo,
+		readRepo:  readRepo,
 	}
 }
 
@@ -30,6 +40,39 @@ func (s *ForumServiceImpl) CreateForum(ctx context.Context, adminID uuid.UUID, r
 		return nil, errors.New("forum slug already exists")
 	}
 
+	var parentID *uuid.UUID
+	if req.ParentID != nil {
+		parsedID, err := uuid.Parse(*req.ParentID)
+		if err != nil {
+			return nil, errors.New("invalid parent forum ID format")
+		}
+		_, byID, err := s.loadForums(ctx)
+		if err != nil {
+			return nil, err
+		}
+		parent, ok := byID[parsedID]
+		if !ok {
+			return nil, errors.New("parent forum not found")
+		}
+		if len(forumChain(byID, parent)) >= maxForumDepth {
+			return nil, errors.New("forums cannot be nested that deep")
+		}
+		parentID = &parsedID
+	}
+
+	viewRule, err := toForumAccessRule(req.ViewRule)
+	if err != nil {
+		return nil, err
+	}
+	postRule, err := toForumAccessRule(req.PostRule)
+	if err != nil {
+		return nil, err
+	}
+	replyRule, err := toForumAccessRule(req.ReplyRule)
+	if err != nil {
+		return nil, err
+	}
+
 	forum := &models.Forum{
 		ID:          uuid.New(),
 		Name:        req.Name,
@@ -38,7 +81,12 @@ func (s *ForumServiceImpl) CreateForum(ctx context.Context, adminID uuid.UUID, r
 		Icon:        req.Icon,
 		Order:       req.Order,
 		IsActive:    true,
+		IsQA:        req.IsQA,
 		TopicCount:  0,
+		ParentID:    parentID,
+		ViewRule:    viewRule,
+		PostRule:    postRule,
+		ReplyRule:   replyRule,
 		CreatedBy:   adminID,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
@@ -73,6 +121,31 @@ func (s *ForumServiceImpl) UpdateForum(ctx context.Context, forumID uuid.UUID, r
 	if req.IsActive != nil {
 		forum.IsActive = *req.IsActive
 	}
+	if req.IsQA != nil {
+		forum.IsQA = *req.IsQA
+	}
+	if req.ParentID != nil {
+		parentID, err := s.resolveNewParent(ctx, forumID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		forum.ParentID = parentID
+	}
+	if req.ViewRule != nil {
+		if forum.ViewRule, err = toForumAccessRule(req.ViewRule); err != nil {
+			return nil, err
+		}
+	}
+	if req.PostRule != nil {
+		if forum.PostRule, err = toForumAccessRule(req.PostRule); err != nil {
+			return nil, err
+		}
+	}
+	if req.ReplyRule != nil {
+		if forum.ReplyRule, err = toForumAccessRule(req.ReplyRule); err != nil {
+			return nil, err
+		}
+	}
 
 	forum.UpdatedAt = time.Now()
 
@@ -94,6 +167,16 @@ func (s *ForumServiceImpl) DeleteForum(ctx context.Context, forumID uuid.UUID) e
 		return errors.New("cannot delete forum with existing topics")
 	}
 
+	forums, _, err := s.loadForums(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range forums {
+		if other.ParentID != nil && *other.ParentID == forumID {
+			return errors.New("cannot delete forum with sub-forums")
+		}
+	}
+
 	return s.forumRepo.Delete(ctx, forumID)
 }
 
@@ -170,30 +253,490 @@ func (s *ForumServiceImpl) SyncAllTopicCounts(ctx context.Context) error {
 	return nil
 }
 
-func (s *ForumServiceImpl) GetActiveForums(ctx context.Context) ([]*dto.ForumResponse, error) {
+func (s *ForumServiceImpl) GetActiveForums(ctx context.Context, viewerID *uuid.UUID) ([]*dto.ForumResponse, error) {
 	// Sync all topic counts first to ensure accurate data
 	if err := s.SyncAllTopicCounts(ctx); err != nil {
 		// Log error but continue - don't fail the entire request
 		// In production, you might want proper logging here
 	}
 
-	return s.GetAllForums(ctx, false)
+	forums, byID, err := s.loadForums(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	viewer, err := s.loadViewer(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flat list ordered like the admin list; clients build the tree from parentId
+	responses := make([]*dto.ForumResponse, 0, len(forums))
+	for _, forum := range forums {
+		if !forum.IsActive || viewer.check(byID, forum, models.ForumActionView) != nil {
+			continue
+		}
+		responses = append(responses, dto.ForumToForumResponse(forum))
+	}
+
+	s.fillUnreadCounts(ctx, viewer, responses)
+
+	return responses, nil
 }
 
-func (s *ForumServiceImpl) GetForumByID(ctx context.Context, forumID uuid.UUID) (*dto.ForumResponse, error) {
-	forum, err := s.forumRepo.GetByID(ctx, forumID)
+func (s *ForumServiceImpl) GetForumByID(ctx context.Context, forumID uuid.UUID, viewerID *uuid.UUID) (*dto.ForumResponse, error) {
+	return s.forumDetail(ctx, forumID, viewerID)
+}
+
+func (s *ForumServiceImpl) GetForumBySlug(ctx context.Context, slug string, viewerID *uuid.UUID) (*dto.ForumResponse, error) {
+	forum, err := s.forumRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, errors.New("forum not found")
 	}
 
-	return dto.ForumToForumResponse(forum), nil
+	return s.forumDetail(ctx, forum.ID, viewerID)
 }
 
-func (s *ForumServiceImpl) GetForumBySlug(ctx context.Context, slug string) (*dto.ForumResponse, error) {
-	forum, err := s.forumRepo.GetBySlug(ctx, slug)
+func (s *ForumServiceImpl) GetModerators(ctx context.Context, forumID uuid.UUID) ([]dto.ForumModeratorResponse, error) {
+	moderators, err := s.forumRepo.GetModerators(ctx, forumID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ForumModeratorResponse, len(moderators))
+	for i, moderator := range moderators {
+		responses[i] = dto.ForumModeratorResponse{
+			ForumID: moderator.ForumID,
+			User: dto.UserSummary{
+				ID:        moderator.User.ID,
+				Username:  moderator.User.Username,
+				FirstName: moderator.User.FirstName,
+				LastName:  moderator.User.LastName,
+				Avatar:    moderator.User.Avatar,
+			},
+			AssignedBy: moderator.AssignedBy,
+			CreatedAt:  moderator.CreatedAt,
+		}
+	}
+
+	return responses, nil
+}
+
+func (s *ForumServiceImpl) AddModerator(ctx context.Context, adminID, forumID, userID uuid.UUID) error {
+	if _, err := s.forumRepo.GetByID(ctx, forumID); err != nil {
+		return errors.New("forum not found")
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	return s.forumRepo.AddModerator(ctx, &models.ForumModerator{
+		ForumID:    forumID,
+		UserID:     userID,
+		AssignedBy: adminID,
+	})
+}
+
+func (s *ForumServiceImpl) RemoveModerator(ctx context.Context, forumID, userID uuid.UUID) error {
+	return s.forumRepo.RemoveModerator(ctx, forumID, userID)
+}
+
+// Permissions
+func (s *ForumServiceImpl) CheckAccess(ctx context.Context, forumID uuid.UUID, userID *uuid.UUID, action models.ForumAction) error {
+	_, byID, err := s.loadForums(ctx)
+	if err != nil {
+		return err
+	}
+
+	forum, ok := byID[forumID]
+	if !ok {
+		return errors.New("forum not found")
+	}
+
+	viewer, err := s.loadViewer(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return viewer.check(byID, forum, action)
+}
+
+func (s *ForumServiceImpl) CanModerate(ctx context.Context, forumID, userID uuid.UUID) (bool, error) {
+	_, byID, err := s.loadForums(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	forum, ok := byID[forumID]
+	if !ok {
+		return false, errors.New("forum not found")
+	}
+
+	viewer, err := s.loadViewer(ctx, &userID)
+	if err != nil {
+		return false, err
+	}
+
+	return viewer.moderates(forumChain(byID, forum)), nil
+}
+
+func (s *ForumServiceImpl) GetHiddenForumIDs(ctx context.Context, userID *uuid.UUID) ([]uuid.UUID, error) {
+	forums, byID, err := s.loadForums(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	viewer, err := s.loadViewer(ctx, userID)
 	if err != nil {
+		return nil, err
+	}
+
+	hidden := make([]uuid.UUID, 0)
+	for _, forum := range forums {
+		if viewer.check(byID, forum, models.ForumActionView) != nil {
+			hidden = append(hidden, forum.ID)
+		}
+	}
+
+	return hidden, nil
+}
+
+func (s *ForumServiceImpl) FilterViewers(ctx context.Context, forumID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	_, byID, err := s.loadForums(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	forum, ok := byID[forumID]
+	if !ok {
 		return nil, errors.New("forum not found")
 	}
 
-	return dto.ForumToForumResponse(forum), nil
+	// Moderators of the forum or its parents see it whatever the rules say
+	chain := forumChain(byID, forum)
+	moderators := make(map[uuid.UUID]bool)
+	for _, f := range chain {
+		assigned, err := s.forumRepo.GetModerators(ctx, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, moderator := range assigned {
+			moderators[moderator.UserID] = true
+		}
+	}
+
+	viewers := make([]uuid.UUID, 0, len(userIDs))
+	for start := 0; start < len(userIDs); start += viewerBatchSize {
+		end := start + viewerBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		users, err := s.userRepo.FindByIDs(ctx, userIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		byUser := make(map[uuid.UUID]*models.User, len(users))
+		for _, user := range users {
+			byUser[user.ID] = user
+		}
+
+		for _, id := range userIDs[start:end] {
+			// Deleted users are checked like anonymous visitors, as in loadViewer
+			viewer := &forumViewer{user: byUser[id], moderated: make(map[uuid.UUID]bool)}
+			if viewer.user != nil && moderators[id] {
+				viewer.moderated[forum.ID] = true
+			}
+			if viewer.check(byID, forum, models.ForumActionView) == nil {
+				viewers = append(viewers, id)
+			}
+		}
+	}
+
+	return viewers, nil
+}
+
+// Helper methods
+func (s *ForumServiceImpl) forumDetail(ctx context.Context, forumID uuid.UUID, viewerID *uuid.UUID) (*dto.ForumResponse, error) {
+	forums, byID, err := s.loadForums(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	forum, ok := byID[forumID]
+	if !ok {
+		return nil, errors.New("forum not found")
+	}
+
+	viewer, err := s.loadViewer(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := viewer.check(byID, forum, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	resp := dto.ForumToForumResponse(forum)
+
+	chain := forumChain(byID, forum)
+	resp.Breadcrumbs = make([]dto.ForumBreadcrumb, len(chain))
+	for i, crumb := range chain {
+		resp.Breadcrumbs[i] = dto.ForumBreadcrumb{ID: crumb.ID, Name: crumb.Name, Slug: crumb.Slug}
+	}
+
+	for _, child := range forums {
+		if child.ParentID == nil || *child.ParentID != forum.ID || !child.IsActive {
+			continue
+		}
+		if viewer.check(byID, child, models.ForumActionView) != nil {
+			continue
+		}
+		resp.Children = append(resp.Children, *dto.ForumToForumResponse(child))
+	}
+
+	unread := []*dto.ForumResponse{resp}
+	for i := range resp.Children {
+		unread = append(unread, &resp.Children[i])
+	}
+	s.fillUnreadCounts(ctx, viewer, unread)
+
+	resp.Permissions = &dto.ForumPermissions{
+		CanView:     true,
+		CanPost:     viewer.check(byID, forum, models.ForumActionPost) == nil,
+		CanReply:    viewer.check(byID, forum, models.ForumActionReply) == nil,
+		CanModerate: viewer.moderates(chain),
+	}
+
+	return resp, nil
+}
+
+// fillUnreadCounts sets UnreadTopicCount for a logged-in viewer; activity from before the
+// account existed never counts as new
+func (s *ForumServiceImpl) fillUnreadCounts(ctx context.Context, viewer *forumViewer, forums []*dto.ForumResponse) {
+	if viewer.user == nil || len(forums) == 0 {
+		return
+	}
+
+	forumIDs := make([]uuid.UUID, len(forums))
+	for i, forum := range forums {
+		forumIDs[i] = forum.ID
+	}
+
+	counts, err := s.readRepo.CountUnreadTopicsByForum(ctx, viewer.user.ID, viewer.user.CreatedAt, forumIDs)
+	if err != nil {
+		return
+	}
+	for _, forum := range forums {
+		forum.UnreadTopicCount = counts[forum.ID]
+	}
+}
+
+// resolveNewParent validates moving a forum under another one; "" moves it to the top level
+func (s *ForumServiceImpl) resolveNewParent(ctx context.Context, forumID uuid.UUID, rawParentID string) (*uuid.UUID, error) {
+	if rawParentID == "" {
+		return nil, nil
+	}
+
+	parentID, err := uuid.Parse(rawParentID)
+	if err != nil {
+		return nil, errors.New("invalid parent forum ID format")
+	}
+
+	forums, byID, err := s.loadForums(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, ok := byID[parentID]
+	if !ok {
+		return nil, errors.New("parent forum not found")
+	}
+
+	chain := forumChain(byID, parent)
+	for _, ancestor := range chain {
+		if ancestor.ID == forumID {
+			return nil, errors.New("a forum cannot be moved into itself or its sub-forums")
+		}
+	}
+
+	if len(chain)+subtreeHeight(forums, forumID) > maxForumDepth {
+		return nil, errors.New("forums cannot be nested that deep")
+	}
+
+	return &parentID, nil
+}
+
+// loadForums returns all forums in display order plus an index by ID; the tree is small enough to keep in memory
+func (s *ForumServiceImpl) loadForums(ctx context.Context) ([]*models.Forum, map[uuid.UUID]*models.Forum, error) {
+	forums, err := s.forumRepo.GetAll(ctx, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Forum, len(forums))
+	for _, forum := range forums {
+		byID[forum.ID] = forum
+	}
+
+	return forums, byID, nil
+}
+
+func (s *ForumServiceImpl) loadViewer(ctx context.Context, userID *uuid.UUID) (*forumViewer, error) {
+	viewer := &forumViewer{moderated: make(map[uuid.UUID]bool)}
+	if userID == nil {
+		return viewer, nil
+	}
+
+	// Tokens of deleted users are treated like anonymous visitors
+	user, err := s.userRepo.GetByID(ctx, *userID)
+	if err != nil {
+		return viewer, nil
+	}
+	viewer.user = user
+
+	forumIDs, err := s.forumRepo.GetModeratedForumIDs(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range forumIDs {
+		viewer.moderated[id] = true
+	}
+
+	return viewer, nil
+}
+
+// forumViewer is what the permission checks need to know about the current user
+type forumViewer struct {
+	user      *models.User // nil = not logged in
+	moderated map[uuid.UUID]bool
+}
+
+func (v *forumViewer) isAdmin() bool {
+	return v.user != nil && v.user.Role == "admin"
+}
+
+// moderates is true for admins and for moderators of the forum or any of its parents
+func (v *forumViewer) moderates(chain []*models.Forum) bool {
+	if v.isAdmin() {
+		return true
+	}
+	for _, forum := range chain {
+		if v.moderated[forum.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *forumViewer) check(byID map[uuid.UUID]*models.Forum, forum *models.Forum, action models.ForumAction) error {
+	chain := forumChain(byID, forum)
+	if v.moderates(chain) {
+		return nil
+	}
+
+	// Every forum on the way down must be open and viewable
+	for _, f := range chain {
+		if !f.IsActive {
+			return errors.New("forum not found")
+		}
+		if !v.allows(f.ViewRule) {
+			return errors.New("you don't have permission to view this forum")
+		}
+	}
+
+	switch action {
+	case models.ForumActionPost:
+		if v.user == nil || !v.allows(forum.PostRule) {
+			return errors.New("you don't have permission to post topics in this forum")
+		}
+	case models.ForumActionReply:
+		if v.user == nil || !v.allows(forum.ReplyRule) {
+			return errors.New("you don't have permission to reply in this forum")
+		}
+	}
+
+	return nil
+}
+
+func (v *forumViewer) allows(rule models.ForumAccessRule) bool {
+	switch rule.Audience {
+	case models.ForumAudienceVerified:
+		if v.user == nil || !v.user.IsVerified {
+			return false
+		}
+	case models.ForumAudienceRole:
+		if v.user == nil || v.user.Role != rule.Role {
+			return false
+		}
+	}
+
+	if rule.MinAccountAgeDays > 0 {
+		if v.user == nil || time.Since(v.user.CreatedAt) < time.Duration(rule.MinAccountAgeDays)*24*time.Hour {
+			return false
+		}
+	}
+
+	return true
+}
+
+// forumChain returns the forum and its parents from the top level down
+func forumChain(byID map[uuid.UUID]*models.Forum, forum *models.Forum) []*models.Forum {
+	chain := []*models.Forum{forum}
+	current := forum
+	for current.ParentID != nil && len(chain) < maxForumDepth*2 {
+		parent, ok := byID[*current.ParentID]
+		if !ok {
+			break
+		}
+		chain = append([]*models.Forum{parent}, chain...)
+		current = parent
+	}
+	return chain
+}
+
+// subtreeHeight counts the levels of a forum and its sub-forums (a forum without children = 1)
+func subtreeHeight(forums []*models.Forum, forumID uuid.UUID) int {
+	height := 0
+	for _, forum := range forums {
+		if forum.ParentID != nil && *forum.ParentID == forumID {
+			if h := subtreeHeight(forums, forum.ID); h > height {
+				height = h
+			}
+		}
+	}
+	return height + 1
+}
+
+func toForumAccessRule(req *dto.ForumAccessRuleRequest) (models.ForumAccessRule, error) {
+	if req == nil {
+		return models.ForumAccessRule{Audience: models.ForumAudienceEveryone}, nil
+	}
+
+	rule := models.ForumAccessRule{
+		Audience:          models.ForumAudience(req.Audience),
+		MinAccountAgeDays: req.MinAccountAgeDays,
+	}
+	if rule.Audience == models.ForumAudienceRole {
+		if req.Role == "" {
+			return rule, errors.New("role is required when audience is role")
+		}
+		rule.Role = req.Role
+	}
+
+	return rule, nil
+}
+
+func (s *ForumServiceImpl) SyncTopicCounts(ctx context.Context) (int, error) {
+	forums, err := s.forumRepo.GetAll(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, forum := range forums {
+		if err := s.forumRepo.SyncTopicCount(ctx, forum.ID); err != nil {
+			return i, err
+		}
+	}
+	return len(forums), nil
 }
@@ -0,0 +1,101 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/services"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RegisterJobHandlers registers the built-in job types with the job service. Each handler
+// reads its options from the job payload and falls back to the defaults documented below.
+func RegisterJobHandlers(
+	jobService services.JobService,
+	forumService services.ForumService,
+	likeService services.LikeService,
+	notificationService services.NotificationService,
+) {
+	// {} - recount the topics of every forum
+	jobService.RegisterHandler(models.JobTypeSyncForumCounts, func(ctx context.Context, payload json.RawMessage) (string, error) {
+		synced, err := forumService.SyncTopicCounts(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("synced topic counts of %d forums", synced), nil
+	})
+
+	// {"targets": ["topic", "reply", "video"]} - all targets when omitted
+	jobService.RegisterHandler(models.JobTypeRecomputeLikeCounts, func(ctx context.Context, payload json.RawMessage) (string, error) {
+		var opts struct {
+			Targets []string `json:"targets"`
+		}
+		if err := json.Unmarshal(payload, &opts); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+		if len(opts.Targets) == 0 {
+			opts.Targets = []string{"topic", "reply", "video"}
+		}
+
+		corrected, err := likeService.RecomputeLikeCounts(ctx, opts.Targets)
+		if err != nil {
+			return "", err
+		}
+
+		targets := make([]string, 0, len(corrected))
+		for target := range corrected {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		parts := make([]string, len(targets))
+		for i, target := range targets {
+			parts[i] = fmt.Sprintf("%s=%d", target, corrected[target])
+		}
+		return "corrected like counts: " + strings.Join(parts, ", "), nil
+	})
+
+	// {"olderThanDays": 90, "includeUnread": false}
+	jobService.RegisterHandler(models.JobTypePurgeNotifications, func(ctx context.Context, payload json.RawMessage) (string, error) {
+		opts := struct {
+			OlderThanDays int  `json:"olderThanDays"`
+			IncludeUnread bool `json:"includeUnread"`
+		}{OlderThanDays: 90}
+		if err := json.Unmarshal(payload, &opts); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+		if opts.OlderThanDays <= 0 {
+			return "", fmt.Errorf("invalid payload: olderThanDays must be positive")
+		}
+
+		before := time.Now().AddDate(0, 0, -opts.OlderThanDays)
+		deleted, err := notificationService.PurgeNotifications(ctx, before, !opts.IncludeUnread)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("deleted %d notifications older than %d days", deleted, opts.OlderThanDays), nil
+	})
+
+	// {"sinceHours": 24, "minUnread": 1}
+	jobService.RegisterHandler(models.JobTypeSendDigests, func(ctx context.Context, payload json.RawMessage) (string, error) {
+		opts := struct {
+			SinceHours int `json:"sinceHours"`
+			MinUnread  int `json:"minUnread"`
+		}{SinceHours: 24, MinUnread: 1}
+		if err := json.Unmarshal(payload, &opts); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+		if opts.SinceHours <= 0 || opts.MinUnread <= 0 {
+			return "", fmt.Errorf("invalid payload: sinceHours and minUnread must be positive")
+		}
+
+		since := time.Now().Add(-time.Duration(opts.SinceHours) * time.Hour)
+		sent, err := notificationService.SendDigests(ctx, since, opts.MinUnread)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("sent %d digests", sent), nil
+	})
+}
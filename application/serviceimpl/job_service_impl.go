@@ -2,27 +2,58 @@ package serviceimpl
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/redis"
 	"gofiber-social/pkg/scheduler"
+	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	jobFiringLockPrefix = "jobs:lock:" // + job ID + ":" + minute of the firing
+	systemJobPrefix     = "system:"    // scheduler IDs of jobs that are not in the jobs table
+
+	defaultCronPreviewCount = 5
+)
+
 type JobServiceImpl struct {
-	jobRepo   repositories.JobRepository
-	scheduler scheduler.EventScheduler
+	jobRepo     repositories.JobRepository
+	scheduler   scheduler.EventScheduler
+	redisClient *redis.RedisClient
+	leader      *redis.LeaderElector
+	lockTTL     time.Duration
+
+	handlers   map[string]services.JobHandler
+	handlersMu sync.RWMutex
 }
 
-func NewJobService(jobRepo repositories.JobRepository, scheduler scheduler.EventScheduler) services.JobService {
+// NewJobService schedules jobs on every replica, but a firing only runs on the leader and only
+// once: the leader also takes a per-firing lock so a leadership hand-over cannot repeat a run.
+func NewJobService(
+	jobRepo repositories.JobRepository,
+	scheduler scheduler.EventScheduler,
+	redisClient *redis.RedisClient,
+	leader *redis.LeaderElector,
+	lockTTL time.Duration,
+) services.JobService {
 	return &JobServiceImpl{
-		jobRepo:   jobRepo,
-		scheduler: scheduler,
+		jobRepo:     jobRepo,
+		scheduler:   scheduler,
+		redisClient: redisClient,
+		leader:      leader,
+		lockTTL:     lockTTL,
+		handlers:    make(map[string]services.JobHandler),
 	}
 }
 
@@ -31,26 +62,43 @@ func (s *JobServiceImpl) CreateJob(ctx context.Context, req *dto.CreateJobReques
 		return nil, fmt.Errorf("invalid cron expression: %v", err)
 	}
 
+	if _, ok := s.handler(req.Type); !ok {
+		return nil, fmt.Errorf("unknown job type %q", req.Type)
+	}
+
+	location, err := s.location(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
 	existingJob, _ := s.jobRepo.GetByName(ctx, req.Name)
 	if existingJob != nil {
 		return nil, errors.New("job with this name already exists")
 	}
 
-	nextRun, err := scheduler.GetNextRunTime(req.CronExpr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate next run time: %v", err)
+	maxConcurrency := req.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
 	}
 
 	job := &models.Job{
-		ID:        uuid.New(),
-		Name:      req.Name,
-		CronExpr:  req.CronExpr,
-		Payload:   req.Payload,
-		Status:    "active",
-		NextRun:   nextRun,
-		IsActive:  true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:             uuid.New(),
+		Name:           req.Name,
+		Type:           req.Type,
+		CronExpr:       req.CronExpr,
+		Timezone:       location.String(),
+		Payload:        req.Payload,
+		Status:         "active",
+		TimeoutSeconds: req.TimeoutSeconds,
+		MaxConcurrency: maxConcurrency,
+		IsActive:       true,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	job.NextRun, err = s.nextRun(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate next run time: %v", err)
 	}
 
 	err = s.jobRepo.Create(ctx, job)
@@ -58,9 +106,7 @@ func (s *JobServiceImpl) CreateJob(ctx context.Context, req *dto.CreateJobReques
 		return nil, err
 	}
 
-	err = s.scheduler.AddJob(job.ID.String(), req.CronExpr, func() {
-		s.ExecuteJob(context.Background(), job)
-	})
+	err = s.schedule(job)
 	if err != nil {
 		s.jobRepo.Delete(ctx, job.ID)
 		return nil, fmt.Errorf("failed to schedule job: %v", err)
@@ -88,6 +134,12 @@ func (s *JobServiceImpl) UpdateJob(ctx context.Context, jobID uuid.UUID, req *dt
 	if req.Name != "" {
 		job.Name = req.Name
 	}
+	if req.Type != "" {
+		if _, ok := s.handler(req.Type); !ok {
+			return nil, fmt.Errorf("unknown job type %q", req.Type)
+		}
+		job.Type = req.Type
+	}
 	if req.CronExpr != "" {
 		if err := scheduler.ValidateCronExpression(req.CronExpr); err != nil {
 			return nil, fmt.Errorf("invalid cron expression: %v", err)
@@ -95,26 +147,39 @@ func (s *JobServiceImpl) UpdateJob(ctx context.Context, jobID uuid.UUID, req *dt
 		job.CronExpr = req.CronExpr
 		needsReschedule = true
 	}
+	if req.Timezone != "" {
+		location, err := s.location(req.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		job.Timezone = location.String()
+		needsReschedule = true
+	}
 	if req.Payload != "" {
 		job.Payload = req.Payload
 	}
-	if req.IsActive != job.IsActive {
-		job.IsActive = req.IsActive
+	if req.TimeoutSeconds != nil && *req.TimeoutSeconds != job.TimeoutSeconds {
+		job.TimeoutSeconds = *req.TimeoutSeconds
+		needsReschedule = true
+	}
+	if req.MaxConcurrency != nil && *req.MaxConcurrency != job.MaxConcurrency {
+		job.MaxConcurrency = *req.MaxConcurrency
+		needsReschedule = true
+	}
+	if req.IsActive != nil && *req.IsActive != job.IsActive {
+		job.IsActive = *req.IsActive
 		needsReschedule = true
 	}
 
 	if needsReschedule {
 		s.scheduler.RemoveJob(jobID.String())
 		if job.IsActive {
-			nextRun, err := scheduler.GetNextRunTime(job.CronExpr)
+			job.NextRun, err = s.nextRun(job)
 			if err != nil {
 				return nil, fmt.Errorf("failed to calculate next run time: %v", err)
 			}
-			job.NextRun = nextRun
 
-			err = s.scheduler.AddJob(jobID.String(), job.CronExpr, func() {
-				s.ExecuteJob(context.Background(), job)
-			})
+			err = s.schedule(job)
 			if err != nil {
 				return nil, fmt.Errorf("failed to reschedule job: %v", err)
 			}
@@ -169,15 +234,12 @@ func (s *JobServiceImpl) StartJob(ctx context.Context, jobID uuid.UUID) error {
 	job.IsActive = true
 	job.UpdatedAt = time.Now()
 
-	nextRun, err := scheduler.GetNextRunTime(job.CronExpr)
+	job.NextRun, err = s.nextRun(job)
 	if err != nil {
 		return fmt.Errorf("failed to calculate next run time: %v", err)
 	}
-	job.NextRun = nextRun
 
-	err = s.scheduler.AddJob(jobID.String(), job.CronExpr, func() {
-		s.ExecuteJob(context.Background(), job)
-	})
+	err = s.schedule(job)
 	if err != nil {
 		return fmt.Errorf("failed to start job: %v", err)
 	}
@@ -195,31 +257,392 @@ func (s *JobServiceImpl) StopJob(ctx context.Context, jobID uuid.UUID) error {
 		return errors.New("job is already inactive")
 	}
 
-	job.IsActive = false
-	job.UpdatedAt = time.Now()
-
 	s.scheduler.RemoveJob(jobID.String())
 
-	return s.jobRepo.Update(ctx, jobID, job)
+	return s.jobRepo.SetActive(ctx, jobID, false)
 }
 
+// ExecuteJob runs the job's handler until ctx ends; the run is still recorded after a timeout
 func (s *JobServiceImpl) ExecuteJob(ctx context.Context, job *models.Job) error {
-	now := time.Now()
+	trigger := models.JobTriggerSchedule
+	if scheduler.IsManualRun(ctx) {
+		trigger = models.JobTriggerManual
+	}
+	runCtx := ctx
+	ctx = context.WithoutCancel(ctx)
+
+	// Scheduled closures hold the job as it was when scheduled; type and payload may have changed
+	if current, err := s.jobRepo.GetByID(ctx, job.ID); err == nil {
+		job = current
+	}
 
-	fmt.Printf("Executing job: %s at %s\n", job.Name, now.Format(time.RFC3339))
+	startedAt := time.Now()
+	run := &models.JobRun{
+		JobID:     job.ID,
+		JobType:   job.Type,
+		Status:    models.JobStatusRunning,
+		StartedAt: startedAt,
+		NodeID:    s.leader.NodeID(),
+		Trigger:   trigger,
+	}
+	if err := s.jobRepo.CreateRun(ctx, run); err != nil {
+		log.Printf("Warning: Failed to record run of job %s: %v", job.Name, err)
+	}
+	_ = s.jobRepo.MarkRunning(ctx, job.ID, startedAt)
+
+	result, runErr := s.runHandler(runCtx, job)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(startedAt).Milliseconds()
+	run.Result = result
+	run.Status = models.JobStatusCompleted
+	if runErr != nil {
+		run.Status = models.JobStatusFailed
+		run.Error = runErr.Error()
+		log.Printf("Job %s (%s) failed after %dms: %v", job.Name, job.Type, run.DurationMs, runErr)
+	} else {
+		log.Printf("Job %s (%s) completed in %dms: %s", job.Name, job.Type, run.DurationMs, result)
+	}
 
-	job.LastRun = &now
-	job.Status = "running"
+	if run.ID != uuid.Nil {
+		if err := s.jobRepo.FinishRun(ctx, run); err != nil {
+			log.Printf("Warning: Failed to record run of job %s: %v", job.Name, err)
+		}
+	}
 
-	nextRun, err := scheduler.GetNextRunTime(job.CronExpr)
-	if err == nil {
-		job.NextRun = nextRun
+	nextRun, err := s.nextRun(job)
+	if err != nil {
+		nextRun = job.NextRun
+	}
+	if err := s.jobRepo.UpdateRunStatus(ctx, job.ID, run.Status, run.Error, run.DurationMs, nextRun); err != nil {
+		return err
 	}
 
-	s.jobRepo.Update(ctx, job.ID, job)
+	return runErr
+}
 
-	job.Status = "completed"
-	job.UpdatedAt = time.Now()
+// schedule adds the job to this replica's scheduler. The closure only keeps the ID: the job is
+// read again at each firing so changes made through another replica are picked up.
+func (s *JobServiceImpl) schedule(job *models.Job) error {
+	options, err := s.jobOptions(job)
+	if err != nil {
+		return err
+	}
+
+	jobID := job.ID
+	err = s.scheduler.AddJobWithOptions(jobID.String(), job.CronExpr, options, func(ctx context.Context) {
+		s.runScheduled(ctx, jobID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if job.IsPaused {
+		return s.scheduler.SetPaused(jobID.String(), true)
+	}
+	return nil
+}
+
+// jobOptions are the scheduler settings of a job
+func (s *JobServiceImpl) jobOptions(job *models.Job) (scheduler.JobOptions, error) {
+	location, err := s.location(job.Timezone)
+	if err != nil {
+		return scheduler.JobOptions{}, err
+	}
+
+	maxConcurrency := job.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
+
+	return scheduler.JobOptions{
+		Location:       location,
+		Timeout:        time.Duration(job.TimeoutSeconds) * time.Second,
+		MaxConcurrency: maxConcurrency,
+	}, nil
+}
+
+// location resolves an IANA timezone name; jobs without one use the scheduler's timezone
+func (s *JobServiceImpl) location(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return s.scheduler.Location(), nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", timezone)
+	}
+	return location, nil
+}
+
+// nextRun is the job's next firing in its timezone
+func (s *JobServiceImpl) nextRun(job *models.Job) (*time.Time, error) {
+	location, err := s.location(job.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	nextRuns, err := scheduler.NextRunTimes(job.CronExpr, location, time.Now(), 1)
+	if err != nil {
+		return nil, err
+	}
+	return &nextRuns[0], nil
+}
+
+// runScheduled executes one firing of a job if this replica is the leader and wins the firing's
+// lock. Manual runs skip those checks: they run where they were requested.
+func (s *JobServiceImpl) runScheduled(ctx context.Context, jobID uuid.UUID) {
+	if scheduler.IsManualRun(ctx) {
+		job, err := s.jobRepo.GetByID(ctx, jobID)
+		if err != nil {
+			log.Printf("Warning: Skipped manual run of job %s: %v", jobID, err)
+			return
+		}
+		s.ExecuteJob(ctx, job)
+		return
+	}
+
+	if !s.leader.IsLeader() {
+		return
+	}
+
+	// Replicas fire within the same minute; cron has no finer resolution. A standalone node
+	// has no Redis for the lock and is the only one running jobs anyway.
+	if !s.leader.Standalone() {
+		firing := time.Now().Truncate(time.Minute)
+		lockKey := fmt.Sprintf("%s%s:%d", jobFiringLockPrefix, jobID, firing.Unix())
+		locked, err := s.redisClient.SetNX(ctx, lockKey, s.leader.NodeID(), s.lockTTL)
+		if err != nil {
+			log.Printf("Warning: Skipped job %s, firing lock unavailable: %v", jobID, err)
+			return
+		}
+		if !locked {
+			return
+		}
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil || !job.IsActive || job.IsPaused {
+		// Deleted, stopped or paused through another replica that hasn't been synced yet
+		return
+	}
+
+	s.ExecuteJob(ctx, job)
+}
+
+func (s *JobServiceImpl) TriggerJob(ctx context.Context, jobID uuid.UUID) error {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return errors.New("job not found")
+	}
+
+	if !job.IsActive {
+		return errors.New("job is stopped, start it first")
+	}
+
+	// Created or started through another replica that hasn't been synced yet
+	if _, ok := s.scheduler.GetJob(jobID.String()); !ok {
+		if err := s.schedule(job); err != nil {
+			return fmt.Errorf("failed to schedule job: %v", err)
+		}
+	}
+
+	return s.scheduler.RunNow(jobID.String())
+}
+
+func (s *JobServiceImpl) PauseJob(ctx context.Context, jobID uuid.UUID) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, errors.New("job not found")
+	}
+
+	if job.IsPaused {
+		return nil, errors.New("job is already paused")
+	}
+
+	pausedAt := time.Now()
+	if err := s.jobRepo.SetPaused(ctx, jobID, true, &pausedAt); err != nil {
+		return nil, err
+	}
+	// Other replicas see the pause when the job fires or at the next sync
+	s.scheduler.SetPaused(jobID.String(), true)
+
+	job.IsPaused = true
+	job.PausedAt = &pausedAt
+	return job, nil
+}
+
+func (s *JobServiceImpl) ResumeJob(ctx context.Context, jobID uuid.UUID) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, errors.New("job not found")
+	}
+
+	if !job.IsPaused {
+		return nil, errors.New("job is not paused")
+	}
+
+	if err := s.jobRepo.SetPaused(ctx, jobID, false, nil); err != nil {
+		return nil, err
+	}
+	s.scheduler.SetPaused(jobID.String(), false)
+
+	job.IsPaused = false
+	job.PausedAt = nil
+	return job, nil
+}
+
+func (s *JobServiceImpl) PreviewCron(ctx context.Context, req *dto.CronPreviewRequest) (*dto.CronPreviewResponse, error) {
+	location, err := s.location(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = defaultCronPreviewCount
+	}
+
+	nextRuns, err := scheduler.NextRunTimes(req.CronExpr, location, time.Now(), count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CronPreviewResponse{
+		CronExpr: req.CronExpr,
+		Timezone: location.String(),
+		NextRuns: nextRuns,
+	}, nil
+}
+
+func (s *JobServiceImpl) SyncScheduledJobs(ctx context.Context) (added, removed int, err error) {
+	jobs, err := s.jobRepo.GetActiveJobs(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	active := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		id := job.ID.String()
+		active[id] = true
+
+		if info, ok := s.scheduler.GetJob(id); ok {
+			options, err := s.jobOptions(job)
+			if err == nil && info.CronExpr == job.CronExpr && info.Location.String() == options.Location.String() &&
+				info.Timeout == options.Timeout && info.MaxConcurrency == options.MaxConcurrency {
+				s.scheduler.SetPaused(id, job.IsPaused)
+				continue
+			}
+			s.scheduler.RemoveJob(id)
+		}
+		if err := s.schedule(job); err != nil {
+			log.Printf("Warning: Failed to schedule job %s: %v", job.Name, err)
+			continue
+		}
+		added++
+	}
+
+	for id := range s.scheduler.ListJobs() {
+		if strings.HasPrefix(id, systemJobPrefix) || active[id] {
+			continue
+		}
+		if err := s.scheduler.RemoveJob(id); err == nil {
+			removed++
+		}
+	}
+
+	return added, removed, nil
+}
+
+func (s *JobServiceImpl) GetSchedulerStatus(ctx context.Context) *dto.SchedulerStatusResponse {
+	status := &dto.SchedulerStatusResponse{
+		NodeID:     s.leader.NodeID(),
+		IsLeader:   s.leader.IsLeader(),
+		Standalone: s.leader.Standalone(),
+		Running:    s.scheduler.IsRunning(),
+	}
+
+	if leaderID, err := s.leader.Leader(ctx); err == nil {
+		status.LeaderID = leaderID
+	}
+
+	for id := range s.scheduler.ListJobs() {
+		if !strings.HasPrefix(id, systemJobPrefix) {
+			status.ScheduledJobs++
+		}
+	}
+
+	return status
+}
+
+// runHandler calls the handler of the job's type; a panic fails the run instead of the process.
+// When ctx ends first the run fails as timed out. A handler that ignores ctx keeps going in the
+// background and keeps its concurrency slot until it returns.
+func (s *JobServiceImpl) runHandler(ctx context.Context, job *models.Job) (string, error) {
+	handler, ok := s.handler(job.Type)
+	if !ok {
+		return "", fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+
+	payload := json.RawMessage(job.Payload)
+	if strings.TrimSpace(job.Payload) == "" {
+		payload = json.RawMessage("{}")
+	}
+
+	type outcome struct {
+		result string
+		err    error
+	}
+	done := make(chan outcome, 1)
+	release := scheduler.HoldRunSlot(ctx)
+	go func() {
+		defer release()
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("job panicked: %v", r)}
+			}
+		}()
+		result, err := handler(ctx, payload)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("job timed out after %ds", job.TimeoutSeconds)
+	}
+}
+
+func (s *JobServiceImpl) RegisterHandler(jobType string, handler services.JobHandler) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	s.handlers[jobType] = handler
+}
+
+func (s *JobServiceImpl) GetJobTypes() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	types := make([]string, 0, len(s.handlers))
+	for jobType := range s.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (s *JobServiceImpl) GetJobRuns(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, int64, error) {
+	if _, err := s.jobRepo.GetByID(ctx, jobID); err != nil {
+		return nil, 0, errors.New("job not found")
+	}
+	return s.jobRepo.ListRuns(ctx, jobID, offset, limit)
+}
 
-	return s.jobRepo.Update(ctx, job.ID, job)
+func (s *JobServiceImpl) handler(jobType string) (services.JobHandler, bool) {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+	handler, ok := s.handlers[jobType]
+	return handler, ok
 }
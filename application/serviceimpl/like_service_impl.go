@@ -3,20 +3,24 @@ package serviceimpl
 import (
 	"context"
 	"errors"
+	"fmt"
 	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/websocket"
 
 	"github.com/google/uuid"
 )
 
 type likeServiceImpl struct {
-	likeRepo            repositories.LikeRepository
-	topicRepo           repositories.TopicRepository
-	videoRepo           repositories.VideoRepository
-	replyRepo           repositories.ReplyRepository
-	commentRepo         repositories.CommentRepository
-	notificationService services.NotificationService
+	likeRepo     repositories.LikeRepository
+	topicRepo    repositories.TopicRepository
+	videoRepo    repositories.VideoRepository
+	replyRepo    repositories.ReplyRepository
+	commentRepo  repositories.CommentRepository
+	bookmarkRepo repositories.BookmarkRepository
+	queueService services.QueueService
 }
 
 func NewLikeService(
@@ -25,15 +29,17 @@ func NewLikeService(
 	videoRepo repositories.VideoRepository,
 	replyRepo repositories.ReplyRepository,
 	commentRepo repositories.CommentRepository,
-	notificationService services.NotificationService,
+	bookmarkRepo repositories.BookmarkRepository,
+	queueService services.QueueService,
 ) services.LikeService {
 	return &likeServiceImpl{
-		likeRepo:            likeRepo,
-		topicRepo:           topicRepo,
-		videoRepo:           videoRepo,
-		replyRepo:           replyRepo,
-		commentRepo:         commentRepo,
-		notificationService: notificationService,
+		likeRepo:     likeRepo,
+		topicRepo:    topicRepo,
+		videoRepo:    videoRepo,
+		replyRepo:    replyRepo,
+		commentRepo:  commentRepo,
+		bookmarkRepo: bookmarkRepo,
+		queueService: queueService,
 	}
 }
 
@@ -55,24 +61,17 @@ func (s *likeServiceImpl) LikeTopic(ctx context.Context, userID uuid.UUID, topic
 		return nil, errors.New("topic already liked")
 	}
 
-	// Create like
-	_, err = s.likeRepo.LikeTopic(ctx, userID, topicID)
+	// Create like, queueing the owner's notification and the like count update of the topic table
+	tasks, err := newTasks(s.queueService,
+		queuedTask{dto.NotifyLikeTask{TargetType: dto.LikeTargetTopic, TargetID: topicID, ActorID: userID}, likeNotificationKey(dto.LikeTargetTopic, topicID, userID)},
+		queuedTask{dto.SyncLikeCountTask{TargetType: dto.LikeTargetTopic, TargetID: topicID}, ""},
+	)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create notification for topic like
-	go func() {
-		_ = s.notificationService.CreateTopicLikeNotification(context.Background(), topicID, userID)
-	}()
-
-	// Update like count in topic table asynchronously
-	go func() {
-		count, err := s.likeRepo.CountTopicLikes(context.Background(), topicID)
-		if err == nil {
-			_ = s.topicRepo.UpdateLikeCount(context.Background(), topicID, int(count))
-		}
-	}()
+	if _, err := s.likeRepo.LikeTopic(ctx, userID, topicID, tasks...); err != nil {
+		return nil, err
+	}
 
 	// Get updated like count
 	likeCount, err := s.likeRepo.CountTopicLikes(ctx, topicID)
@@ -80,6 +79,8 @@ func (s *likeServiceImpl) LikeTopic(ctx context.Context, userID uuid.UUID, topic
 		return nil, err
 	}
 
+	broadcastLikeCount(websocket.TopicRoom(topicID), dto.LikeTargetTopic, topicID, likeCount)
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   true,
 		LikeCount: likeCount,
@@ -97,19 +98,14 @@ func (s *likeServiceImpl) UnlikeTopic(ctx context.Context, userID uuid.UUID, top
 		return nil, errors.New("topic not liked")
 	}
 
-	// Remove like
-	err = s.likeRepo.UnlikeTopic(ctx, userID, topicID)
+	// Remove like, queueing the like count update of the topic table
+	tasks, err := newTasks(s.queueService, queuedTask{dto.SyncLikeCountTask{TargetType: dto.LikeTargetTopic, TargetID: topicID}, ""})
 	if err != nil {
 		return nil, err
 	}
-
-	// Update like count in topic table asynchronously
-	go func() {
-		count, err := s.likeRepo.CountTopicLikes(context.Background(), topicID)
-		if err == nil {
-			_ = s.topicRepo.UpdateLikeCount(context.Background(), topicID, int(count))
-		}
-	}()
+	if err := s.likeRepo.UnlikeTopic(ctx, userID, topicID, tasks...); err != nil {
+		return nil, err
+	}
 
 	// Get updated like count
 	likeCount, err := s.likeRepo.CountTopicLikes(ctx, topicID)
@@ -117,6 +113,8 @@ func (s *likeServiceImpl) UnlikeTopic(ctx context.Context, userID uuid.UUID, top
 		return nil, err
 	}
 
+	broadcastLikeCount(websocket.TopicRoom(topicID), dto.LikeTargetTopic, topicID, likeCount)
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   false,
 		LikeCount: likeCount,
@@ -136,9 +134,15 @@ func (s *likeServiceImpl) GetTopicLikeStatus(ctx context.Context, userID uuid.UU
 		return nil, err
 	}
 
+	isBookmarked, err := s.bookmarkRepo.IsBookmarked(ctx, userID, models.BookmarkTargetTopic, topicID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.LikeStatusResponse{
-		IsLiked:   isLiked,
-		LikeCount: likeCount,
+		IsLiked:      isLiked,
+		LikeCount:    likeCount,
+		IsBookmarked: isBookmarked,
 	}, nil
 }
 
@@ -160,24 +164,17 @@ func (s *likeServiceImpl) LikeVideo(ctx context.Context, userID uuid.UUID, video
 		return nil, errors.New("video already liked")
 	}
 
-	// Create like
-	_, err = s.likeRepo.LikeVideo(ctx, userID, videoID)
+	// Create like, queueing the owner's notification and the like count update of the video table
+	tasks, err := newTasks(s.queueService,
+		queuedTask{dto.NotifyLikeTask{TargetType: dto.LikeTargetVideo, TargetID: videoID, ActorID: userID}, likeNotificationKey(dto.LikeTargetVideo, videoID, userID)},
+		queuedTask{dto.SyncLikeCountTask{TargetType: dto.LikeTargetVideo, TargetID: videoID}, ""},
+	)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create notification for video like
-	go func() {
-		_ = s.notificationService.CreateVideoLikeNotification(context.Background(), videoID, userID)
-	}()
-
-	// Update like count in video table asynchronously
-	go func() {
-		count, err := s.likeRepo.CountVideoLikes(context.Background(), videoID)
-		if err == nil {
-			_ = s.videoRepo.UpdateLikeCount(context.Background(), videoID, int(count))
-		}
-	}()
+	if _, err := s.likeRepo.LikeVideo(ctx, userID, videoID, tasks...); err != nil {
+		return nil, err
+	}
 
 	// Get updated like count
 	likeCount, err := s.likeRepo.CountVideoLikes(ctx, videoID)
@@ -185,6 +182,8 @@ func (s *likeServiceImpl) LikeVideo(ctx context.Context, userID uuid.UUID, video
 		return nil, err
 	}
 
+	broadcastLikeCount(websocket.VideoRoom(videoID), dto.LikeTargetVideo, videoID, likeCount)
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   true,
 		LikeCount: likeCount,
@@ -202,19 +201,14 @@ func (s *likeServiceImpl) UnlikeVideo(ctx context.Context, userID uuid.UUID, vid
 		return nil, errors.New("video not liked")
 	}
 
-	// Remove like
-	err = s.likeRepo.UnlikeVideo(ctx, userID, videoID)
+	// Remove like, queueing the like count update of the video table
+	tasks, err := newTasks(s.queueService, queuedTask{dto.SyncLikeCountTask{TargetType: dto.LikeTargetVideo, TargetID: videoID}, ""})
 	if err != nil {
 		return nil, err
 	}
-
-	// Update like count in video table asynchronously
-	go func() {
-		count, err := s.likeRepo.CountVideoLikes(context.Background(), videoID)
-		if err == nil {
-			_ = s.videoRepo.UpdateLikeCount(context.Background(), videoID, int(count))
-		}
-	}()
+	if err := s.likeRepo.UnlikeVideo(ctx, userID, videoID, tasks...); err != nil {
+		return nil, err
+	}
 
 	// Get updated like count
 	likeCount, err := s.likeRepo.CountVideoLikes(ctx, videoID)
@@ -222,6 +216,8 @@ func (s *likeServiceImpl) UnlikeVideo(ctx context.Context, userID uuid.UUID, vid
 		return nil, err
 	}
 
+	broadcastLikeCount(websocket.VideoRoom(videoID), dto.LikeTargetVideo, videoID, likeCount)
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   false,
 		LikeCount: likeCount,
@@ -241,16 +237,22 @@ func (s *likeServiceImpl) GetVideoLikeStatus(ctx context.Context, userID uuid.UU
 		return nil, err
 	}
 
+	isBookmarked, err := s.bookmarkRepo.IsBookmarked(ctx, userID, models.BookmarkTargetVideo, videoID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.LikeStatusResponse{
-		IsLiked:   isLiked,
-		LikeCount: likeCount,
+		IsLiked:      isLiked,
+		LikeCount:    likeCount,
+		IsBookmarked: isBookmarked,
 	}, nil
 }
 
 // Reply Likes
 func (s *likeServiceImpl) LikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) (*dto.LikeStatusResponse, error) {
 	// Verify reply exists
-	_, err := s.replyRepo.GetByID(ctx, replyID)
+	reply, err := s.replyRepo.GetByID(ctx, replyID)
 	if err != nil {
 		return nil, errors.New("reply not found")
 	}
@@ -265,16 +267,17 @@ func (s *likeServiceImpl) LikeReply(ctx context.Context, userID uuid.UUID, reply
 		return nil, errors.New("reply already liked")
 	}
 
-	// Create like
-	_, err = s.likeRepo.LikeReply(ctx, userID, replyID)
+	// Create like, queueing the owner's notification and the like count update of the reply table
+	tasks, err := newTasks(s.queueService,
+		queuedTask{dto.NotifyLikeTask{TargetType: dto.LikeTargetReply, TargetID: replyID, ActorID: userID}, likeNotificationKey(dto.LikeTargetReply, replyID, userID)},
+		queuedTask{dto.SyncLikeCountTask{TargetType: dto.LikeTargetReply, TargetID: replyID}, ""},
+	)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create notification for reply like
-	go func() {
-		_ = s.notificationService.CreateReplyLikeNotification(context.Background(), replyID, userID)
-	}()
+	if _, err := s.likeRepo.LikeReply(ctx, userID, replyID, tasks...); err != nil {
+		return nil, err
+	}
 
 	// Get updated like count
 	likeCount, err := s.likeRepo.CountReplyLikes(ctx, replyID)
@@ -282,6 +285,8 @@ func (s *likeServiceImpl) LikeReply(ctx context.Context, userID uuid.UUID, reply
 		return nil, err
 	}
 
+	broadcastLikeCount(websocket.TopicRoom(reply.TopicID), dto.LikeTargetReply, replyID, likeCount)
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   true,
 		LikeCount: likeCount,
@@ -289,6 +294,11 @@ func (s *likeServiceImpl) LikeReply(ctx context.Context, userID uuid.UUID, reply
 }
 
 func (s *likeServiceImpl) UnlikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) (*dto.LikeStatusResponse, error) {
+	reply, err := s.replyRepo.GetByID(ctx, replyID)
+	if err != nil {
+		return nil, errors.New("reply not found")
+	}
+
 	// Check if liked
 	isLiked, err := s.likeRepo.IsReplyLikedByUser(ctx, userID, replyID)
 	if err != nil {
@@ -299,11 +309,14 @@ func (s *likeServiceImpl) UnlikeReply(ctx context.Context, userID uuid.UUID, rep
 		return nil, errors.New("reply not liked")
 	}
 
-	// Remove like
-	err = s.likeRepo.UnlikeReply(ctx, userID, replyID)
+	// Remove like, queueing the like count update of the reply table
+	tasks, err := newTasks(s.queueService, queuedTask{dto.SyncLikeCountTask{TargetType: dto.LikeTargetReply, TargetID: replyID}, ""})
 	if err != nil {
 		return nil, err
 	}
+	if err := s.likeRepo.UnlikeReply(ctx, userID, replyID, tasks...); err != nil {
+		return nil, err
+	}
 
 	// Get updated like count
 	likeCount, err := s.likeRepo.CountReplyLikes(ctx, replyID)
@@ -311,6 +324,8 @@ func (s *likeServiceImpl) UnlikeReply(ctx context.Context, userID uuid.UUID, rep
 		return nil, err
 	}
 
+	broadcastLikeCount(websocket.TopicRoom(reply.TopicID), dto.LikeTargetReply, replyID, likeCount)
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   false,
 		LikeCount: likeCount,
@@ -330,16 +345,22 @@ func (s *likeServiceImpl) GetReplyLikeStatus(ctx context.Context, userID uuid.UU
 		return nil, err
 	}
 
+	isBookmarked, err := s.bookmarkRepo.IsBookmarked(ctx, userID, models.BookmarkTargetReply, replyID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.LikeStatusResponse{
-		IsLiked:   isLiked,
-		LikeCount: likeCount,
+		IsLiked:      isLiked,
+		LikeCount:    likeCount,
+		IsBookmarked: isBookmarked,
 	}, nil
 }
 
 // Comment Likes
 func (s *likeServiceImpl) LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*dto.LikeStatusResponse, error) {
 	// Verify comment exists
-	_, err := s.commentRepo.GetByID(ctx, commentID)
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
 	if err != nil {
 		return nil, errors.New("comment not found")
 	}
@@ -354,16 +375,16 @@ func (s *likeServiceImpl) LikeComment(ctx context.Context, userID uuid.UUID, com
 		return nil, errors.New("comment already liked")
 	}
 
-	// Create like
-	_, err = s.likeRepo.LikeComment(ctx, userID, commentID)
+	// Create like, queueing the owner's notification
+	tasks, err := newTasks(s.queueService,
+		queuedTask{dto.NotifyLikeTask{TargetType: dto.LikeTargetComment, TargetID: commentID, ActorID: userID}, likeNotificationKey(dto.LikeTargetComment, commentID, userID)},
+	)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create notification for comment like
-	go func() {
-		_ = s.notificationService.CreateCommentLikeNotification(context.Background(), commentID, userID)
-	}()
+	if _, err := s.likeRepo.LikeComment(ctx, userID, commentID, tasks...); err != nil {
+		return nil, err
+	}
 
 	// Get updated like count
 	likeCount, err := s.likeRepo.CountCommentLikes(ctx, commentID)
@@ -371,6 +392,8 @@ func (s *likeServiceImpl) LikeComment(ctx context.Context, userID uuid.UUID, com
 		return nil, err
 	}
 
+	broadcastLikeCount(websocket.VideoRoom(comment.VideoID), dto.LikeTargetComment, commentID, likeCount)
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   true,
 		LikeCount: likeCount,
@@ -400,6 +423,10 @@ func (s *likeServiceImpl) UnlikeComment(ctx context.Context, userID uuid.UUID, c
 		return nil, err
 	}
 
+	if comment, err := s.commentRepo.GetByID(ctx, commentID); err == nil {
+		broadcastLikeCount(websocket.VideoRoom(comment.VideoID), dto.LikeTargetComment, commentID, likeCount)
+	}
+
 	return &dto.LikeStatusResponse{
 		IsLiked:   false,
 		LikeCount: likeCount,
@@ -419,8 +446,31 @@ func (s *likeServiceImpl) GetCommentLikeStatus(ctx context.Context, userID uuid.
 		return nil, err
 	}
 
+	isBookmarked, err := s.bookmarkRepo.IsBookmarked(ctx, userID, models.BookmarkTargetComment, commentID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.LikeStatusResponse{
-		IsLiked:   isLiked,
-		LikeCount: likeCount,
+		IsLiked:      isLiked,
+		LikeCount:    likeCount,
+		IsBookmarked: isBookmarked,
 	}, nil
 }
+
+func (s *likeServiceImpl) RecomputeLikeCounts(ctx context.Context, targetTypes []string) (map[string]int64, error) {
+	corrected := make(map[string]int64, len(targetTypes))
+	for _, targetType := range targetTypes {
+		count, err := s.likeRepo.RecomputeLikeCounts(ctx, targetType)
+		if err != nil {
+			return corrected, fmt.Errorf("%s like counts: %w", targetType, err)
+		}
+		corrected[targetType] = count
+	}
+	return corrected, nil
+}
+
+// likeNotificationKey allows one like notification per user and target, however often the like is toggled
+func likeNotificationKey(targetType string, targetID, userID uuid.UUID) string {
+	return fmt.Sprintf("like:%s:%s:%s", targetType, targetID, userID)
+}
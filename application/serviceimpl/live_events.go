@@ -0,0 +1,61 @@
+package serviceimpl
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/infrastructure/websocket"
+
+	"github.com/google/uuid"
+)
+
+// Live events go to the room of the topic or video they belong to; see websocket/events.go.
+// The payload is built before returning and sent in the background, so a slow room never
+// holds up the request that triggered the event.
+
+func broadcastToRoom(roomID, eventType string, data interface{}) {
+	go websocket.Manager.BroadcastToRoom(roomID, eventType, data)
+}
+
+// broadcastReply sends a created or edited reply to its topic; the author should be loaded
+func broadcastReply(reply *models.Reply, eventType string) {
+	broadcastToRoom(websocket.TopicRoom(reply.TopicID), eventType, dto.ReplyToReplyResponse(reply, false))
+}
+
+func broadcastReplyDeleted(reply *models.Reply) {
+	broadcastToRoom(websocket.TopicRoom(reply.TopicID), websocket.EventReplyDeleted, dto.ReplyDeletedEvent{
+		ID:       reply.ID,
+		TopicID:  reply.TopicID,
+		ParentID: reply.ParentID,
+	})
+}
+
+func broadcastTopicStatus(topic *models.Topic) {
+	broadcastToRoom(websocket.TopicRoom(topic.ID), websocket.EventTopicStatus, dto.TopicStatusEvent{
+		TopicID:         topic.ID,
+		IsPinned:        topic.IsPinned,
+		IsLocked:        topic.IsLocked,
+		AcceptedReplyID: topic.AcceptedReplyID,
+	})
+}
+
+func broadcastComment(comment *dto.CommentResponse, eventType string) {
+	broadcastToRoom(websocket.VideoRoom(comment.VideoID), eventType, comment)
+}
+
+func broadcastCommentDeleted(comment *models.Comment) {
+	broadcastToRoom(websocket.VideoRoom(comment.VideoID), websocket.EventCommentDeleted, dto.CommentDeletedEvent{
+		ID:       comment.ID,
+		VideoID:  comment.VideoID,
+		ParentID: comment.ParentID,
+	})
+}
+
+// broadcastLikeCount sends a new like count to roomID, the room of the topic or video the
+// liked content is shown in
+func broadcastLikeCount(roomID, targetType string, targetID uuid.UUID, likeCount int64) {
+	broadcastToRoom(roomID, websocket.EventLikeCount, dto.LikeCountEvent{
+		TargetType: targetType,
+		TargetID:   targetID,
+		LikeCount:  likeCount,
+	})
+}
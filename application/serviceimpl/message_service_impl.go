@@ -0,0 +1,805 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/websocket"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultConversationLimit = 20
+	defaultMessageLimit      = 30
+)
+
+type messageServiceImpl struct {
+	conversationRepo repositories.ConversationRepository
+	messageRepo      repositories.MessageRepository
+	userRepo         repositories.UserRepository
+	followRepo       repositories.FollowRepository
+	blockRepo        repositories.BlockRepository
+	fileService      services.FileService
+}
+
+func NewMessageService(
+	conversationRepo repositories.ConversationRepository,
+	messageRepo repositories.MessageRepository,
+	userRepo repositories.UserRepository,
+	followRepo repositories.FollowRepository,
+	blockRepo repositories.BlockRepository,
+	fileService services.FileService,
+) services.MessageService {
+	return &messageServiceImpl{
+		conversationRepo: conversationRepo,
+		messageRepo:      messageRepo,
+		userRepo:         userRepo,
+		followRepo:       followRepo,
+		blockRepo:        blockRepo,
+		fileService:      fileService,
+	}
+}
+
+func (s *messageServiceImpl) CreateConversation(ctx context.Context, userID uuid.UUID, req *dto.CreateConversationRequest) (*dto.ConversationResponse, error) {
+	otherIDs := uniqueIDs(req.ParticipantIDs, userID)
+	if len(otherIDs) == 0 {
+		return nil, errors.New("add at least one other participant")
+	}
+	if len(otherIDs)+1 > models.MaxGroupParticipants {
+		return nil, errors.New("too many participants")
+	}
+
+	sender, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	// Nobody in the conversation may have blocked anyone else in it
+	members := append([]uuid.UUID{userID}, otherIDs...)
+	blocked, err := s.blockRepo.HasBlockAcross(ctx, members, members)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("you can't message this user")
+	}
+
+	var conversation *models.Conversation
+	if len(otherIDs) == 1 {
+		conversation, err = s.openDirectConversation(ctx, sender, otherIDs[0])
+	} else {
+		conversation, err = s.createGroupConversation(ctx, sender, otherIDs, strings.TrimSpace(req.Title))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Message != nil {
+		if _, err := s.sendMessage(ctx, conversation, userID, req.Message); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetConversation(ctx, userID, conversation.ID)
+}
+
+// openDirectConversation returns the conversation between the two users, creating it the first
+// time. Reopening one the sender had declined or left makes it active for them again.
+func (s *messageServiceImpl) openDirectConversation(ctx context.Context, sender *models.User, recipientID uuid.UUID) (*models.Conversation, error) {
+	key := directConversationKey(sender.ID, recipientID)
+	if existing, err := s.conversationRepo.GetByDirectKey(ctx, key); err == nil {
+		if participant := existing.Participant(sender.ID); participant != nil && !participant.CanView() {
+			if err := s.conversationRepo.UpdateParticipantStatus(ctx, existing.ID, sender.ID, models.ParticipantActive); err != nil {
+				return nil, err
+			}
+			participant.Status = models.ParticipantActive
+		}
+		return existing, nil
+	}
+
+	recipient, err := s.userRepo.FindByID(ctx, recipientID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	status, err := s.initialStatus(ctx, sender.ID, recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	conversation := &models.Conversation{
+		Type:          models.ConversationDirect,
+		CreatorID:     sender.ID,
+		DirectKey:     &key,
+		LastMessageAt: now,
+		Participants: []models.ConversationParticipant{
+			{UserID: sender.ID, Status: models.ParticipantActive, JoinedAt: now},
+			{UserID: recipient.ID, Status: status, JoinedAt: now},
+		},
+	}
+	if err := s.conversationRepo.Create(ctx, conversation); err != nil {
+		// Both users opened it at the same time
+		if existing, getErr := s.conversationRepo.GetByDirectKey(ctx, key); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return s.announceConversation(ctx, conversation.ID, sender.ID)
+}
+
+func (s *messageServiceImpl) createGroupConversation(ctx context.Context, creator *models.User, memberIDs []uuid.UUID, title string) (*models.Conversation, error) {
+	now := time.Now()
+	conversation := &models.Conversation{
+		Type:          models.ConversationGroup,
+		Title:         title,
+		CreatorID:     creator.ID,
+		LastMessageAt: now,
+		Participants: []models.ConversationParticipant{
+			{UserID: creator.ID, Status: models.ParticipantActive, JoinedAt: now},
+		},
+	}
+
+	for _, memberID := range memberIDs {
+		member, err := s.userRepo.FindByID(ctx, memberID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		status, err := s.initialStatus(ctx, creator.ID, member)
+		if err != nil {
+			return nil, err
+		}
+		conversation.Participants = append(conversation.Participants, models.ConversationParticipant{
+			UserID:   member.ID,
+			Status:   status,
+			JoinedAt: now,
+		})
+	}
+
+	if err := s.conversationRepo.Create(ctx, conversation); err != nil {
+		return nil, err
+	}
+
+	return s.announceConversation(ctx, conversation.ID, creator.ID)
+}
+
+// announceConversation reloads a new conversation and tells the other participants about it:
+// a message request for those who got one, the conversation itself for the rest
+func (s *messageServiceImpl) announceConversation(ctx context.Context, conversationID, creatorID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, participant := range conversation.Participants {
+		if participant.UserID == creatorID {
+			continue
+		}
+		eventType := websocket.EventConversationCreated
+		if participant.Status == models.ParticipantRequest {
+			eventType = websocket.EventMessageRequest
+		}
+		websocket.Manager.BroadcastToUser(participant.UserID, eventType, dto.ConversationToConversationResponse(conversation, participant.UserID))
+	}
+
+	return conversation, nil
+}
+
+// initialStatus decides whether a user added to a conversation gets it as a message request:
+// private users do, unless either of the two follows the other
+func (s *messageServiceImpl) initialStatus(ctx context.Context, senderID uuid.UUID, recipient *models.User) (models.ParticipantStatus, error) {
+	if !recipient.IsPrivate {
+		return models.ParticipantActive, nil
+	}
+
+	follows, err := s.followRepo.IsFollowing(ctx, senderID, recipient.ID)
+	if err != nil {
+		return "", err
+	}
+	if !follows {
+		follows, err = s.followRepo.IsFollowing(ctx, recipient.ID, senderID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if follows {
+		return models.ParticipantActive, nil
+	}
+	return models.ParticipantRequest, nil
+}
+
+func (s *messageServiceImpl) GetConversations(ctx context.Context, userID uuid.UUID, params *dto.ConversationListParams) (*dto.ConversationListResponse, error) {
+	statuses := []models.ParticipantStatus{models.ParticipantActive}
+	if params.Status == "requests" {
+		statuses = []models.ParticipantStatus{models.ParticipantRequest}
+	}
+
+	limit := params.Limit
+	if limit < 1 {
+		limit = defaultConversationLimit
+	}
+
+	var after *repositories.ConversationCursor
+	if params.Cursor != "" {
+		cursor, err := decodeConversationCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = cursor
+	}
+
+	conversations, err := s.conversationRepo.FindByUser(ctx, userID, statuses, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(conversations) > limit
+	if hasMore {
+		conversations = conversations[:limit]
+	}
+
+	responses, err := s.conversationResponses(ctx, conversations, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ConversationListResponse{
+		Conversations: responses,
+		HasMore:       hasMore,
+	}
+	if hasMore {
+		result.NextCursor = encodeConversationCursor(conversations[len(conversations)-1])
+	}
+	return result, nil
+}
+
+func (s *messageServiceImpl) GetConversation(ctx context.Context, userID, conversationID uuid.UUID) (*dto.ConversationResponse, error) {
+	conversation, _, err := s.loadConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.conversationResponses(ctx, []*models.Conversation{conversation}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// conversationResponses converts conversations for userID with their last message and unread count
+func (s *messageServiceImpl) conversationResponses(ctx context.Context, conversations []*models.Conversation, userID uuid.UUID) ([]dto.ConversationResponse, error) {
+	conversationIDs := make([]uuid.UUID, 0, len(conversations))
+	lastMessageIDs := make([]uuid.UUID, 0, len(conversations))
+	for _, conversation := range conversations {
+		conversationIDs = append(conversationIDs, conversation.ID)
+		if conversation.LastMessageID != nil {
+			lastMessageIDs = append(lastMessageIDs, *conversation.LastMessageID)
+		}
+	}
+
+	lastMessages, err := s.messageRepo.GetByIDs(ctx, lastMessageIDs)
+	if err != nil {
+		return nil, err
+	}
+	messagesByID := make(map[uuid.UUID]*models.Message, len(lastMessages))
+	for _, message := range lastMessages {
+		messagesByID[message.ID] = message
+	}
+
+	unread, err := s.conversationRepo.CountUnread(ctx, userID, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ConversationResponse, 0, len(conversations))
+	for _, conversation := range conversations {
+		resp := dto.ConversationToConversationResponse(conversation, userID)
+		if conversation.LastMessageID != nil {
+			if message, ok := messagesByID[*conversation.LastMessageID]; ok {
+				resp.LastMessage = s.messageResponse(message)
+			}
+		}
+		resp.UnreadCount = unread[conversation.ID]
+		responses = append(responses, *resp)
+	}
+	return responses, nil
+}
+
+func (s *messageServiceImpl) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*dto.UnreadMessageCountResponse, error) {
+	unread, err := s.conversationRepo.CountUnreadTotal(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.conversationRepo.CountByUser(ctx, userID, models.ParticipantRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UnreadMessageCountResponse{
+		Messages:      unread.Messages,
+		Conversations: unread.Conversations,
+		Requests:      requests,
+	}, nil
+}
+
+func (s *messageServiceImpl) AcceptRequest(ctx context.Context, userID, conversationID uuid.UUID) (*dto.ConversationResponse, error) {
+	_, participant, err := s.loadConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if participant.Status != models.ParticipantRequest {
+		return nil, errors.New("this conversation is not a message request")
+	}
+
+	if err := s.conversationRepo.UpdateParticipantStatus(ctx, conversationID, userID, models.ParticipantActive); err != nil {
+		return nil, err
+	}
+
+	return s.GetConversation(ctx, userID, conversationID)
+}
+
+func (s *messageServiceImpl) DeclineRequest(ctx context.Context, userID, conversationID uuid.UUID) error {
+	_, participant, err := s.loadConversation(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if participant.Status != models.ParticipantRequest {
+		return errors.New("this conversation is not a message request")
+	}
+
+	return s.conversationRepo.UpdateParticipantStatus(ctx, conversationID, userID, models.ParticipantDeclined)
+}
+
+func (s *messageServiceImpl) AddParticipants(ctx context.Context, userID, conversationID uuid.UUID, req *dto.AddParticipantsRequest) (*dto.ConversationResponse, error) {
+	conversation, participant, err := s.loadConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Type != models.ConversationGroup {
+		return nil, errors.New("participants can only be added to group conversations")
+	}
+	if participant.Status != models.ParticipantActive {
+		return nil, errors.New("accept the message request first")
+	}
+
+	var memberIDs []uuid.UUID
+	for _, existing := range conversation.Participants {
+		if existing.Status != models.ParticipantLeft {
+			memberIDs = append(memberIDs, existing.UserID)
+		}
+	}
+
+	var newIDs []uuid.UUID
+	for _, id := range uniqueIDs(req.UserIDs, userID) {
+		if existing := conversation.Participant(id); existing == nil || existing.Status == models.ParticipantLeft {
+			newIDs = append(newIDs, id)
+		}
+	}
+	if len(newIDs) == 0 {
+		return s.GetConversation(ctx, userID, conversationID)
+	}
+	if len(memberIDs)+len(newIDs) > models.MaxGroupParticipants {
+		return nil, errors.New("too many participants")
+	}
+
+	// New members may not have a block with anyone already in the group or joining with them
+	blocked, err := s.blockRepo.HasBlockAcross(ctx, newIDs, append(memberIDs, newIDs...))
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("you can't message this user")
+	}
+
+	now := time.Now()
+	participants := make([]*models.ConversationParticipant, 0, len(newIDs))
+	for _, id := range newIDs {
+		member, err := s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		status, err := s.initialStatus(ctx, userID, member)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, &models.ConversationParticipant{
+			ConversationID: conversationID,
+			UserID:         id,
+			Status:         status,
+			JoinedAt:       now,
+		})
+	}
+
+	if err := s.conversationRepo.AddParticipants(ctx, participants); err != nil {
+		return nil, err
+	}
+
+	s.broadcastConversationUpdated(ctx, conversationID, nil)
+	return s.GetConversation(ctx, userID, conversationID)
+}
+
+// RemoveParticipant lets a member leave a group, or its creator remove someone
+func (s *messageServiceImpl) RemoveParticipant(ctx context.Context, userID, conversationID, participantID uuid.UUID) error {
+	conversation, _, err := s.loadConversation(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if conversation.Type != models.ConversationGroup {
+		return errors.New("participants can only be removed from group conversations")
+	}
+	if participantID != userID && conversation.CreatorID != userID {
+		return errors.New("only the creator can remove participants")
+	}
+
+	target := conversation.Participant(participantID)
+	if target == nil || target.Status == models.ParticipantLeft {
+		return errors.New("participant not found")
+	}
+
+	if err := s.conversationRepo.UpdateParticipantStatus(ctx, conversationID, participantID, models.ParticipantLeft); err != nil {
+		return err
+	}
+
+	// The removed user is told too, so their inbox drops the conversation
+	s.broadcastConversationUpdated(ctx, conversationID, &participantID)
+	return nil
+}
+
+func (s *messageServiceImpl) GetMessages(ctx context.Context, userID, conversationID uuid.UUID, params *dto.MessageListParams) (*dto.MessageListResponse, error) {
+	if _, _, err := s.loadConversation(ctx, conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit
+	if limit < 1 {
+		limit = defaultMessageLimit
+	}
+
+	var before *repositories.MessageCursor
+	if params.Cursor != "" {
+		cursor, err := decodeMessageCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before = cursor
+	}
+
+	messages, err := s.messageRepo.FindByConversation(ctx, conversationID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	result := &dto.MessageListResponse{
+		Messages: make([]dto.MessageResponse, 0, len(messages)),
+		HasMore:  hasMore,
+	}
+	for _, message := range messages {
+		result.Messages = append(result.Messages, *s.messageResponse(message))
+	}
+	if hasMore {
+		result.NextCursor = encodeMessageCursor(messages[len(messages)-1])
+	}
+	return result, nil
+}
+
+func (s *messageServiceImpl) SendMessage(ctx context.Context, userID, conversationID uuid.UUID, req *dto.SendMessageRequest) (*dto.MessageResponse, error) {
+	conversation, participant, err := s.loadConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replying to a message request accepts it
+	if participant.Status == models.ParticipantRequest {
+		if err := s.conversationRepo.UpdateParticipantStatus(ctx, conversationID, userID, models.ParticipantActive); err != nil {
+			return nil, err
+		}
+		participant.Status = models.ParticipantActive
+	}
+
+	return s.sendMessage(ctx, conversation, userID, req)
+}
+
+func (s *messageServiceImpl) sendMessage(ctx context.Context, conversation *models.Conversation, senderID uuid.UUID, req *dto.SendMessageRequest) (*dto.MessageResponse, error) {
+	content := strings.TrimSpace(req.Content)
+	attachmentIDs := uniqueIDs(req.AttachmentIDs, uuid.Nil)
+	if content == "" && len(attachmentIDs) == 0 {
+		return nil, errors.New("message must have content or attachments")
+	}
+	if len(attachmentIDs) > models.MaxMessageAttachments {
+		return nil, errors.New("too many attachments")
+	}
+
+	var recipientIDs []uuid.UUID
+	for _, other := range conversation.Participants {
+		if other.UserID != senderID && other.Status != models.ParticipantLeft {
+			recipientIDs = append(recipientIDs, other.UserID)
+		}
+	}
+	blocked, err := s.blockRepo.FindBlockedAmong(ctx, senderID, recipientIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocked) > 0 {
+		return nil, errors.New("you can't message this user")
+	}
+
+	message := &models.Message{
+		ConversationID: conversation.ID,
+		SenderID:       senderID,
+		Content:        content,
+		CreatedAt:      time.Now(),
+	}
+	for _, fileID := range attachmentIDs {
+		file, err := s.fileService.GetFile(ctx, fileID)
+		if err != nil {
+			return nil, errors.New("file not found")
+		}
+		if file.UserID != senderID {
+			return nil, errors.New("you don't have permission to use this file")
+		}
+		// Attachments are private to the conversation: serve them from the secure zone
+		if err := s.fileService.ProtectFile(ctx, file); err != nil {
+			return nil, fmt.Errorf("failed to protect attachment: %v", err)
+		}
+		message.Attachments = append(message.Attachments, models.MessageAttachment{FileID: fileID})
+	}
+
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, err
+	}
+
+	// Keep the files out of orphan cleanup while the message uses them
+	for _, fileID := range attachmentIDs {
+		if err := s.fileService.AttachFile(ctx, fileID, senderID, models.FileReferenceMessage, message.ID); err != nil {
+			log.Printf("Warning: failed to attach file %s to message %s: %v", fileID, message.ID, err)
+		}
+	}
+
+	saved, err := s.messageRepo.GetByID(ctx, message.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.messageResponse(saved)
+	broadcastToParticipants(conversation, websocket.EventMessageCreated, resp)
+	return resp, nil
+}
+
+func (s *messageServiceImpl) UpdateMessage(ctx context.Context, userID, messageID uuid.UUID, req *dto.UpdateMessageRequest) (*dto.MessageResponse, error) {
+	message, conversation, err := s.loadOwnMessage(ctx, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, errors.New("message must have content")
+	}
+
+	if err := s.messageRepo.UpdateContent(ctx, message.ID, content, time.Now()); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.messageRepo.GetByID(ctx, message.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.messageResponse(updated)
+	broadcastToParticipants(conversation, websocket.EventMessageUpdated, resp)
+	return resp, nil
+}
+
+func (s *messageServiceImpl) DeleteMessage(ctx context.Context, userID, messageID uuid.UUID) error {
+	message, conversation, err := s.loadOwnMessage(ctx, messageID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.messageRepo.SoftDelete(ctx, message.ID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.fileService.ReleaseFiles(ctx, models.FileReferenceMessage, message.ID); err != nil {
+		log.Printf("Warning: failed to release files of message %s: %v", message.ID, err)
+	}
+
+	broadcastToParticipants(conversation, websocket.EventMessageDeleted, dto.MessageDeletedEvent{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+	})
+	return nil
+}
+
+func (s *messageServiceImpl) MarkRead(ctx context.Context, userID, conversationID uuid.UUID, req *dto.MarkConversationReadRequest) error {
+	conversation, _, err := s.loadConversation(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	var message *models.Message
+	if req.MessageID != nil {
+		message, err = s.messageRepo.GetByID(ctx, *req.MessageID)
+		if err != nil || message.ConversationID != conversationID {
+			return errors.New("message not found")
+		}
+	} else {
+		message, err = s.messageRepo.GetLatest(ctx, conversationID)
+		if err != nil {
+			// Nothing to read yet
+			return nil
+		}
+	}
+
+	readAt := time.Now()
+	moved, err := s.conversationRepo.MarkRead(ctx, conversationID, userID, message, readAt)
+	if err != nil {
+		return err
+	}
+
+	if moved {
+		broadcastToParticipants(conversation, websocket.EventMessageRead, dto.MessageReadEvent{
+			ConversationID: conversationID,
+			UserID:         userID,
+			MessageID:      message.ID,
+			ReadAt:         readAt,
+		})
+	}
+	return nil
+}
+
+// loadConversation loads a conversation the user can open; to anyone else it doesn't exist
+func (s *messageServiceImpl) loadConversation(ctx context.Context, conversationID, userID uuid.UUID) (*models.Conversation, *models.ConversationParticipant, error) {
+	conversation, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil {
+		return nil, nil, errors.New("conversation not found")
+	}
+
+	participant := conversation.Participant(userID)
+	if participant == nil || !participant.CanView() {
+		return nil, nil, errors.New("conversation not found")
+	}
+	return conversation, participant, nil
+}
+
+// loadOwnMessage loads a message the user sent, in a conversation they are still in
+func (s *messageServiceImpl) loadOwnMessage(ctx context.Context, messageID, userID uuid.UUID) (*models.Message, *models.Conversation, error) {
+	message, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil || message.DeletedAt != nil {
+		return nil, nil, errors.New("message not found")
+	}
+
+	conversation, _, err := s.loadConversation(ctx, message.ConversationID, userID)
+	if err != nil {
+		return nil, nil, errors.New("message not found")
+	}
+
+	if message.SenderID != userID {
+		return nil, nil, errors.New("you can only change your own messages")
+	}
+	return message, conversation, nil
+}
+
+// broadcastConversationUpdated sends the conversation to everyone still in it, plus extra
+func (s *messageServiceImpl) broadcastConversationUpdated(ctx context.Context, conversationID uuid.UUID, extra *uuid.UUID) {
+	conversation, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil {
+		return
+	}
+
+	for _, participant := range conversation.Participants {
+		if participant.CanView() || (extra != nil && participant.UserID == *extra) {
+			websocket.Manager.BroadcastToUser(participant.UserID, websocket.EventConversationUpdated, dto.ConversationToConversationResponse(conversation, participant.UserID))
+		}
+	}
+}
+
+// broadcastToParticipants delivers a message event to every participant who can open the
+// conversation, the sender included so their other devices stay in sync
+func broadcastToParticipants(conversation *models.Conversation, eventType string, data interface{}) {
+	for _, participant := range conversation.Participants {
+		if participant.CanView() {
+			websocket.Manager.BroadcastToUser(participant.UserID, eventType, data)
+		}
+	}
+}
+
+// directConversationKey orders the pair so both users map to the same conversation
+func directConversationKey(a, b uuid.UUID) string {
+	if strings.Compare(a.String(), b.String()) > 0 {
+		a, b = b, a
+	}
+	return a.String() + ":" + b.String()
+}
+
+// uniqueIDs drops duplicates, nil IDs and exclude, keeping the order
+func uniqueIDs(ids []uuid.UUID, exclude uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == uuid.Nil || id == exclude || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+func encodeConversationCursor(conversation *models.Conversation) string {
+	data, _ := json.Marshal(repositories.ConversationCursor{
+		LastMessageAt: conversation.LastMessageAt,
+		ID:            conversation.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeConversationCursor(value string) (*repositories.ConversationCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor repositories.ConversationCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+func encodeMessageCursor(message *models.Message) string {
+	data, _ := json.Marshal(repositories.MessageCursor{
+		CreatedAt: message.CreatedAt,
+		ID:        message.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeMessageCursor(value string) (*repositories.MessageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor repositories.MessageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// messageResponse maps a message with signed attachment URLs; attachments live in the secure zone
+func (s *messageServiceImpl) messageResponse(message *models.Message) *dto.MessageResponse {
+	resp := dto.MessageToMessageResponse(message)
+
+	files := make(map[uuid.UUID]*models.File, len(message.Attachments))
+	for _, attachment := range message.Attachments {
+		if attachment.File != nil {
+			files[attachment.FileID] = attachment.File
+		}
+	}
+	for i := range resp.Attachments {
+		if file, ok := files[resp.Attachments[i].FileID]; ok {
+			resp.Attachments[i].URL = s.fileService.FileURL(file)
+		}
+	}
+	return resp
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
@@ -52,6 +53,7 @@ func (s *notificationServiceImpl) broadcastNotification(notification *models.Not
 			"message":   notification.Message,
 			"actorId":   notification.ActorID,
 			"isRead":    notification.IsRead,
+			"count":     notification.Count,
 			"createdAt": notification.CreatedAt,
 		})
 	}()
@@ -274,6 +276,206 @@ func (s *notificationServiceImpl) CreateReplyLikeNotification(ctx context.Contex
 	return nil
 }
 
+func (s *notificationServiceImpl) CreateAnswerAcceptedNotification(ctx context.Context, replyID, accepterUserID uuid.UUID) error {
+	// Get reply
+	reply, err := s.replyRepo.GetByID(ctx, replyID)
+	if err != nil {
+		return err
+	}
+
+	// Don't notify if accepting own answer
+	if reply.UserID == accepterUserID {
+		return nil
+	}
+
+	// Get accepter user
+	accepter, err := s.userRepo.FindByID(ctx, accepterUserID)
+	if err != nil {
+		return err
+	}
+
+	notification := &models.Notification{
+		UserID:     reply.UserID,
+		ActorID:    accepterUserID,
+		Type:       models.NotificationTypeAnswerAccepted,
+		ResourceID: &reply.TopicID,
+		Message:    fmt.Sprintf("%s เลือกคำตอบของคุณเป็นคำตอบที่ดีที่สุด", accepter.Username),
+		IsRead:     false,
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	// Broadcast notification via WebSocket
+	s.broadcastNotification(notification)
+	return nil
+}
+
+func (s *notificationServiceImpl) CreateWatchedTopicReplyNotifications(ctx context.Context, topicID, replyUserID uuid.UUID, watcherIDs []uuid.UUID) error {
+	if len(watcherIDs) == 0 {
+		return nil
+	}
+
+	// Get topic
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return err
+	}
+
+	// Get replier user
+	replier, err := s.userRepo.FindByID(ctx, replyUserID)
+	if err != nil {
+		return err
+	}
+
+	// Watchers who haven't read the last reply notification of this topic get it updated
+	existing, err := s.notificationRepo.FindUnreadByResource(ctx, watcherIDs, models.NotificationTypeWatchedTopicReply, topicID)
+	if err != nil {
+		return err
+	}
+
+	folded := make(map[uuid.UUID]bool, len(existing))
+	ids := make([]uuid.UUID, len(existing))
+	for i := range existing {
+		ids[i] = existing[i].ID
+		folded[existing[i].UserID] = true
+	}
+	message := fmt.Sprintf("%s และคนอื่นๆ ตอบกระทู้ที่คุณติดตาม: %s", replier.Username, topic.Title)
+
+	notifications := make([]models.Notification, 0, len(watcherIDs)-len(folded))
+	for _, watcherID := range watcherIDs {
+		if folded[watcherID] {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:     watcherID,
+			ActorID:    replyUserID,
+			Type:       models.NotificationTypeWatchedTopicReply,
+			ResourceID: &topicID,
+			Message:    fmt.Sprintf("%s ตอบกระทู้ที่คุณติดตาม: %s", replier.Username, topic.Title),
+			IsRead:     false,
+			Count:      1,
+		})
+	}
+
+	// One transaction, so a retried batch doesn't fold the same reply in twice
+	if err := s.notificationRepo.FoldAndCreate(ctx, ids, replyUserID, message, notifications); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range existing {
+		existing[i].ActorID = replyUserID
+		existing[i].Message = message
+		existing[i].Count++
+		existing[i].CreatedAt = now
+		s.broadcastNotification(&existing[i])
+	}
+
+	// Broadcast notifications via WebSocket
+	for i := range notifications {
+		s.broadcastNotification(&notifications[i])
+	}
+	return nil
+}
+
+func (s *notificationServiceImpl) CreateWatchedForumTopicNotifications(ctx context.Context, topicID uuid.UUID, watcherIDs []uuid.UUID) error {
+	if len(watcherIDs) == 0 {
+		return nil
+	}
+
+	// Get topic (with forum and author)
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]models.Notification, len(watcherIDs))
+	for i, watcherID := range watcherIDs {
+		notifications[i] = models.Notification{
+			UserID:     watcherID,
+			ActorID:    topic.UserID,
+			Type:       models.NotificationTypeWatchedForumTopic,
+			ResourceID: &topicID,
+			Message:    fmt.Sprintf("%s ตั้งกระทู้ใหม่ใน %s: %s", topic.User.Username, topic.Forum.Name, topic.Title),
+			IsRead:     false,
+			Count:      1,
+		}
+	}
+
+	if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+		return err
+	}
+
+	// Broadcast notifications via WebSocket
+	for i := range notifications {
+		s.broadcastNotification(&notifications[i])
+	}
+	return nil
+}
+
+func (s *notificationServiceImpl) CreateTopicModerationNotifications(ctx context.Context, notifType models.NotificationType, topicID, moderatorID uuid.UUID, authorIDs []uuid.UUID, reason string) error {
+	// Get topic (with forum)
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return err
+	}
+
+	// Get moderator
+	moderator, err := s.userRepo.FindByID(ctx, moderatorID)
+	if err != nil {
+		return err
+	}
+
+	var message string
+	switch notifType {
+	case models.NotificationTypeTopicMoved:
+		message = fmt.Sprintf("%s ย้ายกระทู้ \"%s\" ของคุณไปที่ %s", moderator.Username, topic.Title, topic.Forum.Name)
+	case models.NotificationTypeTopicMerged:
+		message = fmt.Sprintf("%s รวมกระทู้ของคุณเข้ากับกระทู้ \"%s\"", moderator.Username, topic.Title)
+	case models.NotificationTypeTopicSplit:
+		message = fmt.Sprintf("%s แยกความคิดเห็นของคุณไปเป็นกระทู้ใหม่ \"%s\"", moderator.Username, topic.Title)
+	default:
+		return fmt.Errorf("unsupported moderation notification type: %s", notifType)
+	}
+	if reason != "" {
+		message += fmt.Sprintf(" (เหตุผล: %s)", reason)
+	}
+
+	// Don't notify the moderator about their own content; one notification per author
+	seen := map[uuid.UUID]bool{moderatorID: true}
+	notifications := make([]models.Notification, 0, len(authorIDs))
+	for _, authorID := range authorIDs {
+		if seen[authorID] {
+			continue
+		}
+		seen[authorID] = true
+		notifications = append(notifications, models.Notification{
+			UserID:     authorID,
+			ActorID:    moderatorID,
+			Type:       notifType,
+			ResourceID: &topicID,
+			Message:    message,
+			IsRead:     false,
+			Count:      1,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+		return err
+	}
+
+	// Broadcast notifications via WebSocket
+	for i := range notifications {
+		s.broadcastNotification(&notifications[i])
+	}
+	return nil
+}
+
 func (s *notificationServiceImpl) CreateCommentLikeNotification(ctx context.Context, commentID, likerUserID uuid.UUID) error {
 	// Get comment
 	comment, err := s.commentRepo.GetByID(ctx, commentID)
@@ -360,6 +562,7 @@ func (s *notificationServiceImpl) GetNotifications(ctx context.Context, userID u
 			ResourceID: notif.ResourceID,
 			Message:    notif.Message,
 			IsRead:     notif.IsRead,
+			Count:      notif.Count,
 			CreatedAt:  notif.CreatedAt,
 		}
 	}
@@ -456,3 +659,31 @@ func (s *notificationServiceImpl) DeleteNotification(ctx context.Context, userID
 
 	return s.notificationRepo.Delete(ctx, notificationID)
 }
+
+func (s *notificationServiceImpl) PurgeNotifications(ctx context.Context, before time.Time, readOnly bool) (int64, error) {
+	return s.notificationRepo.DeleteOlderThan(ctx, before, readOnly)
+}
+
+func (s *notificationServiceImpl) SendDigests(ctx context.Context, since time.Time, minUnread int) (int, error) {
+	counts, err := s.notificationRepo.CountUnreadForDigest(ctx, since, minUnread)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for userID, unread := range counts {
+		notification := &models.Notification{
+			UserID:  userID,
+			ActorID: userID,
+			Type:    models.NotificationTypeDigest,
+			Message: fmt.Sprintf("คุณมีการแจ้งเตือนที่ยังไม่ได้อ่าน %d รายการ", unread),
+			Count:   int(unread),
+		}
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			return sent, err
+		}
+		s.broadcastNotification(notification)
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,253 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxPlaylistItems     = 500
+	maxPlaylistsPerVideo = 200 // playlists shown in the "add to playlist" picker
+)
+
+type playlistServiceImpl struct {
+	playlistRepo repositories.PlaylistRepository
+	videoRepo    repositories.VideoRepository
+	videoService services.VideoService
+}
+
+func NewPlaylistService(
+	playlistRepo repositories.PlaylistRepository,
+	videoRepo repositories.VideoRepository,
+	videoService services.VideoService,
+) services.PlaylistService {
+	return &playlistServiceImpl{
+		playlistRepo: playlistRepo,
+		videoRepo:    videoRepo,
+		videoService: videoService,
+	}
+}
+
+func (s *playlistServiceImpl) CreatePlaylist(ctx context.Context, userID uuid.UUID, req *dto.CreatePlaylistRequest) (*dto.PlaylistResponse, error) {
+	visibility := models.PlaylistVisibility(req.Visibility)
+	if visibility == "" {
+		visibility = models.PlaylistPublic
+	}
+
+	playlist := &models.Playlist{
+		UserID:      userID,
+		Title:       req.Title,
+		Description: req.Description,
+		Visibility:  visibility,
+	}
+
+	if err := s.playlistRepo.Create(ctx, playlist); err != nil {
+		return nil, err
+	}
+
+	return dto.PlaylistToPlaylistResponse(playlist), nil
+}
+
+func (s *playlistServiceImpl) GetPlaylist(ctx context.Context, playlistID uuid.UUID, viewerID *uuid.UUID, page, limit int) (*dto.PlaylistDetailResponse, error) {
+	playlist, err := s.playlistRepo.GetByID(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Private playlists look like they don't exist to anyone but the owner
+	isOwner := viewerID != nil && *viewerID == playlist.UserID
+	if playlist.Visibility == models.PlaylistPrivate && !isOwner {
+		return nil, errors.New("playlist not found")
+	}
+
+	page, limit = dto.NormalizePage(page, limit)
+	offset := (page - 1) * limit
+	items, total, err := s.playlistRepo.GetItems(ctx, playlistID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	videoIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		videoIDs[i] = item.VideoID
+	}
+
+	videos, err := s.videoService.GetVideosByIDs(ctx, videoIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemResponses := make([]dto.PlaylistItemResponse, 0, len(items))
+	for _, item := range items {
+		video, ok := videos[item.VideoID]
+		if !ok {
+			continue
+		}
+		itemResponses = append(itemResponses, dto.PlaylistItemResponse{
+			Position: item.Position,
+			AddedAt:  item.CreatedAt,
+			Video:    video,
+		})
+	}
+
+	return &dto.PlaylistDetailResponse{
+		Playlist: *dto.PlaylistToPlaylistResponse(playlist),
+		Items:    itemResponses,
+		Meta:     dto.NewPaginationMeta(total, offset, limit),
+	}, nil
+}
+
+func (s *playlistServiceImpl) GetUserPlaylists(ctx context.Context, userID uuid.UUID, viewerID *uuid.UUID, page, limit int) (*dto.PlaylistListResponse, error) {
+	publicOnly := viewerID == nil || *viewerID != userID
+
+	page, limit = dto.NormalizePage(page, limit)
+	offset := (page - 1) * limit
+	playlists, total, err := s.playlistRepo.FindByUserID(ctx, userID, publicOnly, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PlaylistResponse, len(playlists))
+	for i, playlist := range playlists {
+		responses[i] = *dto.PlaylistToPlaylistResponse(playlist)
+	}
+
+	return &dto.PlaylistListResponse{
+		Playlists: responses,
+		Meta:      dto.NewPaginationMeta(total, offset, limit),
+	}, nil
+}
+
+func (s *playlistServiceImpl) UpdatePlaylist(ctx context.Context, userID, playlistID uuid.UUID, req *dto.UpdatePlaylistRequest) (*dto.PlaylistResponse, error) {
+	playlist, err := s.getOwnedPlaylist(ctx, userID, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != "" {
+		playlist.Title = req.Title
+	}
+	if req.Description != nil {
+		playlist.Description = *req.Description
+	}
+	if req.Visibility != "" {
+		playlist.Visibility = models.PlaylistVisibility(req.Visibility)
+	}
+
+	if err := s.playlistRepo.Update(ctx, playlist); err != nil {
+		return nil, err
+	}
+
+	return dto.PlaylistToPlaylistResponse(playlist), nil
+}
+
+func (s *playlistServiceImpl) DeletePlaylist(ctx context.Context, userID, playlistID uuid.UUID) error {
+	if _, err := s.getOwnedPlaylist(ctx, userID, playlistID); err != nil {
+		return err
+	}
+
+	return s.playlistRepo.Delete(ctx, playlistID)
+}
+
+func (s *playlistServiceImpl) AddVideo(ctx context.Context, userID, playlistID, videoID uuid.UUID) error {
+	playlist, err := s.getOwnedPlaylist(ctx, userID, playlistID)
+	if err != nil {
+		return err
+	}
+
+	if playlist.ItemCount >= maxPlaylistItems {
+		return errors.New("playlist is full")
+	}
+
+	// Only active videos can be added; hidden/deleted ones are cleaned up from playlists anyway
+	if _, err := s.videoRepo.FindByID(ctx, videoID); err != nil {
+		return err
+	}
+
+	return s.playlistRepo.AddItem(ctx, playlistID, videoID)
+}
+
+func (s *playlistServiceImpl) RemoveVideo(ctx context.Context, userID, playlistID, videoID uuid.UUID) error {
+	if _, err := s.getOwnedPlaylist(ctx, userID, playlistID); err != nil {
+		return err
+	}
+
+	return s.playlistRepo.RemoveItem(ctx, playlistID, videoID)
+}
+
+func (s *playlistServiceImpl) ReorderVideos(ctx context.Context, userID, playlistID uuid.UUID, req *dto.ReorderPlaylistItemsRequest) error {
+	if _, err := s.getOwnedPlaylist(ctx, userID, playlistID); err != nil {
+		return err
+	}
+
+	// The new order must contain exactly the videos currently in the playlist
+	current, err := s.playlistRepo.GetVideoIDs(ctx, playlistID)
+	if err != nil {
+		return err
+	}
+	if len(current) != len(req.VideoIDs) {
+		return errors.New("videoIds must list every video in the playlist exactly once")
+	}
+
+	remaining := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range req.VideoIDs {
+		if !remaining[id] {
+			return errors.New("videoIds must list every video in the playlist exactly once")
+		}
+		delete(remaining, id)
+	}
+
+	return s.playlistRepo.ReorderItems(ctx, playlistID, req.VideoIDs)
+}
+
+func (s *playlistServiceImpl) GetPlaylistsForVideo(ctx context.Context, userID, videoID uuid.UUID) ([]dto.VideoPlaylistStatus, error) {
+	playlists, _, err := s.playlistRepo.FindByUserID(ctx, userID, false, 0, maxPlaylistsPerVideo)
+	if err != nil {
+		return nil, err
+	}
+
+	containing, err := s.playlistRepo.FindIDsContainingVideo(ctx, userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	contains := make(map[uuid.UUID]bool, len(containing))
+	for _, id := range containing {
+		contains[id] = true
+	}
+
+	statuses := make([]dto.VideoPlaylistStatus, len(playlists))
+	for i, playlist := range playlists {
+		statuses[i] = dto.VideoPlaylistStatus{
+			PlaylistResponse: *dto.PlaylistToPlaylistResponse(playlist),
+			ContainsVideo:    contains[playlist.ID],
+		}
+	}
+
+	return statuses, nil
+}
+
+// Helper methods
+func (s *playlistServiceImpl) getOwnedPlaylist(ctx context.Context, userID, playlistID uuid.UUID) (*models.Playlist, error) {
+	playlist, err := s.playlistRepo.GetByID(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if playlist.UserID != userID {
+		return nil, errors.New("you don't have permission to modify this playlist")
+	}
+
+	return playlist, nil
+}
+
+
@@ -0,0 +1,335 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/websocket"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const closeDuePollsBatch = 100
+
+type pollServiceImpl struct {
+	pollRepo     repositories.PollRepository
+	topicRepo    repositories.TopicRepository
+	forumService services.ForumService
+}
+
+func NewPollService(
+	pollRepo repositories.PollRepository,
+	topicRepo repositories.TopicRepository,
+	forumService services.ForumService,
+) services.PollService {
+	return &pollServiceImpl{
+		pollRepo:     pollRepo,
+		topicRepo:    topicRepo,
+		forumService: forumService,
+	}
+}
+
+func (s *pollServiceImpl) GetPoll(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID) (*dto.PollResponse, error) {
+	if _, err := s.getTopic(ctx, topicID, viewerID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	poll, err := s.pollRepo.GetByTopicID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pollResponse(ctx, s.pollRepo, poll, viewerID)
+}
+
+func (s *pollServiceImpl) Vote(ctx context.Context, topicID, userID uuid.UUID, req *dto.VotePollRequest) (*dto.PollResponse, error) {
+	topic, err := s.getTopic(ctx, topicID, &userID, models.ForumActionReply)
+	if err != nil {
+		return nil, err
+	}
+	if topic.IsLocked {
+		return nil, errors.New("topic is locked")
+	}
+
+	poll, err := s.pollRepo.GetByTopicID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.IsClosed(time.Now()) {
+		return nil, errors.New("poll is closed")
+	}
+
+	optionIDs, err := pollOptionIDs(poll, req.OptionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pollRepo.CastVote(ctx, poll.ID, userID, optionIDs, poll.AllowVoteChange); err != nil {
+		return nil, err
+	}
+
+	return s.afterVote(ctx, poll.ID, userID)
+}
+
+func (s *pollServiceImpl) RetractVote(ctx context.Context, topicID, userID uuid.UUID) (*dto.PollResponse, error) {
+	if _, err := s.getTopic(ctx, topicID, &userID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	poll, err := s.pollRepo.GetByTopicID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if !poll.AllowVoteChange {
+		return nil, errors.New("votes on this poll cannot be changed")
+	}
+
+	if err := s.pollRepo.RetractVote(ctx, poll.ID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.afterVote(ctx, poll.ID, userID)
+}
+
+// afterVote reloads the tally, pushes it to everyone watching the topic and returns the voter's view
+func (s *pollServiceImpl) afterVote(ctx context.Context, pollID, userID uuid.UUID) (*dto.PollResponse, error) {
+	poll, err := s.pollRepo.GetByID(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastPollResults(poll, "poll_updated")
+	return pollResponse(ctx, s.pollRepo, poll, &userID)
+}
+
+func (s *pollServiceImpl) GetVoters(ctx context.Context, topicID, optionID uuid.UUID, viewerID *uuid.UUID, page, limit int) (*dto.PollVoterListResponse, error) {
+	if _, err := s.getTopic(ctx, topicID, viewerID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	poll, err := s.pollRepo.GetByTopicID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.IsAnonymous {
+		return nil, errors.New("votes on this poll are anonymous")
+	}
+	if !hasPollOption(poll, optionID) {
+		return nil, errors.New("poll option not found")
+	}
+
+	page, limit = dto.NormalizePage(page, limit)
+	offset := (page - 1) * limit
+	votes, total, err := s.pollRepo.GetVoters(ctx, optionID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	voters := make([]dto.PollVoterResponse, len(votes))
+	for i, vote := range votes {
+		voters[i] = dto.PollVoteToPollVoterResponse(vote)
+	}
+
+	return &dto.PollVoterListResponse{
+		Voters: voters,
+		Meta:   dto.NewPaginationMeta(total, offset, limit),
+	}, nil
+}
+
+func (s *pollServiceImpl) ClosePoll(ctx context.Context, topicID, userID uuid.UUID) (*dto.PollResponse, error) {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return nil, errors.New("topic not found")
+	}
+
+	if topic.UserID != userID {
+		canModerate, err := s.forumService.CanModerate(ctx, topic.ForumID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !canModerate {
+			return nil, errors.New("you don't have permission to close this poll")
+		}
+	}
+
+	poll, err := s.pollRepo.GetByTopicID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.ClosedAt != nil {
+		return nil, errors.New("poll is already closed")
+	}
+
+	if err := s.close(ctx, poll); err != nil {
+		return nil, err
+	}
+
+	return pollResponse(ctx, s.pollRepo, poll, &userID)
+}
+
+func (s *pollServiceImpl) CloseDuePolls(ctx context.Context) (int, error) {
+	closed := 0
+	for {
+		polls, err := s.pollRepo.FindDueForClose(ctx, time.Now(), closeDuePollsBatch)
+		if err != nil {
+			return closed, err
+		}
+
+		for _, poll := range polls {
+			full, err := s.pollRepo.GetByID(ctx, poll.ID)
+			if err != nil {
+				return closed, err
+			}
+			if err := s.close(ctx, full); err != nil {
+				return closed, err
+			}
+			closed++
+		}
+
+		if len(polls) < closeDuePollsBatch {
+			return closed, nil
+		}
+	}
+}
+
+// close stamps closedAt and announces the final results; closing twice is a no-op
+func (s *pollServiceImpl) close(ctx context.Context, poll *models.Poll) error {
+	now := time.Now()
+	ok, err := s.pollRepo.Close(ctx, poll.ID, now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	poll.ClosedAt = &now
+	broadcastPollResults(poll, "poll_closed")
+	log.Printf("Poll closed: ID=%s, TopicID=%s", poll.ID, poll.TopicID)
+	return nil
+}
+
+func (s *pollServiceImpl) getTopic(ctx context.Context, topicID uuid.UUID, userID *uuid.UUID, action models.ForumAction) (*models.Topic, error) {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return nil, errors.New("topic not found")
+	}
+
+	if err := s.forumService.CheckAccess(ctx, topic.ForumID, userID, action); err != nil {
+		return nil, err
+	}
+	return topic, nil
+}
+
+// pollResponse adds the viewer's own choices to the tally
+func pollResponse(ctx context.Context, pollRepo repositories.PollRepository, poll *models.Poll, viewerID *uuid.UUID) (*dto.PollResponse, error) {
+	resp := dto.PollToPollResponse(poll)
+	if viewerID == nil {
+		return resp, nil
+	}
+
+	optionIDs, err := pollRepo.GetUserOptionIDs(ctx, poll.ID, *viewerID)
+	if err != nil {
+		return nil, err
+	}
+	resp.HasVoted = len(optionIDs) > 0
+	resp.MyVotes = optionIDs
+	return resp, nil
+}
+
+func broadcastPollResults(poll *models.Poll, messageType string) {
+	websocket.Manager.BroadcastToRoom(topicRoom(poll.TopicID), messageType, dto.PollToPollResultsResponse(poll))
+}
+
+// topicRoom is the websocket room of clients viewing a topic
+func topicRoom(topicID uuid.UUID) string {
+	return websocket.TopicRoom(topicID)
+}
+
+// pollOptionIDs checks a ballot against the poll's rules and returns the chosen options without duplicates
+func pollOptionIDs(poll *models.Poll, values []string) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(values))
+	optionIDs := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		optionID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errors.New("invalid option ID format")
+		}
+		if !hasPollOption(poll, optionID) {
+			return nil, errors.New("poll option not found")
+		}
+		if !seen[optionID] {
+			seen[optionID] = true
+			optionIDs = append(optionIDs, optionID)
+		}
+	}
+
+	if !poll.AllowMultiple && len(optionIDs) > 1 {
+		return nil, errors.New("this poll allows only one choice")
+	}
+	if len(optionIDs) > poll.MaxChoices {
+		return nil, errors.New("too many choices for this poll")
+	}
+	return optionIDs, nil
+}
+
+func hasPollOption(poll *models.Poll, optionID uuid.UUID) bool {
+	for _, option := range poll.Options {
+		if option.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// newPoll validates a CreatePollRequest and builds the poll saved together with the topic
+func newPoll(topicID uuid.UUID, req *dto.CreatePollRequest) (*models.Poll, error) {
+	if req.ClosesAt != nil && !req.ClosesAt.After(time.Now()) {
+		return nil, errors.New("poll close time must be in the future")
+	}
+
+	seen := make(map[string]bool, len(req.Options))
+	options := make([]models.PollOption, 0, len(req.Options))
+	for _, text := range req.Options {
+		text = strings.TrimSpace(text)
+		key := strings.ToLower(text)
+		if text == "" {
+			return nil, errors.New("poll options cannot be empty")
+		}
+		if seen[key] {
+			return nil, errors.New("poll options must be unique")
+		}
+		seen[key] = true
+		options = append(options, models.PollOption{
+			ID:       uuid.New(),
+			Text:     text,
+			Position: len(options),
+		})
+	}
+
+	maxChoices := 1
+	if req.AllowMultiple {
+		maxChoices = len(options)
+		if req.MaxChoices > 0 && req.MaxChoices < maxChoices {
+			maxChoices = req.MaxChoices
+		}
+	}
+
+	return &models.Poll{
+		ID:              uuid.New(),
+		TopicID:         topicID,
+		Question:        strings.TrimSpace(req.Question),
+		AllowMultiple:   req.AllowMultiple,
+		MaxChoices:      maxChoices,
+		IsAnonymous:     req.IsAnonymous,
+		AllowVoteChange: req.AllowVoteChange,
+		ClosesAt:        req.ClosesAt,
+		Options:         options,
+	}, nil
+}
@@ -0,0 +1,313 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueueOptions tunes the worker pool and the retry schedule of the background task queue
+type QueueOptions struct {
+	Workers      int
+	PollInterval time.Duration // idle workers look for due tasks this often
+	MaxAttempts  int
+	BaseBackoff  time.Duration // delay before the first retry; doubled for every further attempt
+	MaxBackoff   time.Duration
+	TaskTimeout  time.Duration // per attempt
+	StaleAfter   time.Duration // tasks processing for longer are claimed again
+}
+
+type queueServiceImpl struct {
+	taskRepo repositories.BackgroundTaskRepository
+	config   QueueOptions
+	workerID string
+
+	handlers   map[string]services.TaskHandler
+	handlersMu sync.RWMutex
+
+	wake   chan struct{} // nudges an idle worker when a task is queued on this replica
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewQueueService(taskRepo repositories.BackgroundTaskRepository, options QueueOptions) services.QueueService {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "node"
+	}
+
+	return &queueServiceImpl{
+		taskRepo: taskRepo,
+		config:   options,
+		workerID: fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8]),
+		handlers: make(map[string]services.TaskHandler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (s *queueServiceImpl) NewTask(task services.BackgroundTask, idempotencyKey string) (*models.BackgroundTask, error) {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.BackgroundTask{
+		ID:          uuid.New(),
+		Type:        task.TaskType(),
+		Payload:     string(payload),
+		Status:      models.BackgroundTaskPending,
+		MaxAttempts: s.config.MaxAttempts,
+		RunAt:       time.Now(),
+	}
+	if idempotencyKey != "" {
+		record.IdempotencyKey = &idempotencyKey
+	}
+	return record, nil
+}
+
+func (s *queueServiceImpl) Enqueue(ctx context.Context, task services.BackgroundTask, idempotencyKey string) error {
+	record, err := s.NewTask(task, idempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	queued, err := s.taskRepo.Enqueue(ctx, record)
+	if err != nil {
+		return err
+	}
+	if queued {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *queueServiceImpl) RegisterHandler(taskType string, handler services.TaskHandler) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	s.handlers[taskType] = handler
+}
+
+func (s *queueServiceImpl) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for i := 0; i < s.config.Workers; i++ {
+		s.wg.Add(1)
+		go s.work(ctx)
+	}
+	log.Printf("Background task workers started: %d (worker %s)", s.config.Workers, s.workerID)
+}
+
+// Stop lets running tasks finish; tasks still running when ctx ends are claimed again later
+func (s *queueServiceImpl) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *queueServiceImpl) work(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		tasks, err := s.taskRepo.ClaimDue(ctx, s.workerID, 1, time.Now().Add(-s.config.StaleAfter))
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Warning: Claiming background tasks failed: %v", err)
+		}
+		if len(tasks) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case <-time.After(s.config.PollInterval):
+			}
+			continue
+		}
+
+		for _, task := range tasks {
+			s.process(task)
+		}
+	}
+}
+
+// process runs one attempt and records its outcome. It uses its own context so a shutdown
+// doesn't abort a task half-way.
+func (s *queueServiceImpl) process(task *models.BackgroundTask) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.TaskTimeout)
+	defer cancel()
+
+	err := s.runHandler(ctx, task)
+	if err == nil {
+		if err := s.taskRepo.Complete(context.Background(), task, time.Now()); err != nil {
+			log.Printf("Warning: Failed to complete background task %s: %v", task.ID, err)
+		}
+		return
+	}
+
+	if task.Attempts >= task.MaxAttempts {
+		log.Printf("Background task %s (%s) failed for good after %d attempts: %v", task.ID, task.Type, task.Attempts, err)
+		if err := s.taskRepo.MarkDead(context.Background(), task, err.Error()); err != nil {
+			log.Printf("Warning: Failed to move background task %s to the dead letters: %v", task.ID, err)
+		}
+		return
+	}
+
+	runAt := time.Now().Add(s.backoff(task.Attempts))
+	log.Printf("Background task %s (%s) attempt %d failed, retrying at %s: %v", task.ID, task.Type, task.Attempts, runAt.Format(time.RFC3339), err)
+	if err := s.taskRepo.Retry(context.Background(), task, runAt, err.Error()); err != nil {
+		log.Printf("Warning: Failed to reschedule background task %s: %v", task.ID, err)
+	}
+}
+
+func (s *queueServiceImpl) runHandler(ctx context.Context, task *models.BackgroundTask) (err error) {
+	s.handlersMu.RLock()
+	handler, ok := s.handlers[task.Type]
+	s.handlersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler registered for task type %q", task.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return handler(ctx, json.RawMessage(task.Payload))
+}
+
+// backoff is the delay after the given failed attempt: BaseBackoff doubled per attempt, capped at MaxBackoff
+func (s *queueServiceImpl) backoff(attempt int) time.Duration {
+	delay := s.config.BaseBackoff
+	for i := 1; i < attempt && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxBackoff {
+		delay = s.config.MaxBackoff
+	}
+	return delay
+}
+
+func (s *queueServiceImpl) ListTasks(ctx context.Context, params *dto.BackgroundTaskListParams) (*dto.BackgroundTaskListResponse, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	filter := repositories.BackgroundTaskFilter{
+		Status: models.BackgroundTaskStatus(params.Status),
+		Type:   params.Type,
+	}
+	tasks, total, err := s.taskRepo.List(ctx, filter, params.Offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.BackgroundTaskResponse, len(tasks))
+	for i, task := range tasks {
+		responses[i] = *dto.BackgroundTaskToBackgroundTaskResponse(task)
+	}
+
+	return &dto.BackgroundTaskListResponse{
+		Tasks: responses,
+		Meta:  dto.NewPaginationMeta(total, params.Offset, limit),
+	}, nil
+}
+
+func (s *queueServiceImpl) GetTask(ctx context.Context, taskID uuid.UUID) (*dto.BackgroundTaskResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+	return dto.BackgroundTaskToBackgroundTaskResponse(task), nil
+}
+
+func (s *queueServiceImpl) GetStats(ctx context.Context) (*dto.BackgroundTaskStatsResponse, error) {
+	counts, err := s.taskRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BackgroundTaskStatsResponse{
+		Pending:    counts[models.BackgroundTaskPending],
+		Processing: counts[models.BackgroundTaskProcessing],
+		Completed:  counts[models.BackgroundTaskCompleted],
+		Dead:       counts[models.BackgroundTaskDead],
+		Workers:    s.config.Workers,
+	}, nil
+}
+
+func (s *queueServiceImpl) ReplayTasks(ctx context.Context, req *dto.ReplayTasksRequest) (*dto.ReplayTasksResponse, error) {
+	var replayed int64
+	var err error
+	if len(req.TaskIDs) > 0 {
+		replayed, err = s.taskRepo.Replay(ctx, req.TaskIDs)
+	} else {
+		replayed, err = s.taskRepo.ReplayDead(ctx, req.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if replayed > 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return &dto.ReplayTasksResponse{Replayed: replayed}, nil
+}
+
+func (s *queueServiceImpl) PurgeCompleted(ctx context.Context, before time.Time) (int64, error) {
+	return s.taskRepo.DeleteCompletedBefore(ctx, before)
+}
+
+// queuedTask pairs a task with its idempotency key (none when empty)
+type queuedTask struct {
+	task services.BackgroundTask
+	key  string
+}
+
+// newTasks builds the records of the side effects of a change, for its repository to insert
+// in the same transaction
+func newTasks(queueService services.QueueService, tasks ...queuedTask) ([]*models.BackgroundTask, error) {
+	records := make([]*models.BackgroundTask, len(tasks))
+	for i, task := range tasks {
+		record, err := queueService.NewTask(task.task, task.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue %s task: %v", task.task.TaskType(), err)
+		}
+		records[i] = record
+	}
+	return records, nil
+}
@@ -0,0 +1,164 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type readServiceImpl struct {
+	readRepo     repositories.ReadRepository
+	topicRepo    repositories.TopicRepository
+	replyRepo    repositories.ReplyRepository
+	userRepo     repositories.UserRepository
+	forumService services.ForumService
+}
+
+func NewReadService(
+	readRepo repositories.ReadRepository,
+	topicRepo repositories.TopicRepository,
+	replyRepo repositories.ReplyRepository,
+	userRepo repositories.UserRepository,
+	forumService services.ForumService,
+) services.ReadService {
+	return &readServiceImpl{
+		readRepo:     readRepo,
+		topicRepo:    topicRepo,
+		replyRepo:    replyRepo,
+		userRepo:     userRepo,
+		forumService: forumService,
+	}
+}
+
+func (s *readServiceImpl) MarkTopicRead(ctx context.Context, userID, topicID uuid.UUID, req *dto.MarkTopicReadRequest) error {
+	if _, err := s.getVisibleTopic(ctx, userID, topicID); err != nil {
+		return err
+	}
+
+	if req.ReplyID == nil {
+		return s.readRepo.MarkTopicRead(ctx, userID, topicID, nil, time.Now())
+	}
+
+	reply, err := s.replyRepo.GetByID(ctx, *req.ReplyID)
+	if err != nil || reply.TopicID != topicID {
+		return errors.New("reply not found")
+	}
+
+	return s.readRepo.MarkTopicRead(ctx, userID, topicID, &reply.ID, reply.CreatedAt)
+}
+
+func (s *readServiceImpl) MarkForumRead(ctx context.Context, userID, forumID uuid.UUID) error {
+	if err := s.forumService.CheckAccess(ctx, forumID, &userID, models.ForumActionView); err != nil {
+		return err
+	}
+
+	return s.readRepo.MarkForumRead(ctx, userID, forumID, time.Now())
+}
+
+func (s *readServiceImpl) GetFirstUnread(ctx context.Context, userID, topicID uuid.UUID) (*dto.FirstUnreadResponse, error) {
+	if _, err := s.getVisibleTopic(ctx, userID, topicID); err != nil {
+		return nil, err
+	}
+
+	baseline, err := s.baseline(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.FirstUnreadResponse{}
+	states, err := s.readRepo.GetTopicReadStates(ctx, userID, baseline, []uuid.UUID{topicID})
+	if err != nil {
+		return nil, err
+	}
+	if len(states) > 0 {
+		resp.NewReplyCount = states[0].NewReplyCount
+		resp.LastReadAt = states[0].LastReadAt
+		resp.LastReadReplyID = states[0].LastReadReplyID
+	}
+
+	reply, err := s.readRepo.FindFirstUnreadReply(ctx, userID, topicID, baseline)
+	if err != nil {
+		if err.Error() == "no unread replies" {
+			return resp, nil
+		}
+		return nil, err
+	}
+
+	position, err := s.readRepo.CountRepliesBefore(ctx, topicID, reply.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.HasUnread = true
+	resp.ReplyID = &reply.ID
+	resp.ParentID = reply.ParentID
+	resp.Position = position
+	return resp, nil
+}
+
+func (s *readServiceImpl) FillTopicReadState(ctx context.Context, viewerID *uuid.UUID, topics []*dto.TopicResponse) {
+	if viewerID == nil || len(topics) == 0 {
+		return
+	}
+
+	baseline, err := s.baseline(ctx, *viewerID)
+	if err != nil {
+		return
+	}
+
+	topicIDs := make([]uuid.UUID, len(topics))
+	for i, topic := range topics {
+		topicIDs[i] = topic.ID
+	}
+
+	states, err := s.readRepo.GetTopicReadStates(ctx, *viewerID, baseline, topicIDs)
+	if err != nil {
+		return
+	}
+
+	byTopic := make(map[uuid.UUID]repositories.TopicReadState, len(states))
+	for _, state := range states {
+		byTopic[state.TopicID] = state
+	}
+
+	for _, topic := range topics {
+		state, ok := byTopic[topic.ID]
+		if !ok {
+			continue
+		}
+		topic.IsUnread = state.IsUnread
+		topic.NewReplyCount = state.NewReplyCount
+		topic.LastReadAt = state.LastReadAt
+		topic.LastReadReplyID = state.LastReadReplyID
+	}
+}
+
+// Helper methods
+
+func (s *readServiceImpl) getVisibleTopic(ctx context.Context, userID, topicID uuid.UUID) (*models.Topic, error) {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return nil, errors.New("topic not found")
+	}
+
+	if err := s.forumService.CheckAccess(ctx, topic.ForumID, &userID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	return topic, nil
+}
+
+// baseline is when the user joined; older activity is never shown as new
+func (s *readServiceImpl) baseline(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return user.CreatedAt, nil
+}
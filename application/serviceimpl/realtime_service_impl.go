@@ -0,0 +1,80 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/websocket"
+
+	"github.com/google/uuid"
+)
+
+var (
+	errRoomLoginRequired = errors.New("login required to join this room")
+	errRoomForbidden     = errors.New("you don't have access to this room")
+)
+
+type realtimeServiceImpl struct {
+	topicRepo    repositories.TopicRepository
+	forumService services.ForumService
+	videoService services.VideoService
+}
+
+func NewRealtimeService(
+	topicRepo repositories.TopicRepository,
+	forumService services.ForumService,
+	videoService services.VideoService,
+) services.RealtimeService {
+	return &realtimeServiceImpl{
+		topicRepo:    topicRepo,
+		forumService: forumService,
+		videoService: videoService,
+	}
+}
+
+// AuthorizeRoom applies the same visibility as the REST API: a topic room needs view access to
+// the topic's forum, a video room access to the video's media, a user room is the user's own
+// and the admin room is for admins. Admins may join any existing room.
+func (s *realtimeServiceImpl) AuthorizeRoom(ctx context.Context, userID *uuid.UUID, role string, roomID string) error {
+	roomType, id, err := websocket.ParseRoom(roomID)
+	if err != nil {
+		return err
+	}
+	isAdmin := userID != nil && role == "admin"
+
+	switch roomType {
+	case websocket.RoomTypeTopic:
+		topic, err := s.topicRepo.GetByID(ctx, id)
+		if err != nil {
+			return errors.New("topic not found")
+		}
+		return s.forumService.CheckAccess(ctx, topic.ForumID, userID, models.ForumActionView)
+
+	case websocket.RoomTypeVideo:
+		_, err := s.videoService.GetVideoMedia(ctx, id, userID, isAdmin)
+		return err
+
+	case websocket.RoomTypeUser:
+		if userID == nil {
+			return errRoomLoginRequired
+		}
+		if *userID != id && !isAdmin {
+			return errRoomForbidden
+		}
+		return nil
+
+	case websocket.RoomAdmin:
+		if userID == nil {
+			return errRoomLoginRequired
+		}
+		if !isAdmin {
+			return errRoomForbidden
+		}
+		return nil
+	}
+
+	return websocket.ErrInvalidRoom
+}
@@ -2,30 +2,42 @@ package serviceimpl
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"log"
+	"strings"
 	"time"
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/websocket"
+	"gofiber-social/pkg/markdown"
 	"github.com/google/uuid"
 )
 
 type ReplyServiceImpl struct {
-	replyRepo           repositories.ReplyRepository
-	topicRepo           repositories.TopicRepository
-	notificationService services.NotificationService
+	replyRepo       repositories.ReplyRepository
+	topicRepo       repositories.TopicRepository
+	forumService    services.ForumService
+	contentService  services.ContentService
+	queueService    services.QueueService
 }
 
 func NewReplyService(
 	replyRepo repositories.ReplyRepository,
 	topicRepo repositories.TopicRepository,
-	notificationService services.NotificationService,
+	forumService services.ForumService,
+	contentService services.ContentService,
+	queueService services.QueueService,
 ) services.ReplyService {
 	return &ReplyServiceImpl{
-		replyRepo:           replyRepo,
-		topicRepo:           topicRepo,
-		notificationService: notificationService,
+		replyRepo:       replyRepo,
+		topicRepo:       topicRepo,
+		forumService:    forumService,
+		contentService:  contentService,
+		queueService:    queueService,
 	}
 }
 
@@ -41,6 +53,11 @@ func (s *ReplyServiceImpl) CreateReply(ctx context.Context, topicID, userID uuid
 		return nil, errors.New("topic is locked, cannot reply")
 	}
 
+	// ตรวจสิทธิ์ตอบกระทู้ตามกฎของ forum
+	if err := s.forumService.CheckAccess(ctx, topic.ForumID, &userID, models.ForumActionReply); err != nil {
+		return nil, err
+	}
+
 	// ตรวจสอบ parent reply (ถ้ามี)
 	var parentID *uuid.UUID
 	if req.ParentID != nil {
@@ -58,32 +75,78 @@ func (s *ReplyServiceImpl) CreateReply(ctx context.Context, topicID, userID uuid
 		parentID = &parsed
 	}
 
+	// ตรวจสอบ reply ที่ถูกอ้างอิง (ถ้ามี)
+	var quoteID *uuid.UUID
+	if req.QuoteID != nil {
+		parsed, err := uuid.Parse(*req.QuoteID)
+		if err != nil {
+			return nil, errors.New("invalid quote ID format")
+		}
+		quoted, err := s.replyRepo.GetByID(ctx, parsed)
+		if err != nil {
+			return nil, errors.New("quoted reply not found")
+		}
+		if quoted.TopicID != topicID {
+			return nil, errors.New("quoted reply does not belong to this topic")
+		}
+		quoteID = &parsed
+	}
+
+	rendered, err := s.contentService.Render(ctx, userID, req.Content)
+	if err != nil {
+		return nil, err
+	}
+
 	reply := &models.Reply{
 		ID:        uuid.New(),
 		TopicID:   topicID,
 		UserID:    userID,
 		ParentID:  parentID,
+		QuoteID:   quoteID,
 		Content:   req.Content,
+		ContentHTML: rendered.HTML,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := s.replyRepo.Create(ctx, reply); err != nil {
+	// Notify the topic author and the watchers in the background; the reply, the topic's reply
+	// count and the tasks are saved together
+	tasks, err := newTasks(s.queueService,
+		queuedTask{dto.NotifyReplyTask{ReplyID: reply.ID}, "reply:" + reply.ID.String()},
+		queuedTask{dto.WatchReplyTask{ReplyID: reply.ID}, "reply-watch:" + reply.ID.String()},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replyRepo.Create(ctx, reply, tasks...); err != nil {
 		return nil, err
 	}
 
-	// Create notification for topic reply
-	go func() {
-		_ = s.notificationService.CreateTopicReplyNotification(context.Background(), topicID, userID)
-	}()
+	s.contentService.AttachEmbeds(ctx, userID, rendered, models.FileReferenceReplyContent, reply.ID)
+	s.contentService.PrefetchLinkPreviews(linksToPreview(rendered.Links))
 
-	// เพิ่ม reply count
-	s.topicRepo.IncrementReplyCount(ctx, topicID)
+	// ส่ง reply ใหม่ให้คนที่เปิดกระทู้อยู่ (โหลด author มาด้วย); the send itself is in the background
+	if created, err := s.replyRepo.GetByID(ctx, reply.ID); err == nil {
+		broadcastReply(created, websocket.EventReplyCreated)
+	} else {
+		log.Printf("Warning: Failed to load reply %s for broadcast: %v", reply.ID, err)
+	}
 
 	return reply, nil
 }
 
-func (s *ReplyServiceImpl) GetReplies(ctx context.Context, topicID uuid.UUID, offset, limit int) ([]*dto.ReplyResponse, int64, error) {
+func (s *ReplyServiceImpl) GetReplies(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID, offset, limit int) ([]*dto.ReplyResponse, int64, error) {
+	// A merged topic's ID lists the replies of the topic it was merged into
+	topic, err := resolveTopic(ctx, s.topicRepo, topicID)
+	if err != nil {
+		return nil, 0, err
+	}
+	topicID = topic.ID
+
+	if err := s.forumService.CheckAccess(ctx, topic.ForumID, viewerID, models.ForumActionView); err != nil {
+		return nil, 0, err
+	}
+
 	replies, err := s.replyRepo.GetByTopicID(ctx, topicID, offset, limit)
 	if err != nil {
 		return nil, 0, err
@@ -95,9 +158,16 @@ func (s *ReplyServiceImpl) GetReplies(ctx context.Context, topicID uuid.UUID, of
 	}
 
 	responses := make([]*dto.ReplyResponse, len(replies))
+	var targets []previewTarget
 	for i, reply := range replies {
 		responses[i] = dto.ReplyToReplyResponse(reply, true)
+		if topic.AcceptedReplyID != nil {
+			responses[i].IsAccepted = reply.ID == *topic.AcceptedReplyID
+			markAcceptedReply(responses[i].Replies, *topic.AcceptedReplyID)
+		}
+		targets = replyPreviewTargets(responses[i], targets)
 	}
+	fillLinkPreviews(ctx, s.contentService, targets)
 
 	return responses, total, nil
 }
@@ -112,13 +182,42 @@ func (s *ReplyServiceImpl) UpdateReply(ctx context.Context, replyID, userID uuid
 		return nil, errors.New("unauthorized to update this reply")
 	}
 
+	// เนื้อหาเหมือนเดิม ไม่นับเป็นการแก้ไข
+	if reply.Content == req.Content {
+		return reply, nil
+	}
+
+	rendered, err := s.contentService.Render(ctx, userID, req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := reply.Content
+	now := time.Now()
 	reply.Content = req.Content
-	reply.UpdatedAt = time.Now()
+	reply.ContentHTML = rendered.HTML
+	reply.UpdatedAt = now
+	reply.EditCount++
+	reply.EditedAt = &now
 
-	if err := s.replyRepo.Update(ctx, replyID, reply); err != nil {
+	revision := revisionEdit(&dto.ContentEdit{
+		ContentType: models.RevisionContentReply,
+		ContentID:   replyID,
+		AuthorID:    reply.UserID,
+		PostedAt:    reply.CreatedAt,
+		EditorID:    userID,
+		Previous:    dto.RevisionSnapshot{Content: previous},
+		Current:     dto.RevisionSnapshot{Content: reply.Content},
+	})
+	if err := s.replyRepo.Edit(ctx, reply, revision); err != nil {
 		return nil, err
 	}
 
+	s.contentService.AttachEmbeds(ctx, userID, rendered, models.FileReferenceReplyContent, replyID)
+	s.contentService.PrefetchLinkPreviews(linksToPreview(rendered.Links))
+
+	broadcastReply(reply, websocket.EventReplyUpdated)
+
 	return reply, nil
 }
 
@@ -128,14 +227,26 @@ func (s *ReplyServiceImpl) DeleteReply(ctx context.Context, replyID, userID uuid
 		return errors.New("reply not found")
 	}
 
+	// เจ้าของหรือ moderator ของ forum ลบได้
 	if reply.UserID != userID {
-		return errors.New("unauthorized to delete this reply")
+		canModerate, err := s.forumService.CanModerate(ctx, reply.Topic.ForumID, userID)
+		if err != nil {
+			return err
+		}
+		if !canModerate {
+			return errors.New("unauthorized to delete this reply")
+		}
 	}
 
 	// ลด reply count
 	s.topicRepo.DecrementReplyCount(ctx, reply.TopicID)
+	s.clearAcceptedAnswer(ctx, reply)
 
-	return s.replyRepo.Delete(ctx, replyID)
+	if err := s.replyRepo.Delete(ctx, replyID); err != nil {
+		return err
+	}
+	broadcastReplyDeleted(reply)
+	return nil
 }
 
 func (s *ReplyServiceImpl) DeleteReplyByAdmin(ctx context.Context, replyID uuid.UUID) error {
@@ -145,5 +256,372 @@ func (s *ReplyServiceImpl) DeleteReplyByAdmin(ctx context.Context, replyID uuid.
 	}
 
 	s.topicRepo.DecrementReplyCount(ctx, reply.TopicID)
-	return s.replyRepo.Delete(ctx, replyID)
+	s.clearAcceptedAnswer(ctx, reply)
+	if err := s.replyRepo.Delete(ctx, replyID); err != nil {
+		return err
+	}
+	broadcastReplyDeleted(reply)
+	return nil
+}
+
+// clearAcceptedAnswer un-solves the topic when its accepted answer is deleted
+func (s *ReplyServiceImpl) clearAcceptedAnswer(ctx context.Context, reply *models.Reply) {
+	if reply.Topic.AcceptedReplyID != nil && *reply.Topic.AcceptedReplyID == reply.ID {
+		if err := s.topicRepo.SetAcceptedReply(ctx, reply.TopicID, nil); err == nil {
+			reply.Topic.AcceptedReplyID = nil
+			broadcastTopicStatus(&reply.Topic)
+		}
+	}
+}
+
+// Threads
+
+const (
+	defaultThreadDepth    = 3
+	defaultThreadLimit    = 20
+	defaultThreadChildren = 5
+	maxReplyAncestors     = 100 // guards the permalink walk against broken parent chains
+	quoteExcerptLength    = 200
+)
+
+type threadOptions struct {
+	sort     models.ReplySort
+	depth    int
+	limit    int
+	children int
+	after    *repositories.ReplyCursor
+	offset   int
+	accepted *uuid.UUID // accepted answer of the topic, flagged wherever it shows up
+}
+
+func (s *ReplyServiceImpl) GetThread(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID, params *dto.ReplyThreadParams) (*dto.ReplyThreadResponse, error) {
+	// A merged topic's ID lists the replies of the topic it was merged into
+	topic, err := resolveTopic(ctx, s.topicRepo, topicID)
+	if err != nil {
+		return nil, err
+	}
+	topicID = topic.ID
+
+	if err := s.forumService.CheckAccess(ctx, topic.ForumID, viewerID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	opts, err := newThreadOptions(params)
+	if err != nil {
+		return nil, err
+	}
+	opts.accepted = topic.AcceptedReplyID
+
+	return s.loadThread(ctx, topicID, nil, opts)
 }
+
+func (s *ReplyServiceImpl) GetChildReplies(ctx context.Context, replyID uuid.UUID, viewerID *uuid.UUID, params *dto.ReplyThreadParams) (*dto.ReplyThreadResponse, error) {
+	reply, err := s.replyRepo.GetByID(ctx, replyID)
+	if err != nil {
+		return nil, errors.New("reply not found")
+	}
+
+	if err := s.forumService.CheckAccess(ctx, reply.Topic.ForumID, viewerID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	opts, err := newThreadOptions(params)
+	if err != nil {
+		return nil, err
+	}
+	opts.accepted = reply.Topic.AcceptedReplyID
+
+	return s.loadThread(ctx, reply.TopicID, &replyID, opts)
+}
+
+func (s *ReplyServiceImpl) GetReplyContext(ctx context.Context, replyID uuid.UUID, viewerID *uuid.UUID, params *dto.ReplyThreadParams) (*dto.ReplyContextResponse, error) {
+	reply, err := s.replyRepo.GetByID(ctx, replyID)
+	if err != nil {
+		return nil, errors.New("reply not found")
+	}
+
+	if err := s.forumService.CheckAccess(ctx, reply.Topic.ForumID, viewerID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	opts, err := newThreadOptions(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// ไล่ขึ้นไปหา reply ระดับบนสุด
+	chain := []*models.Reply{reply}
+	for current := reply; current.ParentID != nil; {
+		if len(chain) > maxReplyAncestors {
+			return nil, errors.New("reply thread is too deep")
+		}
+		parent, err := s.replyRepo.GetByID(ctx, *current.ParentID)
+		if err != nil {
+			return nil, errors.New("reply is part of a deleted thread")
+		}
+		chain = append([]*models.Reply{parent}, chain...)
+		current = parent
+	}
+	root := chain[0]
+
+	position, err := s.replyRepo.CountSiblingsBefore(ctx, root, opts.sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reply itself comes with its children, every ancestor only with the branch leading to it
+	nodes, err := s.expandReplies(ctx, []*models.Reply{reply}, opts)
+	if err != nil {
+		return nil, err
+	}
+	node := nodes[0]
+
+	ancestorIDs := make([]uuid.UUID, 0, len(chain)-1)
+	for _, ancestor := range chain[:len(chain)-1] {
+		ancestorIDs = append(ancestorIDs, ancestor.ID)
+	}
+	childCounts, err := s.replyRepo.CountChildren(ctx, ancestorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(chain) - 2; i >= 0; i-- {
+		parent := dto.ReplyToReplyResponse(chain[i], false)
+		parent.ReplyCount = childCounts[chain[i].ID]
+		parent.HasMoreReplies = parent.ReplyCount > 1
+		parent.Replies = []dto.ReplyResponse{node}
+		node = *parent
+	}
+
+	thread := []dto.ReplyResponse{node}
+	if reply.Topic.AcceptedReplyID != nil {
+		markAcceptedReply(thread, *reply.Topic.AcceptedReplyID)
+	}
+	if err := s.decorateReplies(ctx, thread); err != nil {
+		return nil, err
+	}
+
+	path := make([]uuid.UUID, len(chain))
+	for i, r := range chain {
+		path[i] = r.ID
+	}
+
+	return &dto.ReplyContextResponse{
+		TopicID: reply.TopicID,
+		ReplyID: reply.ID,
+		RootID:  root.ID,
+		Path:    path,
+		Sort:    string(opts.sort),
+		Page:    int(position)/opts.limit + 1,
+		Limit:   opts.limit,
+		Thread:  thread[0],
+	}, nil
+}
+
+// loadThread returns one page of siblings with their replies expanded down to the requested depth
+func (s *ReplyServiceImpl) loadThread(ctx context.Context, topicID uuid.UUID, parentID *uuid.UUID, opts threadOptions) (*dto.ReplyThreadResponse, error) {
+	replies, err := s.replyRepo.GetThreadPage(ctx, topicID, parentID, repositories.ReplyThreadQuery{
+		Sort:   opts.sort,
+		After:  opts.after,
+		Offset: opts.offset,
+		Limit:  opts.limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(replies) > opts.limit
+	if hasMore {
+		replies = replies[:opts.limit]
+	}
+
+	nodes, err := s.expandReplies(ctx, replies, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.accepted != nil {
+		markAcceptedReply(nodes, *opts.accepted)
+	}
+	if err := s.decorateReplies(ctx, nodes); err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ReplyThreadResponse{
+		TopicID:  topicID,
+		ParentID: parentID,
+		Sort:     string(opts.sort),
+		Replies:  nodes,
+		HasMore:  hasMore,
+	}
+	if hasMore {
+		resp.NextCursor = encodeReplyCursor(replies[len(replies)-1])
+	}
+
+	return resp, nil
+}
+
+// expandReplies loads the children of replies level by level, opts.children per reply,
+// until opts.depth levels are filled
+func (s *ReplyServiceImpl) expandReplies(ctx context.Context, replies []*models.Reply, opts threadOptions) ([]dto.ReplyResponse, error) {
+	nodes := make([]dto.ReplyResponse, len(replies))
+	level := make(map[uuid.UUID]*dto.ReplyResponse, len(replies))
+	ids := make([]uuid.UUID, len(replies))
+	for i, reply := range replies {
+		nodes[i] = *dto.ReplyToReplyResponse(reply, false)
+		level[reply.ID] = &nodes[i]
+		ids[i] = reply.ID
+	}
+
+	for depth := 1; len(ids) > 0; depth++ {
+		counts, err := s.replyRepo.CountChildren(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		var children []*models.Reply
+		if depth < opts.depth {
+			children, err = s.replyRepo.GetChildren(ctx, ids, opts.sort, opts.children)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		byParent := make(map[uuid.UUID][]*models.Reply)
+		for _, child := range children {
+			byParent[*child.ParentID] = append(byParent[*child.ParentID], child)
+		}
+
+		next := make(map[uuid.UUID]*dto.ReplyResponse)
+		var nextIDs []uuid.UUID
+		for _, id := range ids {
+			node := level[id]
+			loaded := byParent[id]
+			node.ReplyCount = counts[id]
+			node.HasMoreReplies = node.ReplyCount > int64(len(loaded))
+			if node.HasMoreReplies && len(loaded) > 0 {
+				node.NextCursor = encodeReplyCursor(loaded[len(loaded)-1])
+			}
+
+			// Allocated once so the pointers kept for the next level stay valid
+			node.Replies = make([]dto.ReplyResponse, len(loaded))
+			for i, child := range loaded {
+				node.Replies[i] = *dto.ReplyToReplyResponse(child, false)
+				next[child.ID] = &node.Replies[i]
+				nextIDs = append(nextIDs, child.ID)
+			}
+		}
+
+		level, ids = next, nextIDs
+	}
+
+	return nodes, nil
+}
+
+// decorateReplies fills quotes and link previews of a reply tree
+func (s *ReplyServiceImpl) decorateReplies(ctx context.Context, nodes []dto.ReplyResponse) error {
+	var quoteIDs []uuid.UUID
+	var targets []previewTarget
+	var walk func(nodes []dto.ReplyResponse)
+	walk = func(nodes []dto.ReplyResponse) {
+		for i := range nodes {
+			if nodes[i].QuoteID != nil {
+				quoteIDs = append(quoteIDs, *nodes[i].QuoteID)
+			}
+			targets = append(targets, previewTarget{previews: &nodes[i].LinkPreviews, links: linksToPreview(markdown.Links(nodes[i].Content))})
+			walk(nodes[i].Replies)
+		}
+	}
+	walk(nodes)
+
+	quoted, err := s.replyRepo.GetByIDsIncludingDeleted(ctx, quoteIDs)
+	if err != nil {
+		return err
+	}
+	quotes := make(map[uuid.UUID]*dto.ReplyQuoteResponse, len(quoted))
+	for _, reply := range quoted {
+		quotes[reply.ID] = replyQuote(reply)
+	}
+
+	var fill func(nodes []dto.ReplyResponse)
+	fill = func(nodes []dto.ReplyResponse) {
+		for i := range nodes {
+			if nodes[i].QuoteID != nil {
+				nodes[i].Quote = quotes[*nodes[i].QuoteID]
+			}
+			fill(nodes[i].Replies)
+		}
+	}
+	fill(nodes)
+
+	fillLinkPreviews(ctx, s.contentService, targets)
+	return nil
+}
+
+func newThreadOptions(params *dto.ReplyThreadParams) (threadOptions, error) {
+	opts := threadOptions{
+		sort:     models.ReplySort(params.Sort),
+		depth:    params.Depth,
+		limit:    params.Limit,
+		children: params.Children,
+	}
+	if opts.sort == "" {
+		opts.sort = models.ReplySortOldest
+	}
+	if opts.depth == 0 {
+		opts.depth = defaultThreadDepth
+	}
+	if opts.limit == 0 {
+		opts.limit = defaultThreadLimit
+	}
+	if opts.children == 0 {
+		opts.children = defaultThreadChildren
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeReplyCursor(params.Cursor)
+		if err != nil {
+			return opts, err
+		}
+		opts.after = cursor
+	} else if params.Page > 1 {
+		opts.offset = (params.Page - 1) * opts.limit
+	}
+
+	return opts, nil
+}
+
+func encodeReplyCursor(reply *models.Reply) string {
+	data, _ := json.Marshal(repositories.ReplyCursor{
+		LikeCount: reply.LikeCount,
+		CreatedAt: reply.CreatedAt,
+		ID:        reply.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeReplyCursor(value string) (*repositories.ReplyCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor repositories.ReplyCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+func replyQuote(reply *models.Reply) *dto.ReplyQuoteResponse {
+	quote := &dto.ReplyQuoteResponse{
+		ID:        reply.ID,
+		UserID:    reply.UserID,
+		Username:  reply.User.Username,
+		IsDeleted: reply.DeletedAt != nil,
+	}
+	if !quote.IsDeleted {
+		quote.Excerpt = truncate(strings.Join(strings.Fields(reply.Content), " "), quoteExcerptLength)
+	}
+	return quote
+}
+
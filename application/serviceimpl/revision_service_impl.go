@@ -0,0 +1,253 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type revisionServiceImpl struct {
+	revisionRepo    repositories.RevisionRepository
+	topicRepo       repositories.TopicRepository
+	replyRepo       repositories.ReplyRepository
+	commentRepo     repositories.CommentRepository
+	activityLogRepo repositories.ActivityLogRepository
+	tagService      services.TagService
+	contentService  services.ContentService
+}
+
+func NewRevisionService(
+	revisionRepo repositories.RevisionRepository,
+	topicRepo repositories.TopicRepository,
+	replyRepo repositories.ReplyRepository,
+	commentRepo repositories.CommentRepository,
+	activityLogRepo repositories.ActivityLogRepository,
+	tagService services.TagService,
+	contentService services.ContentService,
+) services.RevisionService {
+	return &revisionServiceImpl{
+		revisionRepo:    revisionRepo,
+		topicRepo:       topicRepo,
+		replyRepo:       replyRepo,
+		commentRepo:     commentRepo,
+		activityLogRepo: activityLogRepo,
+		tagService:      tagService,
+		contentService:  contentService,
+	}
+}
+
+func (s *revisionServiceImpl) GetRevisions(ctx context.Context, contentType models.RevisionContentType, contentID, requesterID uuid.UUID, isAdmin bool, page, limit int) (*dto.RevisionListResponse, error) {
+	authorID, _, _, err := s.loadContent(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin && authorID != requesterID {
+		return nil, errors.New("you don't have permission to view the edit history")
+	}
+
+	page, limit = dto.NormalizePage(page, limit)
+	offset := (page - 1) * limit
+	revisions, total, err := s.revisionRepo.FindByContent(ctx, contentType, contentID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Versions are contiguous from 1, so the newest version equals the number of revisions
+	responses := make([]dto.RevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		responses[i] = *dto.RevisionToRevisionResponse(revision)
+		responses[i].IsCurrent = int64(revision.Version) == total
+	}
+
+	return &dto.RevisionListResponse{
+		Revisions: responses,
+		Meta:      dto.NewPaginationMeta(total, offset, limit),
+	}, nil
+}
+
+func (s *revisionServiceImpl) RollbackRevision(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID, version int, adminID uuid.UUID) (*dto.RevisionResponse, error) {
+	authorID, postedAt, current, err := s.loadContent(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.revisionRepo.GetByVersion(ctx, contentType, contentID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.revisionRepo.LatestVersion(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Version == latest {
+		return nil, errors.New("revision is already the current version")
+	}
+
+	restored := dto.RevisionSnapshot{Title: target.Title, Content: target.Content}
+	if target.Tags != "" {
+		_ = json.Unmarshal([]byte(target.Tags), &restored.Tags)
+	}
+
+	edit := revisionEdit(&dto.ContentEdit{
+		ContentType: contentType,
+		ContentID:   contentID,
+		AuthorID:    authorID,
+		PostedAt:    postedAt,
+		EditorID:    adminID,
+		Previous:    current,
+		Current:     restored,
+		Note:        fmt.Sprintf("rollback to version %d", version),
+	})
+	if err := s.applySnapshot(ctx, contentType, contentID, restored, edit); err != nil {
+		return nil, err
+	}
+	revision := edit.Revision
+
+	_ = s.activityLogRepo.Create(ctx, &models.ActivityLog{
+		AdminID:      adminID,
+		Action:       "rollback_revision",
+		ResourceType: string(contentType),
+		ResourceID:   contentID,
+		Description:  fmt.Sprintf("Rolled back to version %d", version),
+	})
+
+	resp := dto.RevisionToRevisionResponse(revision)
+	resp.IsCurrent = true
+	return resp, nil
+}
+
+// Helper methods
+
+// revisionEdit builds the revision the content repository saves together with an edit; the
+// original is only stored when the content has no revisions yet
+func revisionEdit(edit *dto.ContentEdit) *repositories.RevisionEdit {
+	original := newRevision(edit.ContentType, edit.ContentID, edit.Previous, edit.AuthorID, "")
+	original.CreatedAt = edit.PostedAt
+
+	return &repositories.RevisionEdit{
+		Original: original,
+		Revision: newRevision(edit.ContentType, edit.ContentID, edit.Current, edit.EditorID, edit.Note),
+	}
+}
+
+// loadContent returns the author, post time and current state of a topic, reply or comment
+func (s *revisionServiceImpl) loadContent(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID) (uuid.UUID, time.Time, dto.RevisionSnapshot, error) {
+	switch contentType {
+	case models.RevisionContentTopic:
+		topic, err := s.topicRepo.GetByID(ctx, contentID)
+		if err != nil {
+			return uuid.Nil, time.Time{}, dto.RevisionSnapshot{}, errors.New("topic not found")
+		}
+		tags, err := s.topicRepo.GetTags(ctx, contentID)
+		if err != nil {
+			return uuid.Nil, time.Time{}, dto.RevisionSnapshot{}, err
+		}
+		return topic.UserID, topic.CreatedAt, dto.RevisionSnapshot{
+			Title:   topic.Title,
+			Content: topic.Content,
+			Tags:    dto.TagsToRevisionTags(tags),
+		}, nil
+
+	case models.RevisionContentReply:
+		reply, err := s.replyRepo.GetByID(ctx, contentID)
+		if err != nil {
+			return uuid.Nil, time.Time{}, dto.RevisionSnapshot{}, errors.New("reply not found")
+		}
+		return reply.UserID, reply.CreatedAt, dto.RevisionSnapshot{Content: reply.Content}, nil
+
+	case models.RevisionContentComment:
+		comment, err := s.commentRepo.GetByID(ctx, contentID)
+		if err != nil {
+			return uuid.Nil, time.Time{}, dto.RevisionSnapshot{}, errors.New("comment not found")
+		}
+		return comment.UserID, comment.CreatedAt, dto.RevisionSnapshot{Content: comment.Content}, nil
+	}
+
+	return uuid.Nil, time.Time{}, dto.RevisionSnapshot{}, errors.New("unsupported content type")
+}
+
+// applySnapshot overwrites the content with a stored version and counts it as an edit
+func (s *revisionServiceImpl) applySnapshot(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID, snapshot dto.RevisionSnapshot, edit *repositories.RevisionEdit) error {
+	now := time.Now()
+
+	switch contentType {
+	case models.RevisionContentTopic:
+		topic, err := s.topicRepo.GetByID(ctx, contentID)
+		if err != nil {
+			return errors.New("topic not found")
+		}
+		topic.Title = snapshot.Title
+		topic.Content = snapshot.Content
+		topic.ContentHTML = s.contentService.RenderStored(ctx, snapshot.Content)
+		topic.EditCount++
+		topic.EditedAt = &now
+		topic.UpdatedAt = now
+
+		// Tags deleted since that version are left out
+		tagIDs := make([]uuid.UUID, len(snapshot.Tags))
+		for i, tag := range snapshot.Tags {
+			tagIDs[i] = tag.ID
+		}
+		tags, err := s.tagService.GetTagsByIDs(ctx, tagIDs)
+		if err != nil {
+			return err
+		}
+		if tags == nil {
+			tags = []*models.Tag{}
+		}
+		return s.topicRepo.Edit(ctx, topic, tags, edit)
+
+	case models.RevisionContentReply:
+		reply, err := s.replyRepo.GetByID(ctx, contentID)
+		if err != nil {
+			return errors.New("reply not found")
+		}
+		reply.Content = snapshot.Content
+		reply.ContentHTML = s.contentService.RenderStored(ctx, snapshot.Content)
+		reply.EditCount++
+		reply.EditedAt = &now
+		reply.UpdatedAt = now
+		return s.replyRepo.Edit(ctx, reply, edit)
+
+	case models.RevisionContentComment:
+		comment, err := s.commentRepo.GetByID(ctx, contentID)
+		if err != nil {
+			return errors.New("comment not found")
+		}
+		comment.Content = snapshot.Content
+		comment.EditCount++
+		comment.EditedAt = &now
+		return s.commentRepo.Edit(ctx, comment, edit)
+	}
+
+	return errors.New("unsupported content type")
+}
+
+func newRevision(contentType models.RevisionContentType, contentID uuid.UUID, snapshot dto.RevisionSnapshot, editorID uuid.UUID, note string) *models.ContentRevision {
+	tags := "[]"
+	if len(snapshot.Tags) > 0 {
+		if encoded, err := json.Marshal(snapshot.Tags); err == nil {
+			tags = string(encoded)
+		}
+	}
+
+	return &models.ContentRevision{
+		ContentType: contentType,
+		ContentID:   contentID,
+		Title:       snapshot.Title,
+		Content:     snapshot.Content,
+		Tags:        tags,
+		EditorID:    editorID,
+		Note:        note,
+	}
+}
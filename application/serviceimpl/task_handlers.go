@@ -0,0 +1,210 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+
+	"gorm.io/gorm"
+)
+
+// RegisterTaskHandlers registers the handlers of the background task types in dto. A task
+// whose topic, video, reply or comment has been deleted meanwhile completes without retrying.
+func RegisterTaskHandlers(
+	queueService services.QueueService,
+	notificationService services.NotificationService,
+	watchService services.WatchService,
+	likeRepo repositories.LikeRepository,
+	topicRepo repositories.TopicRepository,
+	videoRepo repositories.VideoRepository,
+	replyRepo repositories.ReplyRepository,
+	commentRepo repositories.CommentRepository,
+) {
+	queueService.RegisterHandler(dto.TaskTypeNotifyLike, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.NotifyLikeTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		var err error
+		switch task.TargetType {
+		case dto.LikeTargetTopic:
+			err = notificationService.CreateTopicLikeNotification(ctx, task.TargetID, task.ActorID)
+		case dto.LikeTargetVideo:
+			err = notificationService.CreateVideoLikeNotification(ctx, task.TargetID, task.ActorID)
+		case dto.LikeTargetReply:
+			err = notificationService.CreateReplyLikeNotification(ctx, task.TargetID, task.ActorID)
+		case dto.LikeTargetComment:
+			err = notificationService.CreateCommentLikeNotification(ctx, task.TargetID, task.ActorID)
+		default:
+			return fmt.Errorf("unknown like target %q", task.TargetType)
+		}
+		return ignoreDeleted(err)
+	})
+
+	queueService.RegisterHandler(dto.TaskTypeNotifyReply, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.NotifyReplyTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		reply, err := replyRepo.GetByID(ctx, task.ReplyID)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+		topic, err := topicRepo.GetByID(ctx, reply.TopicID)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+
+		return ignoreDeleted(notificationService.CreateTopicReplyNotification(ctx, topic.ID, reply.UserID))
+	})
+
+	// Like replies, a new topic's forum watchers are notified by one task per batch
+	queueService.RegisterHandler(dto.TaskTypeWatchTopic, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.WatchTopicTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		topic, err := topicRepo.GetByID(ctx, task.TopicID)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+
+		batches, err := watchService.HandleNewTopic(ctx, topic)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+		for i, batch := range batches {
+			notify := dto.NotifyForumWatchersTask{TopicID: topic.ID, WatcherIDs: batch}
+			if err := queueService.Enqueue(ctx, notify, fmt.Sprintf("topic-watchers:%s:%d", topic.ID, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	queueService.RegisterHandler(dto.TaskTypeNotifyForumWatchers, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.NotifyForumWatchersTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		return ignoreDeleted(notificationService.CreateWatchedForumTopicNotifications(ctx, task.TopicID, task.WatcherIDs))
+	})
+
+	// Each batch of watchers is its own task, queued under a key so a retry of this one
+	// doesn't queue it again
+	queueService.RegisterHandler(dto.TaskTypeWatchReply, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.WatchReplyTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		reply, err := replyRepo.GetByID(ctx, task.ReplyID)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+		topic, err := topicRepo.GetByID(ctx, reply.TopicID)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+
+		batches, err := watchService.HandleNewReply(ctx, reply, topic)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+		for i, batch := range batches {
+			notify := dto.NotifyReplyWatchersTask{TopicID: topic.ID, ReplyID: reply.ID, ActorID: reply.UserID, WatcherIDs: batch}
+			if err := queueService.Enqueue(ctx, notify, fmt.Sprintf("reply-watchers:%s:%d", reply.ID, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	queueService.RegisterHandler(dto.TaskTypeNotifyReplyWatchers, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.NotifyReplyWatchersTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		return ignoreDeleted(notificationService.CreateWatchedTopicReplyNotifications(ctx, task.TopicID, task.ActorID, task.WatcherIDs))
+	})
+
+	queueService.RegisterHandler(dto.TaskTypeNotifyComment, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.NotifyCommentTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		comment, err := commentRepo.GetByID(ctx, task.CommentID)
+		if err != nil {
+			return ignoreDeleted(err)
+		}
+
+		if comment.ParentID != nil {
+			// This is a reply to a comment
+			return ignoreDeleted(notificationService.CreateCommentReplyNotification(ctx, *comment.ParentID, comment.UserID))
+		}
+		// This is a comment on a video
+		return ignoreDeleted(notificationService.CreateVideoCommentNotification(ctx, comment.VideoID, comment.UserID))
+	})
+
+	queueService.RegisterHandler(dto.TaskTypeSyncLikeCount, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.SyncLikeCountTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		switch task.TargetType {
+		case dto.LikeTargetTopic:
+			count, err := likeRepo.CountTopicLikes(ctx, task.TargetID)
+			if err != nil {
+				return err
+			}
+			return topicRepo.UpdateLikeCount(ctx, task.TargetID, int(count))
+		case dto.LikeTargetVideo:
+			count, err := likeRepo.CountVideoLikes(ctx, task.TargetID)
+			if err != nil {
+				return err
+			}
+			return videoRepo.UpdateLikeCount(ctx, task.TargetID, int(count))
+		case dto.LikeTargetReply:
+			// เก็บยอด like ไว้ที่ reply สำหรับเรียงแบบ top
+			count, err := likeRepo.CountReplyLikes(ctx, task.TargetID)
+			if err != nil {
+				return err
+			}
+			return replyRepo.UpdateLikeCount(ctx, task.TargetID, int(count))
+		default:
+			return fmt.Errorf("unknown like target %q", task.TargetType)
+		}
+	})
+
+	queueService.RegisterHandler(dto.TaskTypeSyncCommentCount, func(ctx context.Context, payload json.RawMessage) error {
+		var task dto.SyncCommentCountTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		count, err := commentRepo.CountByVideoID(ctx, task.VideoID)
+		if err != nil {
+			return err
+		}
+		return videoRepo.UpdateCommentCount(ctx, task.VideoID, int(count))
+	})
+}
+
+// ignoreDeleted treats a record that no longer exists as nothing left to do
+func ignoreDeleted(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
@@ -3,6 +3,9 @@ package serviceimpl
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"sort"
 	"time"
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
@@ -11,24 +14,57 @@ import (
 	"github.com/google/uuid"
 )
 
+// topicRepliesPageSize is how many replies GetTopic returns with the topic
+const topicRepliesPageSize = 100
+
 type TopicServiceImpl struct {
 	topicRepo repositories.TopicRepository
 	forumRepo repositories.ForumRepository
 	replyRepo repositories.ReplyRepository
+	pollRepo repositories.PollRepository
+	bookmarkRepo repositories.BookmarkRepository
+	activityLogRepo repositories.ActivityLogRepository
+	forumService services.ForumService
 	tagService services.TagService
+	fileService services.FileService
+	contentService services.ContentService
+	notificationService services.NotificationService
+	watchService services.WatchService
+	readService services.ReadService
+	queueService services.QueueService
 }
 
 func NewTopicService(
 	topicRepo repositories.TopicRepository,
 	forumRepo repositories.ForumRepository,
 	replyRepo repositories.ReplyRepository,
+	pollRepo repositories.PollRepository,
+	bookmarkRepo repositories.BookmarkRepository,
+	activityLogRepo repositories.ActivityLogRepository,
+	forumService services.ForumService,
 	tagService services.TagService,
+	fileService services.FileService,
+	contentService services.ContentService,
+	notificationService services.NotificationService,
+	watchService services.WatchService,
+	readService services.ReadService,
+	queueService services.QueueService,
 ) services.TopicService {
 	return &TopicServiceImpl{
 		topicRepo: topicRepo,
 		forumRepo: forumRepo,
 		replyRepo: replyRepo,
+		pollRepo: pollRepo,
+		bookmarkRepo: bookmarkRepo,
+		activityLogRepo: activityLogRepo,
+		forumService: forumService,
 		tagService: tagService,
+		fileService: fileService,
+		contentService: contentService,
+		notificationService: notificationService,
+		watchService: watchService,
+		readService: readService,
+		queueService: queueService,
 	}
 }
 
@@ -48,12 +84,23 @@ func (s *TopicServiceImpl) CreateTopic(ctx context.Context, userID uuid.UUID, re
 		return nil, errors.New("forum is not active")
 	}
 
+	// ตรวจสิทธิ์ตั้งกระทู้ตามกฎของ forum
+	if err := s.forumService.CheckAccess(ctx, forumID, &userID, models.ForumActionPost); err != nil {
+		return nil, err
+	}
+
+	rendered, err := s.contentService.Render(ctx, userID, req.Content)
+	if err != nil {
+		return nil, err
+	}
+
 	topic := &models.Topic{
 		ID:        uuid.New(),
 		ForumID:   forumID,
 		UserID:    userID,
 		Title:     req.Title,
 		Content:   req.Content,
+		ContentHTML: rendered.HTML,
 		Thumbnail: req.Thumbnail,
 		ViewCount:  0,
 		ReplyCount: 0,
@@ -62,8 +109,22 @@ func (s *TopicServiceImpl) CreateTopic(ctx context.Context, userID uuid.UUID, re
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
+	topic.LastActivityAt = &topic.CreatedAt
+
+	// โพล (ถ้ามี) ถูกบันทึกพร้อมกระทู้ใน insert เดียวกัน
+	if req.Poll != nil {
+		topic.Poll, err = newPoll(topic.ID, req.Poll)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.topicRepo.Create(ctx, topic); err != nil {
+	// Auto-watch ของผู้ตั้งกระทู้ และแจ้งผู้ที่ติดตาม forum - queued with the topic so it survives a crash
+	tasks, err := newTasks(s.queueService, queuedTask{dto.WatchTopicTask{TopicID: topic.ID}, "topic-watch:" + topic.ID.String()})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.topicRepo.Create(ctx, topic, tasks...); err != nil {
 		return nil, err
 	}
 
@@ -89,46 +150,114 @@ func (s *TopicServiceImpl) CreateTopic(ctx context.Context, userID uuid.UUID, re
 		}
 	}
 
-	// เพิ่ม topic count ใน forum
-	s.forumRepo.IncrementTopicCount(ctx, forumID)
+	// ผูก thumbnail กับกระทู้เพื่อไม่ให้ถูกลบโดย orphan GC
+	if err := s.fileService.AttachFileByURL(ctx, userID, topic.Thumbnail, models.FileReferenceTopicThumbnail, topic.ID); err != nil {
+		log.Printf("Warning: Failed to attach thumbnail of topic %s: %v", topic.ID, err)
+	}
+	s.contentService.AttachEmbeds(ctx, userID, rendered, models.FileReferenceTopicContent, topic.ID)
+	s.contentService.PrefetchLinkPreviews(linksToPreview(rendered.Links))
 
 	return topic, nil
 }
 
-func (s *TopicServiceImpl) GetTopic(ctx context.Context, topicID uuid.UUID) (*dto.TopicDetailResponse, error) {
-	// Get topic
-	topic, err := s.topicRepo.GetByID(ctx, topicID)
+// resolveTopic loads a topic, following the redirect left behind when it was merged into another
+func resolveTopic(ctx context.Context, topicRepo repositories.TopicRepository, topicID uuid.UUID) (*models.Topic, error) {
+	topic, err := topicRepo.GetByID(ctx, topicID)
+	if err == nil {
+		return topic, nil
+	}
+
+	redirect, err := topicRepo.GetRedirect(ctx, topicID)
 	if err != nil {
 		return nil, errors.New("topic not found")
 	}
+	if topic, err = topicRepo.GetByID(ctx, redirect.ToTopicID); err != nil {
+		return nil, errors.New("topic not found")
+	}
+	return topic, nil
+}
+
+func (s *TopicServiceImpl) GetTopic(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID) (*dto.TopicDetailResponse, error) {
+	// Get topic; the ID of a topic merged into another one resolves to the merge target
+	var redirectedFrom *uuid.UUID
+	topic, err := resolveTopic(ctx, s.topicRepo, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if topic.ID != topicID {
+		requestedID := topicID
+		redirectedFrom = &requestedID
+		topicID = topic.ID
+	}
+
+	if err := s.forumService.CheckAccess(ctx, topic.ForumID, viewerID, models.ForumActionView); err != nil {
+		return nil, err
+	}
 
 	// Increment view count
 	s.topicRepo.IncrementViewCount(ctx, topicID)
 
 	// Get replies
-	replies, _ := s.replyRepo.GetByTopicID(ctx, topicID, 0, 100)
+	replies, _ := s.replyRepo.GetByTopicID(ctx, topicID, 0, topicRepliesPageSize)
 
 	// Convert to responses
 	topicResp := dto.TopicToTopicResponse(topic)
 
+	if poll, err := s.pollRepo.GetByTopicID(ctx, topicID); err == nil {
+		topicResp.Poll, _ = pollResponse(ctx, s.pollRepo, poll, viewerID)
+	}
+	if viewerID != nil {
+		topicResp.IsBookmarked, _ = s.bookmarkRepo.IsBookmarked(ctx, *viewerID, models.BookmarkTargetTopic, topicID)
+		if status, err := s.watchService.GetTopicWatchStatus(ctx, *viewerID, topicID); err == nil {
+			topicResp.IsWatching = status.IsWatching
+		}
+
+		// Read state is reported as of before this visit, then the visit moves the marker
+		s.readService.FillTopicReadState(ctx, viewerID, []*dto.TopicResponse{topicResp})
+		s.markVisitRead(ctx, *viewerID, topicID, replies)
+	}
+
 	replyResps := make([]dto.ReplyResponse, len(replies))
+	targets := []previewTarget{topicPreviewTarget(topicResp)}
 	for i, reply := range replies {
 		replyResps[i] = *dto.ReplyToReplyResponse(reply, true)
 	}
+	for i := range replyResps {
+		targets = replyPreviewTargets(&replyResps[i], targets)
+	}
+
+	// คำตอบที่ถูกเลือกแสดงไว้บนสุด แม้จะอยู่นอกหน้าแรกของ replies
+	var acceptedResp *dto.ReplyResponse
+	if topic.AcceptedReplyID != nil {
+		markAcceptedReply(replyResps, *topic.AcceptedReplyID)
+		if accepted, err := s.replyRepo.GetByID(ctx, *topic.AcceptedReplyID); err == nil {
+			acceptedResp = dto.ReplyToReplyResponse(accepted, false)
+			acceptedResp.IsAccepted = true
+			targets = replyPreviewTargets(acceptedResp, targets)
+		}
+	}
+	fillLinkPreviews(ctx, s.contentService, targets)
 
 	return &dto.TopicDetailResponse{
-		Topic:   *topicResp,
-		Replies: replyResps,
+		Topic:          *topicResp,
+		AcceptedAnswer: acceptedResp,
+		Replies:        replyResps,
+		RedirectedFrom: redirectedFrom,
 	}, nil
 }
 
-func (s *TopicServiceImpl) GetTopics(ctx context.Context, offset, limit int) ([]*dto.TopicResponse, int64, error) {
-	topics, err := s.topicRepo.List(ctx, offset, limit)
+func (s *TopicServiceImpl) GetTopics(ctx context.Context, viewerID *uuid.UUID, filter *dto.TopicFilterParams, offset, limit int) ([]*dto.TopicResponse, int64, error) {
+	hiddenForumIDs, err := s.forumService.GetHiddenForumIDs(ctx, viewerID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total, err := s.topicRepo.Count(ctx)
+	topics, err := s.topicRepo.List(ctx, hiddenForumIDs, topicFilter(filter), offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.topicRepo.Count(ctx, hiddenForumIDs, topicFilter(filter))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -138,16 +267,22 @@ func (s *TopicServiceImpl) GetTopics(ctx context.Context, offset, limit int) ([]
 		responses[i] = dto.TopicToTopicResponse(topic)
 	}
 
+	s.readService.FillTopicReadState(ctx, viewerID, responses)
+
 	return responses, total, nil
 }
 
-func (s *TopicServiceImpl) GetTopicsByForum(ctx context.Context, forumID uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error) {
-	topics, err := s.topicRepo.GetByForumID(ctx, forumID, offset, limit)
+func (s *TopicServiceImpl) GetTopicsByForum(ctx context.Context, forumID uuid.UUID, viewerID *uuid.UUID, filter *dto.TopicFilterParams, offset, limit int) ([]*dto.TopicResponse, int64, error) {
+	if err := s.forumService.CheckAccess(ctx, forumID, viewerID, models.ForumActionView); err != nil {
+		return nil, 0, err
+	}
+
+	topics, err := s.topicRepo.GetByForumID(ctx, forumID, topicFilter(filter), offset, limit)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total, err := s.topicRepo.CountByForumID(ctx, forumID)
+	total, err := s.topicRepo.CountByForumID(ctx, forumID, topicFilter(filter))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -157,10 +292,12 @@ func (s *TopicServiceImpl) GetTopicsByForum(ctx context.Context, forumID uuid.UU
 		responses[i] = dto.TopicToTopicResponse(topic)
 	}
 
+	s.readService.FillTopicReadState(ctx, viewerID, responses)
+
 	return responses, total, nil
 }
 
-func (s *TopicServiceImpl) GetTopicsByForumSlug(ctx context.Context, slug string, offset, limit int) ([]*dto.TopicResponse, int64, error) {
+func (s *TopicServiceImpl) GetTopicsByForumSlug(ctx context.Context, slug string, viewerID *uuid.UUID, filter *dto.TopicFilterParams, offset, limit int) ([]*dto.TopicResponse, int64, error) {
 	// Get forum by slug first
 	forum, err := s.forumRepo.GetBySlug(ctx, slug)
 	if err != nil {
@@ -172,13 +309,17 @@ func (s *TopicServiceImpl) GetTopicsByForumSlug(ctx context.Context, slug string
 		return nil, 0, errors.New("forum is not active")
 	}
 
+	if err := s.forumService.CheckAccess(ctx, forum.ID, viewerID, models.ForumActionView); err != nil {
+		return nil, 0, err
+	}
+
 	// Get topics by forum ID
-	topics, err := s.topicRepo.GetByForumID(ctx, forum.ID, offset, limit)
+	topics, err := s.topicRepo.GetByForumID(ctx, forum.ID, topicFilter(filter), offset, limit)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total, err := s.topicRepo.CountByForumID(ctx, forum.ID)
+	total, err := s.topicRepo.CountByForumID(ctx, forum.ID, topicFilter(filter))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -188,16 +329,23 @@ func (s *TopicServiceImpl) GetTopicsByForumSlug(ctx context.Context, slug string
 		responses[i] = dto.TopicToTopicResponse(topic)
 	}
 
+	s.readService.FillTopicReadState(ctx, viewerID, responses)
+
 	return responses, total, nil
 }
 
-func (s *TopicServiceImpl) GetTopicsByTag(ctx context.Context, tag string, offset, limit int) ([]*dto.TopicResponse, int64, error) {
-	topics, err := s.topicRepo.GetByTag(ctx, tag, offset, limit)
+func (s *TopicServiceImpl) GetTopicsByTag(ctx context.Context, tag string, viewerID *uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error) {
+	hiddenForumIDs, err := s.forumService.GetHiddenForumIDs(ctx, viewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	topics, err := s.topicRepo.GetByTag(ctx, tag, hiddenForumIDs, offset, limit)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total, err := s.topicRepo.CountByTag(ctx, tag)
+	total, err := s.topicRepo.CountByTag(ctx, tag, hiddenForumIDs)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -207,20 +355,27 @@ func (s *TopicServiceImpl) GetTopicsByTag(ctx context.Context, tag string, offse
 		responses[i] = dto.TopicToTopicResponse(topic)
 	}
 
+	s.readService.FillTopicReadState(ctx, viewerID, responses)
+
 	return responses, total, nil
 }
 
-func (s *TopicServiceImpl) GetTopicsByTags(ctx context.Context, tags []string, offset, limit int) ([]*dto.TopicResponse, int64, error) {
+func (s *TopicServiceImpl) GetTopicsByTags(ctx context.Context, tags []string, viewerID *uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error) {
 	if len(tags) == 0 {
 		return nil, 0, errors.New("at least one tag is required")
 	}
 
-	topics, err := s.topicRepo.GetByTags(ctx, tags, offset, limit)
+	hiddenForumIDs, err := s.forumService.GetHiddenForumIDs(ctx, viewerID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total, err := s.topicRepo.CountByTags(ctx, tags)
+	topics, err := s.topicRepo.GetByTags(ctx, tags, hiddenForumIDs, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.topicRepo.CountByTags(ctx, tags, hiddenForumIDs)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -230,6 +385,8 @@ func (s *TopicServiceImpl) GetTopicsByTags(ctx context.Context, tags []string, o
 		responses[i] = dto.TopicToTopicResponse(topic)
 	}
 
+	s.readService.FillTopicReadState(ctx, viewerID, responses)
+
 	return responses, total, nil
 }
 
@@ -249,22 +406,98 @@ func (s *TopicServiceImpl) UpdateTopic(ctx context.Context, topicID, userID uuid
 		return nil, errors.New("topic is locked")
 	}
 
+	// เก็บสถานะก่อนแก้ไขไว้ทำ revision
+	currentTags, err := s.topicRepo.GetTags(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	previous := dto.RevisionSnapshot{
+		Title:   topic.Title,
+		Content: topic.Content,
+		Tags:    dto.TagsToRevisionTags(currentTags),
+	}
+
+	// nil = ไม่เปลี่ยน tag, [] = ลบ tag ทั้งหมด
+	newTags := currentTags
+	if req.TagIDs != nil {
+		tagUUIDs := make([]uuid.UUID, 0, len(req.TagIDs))
+		for _, tagID := range req.TagIDs {
+			if parsedID, err := uuid.Parse(tagID); err == nil {
+				tagUUIDs = append(tagUUIDs, parsedID)
+			}
+		}
+		newTags, err = s.tagService.GetTagsByIDs(ctx, tagUUIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tagsChanged := !sameTags(currentTags, newTags)
+
 	// Update fields
 	if req.Title != "" {
 		topic.Title = req.Title
 	}
-	if req.Content != "" {
+	var rendered *dto.RenderedContent
+	if req.Content != "" && req.Content != topic.Content {
+		rendered, err = s.contentService.Render(ctx, userID, req.Content)
+		if err != nil {
+			return nil, err
+		}
 		topic.Content = req.Content
+		topic.ContentHTML = rendered.HTML
 	}
+	thumbnailChanged := req.Thumbnail != "" && req.Thumbnail != topic.Thumbnail
 	if req.Thumbnail != "" {
 		topic.Thumbnail = req.Thumbnail
 	}
-	topic.UpdatedAt = time.Now()
-
-	if err := s.topicRepo.Update(ctx, topicID, topic); err != nil {
+	now := time.Now()
+	topic.UpdatedAt = now
+
+	edited := topic.Title != previous.Title || topic.Content != previous.Content || tagsChanged
+	if edited {
+		topic.EditCount++
+		topic.EditedAt = &now
+	}
+
+	// Fields, tags and the revision are saved together
+	var tags []*models.Tag
+	if tagsChanged {
+		tags = append([]*models.Tag{}, newTags...)
+	}
+	var revision *repositories.RevisionEdit
+	if edited {
+		revision = revisionEdit(&dto.ContentEdit{
+			ContentType: models.RevisionContentTopic,
+			ContentID:   topicID,
+			AuthorID:    topic.UserID,
+			PostedAt:    topic.CreatedAt,
+			EditorID:    userID,
+			Previous:    previous,
+			Current: dto.RevisionSnapshot{
+				Title:   topic.Title,
+				Content: topic.Content,
+				Tags:    dto.TagsToRevisionTags(newTags),
+			},
+		})
+	}
+	if err := s.topicRepo.Edit(ctx, topic, tags, revision); err != nil {
 		return nil, err
 	}
 
+	if rendered != nil {
+		s.contentService.AttachEmbeds(ctx, userID, rendered, models.FileReferenceTopicContent, topicID)
+		s.contentService.PrefetchLinkPreviews(linksToPreview(rendered.Links))
+	}
+
+	if thumbnailChanged {
+		if err := s.fileService.ReleaseFiles(ctx, models.FileReferenceTopicThumbnail, topicID); err != nil {
+			log.Printf("Warning: Failed to release previous thumbnail of topic %s: %v", topicID, err)
+		}
+		if err := s.fileService.AttachFileByURL(ctx, userID, topic.Thumbnail, models.FileReferenceTopicThumbnail, topicID); err != nil {
+			log.Printf("Warning: Failed to attach thumbnail of topic %s: %v", topicID, err)
+		}
+	}
+
 	return topic, nil
 }
 
@@ -274,18 +507,43 @@ func (s *TopicServiceImpl) DeleteTopic(ctx context.Context, topicID, userID uuid
 		return errors.New("topic not found")
 	}
 
+	// เจ้าของกระทู้หรือ moderator ของ forum ลบได้
 	if topic.UserID != userID {
-		return errors.New("unauthorized to delete this topic")
+		canModerate, err := s.forumService.CanModerate(ctx, topic.ForumID, userID)
+		if err != nil {
+			return err
+		}
+		if !canModerate {
+			return errors.New("unauthorized to delete this topic")
+		}
 	}
 
 	// ลด topic count ใน forum
 	s.forumRepo.DecrementTopicCount(ctx, topic.ForumID)
 
-	return s.topicRepo.Delete(ctx, topicID)
+	if err := s.topicRepo.Delete(ctx, topicID); err != nil {
+		return err
+	}
+	s.releaseFiles(ctx, topicID)
+	return nil
 }
 
-func (s *TopicServiceImpl) SearchTopics(ctx context.Context, query string, offset, limit int) ([]*dto.TopicResponse, int64, error) {
-	topics, total, err := s.topicRepo.Search(ctx, query, offset, limit)
+// releaseFiles drops the thumbnail and embedded images of a deleted topic; files nothing else uses are removed
+func (s *TopicServiceImpl) releaseFiles(ctx context.Context, topicID uuid.UUID) {
+	for _, resourceType := range []models.FileReferenceType{models.FileReferenceTopicThumbnail, models.FileReferenceTopicContent} {
+		if err := s.fileService.ReleaseFiles(ctx, resourceType, topicID); err != nil {
+			log.Printf("Warning: Failed to release %s files of topic %s: %v", resourceType, topicID, err)
+		}
+	}
+}
+
+func (s *TopicServiceImpl) SearchTopics(ctx context.Context, query string, viewerID *uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error) {
+	hiddenForumIDs, err := s.forumService.GetHiddenForumIDs(ctx, viewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	topics, total, err := s.topicRepo.Search(ctx, query, hiddenForumIDs, offset, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -295,26 +553,218 @@ func (s *TopicServiceImpl) SearchTopics(ctx context.Context, query string, offse
 		responses[i] = dto.TopicToTopicResponse(topic)
 	}
 
+	s.readService.FillTopicReadState(ctx, viewerID, responses)
+
 	return responses, total, nil
 }
 
-// Admin Actions
-func (s *TopicServiceImpl) PinTopic(ctx context.Context, topicID uuid.UUID) error {
-	return s.topicRepo.Pin(ctx, topicID)
+// Moderator Actions
+func (s *TopicServiceImpl) PinTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error {
+	if err := s.checkModerator(ctx, topicID, moderatorID); err != nil {
+		return err
+	}
+	if err := s.topicRepo.Pin(ctx, topicID); err != nil {
+		return err
+	}
+	s.broadcastStatus(ctx, topicID)
+	return nil
 }
 
-func (s *TopicServiceImpl) UnpinTopic(ctx context.Context, topicID uuid.UUID) error {
-	return s.topicRepo.Unpin(ctx, topicID)
+func (s *TopicServiceImpl) UnpinTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error {
+	if err := s.checkModerator(ctx, topicID, moderatorID); err != nil {
+		return err
+	}
+	if err := s.topicRepo.Unpin(ctx, topicID); err != nil {
+		return err
+	}
+	s.broadcastStatus(ctx, topicID)
+	return nil
 }
 
-func (s *TopicServiceImpl) LockTopic(ctx context.Context, topicID uuid.UUID) error {
-	return s.topicRepo.Lock(ctx, topicID)
+func (s *TopicServiceImpl) LockTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error {
+	if err := s.checkModerator(ctx, topicID, moderatorID); err != nil {
+		return err
+	}
+	if err := s.topicRepo.Lock(ctx, topicID); err != nil {
+		return err
+	}
+	s.broadcastStatus(ctx, topicID)
+	return nil
 }
 
-func (s *TopicServiceImpl) UnlockTopic(ctx context.Context, topicID uuid.UUID) error {
-	return s.topicRepo.Unlock(ctx, topicID)
+func (s *TopicServiceImpl) UnlockTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error {
+	if err := s.checkModerator(ctx, topicID, moderatorID); err != nil {
+		return err
+	}
+	if err := s.topicRepo.Unlock(ctx, topicID); err != nil {
+		return err
+	}
+	s.broadcastStatus(ctx, topicID)
+	return nil
 }
 
+// MoveTopic moves a topic to another forum; the moderator has to moderate both forums
+func (s *TopicServiceImpl) MoveTopic(ctx context.Context, topicID, moderatorID uuid.UUID, req *dto.MoveTopicRequest) (*dto.TopicResponse, error) {
+	forumID, err := uuid.Parse(req.ForumID)
+	if err != nil {
+		return nil, errors.New("invalid forum ID")
+	}
+
+	topic, err := s.moderatedTopic(ctx, topicID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	if topic.ForumID == forumID {
+		return nil, errors.New("topic is already in this forum")
+	}
+
+	forum, err := s.moderatedForum(ctx, forumID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.topicRepo.MoveToForum(ctx, topic, forumID); err != nil {
+		return nil, err
+	}
+
+	s.logModeration(ctx, moderatorID, "move_topic", topicID, fmt.Sprintf("Moved from %s to %s", topic.Forum.Name, forum.Name), req.Reason)
+	go func() {
+		_ = s.notificationService.CreateTopicModerationNotifications(context.Background(), models.NotificationTypeTopicMoved, topicID, moderatorID, []uuid.UUID{topic.UserID}, req.Reason)
+	}()
+
+	return s.topicResponse(ctx, topicID)
+}
+
+// MergeTopic moves every reply of a topic into the target topic. The merged topic's first
+// post becomes a reply (keeping its author and date) with the merged thread under it, and
+// the merged topic's ID redirects to the target.
+func (s *TopicServiceImpl) MergeTopic(ctx context.Context, topicID, moderatorID uuid.UUID, req *dto.MergeTopicRequest) (*dto.TopicResponse, error) {
+	targetID, err := uuid.Parse(req.TargetTopicID)
+	if err != nil {
+		return nil, errors.New("invalid target topic ID")
+	}
+	if targetID == topicID {
+		return nil, errors.New("cannot merge a topic into itself")
+	}
+
+	source, err := s.moderatedTopic(ctx, topicID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.moderatedTopic(ctx, targetID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A topic has at most one poll; the source's poll moves to the target
+	if _, err := s.pollRepo.GetByTopicID(ctx, source.ID); err == nil {
+		if _, err := s.pollRepo.GetByTopicID(ctx, target.ID); err == nil {
+			return nil, errors.New("both topics have a poll")
+		}
+	}
+
+	opening := &models.Reply{
+		TopicID:     target.ID,
+		UserID:      source.UserID,
+		Content:     source.Content,
+		ContentHTML: source.ContentHTML,
+		CreatedAt:   source.CreatedAt,
+	}
+	redirect := &models.TopicRedirect{
+		FromTopicID: source.ID,
+		ToTopicID:   target.ID,
+		CreatedBy:   moderatorID,
+	}
+	if err := s.topicRepo.Merge(ctx, source, opening, redirect); err != nil {
+		return nil, err
+	}
+
+	s.logModeration(ctx, moderatorID, "merge_topic", source.ID, fmt.Sprintf("Merged \"%s\" into \"%s\" (%s)", source.Title, target.Title, target.ID), req.Reason)
+	go func() {
+		_ = s.notificationService.CreateTopicModerationNotifications(context.Background(), models.NotificationTypeTopicMerged, target.ID, moderatorID, []uuid.UUID{source.UserID}, req.Reason)
+	}()
+
+	return s.topicResponse(ctx, target.ID)
+}
+
+// SplitTopic moves the selected replies into a new topic. The oldest of them becomes the new
+// topic's first post; the others keep their threading where their parent came along.
+func (s *TopicServiceImpl) SplitTopic(ctx context.Context, topicID, moderatorID uuid.UUID, req *dto.SplitTopicRequest) (*dto.TopicResponse, error) {
+	source, err := s.moderatedTopic(ctx, topicID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	forumID := source.ForumID
+	if req.ForumID != "" {
+		if forumID, err = uuid.Parse(req.ForumID); err != nil {
+			return nil, errors.New("invalid forum ID")
+		}
+		if forumID != source.ForumID {
+			if _, err := s.moderatedForum(ctx, forumID, moderatorID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	replyIDs := make([]uuid.UUID, 0, len(req.ReplyIDs))
+	seen := make(map[uuid.UUID]bool, len(req.ReplyIDs))
+	for _, raw := range req.ReplyIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("invalid reply ID")
+		}
+		if !seen[id] {
+			seen[id] = true
+			replyIDs = append(replyIDs, id)
+		}
+	}
+
+	replies, err := s.replyRepo.GetByIDsIncludingDeleted(ctx, replyIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) != len(replyIDs) {
+		return nil, errors.New("reply not found")
+	}
+	for _, reply := range replies {
+		if reply.TopicID != topicID || reply.DeletedAt != nil {
+			return nil, errors.New("reply not found")
+		}
+	}
+
+	sort.Slice(replies, func(i, j int) bool {
+		return replies[i].CreatedAt.Before(replies[j].CreatedAt)
+	})
+	opening := replies[0]
+
+	topic := &models.Topic{
+		ForumID:     forumID,
+		UserID:      opening.UserID,
+		Title:       req.Title,
+		Content:     opening.Content,
+		ContentHTML: opening.ContentHTML,
+		CreatedAt:   opening.CreatedAt,
+	}
+	if err := s.topicRepo.Split(ctx, source, topic, opening.ID, replyIDs); err != nil {
+		return nil, err
+	}
+
+	authorIDs := make([]uuid.UUID, len(replies))
+	for i, reply := range replies {
+		authorIDs[i] = reply.UserID
+	}
+
+	s.logModeration(ctx, moderatorID, "split_topic", source.ID, fmt.Sprintf("Split %d replies into \"%s\" (%s)", len(replies), topic.Title, topic.ID), req.Reason)
+	go func() {
+		_ = s.notificationService.CreateTopicModerationNotifications(context.Background(), models.NotificationTypeTopicSplit, topic.ID, moderatorID, authorIDs, req.Reason)
+	}()
+
+	return s.topicResponse(ctx, topic.ID)
+}
+
+// Admin Actions
+
 func (s *TopicServiceImpl) DeleteTopicByAdmin(ctx context.Context, topicID uuid.UUID) error {
 	topic, err := s.topicRepo.GetByID(ctx, topicID)
 	if err != nil {
@@ -322,5 +772,221 @@ func (s *TopicServiceImpl) DeleteTopicByAdmin(ctx context.Context, topicID uuid.
 	}
 
 	s.forumRepo.DecrementTopicCount(ctx, topic.ForumID)
-	return s.topicRepo.Delete(ctx, topicID)
+	if err := s.topicRepo.Delete(ctx, topicID); err != nil {
+		return err
+	}
+	s.releaseFiles(ctx, topicID)
+	return nil
+}
+
+// AcceptAnswer marks one of the topic's replies as its accepted answer, replacing any earlier one
+func (s *TopicServiceImpl) AcceptAnswer(ctx context.Context, topicID, userID uuid.UUID, req *dto.AcceptAnswerRequest) (*dto.TopicResponse, error) {
+	replyID, err := uuid.Parse(req.ReplyID)
+	if err != nil {
+		return nil, errors.New("invalid reply ID format")
+	}
+
+	topic, err := s.checkAnswerPermission(ctx, topicID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := s.replyRepo.GetByID(ctx, replyID)
+	if err != nil {
+		return nil, errors.New("reply not found")
+	}
+	if reply.TopicID != topic.ID {
+		return nil, errors.New("reply does not belong to this topic")
+	}
+	if topic.AcceptedReplyID != nil && *topic.AcceptedReplyID == replyID {
+		return nil, errors.New("reply is already the accepted answer")
+	}
+
+	if err := s.topicRepo.SetAcceptedReply(ctx, topicID, &replyID); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		_ = s.notificationService.CreateAnswerAcceptedNotification(context.Background(), replyID, userID)
+	}()
+
+	s.broadcastStatus(ctx, topicID)
+	return s.topicResponse(ctx, topicID)
+}
+
+func (s *TopicServiceImpl) UnacceptAnswer(ctx context.Context, topicID, userID uuid.UUID) (*dto.TopicResponse, error) {
+	topic, err := s.checkAnswerPermission(ctx, topicID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if topic.AcceptedReplyID == nil {
+		return nil, errors.New("topic has no accepted answer")
+	}
+
+	if err := s.topicRepo.SetAcceptedReply(ctx, topicID, nil); err != nil {
+		return nil, err
+	}
+
+	s.broadcastStatus(ctx, topicID)
+	return s.topicResponse(ctx, topicID)
+}
+
+// checkAnswerPermission allows the topic author and forum moderators to pick answers in Q&A forums
+func (s *TopicServiceImpl) checkAnswerPermission(ctx context.Context, topicID, userID uuid.UUID) (*models.Topic, error) {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return nil, errors.New("topic not found")
+	}
+
+	forum, err := s.forumRepo.GetByID(ctx, topic.ForumID)
+	if err != nil {
+		return nil, errors.New("forum not found")
+	}
+	if !forum.IsQA {
+		return nil, errors.New("accepted answers are only available in Q&A forums")
+	}
+
+	if topic.UserID != userID {
+		canModerate, err := s.forumService.CanModerate(ctx, topic.ForumID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !canModerate {
+			return nil, errors.New("you don't have permission to choose the accepted answer")
+		}
+	}
+
+	return topic, nil
+}
+
+func (s *TopicServiceImpl) topicResponse(ctx context.Context, topicID uuid.UUID) (*dto.TopicResponse, error) {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return nil, errors.New("topic not found")
+	}
+	return dto.TopicToTopicResponse(topic), nil
+}
+
+// broadcastStatus tells the topic's viewers about pin, lock and accepted answer changes
+func (s *TopicServiceImpl) broadcastStatus(ctx context.Context, topicID uuid.UUID) {
+	if topic, err := s.topicRepo.GetByID(ctx, topicID); err == nil {
+		broadcastTopicStatus(topic)
+	}
+}
+
+// checkModerator allows admins and moderators of the topic's forum (or its parents)
+func (s *TopicServiceImpl) checkModerator(ctx context.Context, topicID, moderatorID uuid.UUID) error {
+	_, err := s.moderatedTopic(ctx, topicID, moderatorID)
+	return err
+}
+
+// moderatedTopic loads a topic the user may moderate
+func (s *TopicServiceImpl) moderatedTopic(ctx context.Context, topicID, moderatorID uuid.UUID) (*models.Topic, error) {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return nil, errors.New("topic not found")
+	}
+
+	canModerate, err := s.forumService.CanModerate(ctx, topic.ForumID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModerate {
+		return nil, errors.New("you are not a moderator of this forum")
+	}
+
+	return topic, nil
+}
+
+// moderatedForum loads the active forum content is moved into; the user has to moderate it too
+func (s *TopicServiceImpl) moderatedForum(ctx context.Context, forumID, moderatorID uuid.UUID) (*models.Forum, error) {
+	forum, err := s.forumRepo.GetByID(ctx, forumID)
+	if err != nil {
+		return nil, errors.New("forum not found")
+	}
+	if !forum.IsActive {
+		return nil, errors.New("forum is not active")
+	}
+
+	canModerate, err := s.forumService.CanModerate(ctx, forumID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModerate {
+		return nil, errors.New("you are not a moderator of the destination forum")
+	}
+
+	return forum, nil
+}
+
+func (s *TopicServiceImpl) logModeration(ctx context.Context, moderatorID uuid.UUID, action string, topicID uuid.UUID, description, reason string) {
+	if reason != "" {
+		description = fmt.Sprintf("%s - %s", description, reason)
+	}
+	_ = s.activityLogRepo.Create(ctx, &models.ActivityLog{
+		AdminID:      moderatorID,
+		Action:       action,
+		ResourceType: "topic",
+		ResourceID:   topicID,
+		Description:  description,
+	})
+}
+
+// sameTags reports whether both lists contain the same tags, ignoring order
+func sameTags(a, b []*models.Tag) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := make(map[uuid.UUID]bool, len(a))
+	for _, tag := range a {
+		ids[tag.ID] = true
+	}
+	for _, tag := range b {
+		if !ids[tag.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+func topicFilter(params *dto.TopicFilterParams) repositories.TopicFilter {
+	if params == nil {
+		return repositories.TopicFilter{}
+	}
+	return repositories.TopicFilter{Solved: params.Solved}
+}
+
+// markVisitRead records a topic visit. Only the first page of replies is returned, so on a
+// long topic the marker stops at the newest reply the viewer was actually sent.
+func (s *TopicServiceImpl) markVisitRead(ctx context.Context, viewerID, topicID uuid.UUID, replies []*models.Reply) {
+	req := &dto.MarkTopicReadRequest{}
+	if len(replies) >= topicRepliesPageSize {
+		if newest := newestReply(replies, nil); newest != nil {
+			req.ReplyID = &newest.ID
+		}
+	}
+	_ = s.readService.MarkTopicRead(ctx, viewerID, topicID, req)
+}
+
+// newestReply returns the most recently created reply in the loaded tree
+func newestReply(replies []*models.Reply, newest *models.Reply) *models.Reply {
+	for _, r := range replies {
+		if newest == nil || r.CreatedAt.After(newest.CreatedAt) {
+			newest = r
+		}
+		for i := range r.Replies {
+			newest = newestReply([]*models.Reply{&r.Replies[i]}, newest)
+		}
+	}
+	return newest
+}
+
+// markAcceptedReply flags the accepted answer wherever it appears in a reply tree
+func markAcceptedReply(replies []dto.ReplyResponse, acceptedID uuid.UUID) {
+	for i := range replies {
+		if replies[i].ID == acceptedID {
+			replies[i].IsAccepted = true
+		}
+		markAcceptedReply(replies[i].Replies, acceptedID)
+	}
 }
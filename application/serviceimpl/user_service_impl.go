@@ -7,6 +7,7 @@ import (
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 	"gofiber-social/domain/services"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -19,16 +20,18 @@ type UserServiceImpl struct {
 	topicRepo   repositories.TopicRepository
 	videoRepo   repositories.VideoRepository
 	followRepo  repositories.FollowRepository
+	fileService services.FileService
 	jwtSecret   string
 }
 
-func NewUserService(userRepo repositories.UserRepository, topicRepo repositories.TopicRepository, videoRepo repositories.VideoRepository, followRepo repositories.FollowRepository, jwtSecret string) services.UserService {
+func NewUserService(userRepo repositories.UserRepository, topicRepo repositories.TopicRepository, videoRepo repositories.VideoRepository, followRepo repositories.FollowRepository, fileService services.FileService, jwtSecret string) services.UserService {
 	return &UserServiceImpl{
-		userRepo:   userRepo,
-		topicRepo:  topicRepo,
-		videoRepo:  videoRepo,
-		followRepo: followRepo,
-		jwtSecret:  jwtSecret,
+		userRepo:    userRepo,
+		topicRepo:   topicRepo,
+		videoRepo:   videoRepo,
+		followRepo:  followRepo,
+		fileService: fileService,
+		jwtSecret:   jwtSecret,
 	}
 }
 
@@ -152,6 +155,7 @@ func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID uuid.UUID, r
 	if req.LastName != "" {
 		user.LastName = req.LastName
 	}
+	avatarChanged := req.Avatar != "" && req.Avatar != user.Avatar
 	if req.Avatar != "" {
 		user.Avatar = req.Avatar
 	}
@@ -172,6 +176,16 @@ func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID uuid.UUID, r
 		return nil, err
 	}
 
+	// Swap the file reference so the previous avatar can be cleaned up
+	if avatarChanged {
+		if err := s.fileService.ReleaseFiles(ctx, models.FileReferenceAvatar, userID); err != nil {
+			log.Printf("Warning: Failed to release previous avatar of user %s: %v", userID, err)
+		}
+		if err := s.fileService.AttachFileByURL(ctx, userID, user.Avatar, models.FileReferenceAvatar, userID); err != nil {
+			log.Printf("Warning: Failed to attach avatar of user %s: %v", userID, err)
+		}
+	}
+
 	return user, nil
 }
 
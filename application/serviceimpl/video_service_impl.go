@@ -3,30 +3,52 @@ package serviceimpl
 import (
 	"context"
 	"errors"
+	"fmt"
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/storage"
+	"log"
 	"math"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type videoServiceImpl struct {
-	videoRepo repositories.VideoRepository
-	fileRepo  repositories.FileRepository
-	userRepo  repositories.UserRepository
+	videoRepo    repositories.VideoRepository
+	fileRepo     repositories.FileRepository
+	userRepo     repositories.UserRepository
+	followRepo   repositories.FollowRepository
+	playlistRepo repositories.PlaylistRepository
+	bookmarkRepo repositories.BookmarkRepository
+	fileService  services.FileService
+	storage      storage.BunnyStorage
+	signedURLTTL time.Duration
 }
 
 func NewVideoService(
 	videoRepo repositories.VideoRepository,
 	fileRepo repositories.FileRepository,
 	userRepo repositories.UserRepository,
+	followRepo repositories.FollowRepository,
+	playlistRepo repositories.PlaylistRepository,
+	bookmarkRepo repositories.BookmarkRepository,
+	fileService services.FileService,
+	storage storage.BunnyStorage,
+	signedURLTTL time.Duration,
 ) services.VideoService {
 	return &videoServiceImpl{
-		videoRepo: videoRepo,
-		fileRepo:  fileRepo,
-		userRepo:  userRepo,
+		videoRepo:    videoRepo,
+		fileRepo:     fileRepo,
+		userRepo:     userRepo,
+		followRepo:   followRepo,
+		playlistRepo: playlistRepo,
+		bookmarkRepo: bookmarkRepo,
+		fileService:  fileService,
+		storage:      storage,
+		signedURLTTL: signedURLTTL,
 	}
 }
 
@@ -48,12 +70,22 @@ func (s *videoServiceImpl) CreateVideo(ctx context.Context, userID uuid.UUID, re
 		return nil, errors.New("you don't have permission to use this file")
 	}
 
+	// Video media is only served from the secure zone
+	if err := s.fileService.ProtectFile(ctx, videoFile); err != nil {
+		return nil, fmt.Errorf("failed to protect video file: %v", err)
+	}
+
 	// Get thumbnail URL if provided
 	var thumbnailURL string
+	var thumbnailFileID uuid.UUID
 	if req.ThumbnailID != uuid.Nil {
 		thumbnailFile, err := s.fileRepo.GetByID(ctx, req.ThumbnailID)
 		if err == nil && thumbnailFile.UserID == userID {
+			if err := s.fileService.ProtectFile(ctx, thumbnailFile); err != nil {
+				return nil, fmt.Errorf("failed to protect thumbnail: %v", err)
+			}
 			thumbnailURL = thumbnailFile.URL
+			thumbnailFileID = thumbnailFile.ID
 		}
 	}
 
@@ -75,42 +107,89 @@ func (s *videoServiceImpl) CreateVideo(ctx context.Context, userID uuid.UUID, re
 		return nil, err
 	}
 
+	// Mark the uploaded files as used so the orphan GC keeps them
+	if err := s.fileService.AttachFile(ctx, videoFile.ID, userID, models.FileReferenceVideo, video.ID); err != nil {
+		_ = s.videoRepo.Delete(ctx, video.ID)
+		return nil, err
+	}
+	if thumbnailFileID != uuid.Nil {
+		if err := s.fileService.AttachFile(ctx, thumbnailFileID, userID, models.FileReferenceVideoThumbnail, video.ID); err != nil {
+			log.Printf("Warning: Failed to attach thumbnail of video %s: %v", video.ID, err)
+		}
+	}
+
 	// Load user for response
 	video.User = user
 
-	return dto.VideoToVideoResponse(video), nil
+	return s.toVideoResponse(ctx, video, &userID, false), nil
 }
 
-func (s *videoServiceImpl) GetVideoByID(ctx context.Context, id uuid.UUID) (*dto.VideoResponse, error) {
+func (s *videoServiceImpl) GetVideoByID(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID) (*dto.VideoResponse, error) {
 	video, err := s.videoRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Increment view count asynchronously
-	go func() {
-		_ = s.videoRepo.IncrementViewCount(context.Background(), id)
-	}()
-
-	return dto.VideoToVideoResponse(video), nil
+	// Views are counted by VideoViewService from playback events, not by fetching the detail
+	resp := s.toVideoResponse(ctx, video, viewerID, false)
+	if viewerID != nil {
+		resp.IsBookmarked, _ = s.bookmarkRepo.IsBookmarked(ctx, *viewerID, models.BookmarkTargetVideo, id)
+	}
+	return resp, nil
 }
 
-func (s *videoServiceImpl) GetVideos(ctx context.Context, params *dto.VideoQueryParams) (*dto.VideoListResponse, error) {
+func (s *videoServiceImpl) GetVideos(ctx context.Context, params *dto.VideoQueryParams, viewerID *uuid.UUID) (*dto.VideoListResponse, error) {
 	videos, totalCount, err := s.videoRepo.FindAll(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.toVideoListResponse(videos, totalCount, params), nil
+	return s.toVideoListResponse(ctx, videos, totalCount, params, viewerID, false), nil
 }
 
-func (s *videoServiceImpl) GetUserVideos(ctx context.Context, userID uuid.UUID, params *dto.VideoQueryParams) (*dto.VideoListResponse, error) {
+func (s *videoServiceImpl) GetUserVideos(ctx context.Context, userID uuid.UUID, params *dto.VideoQueryParams, viewerID *uuid.UUID) (*dto.VideoListResponse, error) {
 	videos, totalCount, err := s.videoRepo.FindByUserID(ctx, userID, params)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.toVideoListResponse(videos, totalCount, params), nil
+	return s.toVideoListResponse(ctx, videos, totalCount, params, viewerID, false), nil
+}
+
+// GetVideosByIDs returns the active videos among ids, keyed by ID, with media resolved for the viewer
+func (s *videoServiceImpl) GetVideosByIDs(ctx context.Context, ids []uuid.UUID, viewerID *uuid.UUID) (map[uuid.UUID]dto.VideoResponse, error) {
+	videos, err := s.videoRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	followCache := make(map[uuid.UUID]bool)
+	responses := make(map[uuid.UUID]dto.VideoResponse, len(videos))
+	for i := range videos {
+		media := s.resolveMedia(ctx, &videos[i], viewerID, false, followCache)
+		responses[videos[i].ID] = *s.applyMedia(dto.VideoToVideoResponse(&videos[i]), media)
+	}
+
+	return responses, nil
+}
+
+// GetVideoMedia issues fresh media URLs for a viewer, including hidden videos for their owner and admins
+func (s *videoServiceImpl) GetVideoMedia(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID, isAdmin bool) (*dto.VideoMediaResponse, error) {
+	video, err := s.videoRepo.FindByIDIncludingInactive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	media := s.resolveMedia(ctx, video, viewerID, isAdmin, nil)
+	if media == nil {
+		// ไม่บอกว่ามีวิดีโออยู่ ถ้าเป็นวิดีโอที่ถูกซ่อน
+		if !video.IsActive {
+			return nil, errors.New("video not found")
+		}
+		return nil, errors.New("you don't have access to this video")
+	}
+
+	return media, nil
 }
 
 func (s *videoServiceImpl) UpdateVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID, req *dto.UpdateVideoRequest) (*dto.VideoResponse, error) {
@@ -139,7 +218,12 @@ func (s *videoServiceImpl) UpdateVideo(ctx context.Context, userID uuid.UUID, vi
 		return nil, err
 	}
 
-	return dto.VideoToVideoResponse(video), nil
+	// Hidden videos drop out of playlists
+	if !video.IsActive {
+		_ = s.playlistRepo.RemoveVideoFromAll(ctx, video.ID)
+	}
+
+	return s.toVideoResponse(ctx, video, &userID, false), nil
 }
 
 func (s *videoServiceImpl) DeleteVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID) error {
@@ -153,7 +237,11 @@ func (s *videoServiceImpl) DeleteVideo(ctx context.Context, userID uuid.UUID, vi
 		return errors.New("you don't have permission to delete this video")
 	}
 
-	return s.videoRepo.Delete(ctx, videoID)
+	if err := s.videoRepo.Delete(ctx, videoID); err != nil {
+		return err
+	}
+
+	return s.cleanupDeletedVideo(ctx, videoID)
 }
 
 // Admin operations
@@ -163,11 +251,15 @@ func (s *videoServiceImpl) GetAllVideos(ctx context.Context, params *dto.VideoQu
 		return nil, err
 	}
 
-	return s.toVideoListResponse(videos, totalCount, params), nil
+	return s.toVideoListResponse(ctx, videos, totalCount, params, nil, true), nil
 }
 
 func (s *videoServiceImpl) HideVideo(ctx context.Context, videoID uuid.UUID) error {
-	return s.videoRepo.SetActive(ctx, videoID, false)
+	if err := s.videoRepo.SetActive(ctx, videoID, false); err != nil {
+		return err
+	}
+
+	return s.playlistRepo.RemoveVideoFromAll(ctx, videoID)
 }
 
 func (s *videoServiceImpl) ShowVideo(ctx context.Context, videoID uuid.UUID) error {
@@ -175,14 +267,104 @@ func (s *videoServiceImpl) ShowVideo(ctx context.Context, videoID uuid.UUID) err
 }
 
 func (s *videoServiceImpl) DeleteVideoByAdmin(ctx context.Context, videoID uuid.UUID) error {
-	return s.videoRepo.Delete(ctx, videoID)
+	if err := s.videoRepo.Delete(ctx, videoID); err != nil {
+		return err
+	}
+
+	return s.cleanupDeletedVideo(ctx, videoID)
 }
 
 // Helper methods
-func (s *videoServiceImpl) toVideoListResponse(videos []models.Video, totalCount int64, params *dto.VideoQueryParams) *dto.VideoListResponse {
+// cleanupDeletedVideo removes playlist entries and releases the files of a deleted video
+func (s *videoServiceImpl) cleanupDeletedVideo(ctx context.Context, videoID uuid.UUID) error {
+	if err := s.playlistRepo.RemoveVideoFromAll(ctx, videoID); err != nil {
+		return err
+	}
+	if err := s.fileService.ReleaseFiles(ctx, models.FileReferenceVideo, videoID); err != nil {
+		return err
+	}
+	return s.fileService.ReleaseFiles(ctx, models.FileReferenceVideoThumbnail, videoID)
+}
+
+// resolveMedia applies the visibility rules and returns the URLs this viewer may use, or nil if none.
+// Video media lives in the token-only zone, so every URL is signed; active videos of public accounts
+// are flagged IsPublic so clients may share them.
+// followCache avoids repeating the follow lookup for the same owner within a list.
+func (s *videoServiceImpl) resolveMedia(ctx context.Context, video *models.Video, viewerID *uuid.UUID, isAdmin bool, followCache map[uuid.UUID]bool) *dto.VideoMediaResponse {
+	if video.User == nil {
+		owner, err := s.userRepo.GetByID(ctx, video.UserID)
+		if err != nil {
+			return nil
+		}
+		video.User = owner
+	}
+
+	expiresAt := time.Now().Add(s.signedURLTTL)
+	if video.IsActive && !video.User.IsPrivate {
+		return &dto.VideoMediaResponse{
+			VideoURL:     s.signURL(video.VideoURL, expiresAt),
+			ThumbnailURL: s.signURL(video.ThumbnailURL, expiresAt),
+			IsPublic:     true,
+			ExpiresAt:    &expiresAt,
+		}
+	}
+
+	allowed := isAdmin || (viewerID != nil && *viewerID == video.UserID)
+	if !allowed && video.IsActive && viewerID != nil {
+		// บัญชีส่วนตัว - ให้ดูได้เฉพาะผู้ติดตาม
+		following, cached := followCache[video.UserID]
+		if !cached {
+			following, _ = s.followRepo.IsFollowing(ctx, *viewerID, video.UserID)
+			if followCache != nil {
+				followCache[video.UserID] = following
+			}
+		}
+		allowed = following
+	}
+	if !allowed {
+		return nil
+	}
+
+	return &dto.VideoMediaResponse{
+		VideoURL:     s.signURL(video.VideoURL, expiresAt),
+		ThumbnailURL: s.signURL(video.ThumbnailURL, expiresAt),
+		ExpiresAt:    &expiresAt,
+	}
+}
+
+// signURL signs URLs served from our storage; external URLs are returned unchanged
+func (s *videoServiceImpl) signURL(fileURL string, expiresAt time.Time) string {
+	path := s.storage.GetPathFromURL(fileURL)
+	if path == "" {
+		return fileURL
+	}
+	return s.storage.GetSignedURL(path, expiresAt)
+}
+
+func (s *videoServiceImpl) toVideoResponse(ctx context.Context, video *models.Video, viewerID *uuid.UUID, isAdmin bool) *dto.VideoResponse {
+	return s.applyMedia(dto.VideoToVideoResponse(video), s.resolveMedia(ctx, video, viewerID, isAdmin, nil))
+}
+
+func (s *videoServiceImpl) applyMedia(resp *dto.VideoResponse, media *dto.VideoMediaResponse) *dto.VideoResponse {
+	if media == nil {
+		resp.VideoURL = ""
+		resp.ThumbnailURL = ""
+		resp.MediaRestricted = true
+		return resp
+	}
+
+	resp.VideoURL = media.VideoURL
+	resp.ThumbnailURL = media.ThumbnailURL
+	resp.MediaExpiresAt = media.ExpiresAt
+	return resp
+}
+
+func (s *videoServiceImpl) toVideoListResponse(ctx context.Context, videos []models.Video, totalCount int64, params *dto.VideoQueryParams, viewerID *uuid.UUID, isAdmin bool) *dto.VideoListResponse {
+	followCache := make(map[uuid.UUID]bool)
 	videoResponses := make([]dto.VideoResponse, len(videos))
-	for i, video := range videos {
-		videoResponses[i] = *dto.VideoToVideoResponse(&video)
+	for i := range videos {
+		media := s.resolveMedia(ctx, &videos[i], viewerID, isAdmin, followCache)
+		videoResponses[i] = *s.applyMedia(dto.VideoToVideoResponse(&videos[i]), media)
 	}
 
 	page := params.Page
@@ -0,0 +1,253 @@
+package serviceimpl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"gofiber-social/infrastructure/redis"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	viewCountPendingKey  = "video:views:pending"  // hash: videoID -> views not yet written to the DB
+	viewCountFlushingKey = "video:views:flushing" // pending hash taken over by the current flush
+	viewCountFlushLock   = "video:views:flush-lock"
+	viewDedupeKeyPrefix  = "video:view:dedupe:"
+
+	// Heartbeats may arrive a little later than the time they report
+	viewHeartbeatSlack = 5 * time.Second
+)
+
+// User agents that should never produce a view
+var botUserAgentMarkers = []string{"bot", "crawler", "spider", "slurp", "curl", "wget", "headless"}
+
+type videoViewServiceImpl struct {
+	viewRepo     repositories.VideoViewRepository
+	videoRepo    repositories.VideoRepository
+	redisClient  *redis.RedisClient
+	dedupeWindow time.Duration
+}
+
+func NewVideoViewService(
+	viewRepo repositories.VideoViewRepository,
+	videoRepo repositories.VideoRepository,
+	redisClient *redis.RedisClient,
+	dedupeWindow time.Duration,
+) services.VideoViewService {
+	return &videoViewServiceImpl{
+		viewRepo:     viewRepo,
+		videoRepo:    videoRepo,
+		redisClient:  redisClient,
+		dedupeWindow: dedupeWindow,
+	}
+}
+
+func (s *videoViewServiceImpl) TrackEvent(ctx context.Context, videoID uuid.UUID, viewerID *uuid.UUID, clientIP, userAgent string, req *dto.VideoViewEventRequest) (*dto.VideoViewEventResponse, error) {
+	video, err := s.videoRepo.FindByID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anonymous viewers are told apart by what the server sees, not by an ID the client picks
+	anonymousKey := ""
+	if viewerID == nil {
+		anonymousKey = anonymousViewerKey(clientIP, userAgent)
+	}
+
+	if req.Event == "start" {
+		return s.startView(ctx, video, viewerID, anonymousKey, userAgent, req)
+	}
+	return s.recordProgress(ctx, video, viewerID, anonymousKey, req)
+}
+
+func (s *videoViewServiceImpl) startView(ctx context.Context, video *models.Video, viewerID *uuid.UUID, anonymousKey, userAgent string, req *dto.VideoViewEventRequest) (*dto.VideoViewEventResponse, error) {
+	if isBotUserAgent(userAgent) {
+		return &dto.VideoViewEventResponse{Counted: false}, nil
+	}
+
+	viewer := "anon:" + anonymousKey
+	if viewerID != nil {
+		viewer = "user:" + viewerID.String()
+	}
+	dedupeKey := viewDedupeKeyPrefix + video.ID.String() + ":" + viewer
+
+	// ดูซ้ำภายใน window เดิม - ใช้ view เดิมต่อ ไม่นับเพิ่ม
+	viewID := uuid.New()
+	isNew, err := s.redisClient.SetNX(ctx, dedupeKey, viewID.String(), s.dedupeWindow)
+	if err != nil {
+		return nil, err
+	}
+	if !isNew {
+		var existing string
+		if err := s.redisClient.Get(ctx, dedupeKey, &existing); err == nil {
+			if existingID, err := uuid.Parse(existing); err == nil {
+				return &dto.VideoViewEventResponse{ViewID: existingID, Counted: false}, nil
+			}
+		}
+		return &dto.VideoViewEventResponse{Counted: false}, nil
+	}
+
+	source := req.Source
+	if source == "" {
+		source = "direct"
+	}
+
+	now := time.Now()
+	view := &models.VideoView{
+		ID:          viewID,
+		VideoID:     video.ID,
+		UserID:      viewerID,
+		SessionID:   anonymousKey,
+		Source:      source,
+		MaxPosition: s.clampPosition(video, req.Position),
+		LastEventAt: now,
+	}
+	if err := s.viewRepo.Create(ctx, view); err != nil {
+		_ = s.redisClient.Delete(ctx, dedupeKey)
+		return nil, err
+	}
+
+	if _, err := s.redisClient.HIncrBy(ctx, viewCountPendingKey, video.ID.String(), 1); err != nil {
+		return nil, err
+	}
+
+	return &dto.VideoViewEventResponse{ViewID: viewID, Counted: true}, nil
+}
+
+func (s *videoViewServiceImpl) recordProgress(ctx context.Context, video *models.Video, viewerID *uuid.UUID, anonymousKey string, req *dto.VideoViewEventRequest) (*dto.VideoViewEventResponse, error) {
+	if req.ViewID == uuid.Nil {
+		return nil, errors.New("viewId is required")
+	}
+
+	view, err := s.viewRepo.GetByID(ctx, req.ViewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The view must belong to this video and this viewer
+	if view.VideoID != video.ID {
+		return nil, errors.New("view not found")
+	}
+	if view.UserID != nil {
+		if viewerID == nil || *viewerID != *view.UserID {
+			return nil, errors.New("view not found")
+		}
+	} else if view.SessionID != anonymousKey {
+		return nil, errors.New("view not found")
+	}
+
+	// ไม่ให้ watch time เกินเวลาที่ผ่านไปจริงตั้งแต่ event ก่อนหน้า
+	now := time.Now()
+	watched := req.WatchedSeconds
+	maxWatched := int(now.Sub(view.LastEventAt.Add(-viewHeartbeatSlack)).Seconds())
+	if watched > maxWatched {
+		watched = maxWatched
+	}
+	if watched < 0 {
+		watched = 0
+	}
+
+	completed := req.Event == "complete"
+	if err := s.viewRepo.RecordProgress(ctx, view.ID, watched, s.clampPosition(video, req.Position), completed, now); err != nil {
+		return nil, err
+	}
+
+	return &dto.VideoViewEventResponse{ViewID: view.ID, Counted: false}, nil
+}
+
+func (s *videoViewServiceImpl) FlushViewCounts(ctx context.Context) (int, error) {
+	// Only one instance flushes at a time
+	// Only release our own lock: one that expired mid-flush may belong to another instance by now
+	lockToken := uuid.New().String()
+	locked, err := s.redisClient.SetNX(ctx, viewCountFlushLock, lockToken, time.Minute)
+	if err != nil || !locked {
+		return 0, err
+	}
+	defer s.redisClient.DeleteIfEquals(ctx, viewCountFlushLock, lockToken)
+
+	// A previous flush that failed half-way leaves its batch behind; retry it before taking a new one
+	hasLeftover, err := s.redisClient.Exists(ctx, viewCountFlushingKey)
+	if err != nil {
+		return 0, err
+	}
+	if !hasLeftover {
+		hasPending, err := s.redisClient.Exists(ctx, viewCountPendingKey)
+		if err != nil || !hasPending {
+			return 0, err
+		}
+		if err := s.redisClient.Rename(ctx, viewCountPendingKey, viewCountFlushingKey); err != nil {
+			return 0, err
+		}
+	}
+
+	pending, err := s.redisClient.HGetAll(ctx, viewCountFlushingKey)
+	if err != nil {
+		return 0, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(pending))
+	for field, value := range pending {
+		videoID, err := uuid.Parse(field)
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || count <= 0 {
+			continue
+		}
+		counts[videoID] = count
+	}
+
+	if len(counts) > 0 {
+		if err := s.videoRepo.AddViewCounts(ctx, counts); err != nil {
+			return 0, fmt.Errorf("failed to apply view counts: %w", err)
+		}
+	}
+
+	if err := s.redisClient.Delete(ctx, viewCountFlushingKey); err != nil {
+		return 0, err
+	}
+
+	return len(counts), nil
+}
+
+// Helper methods
+func (s *videoViewServiceImpl) clampPosition(video *models.Video, position int) int {
+	if position < 0 {
+		return 0
+	}
+	if video.Duration > 0 && position > video.Duration {
+		return video.Duration
+	}
+	return position
+}
+
+// anonymousViewerKey identifies a logged-out viewer by IP address and user agent; only the
+// hash is kept
+func anonymousViewerKey(clientIP, userAgent string) string {
+	sum := sha256.Sum256([]byte(clientIP + "\n" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+func isBotUserAgent(userAgent string) bool {
+	if userAgent == "" {
+		return true
+	}
+	ua := strings.ToLower(userAgent)
+	for _, marker := range botUserAgentMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
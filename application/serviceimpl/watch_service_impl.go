@@ -0,0 +1,331 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"gofiber-social/domain/services"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type watchServiceImpl struct {
+	watchRepo           repositories.WatchRepository
+	readRepo            repositories.ReadRepository
+	topicRepo           repositories.TopicRepository
+	forumService        services.ForumService
+	notificationService services.NotificationService
+	autoWatchOnPost     bool
+	autoWatchOnReply    bool
+	notifyBatchSize     int
+}
+
+func NewWatchService(
+	watchRepo repositories.WatchRepository,
+	readRepo repositories.ReadRepository,
+	topicRepo repositories.TopicRepository,
+	forumService services.ForumService,
+	notificationService services.NotificationService,
+	autoWatchOnPost bool,
+	autoWatchOnReply bool,
+	notifyBatchSize int,
+) services.WatchService {
+	if notifyBatchSize < 1 {
+		notifyBatchSize = 500
+	}
+	return &watchServiceImpl{
+		watchRepo:           watchRepo,
+		readRepo:            readRepo,
+		topicRepo:           topicRepo,
+		forumService:        forumService,
+		notificationService: notificationService,
+		autoWatchOnPost:     autoWatchOnPost,
+		autoWatchOnReply:    autoWatchOnReply,
+		notifyBatchSize:     notifyBatchSize,
+	}
+}
+
+func (s *watchServiceImpl) WatchTopic(ctx context.Context, userID, topicID uuid.UUID) (*dto.WatchStatusResponse, error) {
+	if err := s.checkTopicAccess(ctx, userID, topicID); err != nil {
+		return nil, err
+	}
+
+	if err := s.watchRepo.WatchTopic(ctx, userID, topicID); err != nil {
+		return nil, err
+	}
+
+	// Replies from before the watch started don't count as unread
+	_ = s.readRepo.MarkTopicRead(ctx, userID, topicID, nil, time.Now())
+
+	return &dto.WatchStatusResponse{IsWatching: true}, nil
+}
+
+func (s *watchServiceImpl) UnwatchTopic(ctx context.Context, userID, topicID uuid.UUID) (*dto.WatchStatusResponse, error) {
+	// Unwatching doesn't check access, so users can leave topics they can no longer see
+	if _, err := s.topicRepo.GetByID(ctx, topicID); err != nil {
+		return nil, errors.New("topic not found")
+	}
+
+	if err := s.watchRepo.UnwatchTopic(ctx, userID, topicID); err != nil {
+		return nil, err
+	}
+
+	return &dto.WatchStatusResponse{IsWatching: false}, nil
+}
+
+func (s *watchServiceImpl) GetTopicWatchStatus(ctx context.Context, userID, topicID uuid.UUID) (*dto.WatchStatusResponse, error) {
+	isWatching, err := s.watchRepo.IsWatchingTopic(ctx, userID, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WatchStatusResponse{IsWatching: isWatching}, nil
+}
+
+func (s *watchServiceImpl) GetWatchedTopics(ctx context.Context, userID uuid.UUID, params *dto.WatchedTopicQueryParams) (*dto.WatchedTopicListResponse, error) {
+	hiddenForumIDs, err := s.forumService.GetHiddenForumIDs(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repositories.WatchedTopicFilter{
+		UnreadOnly:      params.Unread,
+		ExcludeForumIDs: hiddenForumIDs,
+	}
+
+	page, limit := dto.NormalizePage(params.Page, params.Limit)
+	offset := (page - 1) * limit
+	watched, total, err := s.watchRepo.FindWatchedTopics(ctx, userID, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	topicIDs := make([]uuid.UUID, len(watched))
+	for i, item := range watched {
+		topicIDs[i] = item.TopicID
+	}
+
+	topics, err := s.topicRepo.GetByIDs(ctx, topicIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Topic, len(topics))
+	for _, topic := range topics {
+		byID[topic.ID] = topic
+	}
+
+	responses := make([]dto.WatchedTopicResponse, 0, len(watched))
+	for _, item := range watched {
+		topic, ok := byID[item.TopicID]
+		if !ok {
+			continue
+		}
+		responses = append(responses, dto.WatchedTopicResponse{
+			Topic:       *dto.TopicToTopicResponse(topic),
+			UnreadCount: item.UnreadCount,
+			LastReadAt:  item.LastReadAt,
+		})
+	}
+
+	return &dto.WatchedTopicListResponse{
+		Topics: responses,
+		Meta:   dto.NewPaginationMeta(total, offset, limit),
+	}, nil
+}
+
+func (s *watchServiceImpl) WatchForum(ctx context.Context, userID, forumID uuid.UUID) (*dto.WatchStatusResponse, error) {
+	if err := s.forumService.CheckAccess(ctx, forumID, &userID, models.ForumActionView); err != nil {
+		return nil, err
+	}
+
+	if err := s.watchRepo.WatchForum(ctx, userID, forumID); err != nil {
+		return nil, err
+	}
+
+	return &dto.WatchStatusResponse{IsWatching: true}, nil
+}
+
+func (s *watchServiceImpl) UnwatchForum(ctx context.Context, userID, forumID uuid.UUID) (*dto.WatchStatusResponse, error) {
+	if _, err := s.watchRepo.UnwatchForum(ctx, userID, forumID); err != nil {
+		return nil, err
+	}
+
+	return &dto.WatchStatusResponse{IsWatching: false}, nil
+}
+
+func (s *watchServiceImpl) GetForumWatchStatus(ctx context.Context, userID, forumID uuid.UUID) (*dto.WatchStatusResponse, error) {
+	isWatching, err := s.watchRepo.IsWatchingForum(ctx, userID, forumID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WatchStatusResponse{IsWatching: isWatching}, nil
+}
+
+func (s *watchServiceImpl) GetWatchedForums(ctx context.Context, userID uuid.UUID) ([]*dto.ForumResponse, error) {
+	forumIDs, err := s.watchRepo.FindWatchedForumIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Forums that were deleted or are no longer visible to the user are left out
+	forums := make([]*dto.ForumResponse, 0, len(forumIDs))
+	for _, forumID := range forumIDs {
+		forum, err := s.forumService.GetForumByID(ctx, forumID, &userID)
+		if err != nil {
+			continue
+		}
+		forums = append(forums, forum)
+	}
+
+	return forums, nil
+}
+
+func (s *watchServiceImpl) GetPreferences(ctx context.Context, userID uuid.UUID) (*dto.WatchPreferenceResponse, error) {
+	onPost, onReply, err := s.autoWatch(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WatchPreferenceResponse{
+		AutoWatchOnPost:  onPost,
+		AutoWatchOnReply: onReply,
+	}, nil
+}
+
+func (s *watchServiceImpl) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *dto.UpdateWatchPreferenceRequest) (*dto.WatchPreferenceResponse, error) {
+	onPost, onReply, err := s.autoWatch(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preference := &models.WatchPreference{
+		UserID:           userID,
+		AutoWatchOnPost:  onPost,
+		AutoWatchOnReply: onReply,
+	}
+	if req.AutoWatchOnPost != nil {
+		preference.AutoWatchOnPost = *req.AutoWatchOnPost
+	}
+	if req.AutoWatchOnReply != nil {
+		preference.AutoWatchOnReply = *req.AutoWatchOnReply
+	}
+
+	if err := s.watchRepo.SavePreference(ctx, preference); err != nil {
+		return nil, err
+	}
+
+	return &dto.WatchPreferenceResponse{
+		AutoWatchOnPost:  preference.AutoWatchOnPost,
+		AutoWatchOnReply: preference.AutoWatchOnReply,
+	}, nil
+}
+
+func (s *watchServiceImpl) HandleNewTopic(ctx context.Context, topic *models.Topic) ([][]uuid.UUID, error) {
+	onPost, _, err := s.autoWatch(ctx, topic.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if onPost {
+		if err := s.watchRepo.AutoWatchTopic(ctx, topic.UserID, topic.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.readRepo.MarkTopicRead(ctx, topic.UserID, topic.ID, nil, topic.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	watcherIDs, err := s.watchRepo.GetForumWatcherIDs(ctx, topic.ForumID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, err := s.recipients(ctx, topic.ForumID, watcherIDs, topic.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return chunkUserIDs(recipients, s.notifyBatchSize), nil
+}
+
+func (s *watchServiceImpl) HandleNewReply(ctx context.Context, reply *models.Reply, topic *models.Topic) ([][]uuid.UUID, error) {
+	_, onReply, err := s.autoWatch(ctx, reply.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if onReply {
+		if err := s.watchRepo.AutoWatchTopic(ctx, reply.UserID, topic.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Whoever replies has read the thread up to their own reply
+	if err := s.readRepo.MarkTopicRead(ctx, reply.UserID, topic.ID, &reply.ID, reply.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	watcherIDs, err := s.watchRepo.GetTopicWatcherIDs(ctx, topic.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The topic author already gets a topic_reply notification of their own
+	recipients, err := s.recipients(ctx, topic.ForumID, watcherIDs, reply.UserID, topic.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return chunkUserIDs(recipients, s.notifyBatchSize), nil
+}
+
+// Helper methods
+
+func (s *watchServiceImpl) checkTopicAccess(ctx context.Context, userID, topicID uuid.UUID) error {
+	topic, err := s.topicRepo.GetByID(ctx, topicID)
+	if err != nil {
+		return errors.New("topic not found")
+	}
+	return s.forumService.CheckAccess(ctx, topic.ForumID, &userID, models.ForumActionView)
+}
+
+// autoWatch returns the user's auto-watch settings, falling back to the configured defaults
+func (s *watchServiceImpl) autoWatch(ctx context.Context, userID uuid.UUID) (bool, bool, error) {
+	preference, err := s.watchRepo.GetPreference(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrWatchPreferenceNotFound) {
+			return s.autoWatchOnPost, s.autoWatchOnReply, nil
+		}
+		return false, false, err
+	}
+	return preference.AutoWatchOnPost, preference.AutoWatchOnReply, nil
+}
+
+// recipients drops the excluded users and watchers who can no longer view the forum
+func (s *watchServiceImpl) recipients(ctx context.Context, forumID uuid.UUID, watcherIDs []uuid.UUID, exclude ...uuid.UUID) ([]uuid.UUID, error) {
+	skip := make(map[uuid.UUID]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+
+	candidates := make([]uuid.UUID, 0, len(watcherIDs))
+	for _, watcherID := range watcherIDs {
+		if !skip[watcherID] {
+			candidates = append(candidates, watcherID)
+		}
+	}
+	return s.forumService.FilterViewers(ctx, forumID, candidates)
+}
+
+func chunkUserIDs(ids []uuid.UUID, size int) [][]uuid.UUID {
+	var chunks [][]uuid.UUID
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+	return chunks
+}
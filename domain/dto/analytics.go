@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type AnalyticsQueryParams struct {
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02"` // default: 27 days before To
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02"`   // default: today
+}
+
+type AnalyticsRollupRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+}
+
+// Response DTOs
+type AnalyticsPoint struct {
+	Date           string `json:"date"`
+	Views          int64  `json:"views"`
+	WatchTime      int64  `json:"watchTime"`
+	CompletedViews int64  `json:"completedViews"`
+	Likes          int64  `json:"likes"`
+	Comments       int64  `json:"comments"`
+	Shares         int64  `json:"shares"`
+}
+
+type AnalyticsTotals struct {
+	Views          int64   `json:"views"`
+	WatchTime      int64   `json:"watchTime"`
+	AvgWatchTime   float64 `json:"avgWatchTime"`   // seconds per view (videos only)
+	CompletionRate float64 `json:"completionRate"` // 0-1 (videos only)
+	Likes          int64   `json:"likes"`
+	Comments       int64   `json:"comments"`
+	Shares         int64   `json:"shares"`
+}
+
+type SharePlatformPoint struct {
+	Date     string `json:"date"`
+	Platform string `json:"platform"`
+	Shares   int64  `json:"shares"`
+}
+
+type FollowerGrowthPoint struct {
+	Date           string `json:"date"`
+	NewFollowers   int64  `json:"newFollowers"`
+	TotalFollowers int64  `json:"totalFollowers"`
+}
+
+type TopContentItem struct {
+	ContentType string    `json:"contentType"`
+	ContentID   uuid.UUID `json:"contentId"`
+	Title       string    `json:"title"`
+	Views       int64     `json:"views"`
+	Likes       int64     `json:"likes"`
+	Comments    int64     `json:"comments"`
+	Shares      int64     `json:"shares"`
+}
+
+type ContentAnalyticsResponse struct {
+	ContentType      string               `json:"contentType"`
+	ContentID        uuid.UUID            `json:"contentId"`
+	Title            string               `json:"title"`
+	From             string               `json:"from"`
+	To               string               `json:"to"`
+	Totals           AnalyticsTotals      `json:"totals"`
+	Series           []AnalyticsPoint     `json:"series"`
+	SharesByPlatform map[string]int64     `json:"sharesByPlatform,omitempty"`
+	ShareSeries      []SharePlatformPoint `json:"shareSeries,omitempty"`
+}
+
+type CreatorAnalyticsResponse struct {
+	From             string                `json:"from"`
+	To               string                `json:"to"`
+	Totals           AnalyticsTotals       `json:"totals"`
+	Series           []AnalyticsPoint      `json:"series"`
+	SharesByPlatform map[string]int64      `json:"sharesByPlatform"`
+	ShareSeries      []SharePlatformPoint  `json:"shareSeries"`
+	FollowerGrowth   []FollowerGrowthPoint `json:"followerGrowth"`
+	TopVideos        []TopContentItem      `json:"topVideos"`
+	TopTopics        []TopContentItem      `json:"topTopics"`
+}
@@ -0,0 +1,153 @@
+package dto
+
+import (
+	"time"
+
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// Background task types; each has a payload struct below
+const (
+	TaskTypeNotifyLike          = "notify_like"           // notification for a new like
+	TaskTypeNotifyReply         = "notify_reply"          // topic reply notification for the topic author
+	TaskTypeWatchTopic          = "watch_topic"           // auto-watch for the topic author; queues notify_forum_watchers per batch
+	TaskTypeNotifyForumWatchers = "notify_forum_watchers" // watched forum topic notifications for one batch of watchers
+	TaskTypeWatchReply          = "watch_reply"           // auto-watch for the replier; queues notify_reply_watchers per batch
+	TaskTypeNotifyReplyWatchers = "notify_reply_watchers" // watched topic reply notifications for one batch of watchers
+	TaskTypeNotifyComment       = "notify_comment"        // video comment or comment reply notification
+	TaskTypeSyncLikeCount       = "sync_like_count"       // recount the likes of a topic or video
+	TaskTypeSyncCommentCount    = "sync_comment_count"    // recount the comments of a video
+)
+
+// NotifyLikeTask notifies the owner of a liked topic, video, reply or comment
+type NotifyLikeTask struct {
+	TargetType string    `json:"targetType"` // topic, video, reply or comment
+	TargetID   uuid.UUID `json:"targetId"`
+	ActorID    uuid.UUID `json:"actorId"`
+}
+
+func (NotifyLikeTask) TaskType() string { return TaskTypeNotifyLike }
+
+type NotifyReplyTask struct {
+	ReplyID uuid.UUID `json:"replyId"`
+}
+
+func (NotifyReplyTask) TaskType() string { return TaskTypeNotifyReply }
+
+type WatchTopicTask struct {
+	TopicID uuid.UUID `json:"topicId"`
+}
+
+func (WatchTopicTask) TaskType() string { return TaskTypeWatchTopic }
+
+// NotifyForumWatchersTask is one batch of a new topic's forum watcher fan-out, queued once per batch
+type NotifyForumWatchersTask struct {
+	TopicID    uuid.UUID   `json:"topicId"`
+	WatcherIDs []uuid.UUID `json:"watcherIds"`
+}
+
+func (NotifyForumWatchersTask) TaskType() string { return TaskTypeNotifyForumWatchers }
+
+type WatchReplyTask struct {
+	ReplyID uuid.UUID `json:"replyId"`
+}
+
+func (WatchReplyTask) TaskType() string { return TaskTypeWatchReply }
+
+// NotifyReplyWatchersTask is one batch of a reply's watcher fan-out, queued once per batch
+type NotifyReplyWatchersTask struct {
+	TopicID    uuid.UUID   `json:"topicId"`
+	ReplyID    uuid.UUID   `json:"replyId"`
+	ActorID    uuid.UUID   `json:"actorId"`
+	WatcherIDs []uuid.UUID `json:"watcherIds"`
+}
+
+func (NotifyReplyWatchersTask) TaskType() string { return TaskTypeNotifyReplyWatchers }
+
+type NotifyCommentTask struct {
+	CommentID uuid.UUID `json:"commentId"`
+}
+
+func (NotifyCommentTask) TaskType() string { return TaskTypeNotifyComment }
+
+type SyncLikeCountTask struct {
+	TargetType string    `json:"targetType"` // topic, video or reply
+	TargetID   uuid.UUID `json:"targetId"`
+}
+
+func (SyncLikeCountTask) TaskType() string { return TaskTypeSyncLikeCount }
+
+type SyncCommentCountTask struct {
+	VideoID uuid.UUID `json:"videoId"`
+}
+
+func (SyncCommentCountTask) TaskType() string { return TaskTypeSyncCommentCount }
+
+// Request DTOs
+type BackgroundTaskListParams struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending processing completed dead"`
+	Type   string `query:"type" validate:"omitempty,max=50"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type ReplayTasksRequest struct {
+	TaskIDs []uuid.UUID `json:"taskIds" validate:"omitempty,max=100"` // every dead task (of Type) when empty
+	Type    string      `json:"type" validate:"omitempty,max=50"`
+}
+
+// Response DTOs
+type BackgroundTaskResponse struct {
+	ID             uuid.UUID                   `json:"id"`
+	Type           string                      `json:"type"`
+	Payload        string                      `json:"payload"`
+	IdempotencyKey *string                     `json:"idempotencyKey,omitempty"`
+	Status         models.BackgroundTaskStatus `json:"status"`
+	Attempts       int                         `json:"attempts"`
+	MaxAttempts    int                         `json:"maxAttempts"`
+	RunAt          time.Time                   `json:"runAt"`
+	LockedBy       string                      `json:"lockedBy,omitempty"`
+	LastError      string                      `json:"lastError,omitempty"`
+	CompletedAt    *time.Time                  `json:"completedAt,omitempty"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+	UpdatedAt      time.Time                   `json:"updatedAt"`
+}
+
+type BackgroundTaskListResponse struct {
+	Tasks []BackgroundTaskResponse `json:"tasks"`
+	Meta  PaginationMeta           `json:"meta"`
+}
+
+type BackgroundTaskStatsResponse struct {
+	Pending    int64 `json:"pending"`
+	Processing int64 `json:"processing"`
+	Completed  int64 `json:"completed"`
+	Dead       int64 `json:"dead"`
+	Workers    int   `json:"workers"` // on the replica that answered
+}
+
+type ReplayTasksResponse struct {
+	Replayed int64 `json:"replayed"`
+}
+
+// ============= Converters =============
+
+func BackgroundTaskToBackgroundTaskResponse(task *models.BackgroundTask) *BackgroundTaskResponse {
+	return &BackgroundTaskResponse{
+		ID:             task.ID,
+		Type:           task.Type,
+		Payload:        task.Payload,
+		IdempotencyKey: task.IdempotencyKey,
+		Status:         task.Status,
+		Attempts:       task.Attempts,
+		MaxAttempts:    task.MaxAttempts,
+		RunAt:          task.RunAt,
+		LockedBy:       task.LockedBy,
+		LastError:      task.LastError,
+		CompletedAt:    task.CompletedAt,
+		CreatedAt:      task.CreatedAt,
+		UpdatedAt:      task.UpdatedAt,
+	}
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============= Request DTOs =============
+
+type CreateBookmarkRequest struct {
+	TargetType string     `json:"targetType" validate:"required,oneof=topic video reply comment"`
+	TargetID   uuid.UUID  `json:"targetId" validate:"required"`
+	FolderID   *uuid.UUID `json:"folderId"` // optional folder to save into
+}
+
+// MoveBookmarkRequest puts a bookmark into a folder, or takes it out with folderId = null
+type MoveBookmarkRequest struct {
+	FolderID *uuid.UUID `json:"folderId"`
+}
+
+type BookmarkQueryParams struct {
+	Type     string `query:"type" validate:"omitempty,oneof=topic video reply comment"`
+	FolderID string `query:"folderId" validate:"omitempty,uuid4|eq=none"` // "none" = bookmarks outside folders
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type CreateBookmarkFolderRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type UpdateBookmarkFolderRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// ============= Response DTOs =============
+
+// BookmarkResponse carries the saved content under the field matching targetType
+type BookmarkResponse struct {
+	ID         uuid.UUID        `json:"id"`
+	TargetType string           `json:"targetType"`
+	TargetID   uuid.UUID        `json:"targetId"`
+	FolderID   *uuid.UUID       `json:"folderId,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	Topic      *TopicResponse   `json:"topic,omitempty"`
+	Video      *VideoResponse   `json:"video,omitempty"`
+	Reply      *ReplyResponse   `json:"reply,omitempty"`
+	Comment    *CommentResponse `json:"comment,omitempty"`
+}
+
+type BookmarkListResponse struct {
+	Bookmarks []BookmarkResponse `json:"bookmarks"`
+	Meta      PaginationMeta     `json:"meta"`
+}
+
+type BookmarkStatusResponse struct {
+	IsBookmarked bool       `json:"isBookmarked"`
+	FolderID     *uuid.UUID `json:"folderId,omitempty"`
+}
+
+type BookmarkFolderResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	ItemCount int       `json:"itemCount"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ============= Converters =============
+
+func BookmarkToBookmarkResponse(bookmark *models.Bookmark) *BookmarkResponse {
+	return &BookmarkResponse{
+		ID:         bookmark.ID,
+		TargetType: string(bookmark.TargetType),
+		TargetID:   bookmark.TargetID,
+		FolderID:   bookmark.FolderID,
+		CreatedAt:  bookmark.CreatedAt,
+	}
+}
+
+func BookmarkFolderToBookmarkFolderResponse(folder *models.BookmarkFolder) *BookmarkFolderResponse {
+	return &BookmarkFolderResponse{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		ItemCount: folder.ItemCount,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"gofiber-social/domain/models"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,6 +26,9 @@ type CommentResponse struct {
 	VideoID   uuid.UUID          `json:"videoId"`
 	ParentID  *uuid.UUID         `json:"parentId,omitempty"`
 	Content   string             `json:"content"`
+	IsEdited  bool               `json:"isEdited"`
+	EditCount int                `json:"editCount"`
+	EditedAt  *time.Time         `json:"editedAt,omitempty"`
 	CreatedAt time.Time          `json:"createdAt"`
 	UpdatedAt time.Time          `json:"updatedAt"`
 	Replies   []CommentResponse  `json:"replies,omitempty"` // Nested replies
@@ -38,6 +42,29 @@ type CommentListResponse struct {
 	TotalPages int               `json:"totalPages"`
 }
 
+func CommentToCommentResponse(comment *models.Comment, user *models.User) *CommentResponse {
+	return &CommentResponse{
+		ID:     comment.ID,
+		UserID: comment.UserID,
+		User: UserSummaryComment{
+			ID:        user.ID,
+			Username:  user.Username,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Avatar:    user.Avatar,
+		},
+		VideoID:   comment.VideoID,
+		ParentID:  comment.ParentID,
+		Content:   comment.Content,
+		IsEdited:  comment.EditCount > 0,
+		EditCount: comment.EditCount,
+		EditedAt:  comment.EditedAt,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Replies:   []CommentResponse{}, // Initialize empty slice
+	}
+}
+
 // UserSummaryComment for comment responses
 type UserSummaryComment struct {
 	ID        uuid.UUID `json:"id"`
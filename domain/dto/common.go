@@ -46,6 +46,20 @@ func NewPaginationMeta(total int64, offset, limit int) PaginationMeta {
 	}
 }
 
+// NormalizePage clamps page-based paging: page 1 and 20 per page by default, at most 100 per page
+func NormalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
 type IDRequest struct {
 	ID uuid.UUID `json:"id" validate:"required" param:"id"`
 }
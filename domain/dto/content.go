@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"gofiber-social/pkg/markdown"
+
+	"github.com/google/uuid"
+)
+
+// RenderedContent is validated Markdown together with its sanitized HTML
+type RenderedContent struct {
+	HTML    string
+	FileIDs []uuid.UUID // uploaded images embedded in the content
+	Links   []string    // external links to unfurl
+}
+
+type PreviewContentRequest struct {
+	Content string `json:"content" validate:"required,max=10000"`
+}
+
+type PreviewContentResponse struct {
+	ContentHTML string `json:"contentHtml"`
+}
+
+type LinkPreviewResponse struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+}
+
+// contentHTML returns the cached render, rendering on the fly for posts stored before it existed
+func contentHTML(cached, source string) string {
+	if cached != "" {
+		return cached
+	}
+	return markdown.Parse(source).HTML(markdown.Embeds{})
+}
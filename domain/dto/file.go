@@ -11,6 +11,8 @@ type UploadFileRequest struct {
 	Category   string `json:"category" validate:"omitempty,min=1,max=50"`
 	EntityID   string `json:"entityId" validate:"omitempty,uuid"`
 	FileType   string `json:"fileType" validate:"omitempty,min=1,max=50"`
+	// Protected stores the file in the secure zone; videos always are
+	Protected bool `json:"protected"`
 }
 
 type FileResponse struct {
@@ -26,8 +28,20 @@ type FileResponse struct {
 }
 
 type FileListResponse struct {
-	Files []FileResponse `json:"files"`
-	Meta  PaginationMeta `json:"meta"`
+	Files []FileResponse        `json:"files"`
+	Meta  PaginationMeta        `json:"meta"`
+	Usage *StorageUsageResponse `json:"usage,omitempty"`
+}
+
+type StorageUsageResponse struct {
+	UsedBytes      int64   `json:"usedBytes"`
+	QuotaBytes     int64   `json:"quotaBytes"`
+	RemainingBytes int64   `json:"remainingBytes"`
+	UsedPercent    float64 `json:"usedPercent"`
+}
+
+type CleanupFilesResponse struct {
+	DeletedCount int `json:"deletedCount"`
 }
 
 type UploadResponse struct {
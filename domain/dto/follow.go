@@ -69,3 +69,19 @@ type UserStatsResponse struct {
 	FollowerCount  int64     `json:"followerCount"`
 	FollowingCount int64     `json:"followingCount"`
 }
+
+type BlockedUserResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	FullName  string    `json:"fullName"`
+	Avatar    string    `json:"avatar,omitempty"`
+	BlockedAt time.Time `json:"blockedAt"`
+}
+
+type BlockListResponse struct {
+	Users      []BlockedUserResponse `json:"users"`
+	TotalCount int64                 `json:"totalCount"`
+	Page       int                   `json:"page"`
+	Limit      int                   `json:"limit"`
+	TotalPages int                   `json:"totalPages"`
+}
@@ -8,19 +8,40 @@ import (
 
 // Request DTOs
 type CreateForumRequest struct {
-	Name        string `json:"name" validate:"required,min=3,max=100"`
-	Slug        string `json:"slug" validate:"required,min=3,max=100,lowercase,alphanum"`
-	Description string `json:"description" validate:"required,min=10,max=500"`
-	Icon        string `json:"icon" validate:"omitempty,url"`
-	Order       int    `json:"order" validate:"min=0"`
+	Name        string                  `json:"name" validate:"required,min=3,max=100"`
+	Slug        string                  `json:"slug" validate:"required,min=3,max=100,lowercase,alphanum"`
+	Description string                  `json:"description" validate:"required,min=10,max=500"`
+	Icon        string                  `json:"icon" validate:"omitempty,url"`
+	Order       int                     `json:"order" validate:"min=0"`
+	IsQA        bool                    `json:"isQa"`                                 // Q&A mode
+	ParentID    *string                 `json:"parentId" validate:"omitempty,uuid4"` // สร้างเป็น sub-forum
+	ViewRule    *ForumAccessRuleRequest `json:"viewRule"`
+	PostRule    *ForumAccessRuleRequest `json:"postRule"`
+	ReplyRule   *ForumAccessRuleRequest `json:"replyRule"`
 }
 
 type UpdateForumRequest struct {
-	Name        string `json:"name" validate:"omitempty,min=3,max=100"`
-	Description string `json:"description" validate:"omitempty,min=10,max=500"`
-	Icon        string `json:"icon" validate:"omitempty,url"`
-	Order       int    `json:"order" validate:"omitempty,min=0"`
-	IsActive    *bool  `json:"isActive"` // pointer เพื่อรองรับ true/false
+	Name        string                  `json:"name" validate:"omitempty,min=3,max=100"`
+	Description string                  `json:"description" validate:"omitempty,min=10,max=500"`
+	Icon        string                  `json:"icon" validate:"omitempty,url"`
+	Order       int                     `json:"order" validate:"omitempty,min=0"`
+	IsActive    *bool                   `json:"isActive"`                                // pointer เพื่อรองรับ true/false
+	IsQA        *bool                   `json:"isQa"`
+	ParentID    *string                 `json:"parentId" validate:"omitempty,uuid4|eq="` // "" = ย้ายไปเป็น forum หลัก
+	ViewRule    *ForumAccessRuleRequest `json:"viewRule"`
+	PostRule    *ForumAccessRuleRequest `json:"postRule"`
+	ReplyRule   *ForumAccessRuleRequest `json:"replyRule"`
+}
+
+// ForumAccessRuleRequest: audience = everyone | verified | role (ต้องระบุ role)
+type ForumAccessRuleRequest struct {
+	Audience          string `json:"audience" validate:"required,oneof=everyone verified role"`
+	MinAccountAgeDays int    `json:"minAccountAgeDays" validate:"min=0,max=3650"`
+	Role              string `json:"role" validate:"omitempty,max=20"`
+}
+
+type AddForumModeratorRequest struct {
+	UserID uuid.UUID `json:"userId" validate:"required"`
 }
 
 type ReorderForumsRequest struct {
@@ -34,16 +55,57 @@ type ForumOrder struct {
 
 // Response DTOs
 type ForumResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Slug        string    `json:"slug"`
-	Description string    `json:"description"`
-	Icon        string    `json:"icon"`
-	Order       int       `json:"order"`
-	IsActive    bool      `json:"isActive"`
-	TopicCount  int       `json:"topicCount"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uuid.UUID           `json:"id"`
+	Name        string              `json:"name"`
+	Slug        string              `json:"slug"`
+	Description string              `json:"description"`
+	Icon        string              `json:"icon"`
+	Order       int                 `json:"order"`
+	IsActive    bool                `json:"isActive"`
+	IsQA        bool                `json:"isQa"`
+	TopicCount  int                 `json:"topicCount"`
+	UnreadTopicCount int            `json:"unreadTopicCount"` // topics with new activity for the logged-in viewer
+	ParentID    *uuid.UUID          `json:"parentId,omitempty"`
+	Access      ForumAccessResponse `json:"access"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+
+	// Detail only (GetForumByID / GetForumBySlug)
+	Breadcrumbs []ForumBreadcrumb `json:"breadcrumbs,omitempty"` // root -> forum นี้
+	Children    []ForumResponse   `json:"children,omitempty"`
+	Permissions *ForumPermissions `json:"permissions,omitempty"` // สิทธิ์ของผู้ที่กำลังดู
+}
+
+type ForumAccessRuleResponse struct {
+	Audience          string `json:"audience"`
+	MinAccountAgeDays int    `json:"minAccountAgeDays"`
+	Role              string `json:"role,omitempty"`
+}
+
+type ForumAccessResponse struct {
+	View  ForumAccessRuleResponse `json:"view"`
+	Post  ForumAccessRuleResponse `json:"post"`
+	Reply ForumAccessRuleResponse `json:"reply"`
+}
+
+type ForumBreadcrumb struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+type ForumPermissions struct {
+	CanView     bool `json:"canView"`
+	CanPost     bool `json:"canPost"`
+	CanReply    bool `json:"canReply"`
+	CanModerate bool `json:"canModerate"`
+}
+
+type ForumModeratorResponse struct {
+	ForumID    uuid.UUID   `json:"forumId"`
+	User       UserSummary `json:"user"`
+	AssignedBy uuid.UUID   `json:"assignedBy"`
+	CreatedAt  time.Time   `json:"createdAt"`
 }
 
 type ForumListResponse struct {
@@ -6,34 +6,94 @@ import (
 )
 
 type CreateJobRequest struct {
-	Name     string `json:"name" validate:"required,min=1,max=100"`
-	CronExpr string `json:"cronExpr" validate:"required,min=5,max=50"`
-	Payload  string `json:"payload" validate:"omitempty,json"`
+	Name           string `json:"name" validate:"required,min=1,max=100"`
+	Type           string `json:"type" validate:"required,max=50"` // one of GET /jobs/types
+	CronExpr       string `json:"cronExpr" validate:"required,min=5,max=50"`
+	Timezone       string `json:"timezone" validate:"omitempty,max=64"` // IANA name, e.g. Asia/Bangkok; JOB_TIMEZONE when empty
+	Payload        string `json:"payload" validate:"omitempty,json"`
+	TimeoutSeconds int    `json:"timeoutSeconds" validate:"omitempty,min=0,max=86400"` // no limit when 0
+	MaxConcurrency int    `json:"maxConcurrency" validate:"omitempty,min=1,max=10"`    // 1 when omitted
 }
 
+// UpdateJobRequest changes only the fields that are set
 type UpdateJobRequest struct {
-	Name     string `json:"name" validate:"omitempty,min=1,max=100"`
-	CronExpr string `json:"cronExpr" validate:"omitempty,min=5,max=50"`
-	Payload  string `json:"payload" validate:"omitempty,json"`
-	IsActive bool   `json:"isActive"`
+	Name           string `json:"name" validate:"omitempty,min=1,max=100"`
+	Type           string `json:"type" validate:"omitempty,max=50"`
+	CronExpr       string `json:"cronExpr" validate:"omitempty,min=5,max=50"`
+	Timezone       string `json:"timezone" validate:"omitempty,max=64"`
+	Payload        string `json:"payload" validate:"omitempty,json"`
+	TimeoutSeconds *int   `json:"timeoutSeconds" validate:"omitempty,min=0,max=86400"`
+	MaxConcurrency *int   `json:"maxConcurrency" validate:"omitempty,min=1,max=10"`
+	IsActive       *bool  `json:"isActive"`
+}
+
+// CronPreviewRequest checks a cron expression and lists when it would fire next
+type CronPreviewRequest struct {
+	CronExpr string `json:"cronExpr" validate:"required,min=5,max=50"`
+	Timezone string `json:"timezone" validate:"omitempty,max=64"`
+	Count    int    `json:"count" validate:"omitempty,min=1,max=20"` // 5 when omitted
+}
+
+type CronPreviewResponse struct {
+	CronExpr string      `json:"cronExpr"`
+	Timezone string      `json:"timezone"`
+	NextRuns []time.Time `json:"nextRuns"` // in Timezone
 }
 
 type JobResponse struct {
-	ID        uuid.UUID  `json:"id"`
-	Name      string     `json:"name"`
-	CronExpr  string     `json:"cronExpr"`
-	Payload   string     `json:"payload"`
-	Status    string     `json:"status"`
-	LastRun   *time.Time `json:"lastRun"`
-	NextRun   *time.Time `json:"nextRun"`
-	IsActive  bool       `json:"isActive"`
-	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	CronExpr       string     `json:"cronExpr"`
+	Timezone       string     `json:"timezone"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"` // of the last run: running, completed or failed
+	LastRun        *time.Time `json:"lastRun"`
+	NextRun        *time.Time `json:"nextRun"`
+	LastError      string     `json:"lastError,omitempty"`
+	LastDurationMs int64      `json:"lastDurationMs"`
+	TimeoutSeconds int        `json:"timeoutSeconds"`
+	MaxConcurrency int        `json:"maxConcurrency"`
+	IsActive       bool       `json:"isActive"`
+	IsPaused       bool       `json:"isPaused"`
+	PausedAt       *time.Time `json:"pausedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
 }
 
 type JobListResponse struct {
-	Jobs []JobResponse  `json:"jobs"`
-	Meta PaginationMeta `json:"meta"`
+	Jobs      []JobResponse            `json:"jobs"`
+	Meta      PaginationMeta           `json:"meta"`
+	Scheduler *SchedulerStatusResponse `json:"scheduler"`
+}
+
+// SchedulerStatusResponse describes the replica that answered and which replica runs the jobs
+type SchedulerStatusResponse struct {
+	NodeID        string `json:"nodeId"`
+	LeaderID      string `json:"leaderId,omitempty"` // empty while no replica holds the lease
+	IsLeader      bool   `json:"isLeader"`
+	Standalone    bool   `json:"standalone"` // leading without Redis, see LeaderElector
+	Running       bool   `json:"running"`
+	ScheduledJobs int    `json:"scheduledJobs"` // jobs in this replica's scheduler, system jobs excluded
+}
+
+type JobRunResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	JobID      uuid.UUID  `json:"jobId"`
+	JobType    string     `json:"jobType"`
+	Status     string     `json:"status"`
+	Result     string     `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	DurationMs int64      `json:"durationMs"`
+	NodeID     string     `json:"nodeId,omitempty"`
+	Trigger    string     `json:"trigger"` // schedule or manual
+}
+
+type JobRunListResponse struct {
+	Runs []JobRunResponse `json:"runs"`
+	Meta PaginationMeta   `json:"meta"`
 }
 
 type JobFilterRequest struct {
@@ -25,6 +25,7 @@ type LikeResponse struct {
 }
 
 type LikeStatusResponse struct {
-	IsLiked   bool  `json:"isLiked"`
-	LikeCount int64 `json:"likeCount"`
+	IsLiked      bool  `json:"isLiked"`
+	LikeCount    int64 `json:"likeCount"`
+	IsBookmarked bool  `json:"isBookmarked"`
 }
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payloads of live websocket events that have no REST counterpart
+
+// Targets of LikeCountEvent
+const (
+	LikeTargetTopic   = "topic"
+	LikeTargetReply   = "reply"
+	LikeTargetVideo   = "video"
+	LikeTargetComment = "comment"
+)
+
+type ReplyDeletedEvent struct {
+	ID       uuid.UUID  `json:"id"`
+	TopicID  uuid.UUID  `json:"topicId"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+}
+
+type CommentDeletedEvent struct {
+	ID       uuid.UUID  `json:"id"`
+	VideoID  uuid.UUID  `json:"videoId"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+}
+
+type TopicStatusEvent struct {
+	TopicID         uuid.UUID  `json:"topicId"`
+	IsPinned        bool       `json:"isPinned"`
+	IsLocked        bool       `json:"isLocked"`
+	AcceptedReplyID *uuid.UUID `json:"acceptedReplyId,omitempty"`
+}
+
+type LikeCountEvent struct {
+	TargetType string    `json:"targetType"` // topic, reply, video or comment
+	TargetID   uuid.UUID `json:"targetId"`
+	LikeCount  int64     `json:"likeCount"`
+}
+
+type PresenceEvent struct {
+	RoomID  string `json:"roomId"`
+	Viewers int    `json:"viewers"` // connections in the room across the cluster
+}
+
+type TypingEvent struct {
+	RoomID   string    `json:"roomId"`
+	UserID   uuid.UUID `json:"userId"`
+	IsTyping bool      `json:"isTyping"`
+}
+
+type MessageDeletedEvent struct {
+	ID             uuid.UUID `json:"id"`
+	ConversationID uuid.UUID `json:"conversationId"`
+}
+
+type MessageReadEvent struct {
+	ConversationID uuid.UUID `json:"conversationId"`
+	UserID         uuid.UUID `json:"userId"`
+	MessageID      uuid.UUID `json:"messageId"`
+	ReadAt         time.Time `json:"readAt"`
+}
@@ -162,34 +162,76 @@ func JobToJobResponse(job *models.Job) *JobResponse {
 		return nil
 	}
 	return &JobResponse{
-		ID:        job.ID,
-		Name:      job.Name,
-		CronExpr:  job.CronExpr,
-		Payload:   job.Payload,
-		Status:    job.Status,
-		LastRun:   job.LastRun,
-		NextRun:   job.NextRun,
-		IsActive:  job.IsActive,
-		CreatedAt: job.CreatedAt,
-		UpdatedAt: job.UpdatedAt,
+		ID:             job.ID,
+		Name:           job.Name,
+		Type:           job.Type,
+		CronExpr:       job.CronExpr,
+		Timezone:       job.Timezone,
+		Payload:        job.Payload,
+		Status:         job.Status,
+		LastRun:        job.LastRun,
+		NextRun:        job.NextRun,
+		LastError:      job.LastError,
+		LastDurationMs: job.LastDurationMs,
+		TimeoutSeconds: job.TimeoutSeconds,
+		MaxConcurrency: job.MaxConcurrency,
+		IsActive:       job.IsActive,
+		IsPaused:       job.IsPaused,
+		PausedAt:       job.PausedAt,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+	}
+}
+
+func JobRunToJobRunResponse(run *models.JobRun) *JobRunResponse {
+	if run == nil {
+		return nil
+	}
+	return &JobRunResponse{
+		ID:         run.ID,
+		JobID:      run.JobID,
+		JobType:    run.JobType,
+		Status:     run.Status,
+		Result:     run.Result,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		DurationMs: run.DurationMs,
+		NodeID:     run.NodeID,
+		Trigger:    run.Trigger,
 	}
 }
 
 func CreateJobRequestToJob(req *CreateJobRequest) *models.Job {
 	return &models.Job{
-		Name:     req.Name,
-		CronExpr: req.CronExpr,
-		Payload:  req.Payload,
+		Name:           req.Name,
+		Type:           req.Type,
+		CronExpr:       req.CronExpr,
+		Timezone:       req.Timezone,
+		Payload:        req.Payload,
+		TimeoutSeconds: req.TimeoutSeconds,
+		MaxConcurrency: req.MaxConcurrency,
 	}
 }
 
 func UpdateJobRequestToJob(req *UpdateJobRequest) *models.Job {
-	return &models.Job{
+	job := &models.Job{
 		Name:     req.Name,
+		Type:     req.Type,
 		CronExpr: req.CronExpr,
+		Timezone: req.Timezone,
 		Payload:  req.Payload,
-		IsActive: req.IsActive,
 	}
+	if req.TimeoutSeconds != nil {
+		job.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.MaxConcurrency != nil {
+		job.MaxConcurrency = *req.MaxConcurrency
+	}
+	if req.IsActive != nil {
+		job.IsActive = *req.IsActive
+	}
+	return job
 }
 
 func FileToFileResponse(file *models.File) *FileResponse {
@@ -221,12 +263,31 @@ func ForumToForumResponse(forum *models.Forum) *ForumResponse {
 		Icon:        forum.Icon,
 		Order:       forum.Order,
 		IsActive:    forum.IsActive,
+		IsQA:        forum.IsQA,
 		TopicCount:  forum.TopicCount,
+		ParentID:    forum.ParentID,
+		Access: ForumAccessResponse{
+			View:  forumAccessRuleResponse(forum.ViewRule),
+			Post:  forumAccessRuleResponse(forum.PostRule),
+			Reply: forumAccessRuleResponse(forum.ReplyRule),
+		},
 		CreatedAt:   forum.CreatedAt,
 		UpdatedAt:   forum.UpdatedAt,
 	}
 }
 
+func forumAccessRuleResponse(rule models.ForumAccessRule) ForumAccessRuleResponse {
+	audience := string(rule.Audience)
+	if audience == "" {
+		audience = string(models.ForumAudienceEveryone)
+	}
+	return ForumAccessRuleResponse{
+		Audience:          audience,
+		MinAccountAgeDays: rule.MinAccountAgeDays,
+		Role:              rule.Role,
+	}
+}
+
 func TopicToTopicResponse(topic *models.Topic) *TopicResponse {
 	if topic == nil {
 		return nil
@@ -237,11 +298,18 @@ func TopicToTopicResponse(topic *models.Topic) *TopicResponse {
 		UserID:     topic.UserID,
 		Title:      topic.Title,
 		Content:    topic.Content,
+		ContentHTML: contentHTML(topic.ContentHTML, topic.Content),
 		Thumbnail:  topic.Thumbnail,
 		ViewCount:  topic.ViewCount,
 		ReplyCount: topic.ReplyCount,
 		IsPinned:   topic.IsPinned,
 		IsLocked:   topic.IsLocked,
+		IsSolved:   topic.AcceptedReplyID != nil,
+		AcceptedReplyID: topic.AcceptedReplyID,
+		LastActivityAt: topic.LastActivityAt,
+		IsEdited:   topic.EditCount > 0,
+		EditCount:  topic.EditCount,
+		EditedAt:   topic.EditedAt,
 		CreatedAt:  topic.CreatedAt,
 		UpdatedAt:  topic.UpdatedAt,
 	}
@@ -264,6 +332,11 @@ func TopicToTopicResponse(topic *models.Topic) *TopicResponse {
 		}
 	}
 
+	// Include Poll if loaded
+	if topic.Poll != nil {
+		resp.Poll = PollToPollResponse(topic.Poll)
+	}
+
 	return resp
 }
 
@@ -278,8 +351,14 @@ func ReplyToReplyResponse(reply *models.Reply, includeNested bool) *ReplyRespons
 		UserID:    reply.UserID,
 		ParentID:  reply.ParentID,
 		Content:   reply.Content,
+		ContentHTML: contentHTML(reply.ContentHTML, reply.Content),
+		IsEdited:  reply.EditCount > 0,
+		EditCount: reply.EditCount,
+		EditedAt:  reply.EditedAt,
 		CreatedAt: reply.CreatedAt,
 		UpdatedAt: reply.UpdatedAt,
+		LikeCount: reply.LikeCount,
+		QuoteID:   reply.QuoteID,
 	}
 
 	// Include User if loaded
@@ -0,0 +1,189 @@
+package dto
+
+import (
+	"time"
+
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type CreateConversationRequest struct {
+	// One participant opens (or reuses) the direct conversation with that user; more make a group
+	ParticipantIDs []uuid.UUID         `json:"participantIds" validate:"required,min=1,max=19"`
+	Title          string              `json:"title" validate:"omitempty,max=100"` // groups only
+	Message        *SendMessageRequest `json:"message,omitempty"`                  // optional first message
+}
+
+type SendMessageRequest struct {
+	Content       string      `json:"content" validate:"omitempty,max=5000"`
+	AttachmentIDs []uuid.UUID `json:"attachmentIds" validate:"omitempty,max=10"` // files uploaded through /files
+}
+
+type UpdateMessageRequest struct {
+	Content string `json:"content" validate:"required,min=1,max=5000"`
+}
+
+type AddParticipantsRequest struct {
+	UserIDs []uuid.UUID `json:"userIds" validate:"required,min=1,max=19"`
+}
+
+type MarkConversationReadRequest struct {
+	MessageID *uuid.UUID `json:"messageId"` // latest message when omitted
+}
+
+type ConversationListParams struct {
+	Status string `query:"status" validate:"omitempty,oneof=active requests"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=50"`
+	Cursor string `query:"cursor"`
+}
+
+type MessageListParams struct {
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string `query:"cursor"` // older messages than the cursor
+}
+
+// Response DTOs
+type ParticipantResponse struct {
+	User              UserSummary              `json:"user"`
+	Status            models.ParticipantStatus `json:"status"`
+	LastReadMessageID *uuid.UUID               `json:"lastReadMessageId,omitempty"`
+	ReadAt            *time.Time               `json:"readAt,omitempty"`
+	JoinedAt          time.Time                `json:"joinedAt"`
+}
+
+type ConversationResponse struct {
+	ID            uuid.UUID                `json:"id"`
+	Type          models.ConversationType  `json:"type"`
+	Title         string                   `json:"title,omitempty"`
+	CreatorID     uuid.UUID                `json:"creatorId"`
+	Status        models.ParticipantStatus `json:"status"` // the viewer's: active or request
+	Participants  []ParticipantResponse    `json:"participants"`
+	LastMessage   *MessageResponse         `json:"lastMessage,omitempty"`
+	LastMessageAt time.Time                `json:"lastMessageAt"`
+	UnreadCount   int64                    `json:"unreadCount"`
+	CreatedAt     time.Time                `json:"createdAt"`
+}
+
+type ConversationListResponse struct {
+	Conversations []ConversationResponse `json:"conversations"`
+	HasMore       bool                   `json:"hasMore"`
+	NextCursor    string                 `json:"nextCursor,omitempty"`
+}
+
+type MessageAttachmentResponse struct {
+	FileID   uuid.UUID `json:"fileId"`
+	FileName string    `json:"fileName"`
+	MimeType string    `json:"mimeType"`
+	FileSize int64     `json:"fileSize"`
+	URL      string    `json:"url"`
+}
+
+type MessageResponse struct {
+	ID             uuid.UUID                   `json:"id"`
+	ConversationID uuid.UUID                   `json:"conversationId"`
+	Sender         UserSummary                 `json:"sender"`
+	Content        string                      `json:"content"`
+	Attachments    []MessageAttachmentResponse `json:"attachments"`
+	IsEdited       bool                        `json:"isEdited"`
+	IsDeleted      bool                        `json:"isDeleted"`
+	EditedAt       *time.Time                  `json:"editedAt,omitempty"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+}
+
+// MessageListResponse is a page of history, newest first
+type MessageListResponse struct {
+	Messages   []MessageResponse `json:"messages"`
+	HasMore    bool              `json:"hasMore"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+type UnreadMessageCountResponse struct {
+	Messages      int64 `json:"messages"`      // unread messages in active conversations
+	Conversations int64 `json:"conversations"` // active conversations with unread messages
+	Requests      int64 `json:"requests"`      // pending message requests
+}
+
+// ============= Converters =============
+
+func MessageToMessageResponse(message *models.Message) *MessageResponse {
+	resp := &MessageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		Content:        message.Content,
+		Attachments:    make([]MessageAttachmentResponse, 0, len(message.Attachments)),
+		IsEdited:       message.EditedAt != nil,
+		IsDeleted:      message.DeletedAt != nil,
+		EditedAt:       message.EditedAt,
+		CreatedAt:      message.CreatedAt,
+	}
+
+	if message.Sender != nil {
+		resp.Sender = userToUserSummary(message.Sender)
+	} else {
+		resp.Sender = UserSummary{ID: message.SenderID}
+	}
+
+	for _, attachment := range message.Attachments {
+		if attachment.File == nil {
+			continue
+		}
+		resp.Attachments = append(resp.Attachments, MessageAttachmentResponse{
+			FileID:   attachment.FileID,
+			FileName: attachment.File.FileName,
+			MimeType: attachment.File.MimeType,
+			FileSize: attachment.File.FileSize,
+			URL:      attachment.File.URL,
+		})
+	}
+
+	return resp
+}
+
+// ConversationToConversationResponse converts a conversation as seen by viewerID; participants
+// that left are not listed. The last message and unread count are filled in by the caller.
+func ConversationToConversationResponse(conversation *models.Conversation, viewerID uuid.UUID) *ConversationResponse {
+	resp := &ConversationResponse{
+		ID:            conversation.ID,
+		Type:          conversation.Type,
+		Title:         conversation.Title,
+		CreatorID:     conversation.CreatorID,
+		Participants:  make([]ParticipantResponse, 0, len(conversation.Participants)),
+		LastMessageAt: conversation.LastMessageAt,
+		CreatedAt:     conversation.CreatedAt,
+	}
+
+	for _, participant := range conversation.Participants {
+		if participant.UserID == viewerID {
+			resp.Status = participant.Status
+		}
+		if participant.Status == models.ParticipantLeft {
+			continue
+		}
+
+		summary := UserSummary{ID: participant.UserID}
+		if participant.User != nil {
+			summary = userToUserSummary(participant.User)
+		}
+		resp.Participants = append(resp.Participants, ParticipantResponse{
+			User:              summary,
+			Status:            participant.Status,
+			LastReadMessageID: participant.LastReadMessageID,
+			ReadAt:            participant.ReadAt,
+			JoinedAt:          participant.JoinedAt,
+		})
+	}
+
+	return resp
+}
+
+func userToUserSummary(user *models.User) UserSummary {
+	return UserSummary{
+		ID:        user.ID,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Avatar:    user.Avatar,
+	}
+}
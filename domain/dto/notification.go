@@ -14,7 +14,7 @@ type MarkAsReadRequest struct {
 }
 
 type NotificationQueryParams struct {
-	Type   string `query:"type" validate:"omitempty,oneof=topic_reply topic_like video_like video_comment comment_reply new_follower"`
+	Type   string `query:"type" validate:"omitempty,oneof=topic_reply topic_like video_like video_comment comment_reply new_follower answer_accepted watched_topic_reply watched_forum_topic topic_moved topic_merged topic_split"`
 	IsRead *bool  `query:"isRead"`
 	Page   int    `query:"page" validate:"omitempty,min=1"`
 	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
@@ -36,6 +36,7 @@ type NotificationResponse struct {
 	ResourceID *uuid.UUID              `json:"resourceId,omitempty"`
 	Message    string                  `json:"message"`
 	IsRead     bool                    `json:"isRead"`
+	Count      int                     `json:"count"` // > 1 when several replies were folded into one notification
 	CreatedAt  time.Time               `json:"createdAt"`
 }
 
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============= Request DTOs =============
+
+type CreatePlaylistRequest struct {
+	Title       string `json:"title" validate:"required,min=1,max=200"`
+	Description string `json:"description" validate:"omitempty,max=1000"`
+	Visibility  string `json:"visibility" validate:"omitempty,oneof=public unlisted private"`
+}
+
+type UpdatePlaylistRequest struct {
+	Title       string  `json:"title" validate:"omitempty,min=1,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	Visibility  string  `json:"visibility" validate:"omitempty,oneof=public unlisted private"`
+}
+
+type AddPlaylistItemRequest struct {
+	VideoID uuid.UUID `json:"videoId" validate:"required"`
+}
+
+// ReorderPlaylistItemsRequest contains every video of the playlist in the new order
+type ReorderPlaylistItemsRequest struct {
+	VideoIDs []uuid.UUID `json:"videoIds" validate:"required,min=1"`
+}
+
+// ============= Response DTOs =============
+
+type PlaylistResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"userId"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Visibility  string       `json:"visibility"`
+	ItemCount   int          `json:"itemCount"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+	User        *UserSummary `json:"user,omitempty"`
+}
+
+type PlaylistItemResponse struct {
+	Position int           `json:"position"`
+	AddedAt  time.Time     `json:"addedAt"`
+	Video    VideoResponse `json:"video"`
+}
+
+type PlaylistDetailResponse struct {
+	Playlist PlaylistResponse       `json:"playlist"`
+	Items    []PlaylistItemResponse `json:"items"`
+	Meta     PaginationMeta         `json:"meta"`
+}
+
+type PlaylistListResponse struct {
+	Playlists []PlaylistResponse `json:"playlists"`
+	Meta      PaginationMeta     `json:"meta"`
+}
+
+// VideoPlaylistStatus is used by the "add to playlist" picker on the video detail
+type VideoPlaylistStatus struct {
+	PlaylistResponse
+	ContainsVideo bool `json:"containsVideo"`
+}
+
+// ============= Converters =============
+
+func PlaylistToPlaylistResponse(playlist *models.Playlist) *PlaylistResponse {
+	resp := &PlaylistResponse{
+		ID:          playlist.ID,
+		UserID:      playlist.UserID,
+		Title:       playlist.Title,
+		Description: playlist.Description,
+		Visibility:  string(playlist.Visibility),
+		ItemCount:   playlist.ItemCount,
+		CreatedAt:   playlist.CreatedAt,
+		UpdatedAt:   playlist.UpdatedAt,
+	}
+
+	if playlist.User != nil {
+		resp.User = &UserSummary{
+			ID:        playlist.User.ID,
+			Username:  playlist.User.Username,
+			FirstName: playlist.User.FirstName,
+			LastName:  playlist.User.LastName,
+			Avatar:    playlist.User.Avatar,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,135 @@
+package dto
+
+import (
+	"gofiber-social/domain/models"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============= Request DTOs =============
+
+// CreatePollRequest is sent as the optional `poll` of CreateTopicRequest
+type CreatePollRequest struct {
+	Question        string     `json:"question" validate:"required,min=3,max=300"`
+	Options         []string   `json:"options" validate:"required,min=2,max=10,dive,required,max=200"`
+	AllowMultiple   bool       `json:"allowMultiple"`
+	MaxChoices      int        `json:"maxChoices" validate:"omitempty,min=1,max=10"` // multiple choice only, defaults to every option
+	IsAnonymous     bool       `json:"isAnonymous"`
+	AllowVoteChange bool       `json:"allowVoteChange"`
+	ClosesAt        *time.Time `json:"closesAt"`
+}
+
+type VotePollRequest struct {
+	OptionIDs []string `json:"optionIds" validate:"required,min=1,max=10,dive,uuid4"`
+}
+
+// ============= Response DTOs =============
+
+type PollOptionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Text      string    `json:"text"`
+	Position  int       `json:"position"`
+	VoteCount int       `json:"voteCount"`
+	Percent   float64   `json:"percent"` // share of voters, so multiple choice polls can add up to more than 100
+}
+
+type PollResponse struct {
+	ID              uuid.UUID            `json:"id"`
+	TopicID         uuid.UUID            `json:"topicId"`
+	Question        string               `json:"question"`
+	AllowMultiple   bool                 `json:"allowMultiple"`
+	MaxChoices      int                  `json:"maxChoices"`
+	IsAnonymous     bool                 `json:"isAnonymous"`
+	AllowVoteChange bool                 `json:"allowVoteChange"`
+	ClosesAt        *time.Time           `json:"closesAt,omitempty"`
+	ClosedAt        *time.Time           `json:"closedAt,omitempty"`
+	IsClosed        bool                 `json:"isClosed"`
+	VoterCount      int                  `json:"voterCount"`
+	Options         []PollOptionResponse `json:"options"`
+	HasVoted        bool                 `json:"hasVoted"`
+	MyVotes         []uuid.UUID          `json:"myVotes,omitempty"`
+}
+
+// PollResultsResponse is broadcast to the topic room whenever the tally changes
+type PollResultsResponse struct {
+	PollID     uuid.UUID            `json:"pollId"`
+	TopicID    uuid.UUID            `json:"topicId"`
+	VoterCount int                  `json:"voterCount"`
+	IsClosed   bool                 `json:"isClosed"`
+	Options    []PollOptionResponse `json:"options"`
+}
+
+type PollVoterResponse struct {
+	User    UserSummary `json:"user"`
+	VotedAt time.Time   `json:"votedAt"`
+}
+
+type PollVoterListResponse struct {
+	Voters []PollVoterResponse `json:"voters"`
+	Meta   PaginationMeta      `json:"meta"`
+}
+
+// ============= Converters =============
+
+func PollToPollResponse(poll *models.Poll) *PollResponse {
+	if poll == nil {
+		return nil
+	}
+
+	return &PollResponse{
+		ID:              poll.ID,
+		TopicID:         poll.TopicID,
+		Question:        poll.Question,
+		AllowMultiple:   poll.AllowMultiple,
+		MaxChoices:      poll.MaxChoices,
+		IsAnonymous:     poll.IsAnonymous,
+		AllowVoteChange: poll.AllowVoteChange,
+		ClosesAt:        poll.ClosesAt,
+		ClosedAt:        poll.ClosedAt,
+		IsClosed:        poll.IsClosed(time.Now()),
+		VoterCount:      poll.VoterCount,
+		Options:         pollOptionResponses(poll),
+	}
+}
+
+func PollToPollResultsResponse(poll *models.Poll) *PollResultsResponse {
+	return &PollResultsResponse{
+		PollID:     poll.ID,
+		TopicID:    poll.TopicID,
+		VoterCount: poll.VoterCount,
+		IsClosed:   poll.IsClosed(time.Now()),
+		Options:    pollOptionResponses(poll),
+	}
+}
+
+func pollOptionResponses(poll *models.Poll) []PollOptionResponse {
+	options := make([]PollOptionResponse, len(poll.Options))
+	for i, option := range poll.Options {
+		options[i] = PollOptionResponse{
+			ID:        option.ID,
+			Text:      option.Text,
+			Position:  option.Position,
+			VoteCount: option.VoteCount,
+		}
+		if poll.VoterCount > 0 {
+			options[i].Percent = math.Round(float64(option.VoteCount)*1000/float64(poll.VoterCount)) / 10
+		}
+	}
+	return options
+}
+
+func PollVoteToPollVoterResponse(vote *models.PollVote) PollVoterResponse {
+	resp := PollVoterResponse{VotedAt: vote.CreatedAt}
+	if vote.User != nil {
+		resp.User = UserSummary{
+			ID:        vote.User.ID,
+			Username:  vote.User.Username,
+			FirstName: vote.User.FirstName,
+			LastName:  vote.User.LastName,
+			Avatar:    vote.User.Avatar,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MarkTopicReadRequest moves the read position to a reply; without replyId the whole topic is read
+type MarkTopicReadRequest struct {
+	ReplyID *uuid.UUID `json:"replyId"`
+}
+
+// FirstUnreadResponse tells the client where to jump in a topic. Position is the reply's index
+// among the topic's replies oldest first, for clients that page the flat list.
+type FirstUnreadResponse struct {
+	HasUnread       bool       `json:"hasUnread"`
+	ReplyID         *uuid.UUID `json:"replyId,omitempty"`
+	ParentID        *uuid.UUID `json:"parentId,omitempty"`
+	Position        int64      `json:"position"`
+	NewReplyCount   int        `json:"newReplyCount"`
+	LastReadAt      *time.Time `json:"lastReadAt,omitempty"`
+	LastReadReplyID *uuid.UUID `json:"lastReadReplyId,omitempty"`
+}
@@ -10,6 +10,7 @@ import (
 type CreateReplyRequest struct {
 	Content  string  `json:"content" validate:"required,min=1,max=5000"`
 	ParentID *string `json:"parentId" validate:"omitempty,uuid4"` // สำหรับ nested reply
+	QuoteID  *string `json:"quoteId" validate:"omitempty,uuid4"`  // reply ที่ต้องการอ้างอิง
 }
 
 type UpdateReplyRequest struct {
@@ -24,9 +25,62 @@ type ReplyResponse struct {
 	User      *UserResponseAdmin   `json:"user,omitempty"`
 	ParentID  *uuid.UUID      `json:"parentId,omitempty"`
 	Content   string          `json:"content"`
+	ContentHTML string        `json:"contentHtml"`
+	LinkPreviews []LinkPreviewResponse `json:"linkPreviews,omitempty"`
 	Replies   []ReplyResponse `json:"replies,omitempty"` // Nested replies
+	IsEdited  bool            `json:"isEdited"`
+	IsAccepted bool           `json:"isAccepted"` // accepted answer of a Q&A topic
+	EditCount int             `json:"editCount"`
+	EditedAt  *time.Time      `json:"editedAt,omitempty"`
 	CreatedAt time.Time       `json:"createdAt"`
 	UpdatedAt time.Time       `json:"updatedAt"`
+
+	// Thread fields
+	LikeCount      int                 `json:"likeCount"`
+	QuoteID        *uuid.UUID          `json:"quoteId,omitempty"`
+	Quote          *ReplyQuoteResponse `json:"quote,omitempty"`
+	ReplyCount     int64               `json:"replyCount"`               // direct children
+	HasMoreReplies bool                `json:"hasMoreReplies,omitempty"` // children not included in this response
+	NextCursor     string              `json:"nextCursor,omitempty"`     // cursor for GET /replies/:id/replies; empty means from the start
+}
+
+type ReplyQuoteResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	Username  string    `json:"username,omitempty"`
+	Excerpt   string    `json:"excerpt,omitempty"`
+	IsDeleted bool      `json:"isDeleted"`
+}
+
+type ReplyThreadParams struct {
+	Sort     string `query:"sort" validate:"omitempty,oneof=oldest newest top"`
+	Depth    int    `query:"depth" validate:"omitempty,min=1,max=6"`      // levels returned, including the first
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=50"`     // replies on the first level
+	Children int    `query:"children" validate:"omitempty,min=1,max=20"` // children per reply on deeper levels
+	Cursor   string `query:"cursor"`
+	Page     int    `query:"page" validate:"omitempty,min=1"` // used when no cursor is given
+}
+
+type ReplyThreadResponse struct {
+	TopicID    uuid.UUID       `json:"topicId"`
+	ParentID   *uuid.UUID      `json:"parentId,omitempty"`
+	Sort       string          `json:"sort"`
+	Replies    []ReplyResponse `json:"replies"`
+	HasMore    bool            `json:"hasMore"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+// ReplyContextResponse locates a reply for permalinks: the top-level page holding its
+// thread and the chain of replies from the top-level reply down to it
+type ReplyContextResponse struct {
+	TopicID uuid.UUID     `json:"topicId"`
+	ReplyID uuid.UUID     `json:"replyId"`
+	RootID  uuid.UUID     `json:"rootId"`
+	Path    []uuid.UUID   `json:"path"` // top-level reply first, the reply itself last
+	Sort    string        `json:"sort"`
+	Page    int           `json:"page"`
+	Limit   int           `json:"limit"`
+	Thread  ReplyResponse `json:"thread"`
 }
 
 type ReplyListResponse struct {
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"encoding/json"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ============= Service DTOs =============
+
+// RevisionTag is how a topic tag is kept in a revision, so the name survives the tag being deleted
+type RevisionTag struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// RevisionSnapshot is the editable state of a topic, reply or comment
+type RevisionSnapshot struct {
+	Title   string
+	Content string
+	Tags    []RevisionTag
+}
+
+// ContentEdit describes one edit so the revision service can store it
+type ContentEdit struct {
+	ContentType models.RevisionContentType
+	ContentID   uuid.UUID
+	AuthorID    uuid.UUID
+	PostedAt    time.Time // when the original version was created
+	EditorID    uuid.UUID
+	Previous    RevisionSnapshot
+	Current     RevisionSnapshot
+	Note        string
+}
+
+// ============= Response DTOs =============
+
+type RevisionResponse struct {
+	ID          uuid.UUID     `json:"id"`
+	ContentType string        `json:"contentType"`
+	ContentID   uuid.UUID     `json:"contentId"`
+	Version     int           `json:"version"`
+	Title       string        `json:"title,omitempty"`
+	Content     string        `json:"content"`
+	Tags        []RevisionTag `json:"tags,omitempty"`
+	EditorID    uuid.UUID     `json:"editorId"`
+	Editor      *UserSummary  `json:"editor,omitempty"`
+	Note        string        `json:"note,omitempty"`
+	IsCurrent   bool          `json:"isCurrent"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+type RevisionListResponse struct {
+	Revisions []RevisionResponse `json:"revisions"`
+	Meta      PaginationMeta     `json:"meta"`
+}
+
+// ============= Converters =============
+
+func RevisionToRevisionResponse(revision *models.ContentRevision) *RevisionResponse {
+	resp := &RevisionResponse{
+		ID:          revision.ID,
+		ContentType: string(revision.ContentType),
+		ContentID:   revision.ContentID,
+		Version:     revision.Version,
+		Title:       revision.Title,
+		Content:     revision.Content,
+		EditorID:    revision.EditorID,
+		Note:        revision.Note,
+		CreatedAt:   revision.CreatedAt,
+	}
+
+	if revision.Tags != "" {
+		_ = json.Unmarshal([]byte(revision.Tags), &resp.Tags)
+	}
+
+	if revision.Editor.ID != uuid.Nil {
+		resp.Editor = &UserSummary{
+			ID:        revision.Editor.ID,
+			Username:  revision.Editor.Username,
+			FirstName: revision.Editor.FirstName,
+			LastName:  revision.Editor.LastName,
+			Avatar:    revision.Editor.Avatar,
+		}
+	}
+
+	return resp
+}
+
+func TagsToRevisionTags(tags []*models.Tag) []RevisionTag {
+	revisionTags := make([]RevisionTag, len(tags))
+	for i, tag := range tags {
+		revisionTags[i] = RevisionTag{ID: tag.ID, Name: tag.Name}
+	}
+	return revisionTags
+}
@@ -13,6 +13,7 @@ type CreateTopicRequest struct {
 	Content   string   `json:"content" validate:"required,min=10,max=10000"`
 	Thumbnail string   `json:"thumbnail" validate:"omitempty,url"` // Optional thumbnail image URL
 	TagIDs    []string `json:"tagIds" validate:"omitempty,dive,uuid4"` // Array of tag UUIDs
+	Poll      *CreatePollRequest `json:"poll"`                         // Optional poll
 }
 
 type UpdateTopicRequest struct {
@@ -22,6 +23,36 @@ type UpdateTopicRequest struct {
 	TagIDs    []string `json:"tagIds" validate:"omitempty,dive,uuid4"` // Array of tag UUIDs
 }
 
+// TopicFilterParams are the optional filters of topic listings (?solved=true|false)
+type TopicFilterParams struct {
+	Solved *bool `query:"solved"`
+}
+
+type AcceptAnswerRequest struct {
+	ReplyID string `json:"replyId" validate:"required,uuid4"`
+}
+
+// Moderator requests - Reason is recorded in the activity log and sent to the affected authors
+type MoveTopicRequest struct {
+	ForumID string `json:"forumId" validate:"required,uuid4"`
+	Reason  string `json:"reason" validate:"max=500"`
+}
+
+// MergeTopicRequest merges the topic in the URL into TargetTopicID
+type MergeTopicRequest struct {
+	TargetTopicID string `json:"targetTopicId" validate:"required,uuid4"`
+	Reason        string `json:"reason" validate:"max=500"`
+}
+
+// SplitTopicRequest moves replies into a new topic; the oldest of them becomes its first post.
+// ForumID defaults to the forum of the original topic.
+type SplitTopicRequest struct {
+	ReplyIDs []string `json:"replyIds" validate:"required,min=1,max=500,dive,uuid4"`
+	Title    string   `json:"title" validate:"required,min=5,max=200"`
+	ForumID  string   `json:"forumId" validate:"omitempty,uuid4"`
+	Reason   string   `json:"reason" validate:"max=500"`
+}
+
 // Response DTOs
 type TopicResponse struct {
 	ID         uuid.UUID      `json:"id"`
@@ -31,12 +62,28 @@ type TopicResponse struct {
 	User       *UserResponseAdmin  `json:"user,omitempty"`
 	Title      string         `json:"title"`
 	Content    string         `json:"content"`
+	ContentHTML string        `json:"contentHtml"`
+	LinkPreviews []LinkPreviewResponse `json:"linkPreviews,omitempty"`
 	Thumbnail  string         `json:"thumbnail,omitempty"` // Optional thumbnail image URL
 	ViewCount  int            `json:"viewCount"`
 	ReplyCount int            `json:"replyCount"`
 	IsPinned   bool           `json:"isPinned"`
 	IsLocked   bool           `json:"isLocked"`
+	IsSolved   bool           `json:"isSolved"`
+	IsBookmarked bool         `json:"isBookmarked"` // detail only, for the logged-in viewer
+	IsWatching   bool         `json:"isWatching"`   // detail only, for the logged-in viewer
+	AcceptedReplyID *uuid.UUID `json:"acceptedReplyId,omitempty"`
+	LastActivityAt *time.Time `json:"lastActivityAt,omitempty"`
+	// Read state, for the logged-in viewer
+	IsUnread        bool       `json:"isUnread"`      // new activity since the last visit
+	NewReplyCount   int        `json:"newReplyCount"` // replies by others since the last visit
+	LastReadAt      *time.Time `json:"lastReadAt,omitempty"`
+	LastReadReplyID *uuid.UUID `json:"lastReadReplyId,omitempty"`
 	Tags       []TagResponse  `json:"tags,omitempty"`
+	Poll       *PollResponse  `json:"poll,omitempty"`
+	IsEdited   bool           `json:"isEdited"`
+	EditCount  int            `json:"editCount"`
+	EditedAt   *time.Time     `json:"editedAt,omitempty"`
 	CreatedAt  time.Time      `json:"createdAt"`
 	UpdatedAt  time.Time      `json:"updatedAt"`
 }
@@ -48,5 +95,7 @@ type TopicListResponse struct {
 
 type TopicDetailResponse struct {
 	Topic   TopicResponse   `json:"topic"`
+	AcceptedAnswer *ReplyResponse `json:"acceptedAnswer,omitempty"` // Q&A: shown above the other replies
 	Replies []ReplyResponse `json:"replies"`
+	RedirectedFrom *uuid.UUID `json:"redirectedFrom,omitempty"` // requested ID of a topic merged into this one
 }
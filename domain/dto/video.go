@@ -50,8 +50,21 @@ type VideoResponse struct {
 	LikeCount    int          `json:"likeCount"`
 	CommentCount int          `json:"commentCount"`
 	IsActive     bool         `json:"isActive"`
+	IsBookmarked bool         `json:"isBookmarked"` // detail only, for the logged-in viewer
 	CreatedAt    time.Time    `json:"createdAt"`
 	User         *UserSummary `json:"user,omitempty"`
+
+	// Media access - URLs are signed and expire unless the video is public
+	MediaExpiresAt  *time.Time `json:"mediaExpiresAt,omitempty"`
+	MediaRestricted bool       `json:"mediaRestricted,omitempty"`
+}
+
+// VideoMediaResponse contains playable URLs generated for the current viewer
+type VideoMediaResponse struct {
+	VideoURL     string     `json:"videoUrl"`
+	ThumbnailURL string     `json:"thumbnailUrl"`
+	IsPublic     bool       `json:"isPublic"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
 }
 
 type VideoListResponse struct {
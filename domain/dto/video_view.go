@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type VideoViewEventRequest struct {
+	Event          string    `json:"event" validate:"required,oneof=start heartbeat complete"`
+	ViewID         uuid.UUID `json:"viewId"`                                    // Required for heartbeat/complete (returned by start)
+	Position       int       `json:"position" validate:"omitempty,min=0"`       // Current playback position in seconds
+	WatchedSeconds int       `json:"watchedSeconds" validate:"omitempty,min=0"` // Seconds watched since the previous event
+	Source         string    `json:"source" validate:"omitempty,oneof=feed profile search share playlist direct external"`
+}
+
+// Response DTOs
+type VideoViewEventResponse struct {
+	ViewID  uuid.UUID `json:"viewId"`
+	Counted bool      `json:"counted"` // true only when the start event produced a new view
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============= Request DTOs =============
+
+type WatchedTopicQueryParams struct {
+	Unread bool `query:"unread"` // only topics with replies since the last read
+	Page   int  `query:"page" validate:"omitempty,min=1"`
+	Limit  int  `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// UpdateWatchPreferenceRequest changes only the fields that are sent
+type UpdateWatchPreferenceRequest struct {
+	AutoWatchOnPost  *bool `json:"autoWatchOnPost"`
+	AutoWatchOnReply *bool `json:"autoWatchOnReply"`
+}
+
+// ============= Response DTOs =============
+
+type WatchStatusResponse struct {
+	IsWatching bool `json:"isWatching"`
+}
+
+type WatchedTopicResponse struct {
+	Topic       TopicResponse `json:"topic"`
+	UnreadCount int           `json:"unreadCount"` // replies by others since lastReadAt
+	LastReadAt  *time.Time    `json:"lastReadAt,omitempty"`
+}
+
+type WatchedTopicListResponse struct {
+	Topics []WatchedTopicResponse `json:"topics"`
+	Meta   PaginationMeta         `json:"meta"`
+}
+
+type WatchPreferenceResponse struct {
+	AutoWatchOnPost  bool `json:"autoWatchOnPost"`
+	AutoWatchOnReply bool `json:"autoWatchOnReply"`
+}
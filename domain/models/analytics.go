@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AnalyticsContentType string
+
+const (
+	AnalyticsContentVideo AnalyticsContentType = "video"
+	AnalyticsContentTopic AnalyticsContentType = "topic"
+)
+
+// ContentDailyStat is the daily engagement rollup of one video or topic
+type ContentDailyStat struct {
+	ID             uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Date           time.Time            `gorm:"type:date;not null;uniqueIndex:idx_content_daily_stat_unique,priority:1;index:idx_content_daily_stat_owner,priority:2" json:"date"`
+	ContentType    AnalyticsContentType `gorm:"type:varchar(20);not null;uniqueIndex:idx_content_daily_stat_unique,priority:2" json:"contentType"`
+	ContentID      uuid.UUID            `gorm:"type:uuid;not null;uniqueIndex:idx_content_daily_stat_unique,priority:3" json:"contentId"`
+	OwnerID        uuid.UUID            `gorm:"type:uuid;not null;index:idx_content_daily_stat_owner,priority:1" json:"ownerId"`
+	Views          int64                `gorm:"default:0" json:"views"`
+	ViewTotal      *int64               `json:"-"`                          // Topic view counter snapshot, used to derive daily topic views
+	WatchTime      int64                `gorm:"default:0" json:"watchTime"` // Seconds (videos only)
+	CompletedViews int64                `gorm:"default:0" json:"completedViews"`
+	Likes          int64                `gorm:"default:0" json:"likes"`
+	Comments       int64                `gorm:"default:0" json:"comments"` // Video comments or topic replies
+	Shares         int64                `gorm:"default:0" json:"shares"`
+	UpdatedAt      time.Time            `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (ContentDailyStat) TableName() string {
+	return "content_daily_stats"
+}
+
+// ShareDailyStat is the daily share count of a video per platform
+type ShareDailyStat struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Date     time.Time `gorm:"type:date;not null;uniqueIndex:idx_share_daily_stat_unique,priority:1;index:idx_share_daily_stat_owner,priority:2" json:"date"`
+	VideoID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_share_daily_stat_unique,priority:2" json:"videoId"`
+	Platform string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_share_daily_stat_unique,priority:3" json:"platform"`
+	OwnerID  uuid.UUID `gorm:"type:uuid;not null;index:idx_share_daily_stat_owner,priority:1" json:"ownerId"`
+	Shares   int64     `gorm:"default:0" json:"shares"`
+}
+
+func (ShareDailyStat) TableName() string {
+	return "share_daily_stats"
+}
+
+// FollowerDailyStat is the daily follower growth of a user
+type FollowerDailyStat struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Date           time.Time `gorm:"type:date;not null;uniqueIndex:idx_follower_daily_stat_unique,priority:1;index:idx_follower_daily_stat_user,priority:2" json:"date"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_follower_daily_stat_unique,priority:2;index:idx_follower_daily_stat_user,priority:1" json:"userId"`
+	NewFollowers   int64     `gorm:"default:0" json:"newFollowers"`
+	TotalFollowers int64     `gorm:"default:0" json:"totalFollowers"`
+}
+
+func (FollowerDailyStat) TableName() string {
+	return "follower_daily_stats"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BackgroundTaskStatus string
+
+const (
+	BackgroundTaskPending    BackgroundTaskStatus = "pending"    // waiting for RunAt
+	BackgroundTaskProcessing BackgroundTaskStatus = "processing" // claimed by a worker
+	BackgroundTaskCompleted  BackgroundTaskStatus = "completed"
+	BackgroundTaskDead       BackgroundTaskStatus = "dead" // out of attempts; kept until replayed
+)
+
+// BackgroundTask is a side effect queued in Postgres and run by the worker pool. Workers claim
+// due tasks with FOR UPDATE SKIP LOCKED, so any number of replicas can share the queue.
+type BackgroundTask struct {
+	ID             uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Type           string               `gorm:"type:varchar(50);not null;index" json:"type"`
+	Payload        string               `gorm:"type:jsonb;not null" json:"payload"`
+	IdempotencyKey *string              `gorm:"type:varchar(200);uniqueIndex" json:"idempotencyKey,omitempty"` // a second task with the same key is not queued
+	Status         BackgroundTaskStatus `gorm:"type:varchar(20);not null;default:'pending';index:idx_background_task_status_run_at,priority:1" json:"status"`
+	Attempts       int                  `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts    int                  `gorm:"not null" json:"maxAttempts"`
+	RunAt          time.Time            `gorm:"not null;index:idx_background_task_status_run_at,priority:2" json:"runAt"`
+	LockedAt       *time.Time           `json:"lockedAt,omitempty"`
+	LockedBy       string               `gorm:"type:varchar(100)" json:"lockedBy,omitempty"`
+	LastError      string               `gorm:"type:text" json:"lastError,omitempty"`
+	CompletedAt    *time.Time           `json:"completedAt,omitempty"`
+	CreatedAt      time.Time            `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time            `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (BackgroundTask) TableName() string {
+	return "background_tasks"
+}
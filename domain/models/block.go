@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Block works both ways: neither user can follow or message the other
+type Block struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BlockerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_block_pair,priority:1" json:"blockerId"`
+	BlockedID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_block_pair,priority:2;index" json:"blockedId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relations
+	Blocker *User `gorm:"foreignKey:BlockerID;constraint:OnDelete:CASCADE" json:"blocker,omitempty"`
+	Blocked *User `gorm:"foreignKey:BlockedID;constraint:OnDelete:CASCADE" json:"blocked,omitempty"`
+}
+
+func (Block) TableName() string {
+	return "blocks"
+}
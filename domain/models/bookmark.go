@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BookmarkTargetType string
+
+const (
+	BookmarkTargetTopic   BookmarkTargetType = "topic"
+	BookmarkTargetVideo   BookmarkTargetType = "video"
+	BookmarkTargetReply   BookmarkTargetType = "reply"
+	BookmarkTargetComment BookmarkTargetType = "comment"
+)
+
+// Bookmark is a saved topic, video, reply or comment; FolderID nil = not in a folder
+type Bookmark struct {
+	ID         uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_bookmark_target,priority:1;index:idx_bookmark_user_created,priority:1" json:"userId"`
+	TargetType BookmarkTargetType `gorm:"type:varchar(20);not null;uniqueIndex:idx_bookmark_target,priority:2" json:"targetType"`
+	TargetID   uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_bookmark_target,priority:3" json:"targetId"`
+	FolderID   *uuid.UUID         `gorm:"type:uuid;index" json:"folderId,omitempty"`
+	CreatedAt  time.Time          `gorm:"autoCreateTime;index:idx_bookmark_user_created,priority:2" json:"createdAt"`
+
+	// Relations
+	User   *User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Folder *BookmarkFolder `gorm:"foreignKey:FolderID;constraint:OnDelete:SET NULL" json:"folder,omitempty"`
+}
+
+func (Bookmark) TableName() string {
+	return "bookmarks"
+}
+
+type BookmarkFolder struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bookmark_folder_name,priority:1" json:"userId"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_bookmark_folder_name,priority:2" json:"name"`
+	ItemCount int       `gorm:"->;-:migration" json:"itemCount"` // read-only, filled by the repository when listing
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (BookmarkFolder) TableName() string {
+	return "bookmark_folders"
+}
@@ -13,6 +13,8 @@ type Comment struct {
 	VideoID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"videoId"`
 	ParentID  *uuid.UUID     `gorm:"type:uuid;index" json:"parentId,omitempty"` // For nested comments
 	Content   string         `gorm:"type:text;not null" json:"content"`
+	EditCount int            `gorm:"default:0" json:"editCount"`
+	EditedAt  *time.Time     `json:"editedAt,omitempty"`
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
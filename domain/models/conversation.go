@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ConversationType string
+
+const (
+	ConversationDirect ConversationType = "direct" // exactly two users, one conversation per pair
+	ConversationGroup  ConversationType = "group"
+)
+
+// MaxGroupParticipants bounds group conversations, creator included
+const MaxGroupParticipants = 20
+
+type ParticipantStatus string
+
+const (
+	ParticipantActive   ParticipantStatus = "active"   // in the inbox, gets messages live
+	ParticipantRequest  ParticipantStatus = "request"  // message request from someone a private user doesn't know
+	ParticipantDeclined ParticipantStatus = "declined" // declined the request; nothing more is delivered
+	ParticipantLeft     ParticipantStatus = "left"     // left or was removed from a group
+)
+
+type Conversation struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Type      ConversationType `gorm:"type:varchar(20);not null" json:"type"`
+	Title     string           `gorm:"type:varchar(100)" json:"title"` // groups only
+	CreatorID uuid.UUID        `gorm:"type:uuid;not null" json:"creatorId"`
+	// DirectKey is "<lower user ID>:<higher user ID>" for direct conversations
+	DirectKey     *string    `gorm:"type:varchar(80);uniqueIndex" json:"-"`
+	LastMessageID *uuid.UUID `gorm:"type:uuid" json:"lastMessageId,omitempty"`
+	LastMessageAt time.Time  `gorm:"not null;index" json:"lastMessageAt"` // creation time until the first message
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	Creator      *User                     `gorm:"foreignKey:CreatorID;constraint:OnDelete:CASCADE" json:"creator,omitempty"`
+	Participants []ConversationParticipant `gorm:"foreignKey:ConversationID" json:"participants,omitempty"`
+}
+
+func (Conversation) TableName() string {
+	return "conversations"
+}
+
+// Participant returns the conversation's entry for a user, or nil
+func (c *Conversation) Participant(userID uuid.UUID) *ConversationParticipant {
+	for i := range c.Participants {
+		if c.Participants[i].UserID == userID {
+			return &c.Participants[i]
+		}
+	}
+	return nil
+}
+
+// ConversationParticipant also carries the user's read receipt. Messages by others sent after
+// LastReadMessageAt (or after JoinedAt when nothing was read) are unread.
+type ConversationParticipant struct {
+	ConversationID    uuid.UUID         `gorm:"type:uuid;primaryKey" json:"conversationId"`
+	UserID            uuid.UUID         `gorm:"type:uuid;primaryKey;index" json:"userId"`
+	Status            ParticipantStatus `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	LastReadMessageID *uuid.UUID        `gorm:"type:uuid" json:"lastReadMessageId,omitempty"`
+	LastReadMessageAt *time.Time        `json:"lastReadMessageAt,omitempty"` // when that message was sent
+	ReadAt            *time.Time        `json:"readAt,omitempty"`            // when the receipt last moved
+	JoinedAt          time.Time         `gorm:"not null" json:"joinedAt"`
+	UpdatedAt         time.Time         `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	Conversation *Conversation `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE" json:"-"`
+	User         *User         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (ConversationParticipant) TableName() string {
+	return "conversation_participants"
+}
+
+// CanView reports whether the participant may open the conversation
+func (p *ConversationParticipant) CanView() bool {
+	return p.Status == ParticipantActive || p.Status == ParticipantRequest
+}
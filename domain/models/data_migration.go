@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// One-off data migrations, recorded once applied
+const (
+	DataMigrationFileReferences = "file_references_backfill"  // references of media uploaded before they were tracked
+	DataMigrationReplyLikeCount = "reply_like_count_backfill" // like_count of replies liked before it was kept
+)
+
+// DataMigration records that a one-off data migration has run
+type DataMigration struct {
+	Name      string `gorm:"primaryKey;type:varchar(100)"`
+	AppliedAt time.Time
+}
+
+func (DataMigration) TableName() string {
+	return "data_migrations"
+}
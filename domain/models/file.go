@@ -12,6 +12,7 @@ type File struct {
 	MimeType  string
 	URL       string    `gorm:"not null"`
 	CDNPath   string
+	Protected bool      `gorm:"not null;default:false"` // stored in the secure zone, served through signed URLs only
 	UserID    uuid.UUID `gorm:"not null"`
 	User      User      `gorm:"foreignKey:UserID"`
 	CreatedAt time.Time
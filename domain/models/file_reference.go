@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileReferenceType string
+
+const (
+	FileReferenceVideo          FileReferenceType = "video"           // ไฟล์วิดีโอหลัก
+	FileReferenceVideoThumbnail FileReferenceType = "video_thumbnail" // ภาพปกวิดีโอ
+	FileReferenceAvatar         FileReferenceType = "avatar"          // รูปโปรไฟล์ผู้ใช้
+	FileReferenceTopicThumbnail FileReferenceType = "topic_thumbnail" // ภาพปกกระทู้
+	FileReferenceTopicContent   FileReferenceType = "topic_content"   // รูปที่ฝังในเนื้อหากระทู้
+	FileReferenceReplyContent   FileReferenceType = "reply_content"   // รูปที่ฝังในเนื้อหาคำตอบ
+	FileReferenceMessage        FileReferenceType = "message"         // ไฟล์แนบในข้อความส่วนตัว
+)
+
+// FileReference records that a resource (video, avatar, topic) uses a stored file.
+// Files with no references are considered orphaned and removed by the GC job.
+type FileReference struct {
+	ID           uuid.UUID         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FileID       uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_file_reference_unique"`
+	ResourceType FileReferenceType `gorm:"type:varchar(50);not null;uniqueIndex:idx_file_reference_unique;index:idx_file_reference_resource"`
+	ResourceID   uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_file_reference_unique;index:idx_file_reference_resource"`
+	CreatedAt    time.Time
+
+	// Relations
+	File File `gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE"`
+}
+
+func (FileReference) TableName() string {
+	return "file_references"
+}
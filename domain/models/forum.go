@@ -8,14 +8,22 @@ import (
 
 type Forum struct {
 	ID          uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index"` // nil = forum หลัก
 	Name        string    `gorm:"type:varchar(100);not null"`
 	Slug        string    `gorm:"type:varchar(100);uniqueIndex;not null"` // URL-friendly
 	Description string    `gorm:"type:text;not null"`
 	Icon        string    `gorm:"type:varchar(500)"` // URL ของไอคอน
 	Order       int       `gorm:"default:0"`         // ลำดับการแสดงผล
 	IsActive    bool      `gorm:"default:true"`
+	IsQA        bool      `gorm:"default:false"`      // Q&A mode: topics are questions that can have an accepted answer
 	TopicCount  int       `gorm:"default:0"`         // จำนวนกระทู้
 	CreatedBy   uuid.UUID `gorm:"type:uuid;not null"` // Admin ID
+
+	// สิทธิ์การเข้าถึงแยกตาม action (sub-forum ต้องผ่านสิทธิ์ดูของ forum แม่ด้วย)
+	ViewRule  ForumAccessRule `gorm:"embedded;embeddedPrefix:view_"`
+	PostRule  ForumAccessRule `gorm:"embedded;embeddedPrefix:post_"`
+	ReplyRule ForumAccessRule `gorm:"embedded;embeddedPrefix:reply_"`
+
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 
@@ -26,3 +34,44 @@ type Forum struct {
 func (Forum) TableName() string {
 	return "forums"
 }
+
+type ForumAction string
+
+const (
+	ForumActionView  ForumAction = "view"
+	ForumActionPost  ForumAction = "post"
+	ForumActionReply ForumAction = "reply"
+)
+
+type ForumAudience string
+
+const (
+	ForumAudienceEveryone ForumAudience = "everyone" // view: รวมผู้ที่ไม่ได้ login, post/reply: ทุกคนที่ login
+	ForumAudienceVerified ForumAudience = "verified"
+	ForumAudienceRole     ForumAudience = "role"
+)
+
+// ForumAccessRule decides who may perform one action in a forum. Admins and the
+// forum's moderators always pass.
+type ForumAccessRule struct {
+	Audience          ForumAudience `gorm:"type:varchar(20);default:'everyone'"`
+	MinAccountAgeDays int           `gorm:"default:0"`
+	Role              string        `gorm:"type:varchar(20)"` // required role when Audience = role
+}
+
+// ForumModerator can pin, lock and delete content in the forum and its sub-forums
+type ForumModerator struct {
+	ID         uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ForumID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_forum_moderator"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_forum_moderator;index"`
+	AssignedBy uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time
+
+	// Relations
+	Forum Forum `gorm:"foreignKey:ForumID"`
+	User  User  `gorm:"foreignKey:UserID"`
+}
+
+func (ForumModerator) TableName() string {
+	return "forum_moderators"
+}
@@ -1,23 +1,74 @@
 package models
 
 import (
-	"time"
 	"github.com/google/uuid"
+	"time"
+)
+
+// Job types with a built-in handler; ExecuteJob dispatches on Job.Type
+const (
+	JobTypeSyncForumCounts     = "sync_forum_counts"     // recount topics per forum
+	JobTypeRecomputeLikeCounts = "recompute_like_counts" // recount like_count of topics, replies and videos
+	JobTypePurgeNotifications  = "purge_notifications"   // delete old notifications
+	JobTypeSendDigests         = "send_digests"          // remind users of unread notifications
+)
+
+// Statuses of a job's last execution, and of a JobRun
+const (
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// What started a JobRun
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual" // POST /jobs/:id/run
 )
 
 type Job struct {
-	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	Name      string     `gorm:"not null"`
-	CronExpr  string     `gorm:"not null"`
-	Payload   string     `gorm:"type:jsonb"`
-	Status    string     `gorm:"default:'active'"`
-	LastRun   *time.Time
-	NextRun   *time.Time
-	IsActive  bool       `gorm:"default:true"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name           string    `gorm:"not null"`
+	Type           string    `gorm:"type:varchar(50);not null;default:''"`
+	CronExpr       string    `gorm:"not null"`
+	Timezone       string    `gorm:"type:varchar(64);not null;default:''"` // IANA name; the scheduler's timezone when empty
+	Payload        string    `gorm:"type:jsonb"`
+	Status         string    `gorm:"default:'active'"`
+	LastRun        *time.Time
+	NextRun        *time.Time
+	LastError      string `gorm:"type:text"` // empty when the last run succeeded
+	LastDurationMs int64
+	TimeoutSeconds int  `gorm:"not null;default:0"` // a run is cancelled after this long; no limit when 0
+	MaxConcurrency int  `gorm:"not null;default:1"` // overlapping runs allowed; further firings are skipped
+	IsActive       bool `gorm:"default:true"`
+	IsPaused       bool `gorm:"not null;default:false"` // stays scheduled but firings are skipped
+	PausedAt       *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 func (Job) TableName() string {
 	return "jobs"
-}
\ No newline at end of file
+}
+
+// JobRun is one execution of a job
+type JobRun struct {
+	ID         uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	JobID      uuid.UUID `gorm:"type:uuid;not null;index:idx_job_run_job_started,priority:1"`
+	JobType    string    `gorm:"type:varchar(50);not null"`
+	Status     string    `gorm:"type:varchar(20);not null"`
+	Result     string    `gorm:"type:text"` // the handler's summary, e.g. "synced 12 forums"
+	Error      string    `gorm:"type:text"`
+	StartedAt  time.Time `gorm:"not null;index:idx_job_run_job_started,priority:2"`
+	FinishedAt *time.Time
+	DurationMs int64
+	NodeID     string `gorm:"type:varchar(100)"` // replica that ran it
+	Trigger    string `gorm:"type:varchar(20);not null;default:'schedule'"`
+
+	// Relations
+	Job *Job `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LinkPreview caches the unfurl metadata of an external URL used in topics and replies
+type LinkPreview struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	URL         string    `gorm:"type:varchar(2048);not null;uniqueIndex"`
+	Title       string    `gorm:"type:varchar(300)"`
+	Description string    `gorm:"type:varchar(1000)"`
+	ImageURL    string    `gorm:"type:varchar(2048)"`
+	SiteName    string    `gorm:"type:varchar(200)"`
+	Failed      bool      `gorm:"default:false"` // the page could not be fetched, retried after the refresh period
+	FetchedAt   time.Time `gorm:"not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (LinkPreview) TableName() string {
+	return "link_previews"
+}
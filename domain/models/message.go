@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxMessageAttachments bounds the files sent with one message
+const MaxMessageAttachments = 10
+
+// Message is a direct message. Deleting keeps the row (content cleared) so the history shows
+// where it was.
+type Message struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ConversationID uuid.UUID  `gorm:"type:uuid;not null;index:idx_message_conversation_created,priority:1" json:"conversationId"`
+	SenderID       uuid.UUID  `gorm:"type:uuid;not null" json:"senderId"`
+	Content        string     `gorm:"type:text" json:"content"`
+	EditedAt       *time.Time `json:"editedAt,omitempty"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
+	CreatedAt      time.Time  `gorm:"not null;index:idx_message_conversation_created,priority:2" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	Conversation *Conversation       `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE" json:"-"`
+	Sender       *User               `gorm:"foreignKey:SenderID;constraint:OnDelete:CASCADE" json:"sender,omitempty"`
+	Attachments  []MessageAttachment `gorm:"foreignKey:MessageID" json:"attachments,omitempty"`
+}
+
+func (Message) TableName() string {
+	return "messages"
+}
+
+// MessageAttachment is a file uploaded through the file service and sent with a message
+type MessageAttachment struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MessageID uuid.UUID `gorm:"type:uuid;not null;index" json:"messageId"`
+	FileID    uuid.UUID `gorm:"type:uuid;not null" json:"fileId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relations
+	Message *Message `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"-"`
+	File    *File    `gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE" json:"file,omitempty"`
+}
+
+func (MessageAttachment) TableName() string {
+	return "message_attachments"
+}
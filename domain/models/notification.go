@@ -17,6 +17,13 @@ const (
 	NotificationTypeReplyLike    NotificationType = "reply_like"     // มีคนไลค์การตอบกลับ
 	NotificationTypeCommentLike  NotificationType = "comment_like"   // มีคนไลค์ความคิดเห็น
 	NotificationTypeNewFollower  NotificationType = "new_follower"   // มีคนติดตาม
+	NotificationTypeAnswerAccepted NotificationType = "answer_accepted" // คำตอบถูกเลือกเป็นคำตอบที่ดีที่สุด
+	NotificationTypeWatchedTopicReply NotificationType = "watched_topic_reply" // มีคนตอบกระทู้ที่ติดตาม
+	NotificationTypeWatchedForumTopic NotificationType = "watched_forum_topic" // มีกระทู้ใหม่ใน forum ที่ติดตาม
+	NotificationTypeTopicMoved  NotificationType = "topic_moved"  // moderator ย้ายกระทู้ไป forum อื่น
+	NotificationTypeTopicMerged NotificationType = "topic_merged" // moderator รวมกระทู้เข้ากับกระทู้อื่น
+	NotificationTypeTopicSplit  NotificationType = "topic_split"  // moderator แยกความคิดเห็นไปเป็นกระทู้ใหม่
+	NotificationTypeDigest      NotificationType = "digest"       // สรุปการแจ้งเตือนที่ยังไม่ได้อ่าน (job send_digests)
 )
 
 type Notification struct {
@@ -27,6 +34,7 @@ type Notification struct {
 	ResourceID *uuid.UUID       `gorm:"type:uuid"` // ID ของ resource (topic_id, video_id, comment_id)
 	Message    string           `gorm:"type:text"`
 	IsRead     bool             `gorm:"type:boolean;default:false;index"`
+	Count      int              `gorm:"not null;default:1"` // จำนวนเหตุการณ์ที่รวมไว้ในการแจ้งเตือนนี้ (กระทู้ที่มีคนตอบถี่)
 	CreatedAt  time.Time
 
 	// Relations
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PlaylistVisibility string
+
+const (
+	PlaylistPublic   PlaylistVisibility = "public"   // listed on the owner's profile
+	PlaylistUnlisted PlaylistVisibility = "unlisted" // anyone with the link
+	PlaylistPrivate  PlaylistVisibility = "private"  // owner only
+)
+
+type Playlist struct {
+	ID          uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID          `gorm:"type:uuid;not null;index" json:"userId"`
+	Title       string             `gorm:"type:varchar(200);not null" json:"title"`
+	Description string             `gorm:"type:text" json:"description"`
+	Visibility  PlaylistVisibility `gorm:"type:varchar(20);not null;default:'public'" json:"visibility"`
+	ItemCount   int                `gorm:"type:int;default:0" json:"itemCount"`
+	CreatedAt   time.Time          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt     `gorm:"index" json:"-"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (Playlist) TableName() string {
+	return "playlists"
+}
+
+// PlaylistItem is a video at a position (0-based) in a playlist
+type PlaylistItem struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PlaylistID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_playlist_item_video,priority:1;index:idx_playlist_item_position,priority:1" json:"playlistId"`
+	VideoID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_playlist_item_video,priority:2;index" json:"videoId"`
+	Position   int       `gorm:"type:int;not null;index:idx_playlist_item_position,priority:2" json:"position"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relations
+	Playlist *Playlist `gorm:"foreignKey:PlaylistID;constraint:OnDelete:CASCADE" json:"playlist,omitempty"`
+	Video    *Video    `gorm:"foreignKey:VideoID;constraint:OnDelete:CASCADE" json:"video,omitempty"`
+}
+
+func (PlaylistItem) TableName() string {
+	return "playlist_items"
+}
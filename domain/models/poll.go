@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll is an optional vote attached to a topic (at most one per topic)
+type Poll struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TopicID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"topicId"`
+	Question        string     `gorm:"type:varchar(300);not null" json:"question"`
+	AllowMultiple   bool       `gorm:"default:false" json:"allowMultiple"`
+	MaxChoices      int        `gorm:"type:int;not null;default:1" json:"maxChoices"` // options a voter may pick
+	IsAnonymous     bool       `gorm:"default:false" json:"isAnonymous"`              // hide who voted for what
+	AllowVoteChange bool       `gorm:"default:false" json:"allowVoteChange"`
+	ClosesAt        *time.Time `gorm:"index" json:"closesAt,omitempty"`
+	ClosedAt        *time.Time `json:"closedAt,omitempty"`
+	VoterCount      int        `gorm:"type:int;default:0" json:"voterCount"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	Options []PollOption `gorm:"foreignKey:PollID;constraint:OnDelete:CASCADE" json:"options,omitempty"`
+}
+
+func (Poll) TableName() string {
+	return "polls"
+}
+
+// IsClosed also covers polls whose close time passed before the close job ran
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || (p.ClosesAt != nil && !p.ClosesAt.After(now))
+}
+
+type PollOption struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PollID    uuid.UUID `gorm:"type:uuid;not null;index" json:"pollId"`
+	Text      string    `gorm:"type:varchar(200);not null" json:"text"`
+	Position  int       `gorm:"type:int;not null" json:"position"`
+	VoteCount int       `gorm:"type:int;default:0" json:"voteCount"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (PollOption) TableName() string {
+	return "poll_options"
+}
+
+// PollBallot records that a user voted; the unique index is what limits a user to one vote per poll
+type PollBallot struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PollID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_ballot_user,priority:1" json:"pollId"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_ballot_user,priority:2;index" json:"userId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relations
+	Poll *Poll `gorm:"foreignKey:PollID;constraint:OnDelete:CASCADE" json:"poll,omitempty"`
+}
+
+func (PollBallot) TableName() string {
+	return "poll_ballots"
+}
+
+// PollVote is one chosen option of a ballot
+type PollVote struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BallotID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_vote_option,priority:1" json:"ballotId"`
+	OptionID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_vote_option,priority:2;index" json:"optionId"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"userId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relations
+	Ballot *PollBallot `gorm:"foreignKey:BallotID;constraint:OnDelete:CASCADE" json:"-"`
+	Option *PollOption `gorm:"foreignKey:OptionID;constraint:OnDelete:CASCADE" json:"-"`
+	User   *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (PollVote) TableName() string {
+	return "poll_votes"
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TopicReadMarker is how far a user has read a topic; replies after LastReadAt are unread
+type TopicReadMarker struct {
+	UserID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"userId"`
+	TopicID         uuid.UUID  `gorm:"type:uuid;primaryKey;index" json:"topicId"`
+	LastReadAt      time.Time  `gorm:"not null" json:"lastReadAt"`
+	LastReadReplyID *uuid.UUID `gorm:"type:uuid" json:"lastReadReplyId,omitempty"` // nil = read up to the topic itself
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	User  *User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Topic *Topic `gorm:"foreignKey:TopicID;constraint:OnDelete:CASCADE" json:"topic,omitempty"`
+}
+
+func (TopicReadMarker) TableName() string {
+	return "topic_read_markers"
+}
+
+// ForumReadMarker marks every topic of a forum read up to MarkedAt ("mark forum as read"),
+// without writing a marker per topic
+type ForumReadMarker struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"userId"`
+	ForumID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"forumId"`
+	MarkedAt  time.Time `gorm:"not null" json:"markedAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	User  *User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Forum *Forum `gorm:"foreignKey:ForumID;constraint:OnDelete:CASCADE" json:"forum,omitempty"`
+}
+
+func (ForumReadMarker) TableName() string {
+	return "forum_read_markers"
+}
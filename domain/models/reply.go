@@ -7,14 +7,19 @@ import (
 )
 
 type Reply struct {
-	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	TopicID   uuid.UUID  `gorm:"type:uuid;not null;index"`
-	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
-	ParentID  *uuid.UUID `gorm:"type:uuid;index"` // สำหรับ nested reply
-	Content   string     `gorm:"type:text;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time `gorm:"index"` // Soft delete
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	TopicID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index"` // สำหรับ nested reply
+	QuoteID     *uuid.UUID `gorm:"type:uuid;index"` // reply ที่ถูกอ้างอิง (quote-reply)
+	Content     string     `gorm:"type:text;not null"`
+	ContentHTML string     `gorm:"type:text"` // sanitized render of Content
+	LikeCount   int        `gorm:"default:0"`
+	EditCount   int        `gorm:"default:0"`
+	EditedAt    *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time `gorm:"index"` // Soft delete
 
 	// Relations
 	Topic   Topic   `gorm:"foreignKey:TopicID"`
@@ -26,3 +31,12 @@ type Reply struct {
 func (Reply) TableName() string {
 	return "replies"
 }
+
+// ReplySort is the order of replies in a thread
+type ReplySort string
+
+const (
+	ReplySortOldest ReplySort = "oldest"
+	ReplySortNewest ReplySort = "newest"
+	ReplySortTop    ReplySort = "top" // most liked first
+)
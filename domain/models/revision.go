@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevisionContentType string
+
+const (
+	RevisionContentTopic   RevisionContentType = "topic"
+	RevisionContentReply   RevisionContentType = "reply"
+	RevisionContentComment RevisionContentType = "comment"
+)
+
+// ContentRevision is a full snapshot of a topic, reply or comment. Version 1 is the
+// original post, every edit (including rollbacks) appends the next version.
+type ContentRevision struct {
+	ID          uuid.UUID           `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ContentType RevisionContentType `gorm:"type:varchar(20);not null;uniqueIndex:idx_content_revision_version,priority:1"`
+	ContentID   uuid.UUID           `gorm:"type:uuid;not null;uniqueIndex:idx_content_revision_version,priority:2"`
+	Version     int                 `gorm:"not null;uniqueIndex:idx_content_revision_version,priority:3"`
+	Title       string              `gorm:"type:varchar(200)"` // topics only
+	Content     string              `gorm:"type:text;not null"`
+	Tags        string              `gorm:"type:jsonb"` // [{"id","name"}] of topic tags at that version
+	EditorID    uuid.UUID           `gorm:"type:uuid;not null;index"`
+	Note        string              `gorm:"type:varchar(200)"` // e.g. rollback to version 2
+	CreatedAt   time.Time
+
+	// Relations
+	Editor User `gorm:"foreignKey:EditorID"`
+}
+
+func (ContentRevision) TableName() string {
+	return "content_revisions"
+}
@@ -8,28 +8,48 @@ import (
 )
 
 type Topic struct {
-	ID         uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	ForumID    uuid.UUID  `gorm:"type:uuid;not null;index"`
-	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"`
-	Title      string     `gorm:"type:varchar(200);not null"`
-	Content    string     `gorm:"type:text;not null"`
-	Thumbnail  string     `gorm:"type:varchar(500)"` // Optional thumbnail image URL
-	ViewCount  int        `gorm:"default:0"`
-	ReplyCount int        `gorm:"default:0"`
-	LikeCount  int        `gorm:"default:0"`
-	IsPinned   bool       `gorm:"default:false"`
-	IsLocked   bool       `gorm:"default:false"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	DeletedAt  gorm.DeletedAt `gorm:"index"` // Soft delete
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ForumID     uuid.UUID  `gorm:"type:uuid;not null;index;index:idx_topics_forum_activity,priority:1"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Title       string     `gorm:"type:varchar(200);not null"`
+	Content     string     `gorm:"type:text;not null"`
+	ContentHTML string     `gorm:"type:text"`         // sanitized render of Content
+	Thumbnail   string     `gorm:"type:varchar(500)"` // Optional thumbnail image URL
+	ViewCount   int        `gorm:"default:0"`
+	ReplyCount  int        `gorm:"default:0"`
+	LikeCount   int        `gorm:"default:0"`
+	IsPinned    bool       `gorm:"default:false"`
+	IsLocked    bool       `gorm:"default:false"`
+	EditCount   int        `gorm:"default:0"`
+	EditedAt    *time.Time // last edit of title/content/tags
+	AcceptedReplyID *uuid.UUID `gorm:"type:uuid;index"` // accepted answer (Q&A forums only)
+	AcceptedAt      *time.Time
+	LastActivityAt  *time.Time `gorm:"index:idx_topics_forum_activity,priority:2"` // newest reply, or creation; drives unread tracking
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"` // Soft delete
 
 	// Relations
 	Forum   Forum   `gorm:"foreignKey:ForumID"`
 	User    User    `gorm:"foreignKey:UserID"`
 	Replies []Reply `gorm:"foreignKey:TopicID"`
 	Tags    []Tag   `gorm:"many2many:topic_tags;"`
+	Poll    *Poll   `gorm:"foreignKey:TopicID;constraint:OnDelete:CASCADE"`
 }
 
 func (Topic) TableName() string {
 	return "topics"
 }
+
+// TopicRedirect keeps the ID of a topic merged into another one resolving to the topic
+// that took its replies. Chains are flattened when the target is merged again.
+type TopicRedirect struct {
+	FromTopicID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ToTopicID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedBy   uuid.UUID `gorm:"type:uuid;not null"` // moderator who merged
+	CreatedAt   time.Time
+}
+
+func (TopicRedirect) TableName() string {
+	return "topic_redirects"
+}
@@ -26,6 +26,9 @@ type User struct {
 	FollowerCount  int `gorm:"default:0"`
 	FollowingCount int `gorm:"default:0"`
 
+	// Storage quota in bytes (0 = ใช้ค่า default จาก config)
+	StorageQuota int64 `gorm:"default:0"`
+
 	// Admin System (Task 06)
 	SuspendedUntil *time.Time
 	SuspendReason  string `gorm:"type:text"`
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoView is one counted view (deduplicated per user/session) with its engagement data
+type VideoView struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	VideoID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_video_view_video_created" json:"videoId"`
+	UserID      *uuid.UUID `gorm:"type:uuid;index" json:"userId,omitempty"` // Nullable - anonymous viewers
+	SessionID   string     `gorm:"type:varchar(100)" json:"-"`              // anonymous viewers: hash of IP address and user agent
+	Source      string     `gorm:"type:varchar(50);index" json:"source"`    // "feed", "profile", "search", "share", "playlist", "direct", "external"
+	WatchTime   int        `gorm:"type:int;default:0" json:"watchTime"`     // Seconds actually watched
+	MaxPosition int        `gorm:"type:int;default:0" json:"maxPosition"`   // Furthest position reached in seconds
+	Completed   bool       `gorm:"type:boolean;default:false" json:"completed"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	LastEventAt time.Time  `json:"lastEventAt"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime;index:idx_video_view_video_created" json:"createdAt"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	Video *Video `gorm:"foreignKey:VideoID;constraint:OnDelete:CASCADE" json:"video,omitempty"`
+}
+
+func (VideoView) TableName() string {
+	return "video_views"
+}
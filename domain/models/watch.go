@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TopicWatch subscribes a user to new replies in a topic. Unwatching keeps the row with
+// IsWatching = false so auto-watch on a later reply doesn't subscribe the user again.
+type TopicWatch struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_topic_watch_user_topic,priority:1" json:"userId"`
+	TopicID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_topic_watch_user_topic,priority:2;index" json:"topicId"`
+	IsWatching bool      `gorm:"not null;default:true" json:"isWatching"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	User  *User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Topic *Topic `gorm:"foreignKey:TopicID;constraint:OnDelete:CASCADE" json:"topic,omitempty"`
+}
+
+func (TopicWatch) TableName() string {
+	return "topic_watches"
+}
+
+// ForumWatch subscribes a user to new topics in a forum
+type ForumWatch struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_forum_watch_user_forum,priority:1" json:"userId"`
+	ForumID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_forum_watch_user_forum,priority:2;index" json:"forumId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relations
+	User  *User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Forum *Forum `gorm:"foreignKey:ForumID;constraint:OnDelete:CASCADE" json:"forum,omitempty"`
+}
+
+func (ForumWatch) TableName() string {
+	return "forum_watches"
+}
+
+// WatchPreference overrides the configured auto-watch defaults for one user
+type WatchPreference struct {
+	UserID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"userId"`
+	AutoWatchOnPost  bool      `gorm:"not null" json:"autoWatchOnPost"`
+	AutoWatchOnReply bool      `gorm:"not null" json:"autoWatchOnReply"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (WatchPreference) TableName() string {
+	return "watch_preferences"
+}
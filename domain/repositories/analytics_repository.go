@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContentStatFilter selects rollup rows: all content of an owner, optionally narrowed to one type or item
+type ContentStatFilter struct {
+	OwnerID     uuid.UUID
+	ContentType models.AnalyticsContentType // empty = all types
+	ContentID   *uuid.UUID
+	From        time.Time // inclusive date
+	To          time.Time // inclusive date
+}
+
+type AnalyticsRepository interface {
+	// RollupDay (re)computes the daily stats of one day; start/end bound the day in the reporting timezone.
+	// snapshotTopicViews is only valid while the day is still in progress, because topic views are
+	// derived from the live ViewCount counter.
+	RollupDay(ctx context.Context, day, start, end time.Time, snapshotTopicViews bool) error
+
+	// Queries - series are summed per date, top content per item
+	GetContentSeries(ctx context.Context, filter ContentStatFilter) ([]models.ContentDailyStat, error)
+	GetTopContent(ctx context.Context, filter ContentStatFilter, limit int) ([]models.ContentDailyStat, error)
+	GetShareSeries(ctx context.Context, filter ContentStatFilter) ([]models.ShareDailyStat, error)
+	GetFollowerSeries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.FollowerDailyStat, error) // includes the last row before from
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrTaskNotOwned means another worker claimed the task after this one's claim went stale
+var ErrTaskNotOwned = errors.New("background task is no longer owned by this worker")
+
+type BackgroundTaskFilter struct {
+	Status models.BackgroundTaskStatus // all when empty
+	Type   string                      // all when empty
+}
+
+type BackgroundTaskRepository interface {
+	// Enqueue inserts the task unless one with the same idempotency key exists; reports whether it was queued
+	Enqueue(ctx context.Context, task *models.BackgroundTask) (bool, error)
+	// ClaimDue marks up to limit due tasks as processing by workerID and returns them. Tasks
+	// left processing since staleBefore (their worker died) are claimed again, or moved to the
+	// dead letters when that was their last attempt.
+	ClaimDue(ctx context.Context, workerID string, limit int, staleBefore time.Time) ([]*models.BackgroundTask, error)
+	// Complete, Retry and MarkDead only apply to the claim task was returned by; they fail with
+	// ErrTaskNotOwned when the task has been claimed again since
+	Complete(ctx context.Context, task *models.BackgroundTask, completedAt time.Time) error
+	Retry(ctx context.Context, task *models.BackgroundTask, runAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, task *models.BackgroundTask, lastError string) error
+
+	GetByID(ctx context.Context, id uuid.UUID) (*models.BackgroundTask, error)
+	List(ctx context.Context, filter BackgroundTaskFilter, offset, limit int) ([]*models.BackgroundTask, int64, error)
+	CountByStatus(ctx context.Context) (map[models.BackgroundTaskStatus]int64, error)
+
+	// Replay queues dead tasks again with fresh attempts; returns how many were replayed
+	Replay(ctx context.Context, ids []uuid.UUID) (int64, error)
+	ReplayDead(ctx context.Context, taskType string) (int64, error)
+	DeleteCompletedBefore(ctx context.Context, before time.Time) (int64, error)
+}
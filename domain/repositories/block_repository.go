@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type BlockRepository interface {
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error)
+
+	// IsBlockedEither reports a block between the two users in either direction
+	IsBlockedEither(ctx context.Context, userID, otherID uuid.UUID) (bool, error)
+	// FindBlockedAmong returns the users of otherIDs that have a block with userID either way
+	FindBlockedAmong(ctx context.Context, userID uuid.UUID, otherIDs []uuid.UUID) ([]uuid.UUID, error)
+	// HasBlockAcross reports a block either way between any user of userIDs and any user of otherIDs
+	HasBlockAcross(ctx context.Context, userIDs, otherIDs []uuid.UUID) (bool, error)
+
+	FindBlocked(ctx context.Context, blockerID uuid.UUID, offset, limit int) ([]*models.Block, int64, error)
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// BookmarkFilter narrows a user's bookmark list; content that is deleted, hidden or in
+// ExcludeForumIDs is always left out
+type BookmarkFilter struct {
+	TargetType      models.BookmarkTargetType // empty = every type
+	FolderID        *uuid.UUID
+	Unsorted        bool // only bookmarks that are not in a folder
+	ExcludeForumIDs []uuid.UUID
+}
+
+type BookmarkRepository interface {
+	// Bookmarks - Create reports false when the target was already bookmarked
+	Create(ctx context.Context, bookmark *models.Bookmark) (bool, error)
+	Delete(ctx context.Context, userID uuid.UUID, targetType models.BookmarkTargetType, targetID uuid.UUID) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Bookmark, error)
+	GetByTarget(ctx context.Context, userID uuid.UUID, targetType models.BookmarkTargetType, targetID uuid.UUID) (*models.Bookmark, error)
+	IsBookmarked(ctx context.Context, userID uuid.UUID, targetType models.BookmarkTargetType, targetID uuid.UUID) (bool, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID, filter BookmarkFilter, offset, limit int) ([]*models.Bookmark, int64, error)
+	SetFolder(ctx context.Context, id uuid.UUID, folderID *uuid.UUID) error
+
+	// Folders - deleting a folder keeps its bookmarks outside any folder
+	CreateFolder(ctx context.Context, folder *models.BookmarkFolder) error
+	GetFolderByID(ctx context.Context, id uuid.UUID) (*models.BookmarkFolder, error)
+	FindFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*models.BookmarkFolder, error)
+	FolderNameExists(ctx context.Context, userID uuid.UUID, name string, excludeID *uuid.UUID) (bool, error)
+	UpdateFolder(ctx context.Context, folder *models.BookmarkFolder) error
+	DeleteFolder(ctx context.Context, id uuid.UUID) error
+}
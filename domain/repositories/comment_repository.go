@@ -9,10 +9,14 @@ import (
 
 type CommentRepository interface {
 	// Basic CRUD
-	Create(ctx context.Context, comment *models.Comment) error
+	// Create and Delete queue the given tasks in the same transaction
+	Create(ctx context.Context, comment *models.Comment, tasks ...*models.BackgroundTask) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Comment, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Comment, error)
 	Update(ctx context.Context, comment *models.Comment) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Edit saves an edited comment and its revision in one transaction
+	Edit(ctx context.Context, comment *models.Comment, revision *RevisionEdit) error
+	Delete(ctx context.Context, id uuid.UUID, tasks ...*models.BackgroundTask) error
 
 	// Query methods
 	FindByVideoID(ctx context.Context, videoID uuid.UUID, offset, limit int) ([]*models.Comment, int64, error)
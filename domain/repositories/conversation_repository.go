@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConversationCursor is the position of the last conversation of an inbox page
+type ConversationCursor struct {
+	LastMessageAt time.Time `json:"t"`
+	ID            uuid.UUID `json:"i"`
+}
+
+// MessageCursor is the position of the oldest message of a history page
+type MessageCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// UnreadMessageCount sums unread messages over the conversations a user is active in
+type UnreadMessageCount struct {
+	Messages      int64
+	Conversations int64
+}
+
+type ConversationRepository interface {
+	// Create inserts the conversation with its participants
+	Create(ctx context.Context, conversation *models.Conversation) error
+	// GetByID loads the conversation with all participants and their users
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Conversation, error)
+	GetByDirectKey(ctx context.Context, directKey string) (*models.Conversation, error)
+
+	// FindByUser lists the conversations where the user has one of the statuses, most recent
+	// message first
+	FindByUser(ctx context.Context, userID uuid.UUID, statuses []models.ParticipantStatus, after *ConversationCursor, limit int) ([]*models.Conversation, error)
+	CountByUser(ctx context.Context, userID uuid.UUID, status models.ParticipantStatus) (int64, error)
+
+	// AddParticipants inserts participants, or re-adds them with their new status if they had
+	// left or declined
+	AddParticipants(ctx context.Context, participants []*models.ConversationParticipant) error
+	UpdateParticipantStatus(ctx context.Context, conversationID, userID uuid.UUID, status models.ParticipantStatus) error
+	// MarkRead moves the user's read receipt forward to the message; never backwards. Reports
+	// whether it moved.
+	MarkRead(ctx context.Context, conversationID, userID uuid.UUID, message *models.Message, readAt time.Time) (bool, error)
+
+	// CountUnread counts messages by others since each conversation was last read
+	CountUnread(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	CountUnreadTotal(ctx context.Context, userID uuid.UUID) (*UnreadMessageCount, error)
+}
+
+type MessageRepository interface {
+	// Create inserts the message with its attachments, makes it the conversation's last
+	// message and marks it read for the sender
+	Create(ctx context.Context, message *models.Message) error
+	// GetByID loads the message with its sender and attachment files
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Message, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error)
+	GetLatest(ctx context.Context, conversationID uuid.UUID) (*models.Message, error)
+	UpdateContent(ctx context.Context, id uuid.UUID, content string, editedAt time.Time) error
+	// SoftDelete clears the content and attachments but keeps the message in the history
+	SoftDelete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+
+	// FindByConversation returns a page of history, newest first, older than before
+	FindByConversation(ctx context.Context, conversationID uuid.UUID, before *MessageCursor, limit int) ([]*models.Message, error)
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"gofiber-social/domain/models"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,10 +11,22 @@ import (
 type FileRepository interface {
 	Create(ctx context.Context, file *models.File) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.File, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.File, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.File, error)
 	Update(ctx context.Context, id uuid.UUID, file *models.File) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, offset, limit int) ([]*models.File, error)
 	Count(ctx context.Context) (int64, error)
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetByURL(ctx context.Context, url string) (*models.File, error)
+	SumSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// Reference tracking
+	CreateReference(ctx context.Context, ref *models.FileReference) error
+	DeleteReferencesByResource(ctx context.Context, resourceType models.FileReferenceType, resourceID uuid.UUID) ([]uuid.UUID, error)
+	CountReferences(ctx context.Context, fileID uuid.UUID) (int64, error)
+	FindOrphaned(ctx context.Context, createdBefore time.Time, limit int) ([]*models.File, error)
+	// FindUnprotectedReferenced lists public files that resources of the given types use
+	FindUnprotectedReferenced(ctx context.Context, resourceTypes []models.FileReferenceType, limit int) ([]*models.File, error)
+	MarkProtected(ctx context.Context, id uuid.UUID, url string) error
 }
@@ -21,4 +21,10 @@ type ForumRepository interface {
 	DecrementTopicCount(ctx context.Context, id uuid.UUID) error
 	SyncTopicCount(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context) (int64, error)
+
+	// Moderators
+	AddModerator(ctx context.Context, moderator *models.ForumModerator) error
+	RemoveModerator(ctx context.Context, forumID, userID uuid.UUID) error
+	GetModerators(ctx context.Context, forumID uuid.UUID) ([]*models.ForumModerator, error)
+	GetModeratedForumIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 }
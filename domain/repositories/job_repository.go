@@ -13,10 +13,24 @@ type JobRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error)
 	GetByName(ctx context.Context, name string) (*models.Job, error)
 	GetActiveJobs(ctx context.Context) ([]*models.Job, error)
+	// Update writes the editable settings of the job, zero values included; run bookkeeping is
+	// left to MarkRunning and UpdateRunStatus
 	Update(ctx context.Context, id uuid.UUID, job *models.Job) error
+	SetActive(ctx context.Context, id uuid.UUID, isActive bool) error
+	// SetPaused records a pause (pausedAt set) or a resume (pausedAt nil)
+	SetPaused(ctx context.Context, id uuid.UUID, paused bool, pausedAt *time.Time) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, offset, limit int) ([]*models.Job, error)
 	Count(ctx context.Context) (int64, error)
 	UpdateLastRun(ctx context.Context, id uuid.UUID, lastRun *time.Time) error
 	UpdateNextRun(ctx context.Context, id uuid.UUID, nextRun *time.Time) error
+	// MarkRunning records the start of an execution on the job
+	MarkRunning(ctx context.Context, id uuid.UUID, startedAt time.Time) error
+	// UpdateRunStatus records the outcome of the latest execution on the job
+	UpdateRunStatus(ctx context.Context, id uuid.UUID, status, lastError string, durationMs int64, nextRun *time.Time) error
+
+	// Run history
+	CreateRun(ctx context.Context, run *models.JobRun) error
+	FinishRun(ctx context.Context, run *models.JobRun) error
+	ListRuns(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, int64, error)
 }
@@ -7,31 +7,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// Like and Unlike insert the given background tasks in the same transaction as the change
 type LikeRepository interface {
 	// Topic Likes
-	LikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID) (*models.Like, error)
-	UnlikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID) error
+	LikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error)
+	UnlikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID, tasks ...*models.BackgroundTask) error
 	IsTopicLikedByUser(ctx context.Context, userID uuid.UUID, topicID uuid.UUID) (bool, error)
 	CountTopicLikes(ctx context.Context, topicID uuid.UUID) (int64, error)
 	GetTopicLikesByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Like, int64, error)
 
 	// Video Likes
-	LikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID) (*models.Like, error)
-	UnlikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID) error
+	LikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error)
+	UnlikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID, tasks ...*models.BackgroundTask) error
 	IsVideoLikedByUser(ctx context.Context, userID uuid.UUID, videoID uuid.UUID) (bool, error)
 	CountVideoLikes(ctx context.Context, videoID uuid.UUID) (int64, error)
 	GetVideoLikesByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Like, int64, error)
 
 	// Reply Likes
-	LikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) (*models.Like, error)
-	UnlikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) error
+	LikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error)
+	UnlikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID, tasks ...*models.BackgroundTask) error
 	IsReplyLikedByUser(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) (bool, error)
 	CountReplyLikes(ctx context.Context, replyID uuid.UUID) (int64, error)
 	GetReplyLikesByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Like, int64, error)
 
 	// Comment Likes
-	LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*models.Like, error)
-	UnlikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) error
+	LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error)
+	UnlikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, tasks ...*models.BackgroundTask) error
 	IsCommentLikedByUser(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (bool, error)
 	CountCommentLikes(ctx context.Context, commentID uuid.UUID) (int64, error)
 	GetCommentLikesByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Like, int64, error)
@@ -39,4 +40,8 @@ type LikeRepository interface {
 	// General
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Like, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// RecomputeLikeCounts sets like_count of every topic, reply or video (targetType) to its
+	// number of likes and returns how many were wrong
+	RecomputeLikeCounts(ctx context.Context, targetType string) (int64, error)
 }
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+)
+
+type LinkPreviewRepository interface {
+	GetByURLs(ctx context.Context, urls []string) ([]*models.LinkPreview, error)
+	Upsert(ctx context.Context, preview *models.LinkPreview) error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
@@ -18,13 +19,25 @@ type NotificationRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Notification, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, params *dto.NotificationQueryParams) ([]models.Notification, int64, error)
 	GetUnreadCount(ctx context.Context, userID uuid.UUID) (int64, error)
+	FindUnreadByResource(ctx context.Context, userIDs []uuid.UUID, notificationType models.NotificationType, resourceID uuid.UUID) ([]models.Notification, error)
 
 	// Update
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 	MarkMultipleAsRead(ctx context.Context, ids []uuid.UUID) error
 	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
+	// Fold adds one more event to unread notifications: bumps Count, replaces actor and message
+	// and moves them back to the top of the list
+	Fold(ctx context.Context, ids []uuid.UUID, actorID uuid.UUID, message string) error
+	// FoldAndCreate folds ids and creates notifications in one transaction, so a retried
+	// fan-out either did both or neither
+	FoldAndCreate(ctx context.Context, ids []uuid.UUID, actorID uuid.UUID, message string, notifications []models.Notification) error
 
 	// Delete
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteOlderThan(ctx context.Context, before time.Time, readOnly bool) (int64, error)
+
+	// CountUnreadForDigest counts unread notifications created since the given time for users
+	// with at least minUnread of them who haven't had a digest since then
+	CountUnreadForDigest(ctx context.Context, since time.Time, minUnread int) (map[uuid.UUID]int64, error)
 }
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type PlaylistRepository interface {
+	// Basic CRUD
+	Create(ctx context.Context, playlist *models.Playlist) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error)
+	Update(ctx context.Context, playlist *models.Playlist) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List - publicOnly hides unlisted and private playlists
+	FindByUserID(ctx context.Context, userID uuid.UUID, publicOnly bool, offset, limit int) ([]*models.Playlist, int64, error)
+	FindIDsContainingVideo(ctx context.Context, userID, videoID uuid.UUID) ([]uuid.UUID, error)
+
+	// Items - positions are kept contiguous from 0
+	GetItems(ctx context.Context, playlistID uuid.UUID, offset, limit int) ([]*models.PlaylistItem, int64, error)
+	GetVideoIDs(ctx context.Context, playlistID uuid.UUID) ([]uuid.UUID, error)
+	AddItem(ctx context.Context, playlistID, videoID uuid.UUID) error
+	RemoveItem(ctx context.Context, playlistID, videoID uuid.UUID) error
+	ReorderItems(ctx context.Context, playlistID uuid.UUID, videoIDs []uuid.UUID) error
+
+	// RemoveVideoFromAll drops a deleted or hidden video from every playlist
+	RemoveVideoFromAll(ctx context.Context, videoID uuid.UUID) error
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PollRepository interface {
+	// Polls are created together with their topic, options ordered by position
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Poll, error)
+	GetByTopicID(ctx context.Context, topicID uuid.UUID) (*models.Poll, error)
+
+	// Votes - CastVote fails with "you have already voted" unless replace is set,
+	// in which case the user's previous ballot is swapped out in the same transaction
+	CastVote(ctx context.Context, pollID, userID uuid.UUID, optionIDs []uuid.UUID, replace bool) error
+	RetractVote(ctx context.Context, pollID, userID uuid.UUID) error
+	GetUserOptionIDs(ctx context.Context, pollID, userID uuid.UUID) ([]uuid.UUID, error)
+	GetVoters(ctx context.Context, optionID uuid.UUID, offset, limit int) ([]*models.PollVote, int64, error)
+
+	// Closing - Close reports false when the poll was already closed
+	Close(ctx context.Context, pollID uuid.UUID, closedAt time.Time) (bool, error)
+	FindDueForClose(ctx context.Context, now time.Time, limit int) ([]*models.Poll, error)
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TopicReadState is a user's read position in a topic. The effective read time is the latest
+// of the topic marker, the forum marker and the user's baseline (activity before it is never new).
+type TopicReadState struct {
+	TopicID         uuid.UUID
+	LastReadAt      *time.Time
+	LastReadReplyID *uuid.UUID
+	IsUnread        bool
+	NewReplyCount   int
+}
+
+type ReadRepository interface {
+	// Markers never move backwards
+	MarkTopicRead(ctx context.Context, userID, topicID uuid.UUID, replyID *uuid.UUID, at time.Time) error
+	MarkForumRead(ctx context.Context, userID, forumID uuid.UUID, at time.Time) error
+
+	// Read state for a page of topics / unread topic counts for a set of forums
+	GetTopicReadStates(ctx context.Context, userID uuid.UUID, baseline time.Time, topicIDs []uuid.UUID) ([]TopicReadState, error)
+	CountUnreadTopicsByForum(ctx context.Context, userID uuid.UUID, baseline time.Time, forumIDs []uuid.UUID) (map[uuid.UUID]int, error)
+
+	// FindFirstUnreadReply returns the oldest reply by someone else after the effective read time
+	FindFirstUnreadReply(ctx context.Context, userID, topicID uuid.UUID, baseline time.Time) (*models.Reply, error)
+	// CountRepliesBefore is the reply's position in the topic's chronological order
+	CountRepliesBefore(ctx context.Context, topicID uuid.UUID, at time.Time) (int64, error)
+}
@@ -3,15 +3,43 @@ package repositories
 import (
 	"context"
 	"gofiber-social/domain/models"
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// ReplyCursor is the position of the last reply of a thread page
+type ReplyCursor struct {
+	LikeCount int       `json:"l"`
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// ReplyThreadQuery selects one page of siblings; After takes precedence over Offset
+type ReplyThreadQuery struct {
+	Sort   models.ReplySort
+	After  *ReplyCursor
+	Offset int
+	Limit  int
+}
+
 type ReplyRepository interface {
-	Create(ctx context.Context, reply *models.Reply) error
+	// Create also bumps the topic's reply count and queues the given tasks, in one transaction
+	Create(ctx context.Context, reply *models.Reply, tasks ...*models.BackgroundTask) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Reply, error)
 	GetByTopicID(ctx context.Context, topicID uuid.UUID, offset, limit int) ([]*models.Reply, error)
 	GetByParentID(ctx context.Context, parentID uuid.UUID) ([]*models.Reply, error)
 	Update(ctx context.Context, id uuid.UUID, reply *models.Reply) error
+	// Edit saves an edited reply and its revision in one transaction
+	Edit(ctx context.Context, reply *models.Reply, revision *RevisionEdit) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context, topicID uuid.UUID) (int64, error)
+	UpdateLikeCount(ctx context.Context, id uuid.UUID, count int) error
+
+	// Threads
+	GetThreadPage(ctx context.Context, topicID uuid.UUID, parentID *uuid.UUID, query ReplyThreadQuery) ([]*models.Reply, error)
+	GetChildren(ctx context.Context, parentIDs []uuid.UUID, sort models.ReplySort, perParent int) ([]*models.Reply, error)
+	CountChildren(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	CountSiblingsBefore(ctx context.Context, reply *models.Reply, sort models.ReplySort) (int64, error)
+	GetByIDsIncludingDeleted(ctx context.Context, ids []uuid.UUID) ([]*models.Reply, error)
 }
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// RevisionEdit is the revision recorded with an edit, in the edit's transaction. Original is
+// stored first as version 1 when the content has no revisions yet; versions are assigned on save.
+type RevisionEdit struct {
+	Original *models.ContentRevision
+	Revision *models.ContentRevision
+}
+
+type RevisionRepository interface {
+	Create(ctx context.Context, revision *models.ContentRevision) error
+	GetByVersion(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID, version int) (*models.ContentRevision, error)
+
+	// LatestVersion returns 0 when the content was never edited
+	LatestVersion(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID) (int, error)
+
+	// FindByContent lists revisions newest first
+	FindByContent(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID, offset, limit int) ([]*models.ContentRevision, int64, error)
+}
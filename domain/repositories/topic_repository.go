@@ -6,30 +6,54 @@ import (
 	"github.com/google/uuid"
 )
 
+// TopicFilter narrows topic listings; Solved = false only matches topics of Q&A forums
+type TopicFilter struct {
+	Solved *bool
+}
+
+// excludeForumIDs hides topics of forums the viewer may not see (nil = no filter)
 type TopicRepository interface {
-	Create(ctx context.Context, topic *models.Topic) error
+	// Create saves the topic, counts it in its forum and saves the background tasks it queues, all at once
+	Create(ctx context.Context, topic *models.Topic, tasks ...*models.BackgroundTask) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Topic, error)
-	GetByForumID(ctx context.Context, forumID uuid.UUID, offset, limit int) ([]*models.Topic, error)
-	GetByTag(ctx context.Context, tag string, offset, limit int) ([]*models.Topic, error)
-	GetByTags(ctx context.Context, tags []string, offset, limit int) ([]*models.Topic, error)
-	List(ctx context.Context, offset, limit int) ([]*models.Topic, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Topic, error)
+	GetByForumID(ctx context.Context, forumID uuid.UUID, filter TopicFilter, offset, limit int) ([]*models.Topic, error)
+	GetByTag(ctx context.Context, tag string, excludeForumIDs []uuid.UUID, offset, limit int) ([]*models.Topic, error)
+	GetByTags(ctx context.Context, tags []string, excludeForumIDs []uuid.UUID, offset, limit int) ([]*models.Topic, error)
+	List(ctx context.Context, excludeForumIDs []uuid.UUID, filter TopicFilter, offset, limit int) ([]*models.Topic, error)
 	Update(ctx context.Context, id uuid.UUID, topic *models.Topic) error
+	// Edit saves an edited topic, its tags (unchanged when nil) and its revision (none when nil)
+	// in one transaction
+	Edit(ctx context.Context, topic *models.Topic, tags []*models.Tag, revision *RevisionEdit) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	IncrementViewCount(ctx context.Context, id uuid.UUID) error
-	IncrementReplyCount(ctx context.Context, id uuid.UUID) error
+	IncrementReplyCount(ctx context.Context, id uuid.UUID) error // also moves LastActivityAt to now
 	DecrementReplyCount(ctx context.Context, id uuid.UUID) error
 	Pin(ctx context.Context, id uuid.UUID) error
 	Unpin(ctx context.Context, id uuid.UUID) error
 	Lock(ctx context.Context, id uuid.UUID) error
 	Unlock(ctx context.Context, id uuid.UUID) error
-	Count(ctx context.Context) (int64, error)
-	CountByForumID(ctx context.Context, forumID uuid.UUID) (int64, error)
+	Count(ctx context.Context, excludeForumIDs []uuid.UUID, filter TopicFilter) (int64, error)
+	CountByForumID(ctx context.Context, forumID uuid.UUID, filter TopicFilter) (int64, error)
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
-	CountByTag(ctx context.Context, tag string) (int64, error)
-	CountByTags(ctx context.Context, tags []string) (int64, error)
-	Search(ctx context.Context, query string, offset, limit int) ([]*models.Topic, int64, error)
+	CountByTag(ctx context.Context, tag string, excludeForumIDs []uuid.UUID) (int64, error)
+	CountByTags(ctx context.Context, tags []string, excludeForumIDs []uuid.UUID) (int64, error)
+	Search(ctx context.Context, query string, excludeForumIDs []uuid.UUID, offset, limit int) ([]*models.Topic, int64, error)
 	UpdateLikeCount(ctx context.Context, topicID uuid.UUID, count int) error
+	SetAcceptedReply(ctx context.Context, topicID uuid.UUID, replyID *uuid.UUID) error
 	GetTotalCount(ctx context.Context) (int64, error)
 	AssociateTags(ctx context.Context, topicID uuid.UUID, tags []*models.Tag) error
 	RemoveAllTags(ctx context.Context, topicID uuid.UUID) error
+	GetTags(ctx context.Context, topicID uuid.UUID) ([]*models.Tag, error)
+
+	// Moderation - each runs in one transaction and keeps forum topic counts and topic
+	// reply counts consistent
+	MoveToForum(ctx context.Context, topic *models.Topic, forumID uuid.UUID) error
+	// Merge moves source's replies into the target under opening (a reply holding source's
+	// first post), deletes source and leaves redirect behind
+	Merge(ctx context.Context, source *models.Topic, opening *models.Reply, redirect *models.TopicRedirect) error
+	// Split creates topic from source's replyIDs; openingID is the reply that became the
+	// new topic's first post and is removed
+	Split(ctx context.Context, source, topic *models.Topic, openingID uuid.UUID, replyIDs []uuid.UUID) error
+	GetRedirect(ctx context.Context, fromTopicID uuid.UUID) (*models.TopicRedirect, error)
 }
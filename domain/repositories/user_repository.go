@@ -20,6 +20,7 @@ type UserRepository interface {
 	List(ctx context.Context, offset, limit int) ([]*models.User, error)
 	Count(ctx context.Context) (int64, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error)
 	UpdateFollowerCount(ctx context.Context, userID uuid.UUID, count int) error
 	UpdateFollowingCount(ctx context.Context, userID uuid.UUID, count int) error
 
@@ -18,16 +18,18 @@ type VideoRepository interface {
 	// List & Query
 	FindAll(ctx context.Context, params *dto.VideoQueryParams) ([]models.Video, int64, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, params *dto.VideoQueryParams) ([]models.Video, int64, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Video, error)
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 
-	// View Count
-	IncrementViewCount(ctx context.Context, id uuid.UUID) error
+	// View Count - counts are buffered and applied in batches
+	AddViewCounts(ctx context.Context, counts map[uuid.UUID]int64) error
 
 	// Like & Comment Count
 	UpdateLikeCount(ctx context.Context, id uuid.UUID, count int) error
 	UpdateCommentCount(ctx context.Context, id uuid.UUID, count int) error
 
 	// Admin
+	FindByIDIncludingInactive(ctx context.Context, id uuid.UUID) (*models.Video, error)
 	FindAllIncludingInactive(ctx context.Context, params *dto.VideoQueryParams) ([]models.Video, int64, error)
 	SetActive(ctx context.Context, id uuid.UUID, isActive bool) error
 	GetTotalCount(ctx context.Context) (int64, error)
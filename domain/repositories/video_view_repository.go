@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VideoViewRepository interface {
+	Create(ctx context.Context, view *models.VideoView) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.VideoView, error)
+
+	// RecordProgress adds watch time and moves the furthest position forward
+	RecordProgress(ctx context.Context, id uuid.UUID, watchedSeconds, position int, completed bool, at time.Time) error
+}
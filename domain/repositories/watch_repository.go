@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrWatchPreferenceNotFound means the user never saved watch preferences and the defaults apply
+var ErrWatchPreferenceNotFound = errors.New("watch preference not found")
+
+// WatchedTopicFilter narrows the watched topics list; deleted topics and topics in
+// ExcludeForumIDs are always left out
+type WatchedTopicFilter struct {
+	UnreadOnly      bool
+	ExcludeForumIDs []uuid.UUID
+}
+
+// WatchedTopic is a watched topic with the replies by others since the user's last read
+type WatchedTopic struct {
+	TopicID     uuid.UUID
+	UnreadCount int
+	LastReadAt  *time.Time
+}
+
+type WatchRepository interface {
+	// Topics - WatchTopic and UnwatchTopic record an explicit choice; AutoWatchTopic only
+	// subscribes users who never chose either way
+	WatchTopic(ctx context.Context, userID, topicID uuid.UUID) error
+	AutoWatchTopic(ctx context.Context, userID, topicID uuid.UUID) error
+	UnwatchTopic(ctx context.Context, userID, topicID uuid.UUID) error
+	IsWatchingTopic(ctx context.Context, userID, topicID uuid.UUID) (bool, error)
+	// GetTopicWatcherIDs is ordered by user, so batches of it stay the same when a task is retried
+	GetTopicWatcherIDs(ctx context.Context, topicID uuid.UUID) ([]uuid.UUID, error)
+	FindWatchedTopics(ctx context.Context, userID uuid.UUID, filter WatchedTopicFilter, offset, limit int) ([]WatchedTopic, int64, error)
+
+	// Forums
+	WatchForum(ctx context.Context, userID, forumID uuid.UUID) error
+	UnwatchForum(ctx context.Context, userID, forumID uuid.UUID) (bool, error)
+	IsWatchingForum(ctx context.Context, userID, forumID uuid.UUID) (bool, error)
+	GetForumWatcherIDs(ctx context.Context, forumID uuid.UUID) ([]uuid.UUID, error)
+	FindWatchedForumIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	// Preferences
+	GetPreference(ctx context.Context, userID uuid.UUID) (*models.WatchPreference, error)
+	SavePreference(ctx context.Context, preference *models.WatchPreference) error
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AnalyticsService interface {
+	// Creator analytics (served from daily rollups)
+	GetCreatorAnalytics(ctx context.Context, userID uuid.UUID, params *dto.AnalyticsQueryParams) (*dto.CreatorAnalyticsResponse, error)
+	GetVideoAnalytics(ctx context.Context, userID, videoID uuid.UUID, params *dto.AnalyticsQueryParams) (*dto.ContentAnalyticsResponse, error)
+	GetTopicAnalytics(ctx context.Context, userID, topicID uuid.UUID, params *dto.AnalyticsQueryParams) (*dto.ContentAnalyticsResponse, error)
+
+	// Rollups
+	RollupDay(ctx context.Context, day time.Time) error
+	RollupRecent(ctx context.Context) error
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type BookmarkService interface {
+	// Bookmark operations
+	AddBookmark(ctx context.Context, userID uuid.UUID, req *dto.CreateBookmarkRequest) (*dto.BookmarkResponse, error)
+	RemoveBookmark(ctx context.Context, userID uuid.UUID, targetType string, targetID uuid.UUID) error
+	GetBookmarkStatus(ctx context.Context, userID uuid.UUID, targetType string, targetID uuid.UUID) (*dto.BookmarkStatusResponse, error)
+	MoveBookmark(ctx context.Context, userID, bookmarkID uuid.UUID, req *dto.MoveBookmarkRequest) (*dto.BookmarkResponse, error)
+
+	// GetBookmarks lists the user's saved items, leaving out deleted, hidden or no longer visible content
+	GetBookmarks(ctx context.Context, userID uuid.UUID, params *dto.BookmarkQueryParams) (*dto.BookmarkListResponse, error)
+
+	// Folder operations (owner only)
+	CreateFolder(ctx context.Context, userID uuid.UUID, req *dto.CreateBookmarkFolderRequest) (*dto.BookmarkFolderResponse, error)
+	GetFolders(ctx context.Context, userID uuid.UUID) ([]dto.BookmarkFolderResponse, error)
+	UpdateFolder(ctx context.Context, userID, folderID uuid.UUID, req *dto.UpdateBookmarkFolderRequest) (*dto.BookmarkFolderResponse, error)
+	DeleteFolder(ctx context.Context, userID, folderID uuid.UUID) error
+}
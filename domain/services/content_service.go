@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// ContentService renders the Markdown subset of topics and replies and manages link previews
+type ContentService interface {
+	// Render validates source written by authorID and returns its sanitized HTML
+	Render(ctx context.Context, authorID uuid.UUID, source string) (*dto.RenderedContent, error)
+	// RenderStored re-renders content that was already accepted; missing embeds are shown as unavailable
+	RenderStored(ctx context.Context, source string) string
+	// AttachEmbeds keeps the embedded images of a post from being removed as orphans
+	AttachEmbeds(ctx context.Context, authorID uuid.UUID, content *dto.RenderedContent, resourceType models.FileReferenceType, resourceID uuid.UUID)
+
+	// GetLinkPreviews returns cached previews by URL; missing or stale ones are fetched in the background
+	GetLinkPreviews(ctx context.Context, urls []string) map[string]dto.LinkPreviewResponse
+	PrefetchLinkPreviews(urls []string)
+}
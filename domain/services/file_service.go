@@ -15,4 +15,20 @@ type FileService interface {
 	GetUserFiles(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.File, int64, error)
 	DeleteFile(ctx context.Context, fileID uuid.UUID) error
 	ListFiles(ctx context.Context, offset, limit int) ([]*models.File, int64, error)
+
+	// Reference tracking
+	AttachFile(ctx context.Context, fileID, ownerID uuid.UUID, resourceType models.FileReferenceType, resourceID uuid.UUID) error
+	AttachFileByURL(ctx context.Context, ownerID uuid.UUID, url string, resourceType models.FileReferenceType, resourceID uuid.UUID) error
+	ReleaseFiles(ctx context.Context, resourceType models.FileReferenceType, resourceID uuid.UUID) error
+	CleanupOrphanedFiles(ctx context.Context) (int, error)
+
+	// Protected media - video files, video thumbnails and message attachments are kept in the
+	// secure zone and only handed out through signed, expiring URLs
+	ProtectFile(ctx context.Context, file *models.File) error
+	ProtectLegacyMedia(ctx context.Context) (int, error)
+	// FileURL is the URL to return for a file: signed when it is protected
+	FileURL(file *models.File) string
+
+	// Quota
+	GetStorageUsage(ctx context.Context, userID uuid.UUID) (*dto.StorageUsageResponse, error)
 }
@@ -21,4 +21,9 @@ type FollowService interface {
 
 	// Get stats
 	GetUserStats(ctx context.Context, userID uuid.UUID) (*dto.UserStatsResponse, error)
+
+	// Blocks: a block removes follows both ways and stops following and messaging either way
+	BlockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	UnblockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	GetBlockedUsers(ctx context.Context, userID uuid.UUID, page, limit int) (*dto.BlockListResponse, error)
 }
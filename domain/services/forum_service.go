@@ -18,9 +18,22 @@ type ForumService interface {
 	GetAllForums(ctx context.Context, includeInactive bool) ([]*dto.ForumResponse, error)
 	SyncTopicCount(ctx context.Context, forumID uuid.UUID) error
 	SyncAllTopicCounts(ctx context.Context) error
+	AddModerator(ctx context.Context, adminID, forumID, userID uuid.UUID) error
+	RemoveModerator(ctx context.Context, forumID, userID uuid.UUID) error
 
-	// Public Actions
-	GetActiveForums(ctx context.Context) ([]*dto.ForumResponse, error)
-	GetForumByID(ctx context.Context, forumID uuid.UUID) (*dto.ForumResponse, error)
-	GetForumBySlug(ctx context.Context, slug string) (*dto.ForumResponse, error)
+	// Public Actions (viewerID = nil สำหรับผู้ที่ไม่ได้ login)
+	GetActiveForums(ctx context.Context, viewerID *uuid.UUID) ([]*dto.ForumResponse, error)
+	GetForumByID(ctx context.Context, forumID uuid.UUID, viewerID *uuid.UUID) (*dto.ForumResponse, error)
+	GetForumBySlug(ctx context.Context, slug string, viewerID *uuid.UUID) (*dto.ForumResponse, error)
+	GetModerators(ctx context.Context, forumID uuid.UUID) ([]dto.ForumModeratorResponse, error)
+
+	// Permissions (used by TopicService and ReplyService)
+	CheckAccess(ctx context.Context, forumID uuid.UUID, userID *uuid.UUID, action models.ForumAction) error
+	CanModerate(ctx context.Context, forumID, userID uuid.UUID) (bool, error)
+	GetHiddenForumIDs(ctx context.Context, userID *uuid.UUID) ([]uuid.UUID, error)
+	// FilterViewers returns the users of userIDs that may view the forum, keeping their order
+	FilterViewers(ctx context.Context, forumID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+
+	// SyncTopicCounts recounts the topics of every forum; returns how many forums were synced
+	SyncTopicCounts(ctx context.Context) (int, error)
 }
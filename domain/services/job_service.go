@@ -2,12 +2,17 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
 
 	"github.com/google/uuid"
 )
 
+// JobHandler performs one type of job. payload is the job's JSON payload ("{}" when it has
+// none); the returned summary is kept in the run history.
+type JobHandler func(ctx context.Context, payload json.RawMessage) (string, error)
+
 type JobService interface {
 	CreateJob(ctx context.Context, req *dto.CreateJobRequest) (*models.Job, error)
 	GetJob(ctx context.Context, jobID uuid.UUID) (*models.Job, error)
@@ -17,4 +22,21 @@ type JobService interface {
 	StartJob(ctx context.Context, jobID uuid.UUID) error
 	StopJob(ctx context.Context, jobID uuid.UUID) error
 	ExecuteJob(ctx context.Context, job *models.Job) error
+
+	// Run controls - TriggerJob starts a run on this replica right away, within the job's
+	// concurrency limit; a paused job stays scheduled but skips its firings until resumed
+	TriggerJob(ctx context.Context, jobID uuid.UUID) error
+	PauseJob(ctx context.Context, jobID uuid.UUID) (*models.Job, error)
+	ResumeJob(ctx context.Context, jobID uuid.UUID) (*models.Job, error)
+	PreviewCron(ctx context.Context, req *dto.CronPreviewRequest) (*dto.CronPreviewResponse, error)
+
+	// Replicas - SyncScheduledJobs makes this replica's schedule match the active jobs in the
+	// database (at startup and periodically); only the leader runs them
+	SyncScheduledJobs(ctx context.Context) (added, removed int, err error)
+	GetSchedulerStatus(ctx context.Context) *dto.SchedulerStatusResponse
+
+	// Handlers - ExecuteJob dispatches on Job.Type; jobs can only be created for registered types
+	RegisterHandler(jobType string, handler JobHandler)
+	GetJobTypes() []string
+	GetJobRuns(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, int64, error)
 }
@@ -27,4 +27,8 @@ type LikeService interface {
 	LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*dto.LikeStatusResponse, error)
 	UnlikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*dto.LikeStatusResponse, error)
 	GetCommentLikeStatus(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*dto.LikeStatusResponse, error)
+
+	// RecomputeLikeCounts repairs the stored like counts of the given targets (topic, reply,
+	// video) and returns how many rows were corrected per target
+	RecomputeLikeCounts(ctx context.Context, targetTypes []string) (map[string]int64, error)
 }
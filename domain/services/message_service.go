@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type MessageService interface {
+	// Conversations
+	CreateConversation(ctx context.Context, userID uuid.UUID, req *dto.CreateConversationRequest) (*dto.ConversationResponse, error)
+	GetConversations(ctx context.Context, userID uuid.UUID, params *dto.ConversationListParams) (*dto.ConversationListResponse, error)
+	GetConversation(ctx context.Context, userID, conversationID uuid.UUID) (*dto.ConversationResponse, error)
+	GetUnreadCount(ctx context.Context, userID uuid.UUID) (*dto.UnreadMessageCountResponse, error)
+
+	// Message requests
+	AcceptRequest(ctx context.Context, userID, conversationID uuid.UUID) (*dto.ConversationResponse, error)
+	DeclineRequest(ctx context.Context, userID, conversationID uuid.UUID) error
+
+	// Group participants
+	AddParticipants(ctx context.Context, userID, conversationID uuid.UUID, req *dto.AddParticipantsRequest) (*dto.ConversationResponse, error)
+	RemoveParticipant(ctx context.Context, userID, conversationID, participantID uuid.UUID) error
+
+	// Messages
+	GetMessages(ctx context.Context, userID, conversationID uuid.UUID, params *dto.MessageListParams) (*dto.MessageListResponse, error)
+	SendMessage(ctx context.Context, userID, conversationID uuid.UUID, req *dto.SendMessageRequest) (*dto.MessageResponse, error)
+	UpdateMessage(ctx context.Context, userID, messageID uuid.UUID, req *dto.UpdateMessageRequest) (*dto.MessageResponse, error)
+	DeleteMessage(ctx context.Context, userID, messageID uuid.UUID) error
+	MarkRead(ctx context.Context, userID, conversationID uuid.UUID, req *dto.MarkConversationReadRequest) error
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
 
 	"github.com/google/uuid"
 )
@@ -18,6 +20,16 @@ type NotificationService interface {
 	CreateReplyLikeNotification(ctx context.Context, replyID, likerUserID uuid.UUID) error
 	CreateCommentLikeNotification(ctx context.Context, commentID, likerUserID uuid.UUID) error
 	CreateNewFollowerNotification(ctx context.Context, followedUserID, followerUserID uuid.UUID) error
+	CreateAnswerAcceptedNotification(ctx context.Context, replyID, accepterUserID uuid.UUID) error
+
+	// Watchers - recipients are resolved by WatchService; unread reply notifications for the
+	// same topic are folded into one instead of stacking up
+	CreateWatchedTopicReplyNotifications(ctx context.Context, topicID, replyUserID uuid.UUID, watcherIDs []uuid.UUID) error
+	CreateWatchedForumTopicNotifications(ctx context.Context, topicID uuid.UUID, watcherIDs []uuid.UUID) error
+
+	// Moderation - tells authors their topic was moved or merged, or their replies split off;
+	// topicID is where the content ended up
+	CreateTopicModerationNotifications(ctx context.Context, notifType models.NotificationType, topicID, moderatorID uuid.UUID, authorIDs []uuid.UUID, reason string) error
 
 	// Read notifications
 	GetNotifications(ctx context.Context, userID uuid.UUID, params *dto.NotificationQueryParams) (*dto.NotificationListResponse, error)
@@ -30,4 +42,10 @@ type NotificationService interface {
 
 	// Delete notifications
 	DeleteNotification(ctx context.Context, userID, notificationID uuid.UUID) error
+
+	// Maintenance (scheduled jobs)
+	PurgeNotifications(ctx context.Context, before time.Time, readOnly bool) (int64, error)
+	// SendDigests reminds users with unread notifications from since onwards; users who already
+	// got a digest in that window are skipped. Returns how many digests were sent.
+	SendDigests(ctx context.Context, since time.Time, minUnread int) (int, error)
 }
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type PlaylistService interface {
+	// Playlist operations
+	CreatePlaylist(ctx context.Context, userID uuid.UUID, req *dto.CreatePlaylistRequest) (*dto.PlaylistResponse, error)
+	GetPlaylist(ctx context.Context, playlistID uuid.UUID, viewerID *uuid.UUID, page, limit int) (*dto.PlaylistDetailResponse, error)
+	GetUserPlaylists(ctx context.Context, userID uuid.UUID, viewerID *uuid.UUID, page, limit int) (*dto.PlaylistListResponse, error)
+	UpdatePlaylist(ctx context.Context, userID, playlistID uuid.UUID, req *dto.UpdatePlaylistRequest) (*dto.PlaylistResponse, error)
+	DeletePlaylist(ctx context.Context, userID, playlistID uuid.UUID) error
+
+	// Item operations (owner only)
+	AddVideo(ctx context.Context, userID, playlistID, videoID uuid.UUID) error
+	RemoveVideo(ctx context.Context, userID, playlistID, videoID uuid.UUID) error
+	ReorderVideos(ctx context.Context, userID, playlistID uuid.UUID, req *dto.ReorderPlaylistItemsRequest) error
+
+	// GetPlaylistsForVideo lists the user's playlists with whether each one contains the video
+	GetPlaylistsForVideo(ctx context.Context, userID, videoID uuid.UUID) ([]dto.VideoPlaylistStatus, error)
+}
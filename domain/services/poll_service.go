@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type PollService interface {
+	// Polls are created with their topic (CreateTopicRequest.Poll)
+	GetPoll(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID) (*dto.PollResponse, error)
+
+	// Voting - one ballot per user; a second vote replaces the first only when the poll allows it
+	Vote(ctx context.Context, topicID, userID uuid.UUID, req *dto.VotePollRequest) (*dto.PollResponse, error)
+	RetractVote(ctx context.Context, topicID, userID uuid.UUID) (*dto.PollResponse, error)
+
+	// GetVoters lists who picked an option; not available on anonymous polls
+	GetVoters(ctx context.Context, topicID, optionID uuid.UUID, viewerID *uuid.UUID, page, limit int) (*dto.PollVoterListResponse, error)
+
+	// Closing - by the topic author or a moderator, or by the scheduler once closesAt has passed
+	ClosePoll(ctx context.Context, topicID, userID uuid.UUID) (*dto.PollResponse, error)
+	CloseDuePolls(ctx context.Context) (int, error)
+}
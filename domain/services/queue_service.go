@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackgroundTask is a typed task payload (see the Task* types in dto); it is stored as JSON
+type BackgroundTask interface {
+	TaskType() string
+}
+
+// TaskHandler runs one task. Returning an error retries the task with exponential backoff
+// until it runs out of attempts; handlers must therefore be safe to run more than once.
+type TaskHandler func(ctx context.Context, payload json.RawMessage) error
+
+type QueueService interface {
+	// Enqueue stores the task for the worker pool. A non-empty idempotencyKey makes repeated
+	// enqueues of the same event a no-op.
+	Enqueue(ctx context.Context, task BackgroundTask, idempotencyKey string) error
+	// NewTask builds the record of a task for a repository to insert in the same transaction
+	// as the change it belongs to; workers pick it up within the poll interval
+	NewTask(task BackgroundTask, idempotencyKey string) (*models.BackgroundTask, error)
+	RegisterHandler(taskType string, handler TaskHandler)
+
+	// Worker pool
+	Start()
+	Stop(ctx context.Context) error
+
+	// Admin
+	ListTasks(ctx context.Context, params *dto.BackgroundTaskListParams) (*dto.BackgroundTaskListResponse, error)
+	GetTask(ctx context.Context, taskID uuid.UUID) (*dto.BackgroundTaskResponse, error)
+	GetStats(ctx context.Context) (*dto.BackgroundTaskStatsResponse, error)
+	ReplayTasks(ctx context.Context, req *dto.ReplayTasksRequest) (*dto.ReplayTasksResponse, error)
+	PurgeCompleted(ctx context.Context, before time.Time) (int64, error)
+}
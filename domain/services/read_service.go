@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type ReadService interface {
+	MarkTopicRead(ctx context.Context, userID, topicID uuid.UUID, req *dto.MarkTopicReadRequest) error
+	MarkForumRead(ctx context.Context, userID, forumID uuid.UUID) error
+	GetFirstUnread(ctx context.Context, userID, topicID uuid.UUID) (*dto.FirstUnreadResponse, error)
+
+	// FillTopicReadState sets the read fields of topic responses (used by TopicService; no-op for guests)
+	FillTopicReadState(ctx context.Context, viewerID *uuid.UUID, topics []*dto.TopicResponse)
+}
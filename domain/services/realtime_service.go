@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RealtimeService decides who may listen to which WebSocket room
+type RealtimeService interface {
+	// AuthorizeRoom checks a join of a canonical room name; userID is nil for guests
+	AuthorizeRoom(ctx context.Context, userID *uuid.UUID, role string, roomID string) error
+}
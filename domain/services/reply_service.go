@@ -9,8 +9,11 @@ import (
 
 type ReplyService interface {
 	CreateReply(ctx context.Context, topicID, userID uuid.UUID, req *dto.CreateReplyRequest) (*models.Reply, error)
-	GetReplies(ctx context.Context, topicID uuid.UUID, offset, limit int) ([]*dto.ReplyResponse, int64, error)
+	GetReplies(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID, offset, limit int) ([]*dto.ReplyResponse, int64, error)
+	GetThread(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID, params *dto.ReplyThreadParams) (*dto.ReplyThreadResponse, error)
+	GetChildReplies(ctx context.Context, replyID uuid.UUID, viewerID *uuid.UUID, params *dto.ReplyThreadParams) (*dto.ReplyThreadResponse, error)
+	GetReplyContext(ctx context.Context, replyID uuid.UUID, viewerID *uuid.UUID, params *dto.ReplyThreadParams) (*dto.ReplyContextResponse, error)
 	UpdateReply(ctx context.Context, replyID, userID uuid.UUID, req *dto.UpdateReplyRequest) (*models.Reply, error)
-	DeleteReply(ctx context.Context, replyID, userID uuid.UUID) error
+	DeleteReply(ctx context.Context, replyID, userID uuid.UUID) error // owner or forum moderator
 	DeleteReplyByAdmin(ctx context.Context, replyID uuid.UUID) error
 }
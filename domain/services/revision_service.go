@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type RevisionService interface {
+	// GetRevisions is available to the author and admins only
+	GetRevisions(ctx context.Context, contentType models.RevisionContentType, contentID, requesterID uuid.UUID, isAdmin bool, page, limit int) (*dto.RevisionListResponse, error)
+
+	// RollbackRevision restores an older version as a new revision (admin)
+	RollbackRevision(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID, version int, adminID uuid.UUID) (*dto.RevisionResponse, error)
+}
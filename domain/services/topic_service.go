@@ -8,22 +8,33 @@ import (
 )
 
 type TopicService interface {
-	// User Actions
+	// User Actions (viewerID = nil สำหรับผู้ที่ไม่ได้ login; ใช้ตรวจสิทธิ์ดู forum)
 	CreateTopic(ctx context.Context, userID uuid.UUID, req *dto.CreateTopicRequest) (*models.Topic, error)
-	GetTopic(ctx context.Context, topicID uuid.UUID) (*dto.TopicDetailResponse, error)
-	GetTopics(ctx context.Context, offset, limit int) ([]*dto.TopicResponse, int64, error)
-	GetTopicsByForum(ctx context.Context, forumID uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error)
-	GetTopicsByForumSlug(ctx context.Context, slug string, offset, limit int) ([]*dto.TopicResponse, int64, error)
-	GetTopicsByTag(ctx context.Context, tag string, offset, limit int) ([]*dto.TopicResponse, int64, error)
-	GetTopicsByTags(ctx context.Context, tags []string, offset, limit int) ([]*dto.TopicResponse, int64, error)
+	GetTopic(ctx context.Context, topicID uuid.UUID, viewerID *uuid.UUID) (*dto.TopicDetailResponse, error)
+	GetTopics(ctx context.Context, viewerID *uuid.UUID, filter *dto.TopicFilterParams, offset, limit int) ([]*dto.TopicResponse, int64, error)
+	GetTopicsByForum(ctx context.Context, forumID uuid.UUID, viewerID *uuid.UUID, filter *dto.TopicFilterParams, offset, limit int) ([]*dto.TopicResponse, int64, error)
+	GetTopicsByForumSlug(ctx context.Context, slug string, viewerID *uuid.UUID, filter *dto.TopicFilterParams, offset, limit int) ([]*dto.TopicResponse, int64, error)
+	GetTopicsByTag(ctx context.Context, tag string, viewerID *uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error)
+	GetTopicsByTags(ctx context.Context, tags []string, viewerID *uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error)
 	UpdateTopic(ctx context.Context, topicID, userID uuid.UUID, req *dto.UpdateTopicRequest) (*models.Topic, error)
-	DeleteTopic(ctx context.Context, topicID, userID uuid.UUID) error
-	SearchTopics(ctx context.Context, query string, offset, limit int) ([]*dto.TopicResponse, int64, error)
+	DeleteTopic(ctx context.Context, topicID, userID uuid.UUID) error // owner or forum moderator
+	SearchTopics(ctx context.Context, query string, viewerID *uuid.UUID, offset, limit int) ([]*dto.TopicResponse, int64, error)
+
+	// Q&A forums: the topic author or a forum moderator picks one reply as the accepted answer
+	AcceptAnswer(ctx context.Context, topicID, userID uuid.UUID, req *dto.AcceptAnswerRequest) (*dto.TopicResponse, error)
+	UnacceptAnswer(ctx context.Context, topicID, userID uuid.UUID) (*dto.TopicResponse, error)
+
+	// Moderator Actions (admins or moderators of the topic's forum)
+	PinTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error
+	UnpinTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error
+	LockTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error
+	UnlockTopic(ctx context.Context, topicID, moderatorID uuid.UUID) error
+
+	// Moving content into another forum also requires moderating that forum
+	MoveTopic(ctx context.Context, topicID, moderatorID uuid.UUID, req *dto.MoveTopicRequest) (*dto.TopicResponse, error)
+	MergeTopic(ctx context.Context, topicID, moderatorID uuid.UUID, req *dto.MergeTopicRequest) (*dto.TopicResponse, error) // returns the target topic
+	SplitTopic(ctx context.Context, topicID, moderatorID uuid.UUID, req *dto.SplitTopicRequest) (*dto.TopicResponse, error)  // returns the new topic
 
 	// Admin Actions
-	PinTopic(ctx context.Context, topicID uuid.UUID) error
-	UnpinTopic(ctx context.Context, topicID uuid.UUID) error
-	LockTopic(ctx context.Context, topicID uuid.UUID) error
-	UnlockTopic(ctx context.Context, topicID uuid.UUID) error
 	DeleteTopicByAdmin(ctx context.Context, topicID uuid.UUID) error
 }
@@ -10,9 +10,11 @@ import (
 type VideoService interface {
 	// User operations
 	CreateVideo(ctx context.Context, userID uuid.UUID, req *dto.UploadVideoRequest) (*dto.VideoResponse, error)
-	GetVideoByID(ctx context.Context, id uuid.UUID) (*dto.VideoResponse, error)
-	GetVideos(ctx context.Context, params *dto.VideoQueryParams) (*dto.VideoListResponse, error)
-	GetUserVideos(ctx context.Context, userID uuid.UUID, params *dto.VideoQueryParams) (*dto.VideoListResponse, error)
+	GetVideoByID(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID) (*dto.VideoResponse, error)
+	GetVideos(ctx context.Context, params *dto.VideoQueryParams, viewerID *uuid.UUID) (*dto.VideoListResponse, error)
+	GetUserVideos(ctx context.Context, userID uuid.UUID, params *dto.VideoQueryParams, viewerID *uuid.UUID) (*dto.VideoListResponse, error)
+	GetVideosByIDs(ctx context.Context, ids []uuid.UUID, viewerID *uuid.UUID) (map[uuid.UUID]dto.VideoResponse, error)
+	GetVideoMedia(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID, isAdmin bool) (*dto.VideoMediaResponse, error)
 	UpdateVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID, req *dto.UpdateVideoRequest) (*dto.VideoResponse, error)
 	DeleteVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID) error
 
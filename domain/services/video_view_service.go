@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+type VideoViewService interface {
+	// TrackEvent ingests a playback event (start, heartbeat, complete) from a viewer; anonymous
+	// viewers are identified by clientIP and userAgent
+	TrackEvent(ctx context.Context, videoID uuid.UUID, viewerID *uuid.UUID, clientIP, userAgent string, req *dto.VideoViewEventRequest) (*dto.VideoViewEventResponse, error)
+
+	// FlushViewCounts writes the view counts buffered in Redis to the videos table
+	FlushViewCounts(ctx context.Context) (int, error)
+}
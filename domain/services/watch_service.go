@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type WatchService interface {
+	// Topic watches
+	WatchTopic(ctx context.Context, userID, topicID uuid.UUID) (*dto.WatchStatusResponse, error)
+	UnwatchTopic(ctx context.Context, userID, topicID uuid.UUID) (*dto.WatchStatusResponse, error)
+	GetTopicWatchStatus(ctx context.Context, userID, topicID uuid.UUID) (*dto.WatchStatusResponse, error)
+	GetWatchedTopics(ctx context.Context, userID uuid.UUID, params *dto.WatchedTopicQueryParams) (*dto.WatchedTopicListResponse, error)
+
+	// Forum watches (new topics)
+	WatchForum(ctx context.Context, userID, forumID uuid.UUID) (*dto.WatchStatusResponse, error)
+	UnwatchForum(ctx context.Context, userID, forumID uuid.UUID) (*dto.WatchStatusResponse, error)
+	GetForumWatchStatus(ctx context.Context, userID, forumID uuid.UUID) (*dto.WatchStatusResponse, error)
+	GetWatchedForums(ctx context.Context, userID uuid.UUID) ([]*dto.ForumResponse, error)
+
+	// Auto-watch preferences
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*dto.WatchPreferenceResponse, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req *dto.UpdateWatchPreferenceRequest) (*dto.WatchPreferenceResponse, error)
+
+	// Hooks (run by the watch_topic and watch_reply tasks after the content is saved)
+
+	// HandleNewTopic auto-watches for the author and returns the forum watchers to notify, in batches
+	HandleNewTopic(ctx context.Context, topic *models.Topic) ([][]uuid.UUID, error)
+	// HandleNewReply auto-watches for the author and returns the watchers to notify, in batches
+	// the caller notifies one at a time
+	HandleNewReply(ctx context.Context, reply *models.Reply, topic *models.Topic) ([][]uuid.UUID, error)
+}
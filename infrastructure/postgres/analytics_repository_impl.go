@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type analyticsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) repositories.AnalyticsRepository {
+	return &analyticsRepositoryImpl{db: db}
+}
+
+// Video engagement of one day: views from video_views, likes, comments and shares created that day
+const rollupVideoStatsSQL = `
+INSERT INTO content_daily_stats (date, content_type, content_id, owner_id, views, watch_time, completed_views, likes, comments, shares, updated_at)
+SELECT @day, 'video', v.id, v.user_id,
+	COALESCE(vv.views, 0), COALESCE(vv.watch_time, 0), COALESCE(vv.completed, 0),
+	COALESCE(l.likes, 0), COALESCE(c.comments, 0), COALESCE(s.shares, 0), NOW()
+FROM videos v
+LEFT JOIN (
+	SELECT video_id, COUNT(*) AS views, SUM(watch_time) AS watch_time, COUNT(*) FILTER (WHERE completed) AS completed
+	FROM video_views WHERE created_at >= @start AND created_at < @end GROUP BY video_id
+) vv ON vv.video_id = v.id
+LEFT JOIN (
+	SELECT video_id, COUNT(*) AS likes
+	FROM likes WHERE video_id IS NOT NULL AND created_at >= @start AND created_at < @end GROUP BY video_id
+) l ON l.video_id = v.id
+LEFT JOIN (
+	SELECT video_id, COUNT(*) AS comments
+	FROM comments WHERE deleted_at IS NULL AND created_at >= @start AND created_at < @end GROUP BY video_id
+) c ON c.video_id = v.id
+LEFT JOIN (
+	SELECT video_id, COUNT(*) AS shares
+	FROM shares WHERE created_at >= @start AND created_at < @end GROUP BY video_id
+) s ON s.video_id = v.id
+WHERE v.deleted_at IS NULL
+	AND (vv.video_id IS NOT NULL OR l.video_id IS NOT NULL OR c.video_id IS NOT NULL OR s.video_id IS NOT NULL)
+ON CONFLICT (date, content_type, content_id) DO UPDATE SET
+	views = EXCLUDED.views, watch_time = EXCLUDED.watch_time, completed_views = EXCLUDED.completed_views,
+	likes = EXCLUDED.likes, comments = EXCLUDED.comments, shares = EXCLUDED.shares, updated_at = NOW()`
+
+// Topic engagement of one day. Topics have no view events, so daily views are the growth of
+// topics.view_count since the previous snapshot; past days keep the views they were rolled up with.
+const rollupTopicStatsSQL = `
+INSERT INTO content_daily_stats (date, content_type, content_id, owner_id, views, view_total, likes, comments, updated_at)
+SELECT @day, 'topic', t.id, t.user_id,
+	CASE WHEN @snapshot THEN GREATEST(t.view_count - COALESCE(p.view_total, 0), 0) ELSE 0 END,
+	CASE WHEN @snapshot THEN t.view_count ELSE NULL END,
+	COALESCE(l.likes, 0), COALESCE(r.replies, 0), NOW()
+FROM topics t
+LEFT JOIN LATERAL (
+	SELECT view_total FROM content_daily_stats
+	WHERE content_type = 'topic' AND content_id = t.id AND date < @day AND view_total IS NOT NULL
+	ORDER BY date DESC LIMIT 1
+) p ON TRUE
+LEFT JOIN (
+	SELECT topic_id, COUNT(*) AS likes
+	FROM likes WHERE topic_id IS NOT NULL AND created_at >= @start AND created_at < @end GROUP BY topic_id
+) l ON l.topic_id = t.id
+LEFT JOIN (
+	SELECT topic_id, COUNT(*) AS replies
+	FROM replies WHERE deleted_at IS NULL AND created_at >= @start AND created_at < @end GROUP BY topic_id
+) r ON r.topic_id = t.id
+WHERE t.deleted_at IS NULL
+	AND (l.topic_id IS NOT NULL OR r.topic_id IS NOT NULL OR (@snapshot AND t.view_count <> COALESCE(p.view_total, 0)))
+ON CONFLICT (date, content_type, content_id) DO UPDATE SET
+	views = CASE WHEN @snapshot THEN EXCLUDED.views ELSE content_daily_stats.views END,
+	view_total = CASE WHEN @snapshot THEN EXCLUDED.view_total ELSE content_daily_stats.view_total END,
+	likes = EXCLUDED.likes, comments = EXCLUDED.comments, updated_at = NOW()`
+
+// The first topic snapshot ever taken starts from a baseline dated the day before: without it
+// every existing topic would report its lifetime views as views of that one day. Topics created
+// later start from zero, which needs no row.
+const seedTopicViewBaselineSQL = `
+INSERT INTO content_daily_stats (date, content_type, content_id, owner_id, views, view_total, likes, comments, updated_at)
+SELECT CAST(@day AS date) - 1, 'topic', t.id, t.user_id, 0, t.view_count, 0, 0, NOW()
+FROM topics t
+WHERE t.deleted_at IS NULL AND t.created_at < @start AND t.view_count > 0
+	AND NOT EXISTS (SELECT 1 FROM content_daily_stats s WHERE s.content_type = 'topic' AND s.view_total IS NOT NULL)
+ON CONFLICT (date, content_type, content_id) DO UPDATE SET view_total = EXCLUDED.view_total`
+
+const rollupShareStatsSQL = `
+INSERT INTO share_daily_stats (date, video_id, platform, owner_id, shares)
+SELECT @day, s.video_id, COALESCE(NULLIF(s.platform, ''), 'other'), v.user_id, COUNT(*)
+FROM shares s
+JOIN videos v ON v.id = s.video_id
+WHERE s.created_at >= @start AND s.created_at < @end
+GROUP BY s.video_id, COALESCE(NULLIF(s.platform, ''), 'other'), v.user_id
+ON CONFLICT (date, video_id, platform) DO UPDATE SET shares = EXCLUDED.shares`
+
+// Unfollows are hard deletes, so the total is the number of follows still present that existed by the end of the day.
+// A day gets a row when someone followed the user or the total moved since their previous row, which covers
+// days with only unfollows, down to users who lost their last follower.
+const rollupFollowerStatsSQL = `
+INSERT INTO follower_daily_stats (date, user_id, new_followers, total_followers)
+SELECT @day, u.user_id, COALESCE(f.new_followers, 0), COALESCE(f.total_followers, 0)
+FROM (
+	SELECT following_id AS user_id FROM follows WHERE created_at < @end
+	UNION
+	SELECT user_id FROM follower_daily_stats WHERE date <= @day
+) u
+LEFT JOIN (
+	SELECT following_id, COUNT(*) FILTER (WHERE created_at >= @start) AS new_followers, COUNT(*) AS total_followers
+	FROM follows WHERE created_at < @end GROUP BY following_id
+) f ON f.following_id = u.user_id
+LEFT JOIN LATERAL (
+	SELECT total_followers FROM follower_daily_stats
+	WHERE user_id = u.user_id AND date < @day
+	ORDER BY date DESC LIMIT 1
+) p ON TRUE
+WHERE COALESCE(f.new_followers, 0) > 0
+	OR COALESCE(f.total_followers, 0) <> COALESCE(p.total_followers, 0)
+	OR EXISTS (SELECT 1 FROM follower_daily_stats fs WHERE fs.user_id = u.user_id AND fs.date = @day)
+ON CONFLICT (date, user_id) DO UPDATE SET
+	new_followers = EXCLUDED.new_followers, total_followers = EXCLUDED.total_followers`
+
+func (r *analyticsRepositoryImpl) RollupDay(ctx context.Context, day, start, end time.Time, snapshotTopicViews bool) error {
+	args := map[string]interface{}{
+		"day":      day.Format("2006-01-02"),
+		"start":    start,
+		"end":      end,
+		"snapshot": snapshotTopicViews,
+	}
+
+	queries := []string{rollupVideoStatsSQL, rollupTopicStatsSQL, rollupShareStatsSQL, rollupFollowerStatsSQL}
+	if snapshotTopicViews {
+		queries = append([]string{seedTopicViewBaselineSQL}, queries...)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, query := range queries {
+			if err := tx.Exec(query, args).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *analyticsRepositoryImpl) GetContentSeries(ctx context.Context, filter repositories.ContentStatFilter) ([]models.ContentDailyStat, error) {
+	var stats []models.ContentDailyStat
+	err := r.contentQuery(ctx, filter).
+		Select("date, SUM(views) AS views, SUM(watch_time) AS watch_time, SUM(completed_views) AS completed_views, " +
+			"SUM(likes) AS likes, SUM(comments) AS comments, SUM(shares) AS shares").
+		Group("date").
+		Order("date ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+func (r *analyticsRepositoryImpl) GetTopContent(ctx context.Context, filter repositories.ContentStatFilter, limit int) ([]models.ContentDailyStat, error) {
+	var stats []models.ContentDailyStat
+	err := r.contentQuery(ctx, filter).
+		Select("content_type, content_id, SUM(views) AS views, SUM(watch_time) AS watch_time, SUM(completed_views) AS completed_views, " +
+			"SUM(likes) AS likes, SUM(comments) AS comments, SUM(shares) AS shares").
+		Group("content_type, content_id").
+		Order("views DESC, likes DESC").
+		Limit(limit).
+		Find(&stats).Error
+	return stats, err
+}
+
+func (r *analyticsRepositoryImpl) GetShareSeries(ctx context.Context, filter repositories.ContentStatFilter) ([]models.ShareDailyStat, error) {
+	var stats []models.ShareDailyStat
+	query := r.db.WithContext(ctx).
+		Model(&models.ShareDailyStat{}).
+		Where("owner_id = ? AND date BETWEEN ? AND ?", filter.OwnerID, filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"))
+	if filter.ContentID != nil {
+		query = query.Where("video_id = ?", *filter.ContentID)
+	}
+
+	err := query.
+		Select("date, platform, SUM(shares) AS shares").
+		Group("date, platform").
+		Order("date ASC, platform ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+func (r *analyticsRepositoryImpl) GetFollowerSeries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.FollowerDailyStat, error) {
+	var stats []models.FollowerDailyStat
+	fromDate, toDate := from.Format("2006-01-02"), to.Format("2006-01-02")
+
+	// The last row before the range carries the starting total
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND date <= ?", userID, toDate).
+		Where("date >= ? OR date = (SELECT MAX(date) FROM follower_daily_stats WHERE user_id = ? AND date < ?)", fromDate, userID, fromDate).
+		Order("date ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+func (r *analyticsRepositoryImpl) contentQuery(ctx context.Context, filter repositories.ContentStatFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Model(&models.ContentDailyStat{}).
+		Where("owner_id = ? AND date BETWEEN ? AND ?", filter.OwnerID, filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"))
+	if filter.ContentType != "" {
+		query = query.Where("content_type = ?", filter.ContentType)
+	}
+	if filter.ContentID != nil {
+		query = query.Where("content_id = ?", *filter.ContentID)
+	}
+	return query
+}
@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type backgroundTaskRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBackgroundTaskRepository(db *gorm.DB) repositories.BackgroundTaskRepository {
+	return &backgroundTaskRepositoryImpl{db: db}
+}
+
+func (r *backgroundTaskRepositoryImpl) Enqueue(ctx context.Context, task *models.BackgroundTask) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).
+		Create(task)
+	return result.RowsAffected > 0, result.Error
+}
+
+// insertTasks queues the side effects of a change inside its transaction, so they are queued
+// exactly when the change commits
+func insertTasks(tx *gorm.DB, tasks []*models.BackgroundTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).
+		Create(tasks).Error
+}
+
+// createWithTasks inserts a record together with the tasks of its side effects
+func createWithTasks(db *gorm.DB, record interface{}, tasks []*models.BackgroundTask) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		return insertTasks(tx, tasks)
+	})
+}
+
+// expireStaleTasksSQL moves tasks whose worker died during their last attempt to the dead letters
+const expireStaleTasksSQL = `
+UPDATE background_tasks SET
+	status = 'dead',
+	locked_at = NULL,
+	locked_by = '',
+	last_error = 'worker stopped responding during the last attempt',
+	updated_at = @now
+WHERE status = 'processing' AND locked_at < @stale AND attempts >= max_attempts`
+
+const claimDueTasksSQL = `
+UPDATE background_tasks SET
+	status = 'processing',
+	attempts = attempts + 1,
+	locked_at = @now,
+	locked_by = @worker,
+	updated_at = @now
+WHERE id IN (
+	SELECT id FROM background_tasks
+	WHERE (status = 'pending' AND run_at <= @now)
+	   OR (status = 'processing' AND locked_at < @stale AND attempts < max_attempts)
+	ORDER BY run_at
+	LIMIT @limit
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`
+
+func (r *backgroundTaskRepositoryImpl) ClaimDue(ctx context.Context, workerID string, limit int, staleBefore time.Time) ([]*models.BackgroundTask, error) {
+	args := map[string]interface{}{
+		"now":    time.Now(),
+		"worker": workerID,
+		"stale":  staleBefore,
+		"limit":  limit,
+	}
+
+	if err := r.db.WithContext(ctx).Exec(expireStaleTasksSQL, args).Error; err != nil {
+		return nil, err
+	}
+
+	var tasks []*models.BackgroundTask
+	err := r.db.WithContext(ctx).Raw(claimDueTasksSQL, args).Scan(&tasks).Error
+	return tasks, err
+}
+
+// finish records the outcome of a claim; the attempt count tells two claims by the same worker apart
+func (r *backgroundTaskRepositoryImpl) finish(ctx context.Context, task *models.BackgroundTask, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.BackgroundTask{}).
+		Where("id = ? AND status = ? AND locked_by = ? AND attempts = ?", task.ID, models.BackgroundTaskProcessing, task.LockedBy, task.Attempts).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrTaskNotOwned
+	}
+	return nil
+}
+
+func (r *backgroundTaskRepositoryImpl) Complete(ctx context.Context, task *models.BackgroundTask, completedAt time.Time) error {
+	return r.finish(ctx, task, map[string]interface{}{
+		"status":       models.BackgroundTaskCompleted,
+		"completed_at": completedAt,
+		"locked_at":    nil,
+		"locked_by":    "",
+		"last_error":   "",
+	})
+}
+
+func (r *backgroundTaskRepositoryImpl) Retry(ctx context.Context, task *models.BackgroundTask, runAt time.Time, lastError string) error {
+	return r.finish(ctx, task, map[string]interface{}{
+		"status":     models.BackgroundTaskPending,
+		"run_at":     runAt,
+		"locked_at":  nil,
+		"locked_by":  "",
+		"last_error": lastError,
+	})
+}
+
+func (r *backgroundTaskRepositoryImpl) MarkDead(ctx context.Context, task *models.BackgroundTask, lastError string) error {
+	return r.finish(ctx, task, map[string]interface{}{
+		"status":     models.BackgroundTaskDead,
+		"locked_at":  nil,
+		"locked_by":  "",
+		"last_error": lastError,
+	})
+}
+
+func (r *backgroundTaskRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.BackgroundTask, error) {
+	var task models.BackgroundTask
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *backgroundTaskRepositoryImpl) List(ctx context.Context, filter repositories.BackgroundTaskFilter, offset, limit int) ([]*models.BackgroundTask, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.BackgroundTask{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []*models.BackgroundTask
+	err := query.Order("updated_at DESC").Offset(offset).Limit(limit).Find(&tasks).Error
+	return tasks, total, err
+}
+
+func (r *backgroundTaskRepositoryImpl) CountByStatus(ctx context.Context) (map[models.BackgroundTaskStatus]int64, error) {
+	var rows []struct {
+		Status models.BackgroundTaskStatus
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.BackgroundTask{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.BackgroundTaskStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// replayUpdates gives a dead task a fresh set of attempts, due now
+func replayUpdates() map[string]interface{} {
+	return map[string]interface{}{
+		"status":   models.BackgroundTaskPending,
+		"attempts": 0,
+		"run_at":   time.Now(),
+	}
+}
+
+func (r *backgroundTaskRepositoryImpl) Replay(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Model(&models.BackgroundTask{}).
+		Where("id IN ? AND status = ?", ids, models.BackgroundTaskDead).
+		Updates(replayUpdates())
+	return result.RowsAffected, result.Error
+}
+
+func (r *backgroundTaskRepositoryImpl) ReplayDead(ctx context.Context, taskType string) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.BackgroundTask{}).
+		Where("status = ?", models.BackgroundTaskDead)
+	if taskType != "" {
+		query = query.Where("type = ?", taskType)
+	}
+	result := query.Updates(replayUpdates())
+	return result.RowsAffected, result.Error
+}
+
+func (r *backgroundTaskRepositoryImpl) DeleteCompletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND completed_at < ?", models.BackgroundTaskCompleted, before).
+		Delete(&models.BackgroundTask{})
+	return result.RowsAffected, result.Error
+}
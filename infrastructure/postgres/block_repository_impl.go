@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type blockRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBlockRepository(db *gorm.DB) repositories.BlockRepository {
+	return &blockRepositoryImpl{db: db}
+}
+
+func (r *blockRepositoryImpl) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.Block{BlockerID: blockerID, BlockedID: blockedID}).Error
+}
+
+func (r *blockRepositoryImpl) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&models.Block{}).Error
+}
+
+func (r *blockRepositoryImpl) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Block{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *blockRepositoryImpl) IsBlockedEither(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	blocked, err := r.FindBlockedAmong(ctx, userID, []uuid.UUID{otherID})
+	return len(blocked) > 0, err
+}
+
+func (r *blockRepositoryImpl) FindBlockedAmong(ctx context.Context, userID uuid.UUID, otherIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var blocked []uuid.UUID
+	if len(otherIDs) == 0 {
+		return blocked, nil
+	}
+
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT blocked_id FROM blocks WHERE blocker_id = ? AND blocked_id IN ?
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id = ? AND blocker_id IN ?`,
+		userID, otherIDs, userID, otherIDs).
+		Scan(&blocked).Error
+	return blocked, err
+}
+
+func (r *blockRepositoryImpl) HasBlockAcross(ctx context.Context, userIDs, otherIDs []uuid.UUID) (bool, error) {
+	if len(userIDs) == 0 || len(otherIDs) == 0 {
+		return false, nil
+	}
+
+	var blocked bool
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id IN ? AND blocked_id IN ?) OR (blocker_id IN ? AND blocked_id IN ?)
+		)`,
+		userIDs, otherIDs, otherIDs, userIDs).
+		Scan(&blocked).Error
+	return blocked, err
+}
+
+func (r *blockRepositoryImpl) FindBlocked(ctx context.Context, blockerID uuid.UUID, offset, limit int) ([]*models.Block, int64, error) {
+	var blocks []*models.Block
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Block{}).Where("blocker_id = ?", blockerID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Blocked").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&blocks).Error
+	return blocks, total, err
+}
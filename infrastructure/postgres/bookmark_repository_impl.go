@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bookmarkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(db *gorm.DB) repositories.BookmarkRepository {
+	return &bookmarkRepositoryImpl{db: db}
+}
+
+func (r *bookmarkRepositoryImpl) Create(ctx context.Context, bookmark *models.Bookmark) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(bookmark)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *bookmarkRepositoryImpl) Delete(ctx context.Context, userID uuid.UUID, targetType models.BookmarkTargetType, targetID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+		Delete(&models.Bookmark{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *bookmarkRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Bookmark, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+func (r *bookmarkRepositoryImpl) GetByTarget(ctx context.Context, userID uuid.UUID, targetType models.BookmarkTargetType, targetID uuid.UUID) (*models.Bookmark, error) {
+	return r.first(r.db.WithContext(ctx).
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID))
+}
+
+func (r *bookmarkRepositoryImpl) first(query *gorm.DB) (*models.Bookmark, error) {
+	var bookmark models.Bookmark
+	if err := query.First(&bookmark).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bookmark not found")
+		}
+		return nil, err
+	}
+	return &bookmark, nil
+}
+
+func (r *bookmarkRepositoryImpl) IsBookmarked(ctx context.Context, userID uuid.UUID, targetType models.BookmarkTargetType, targetID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Bookmark{}).
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *bookmarkRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID, filter repositories.BookmarkFilter, offset, limit int) ([]*models.Bookmark, int64, error) {
+	var bookmarks []*models.Bookmark
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Bookmark{}).
+		Scopes(bookmarkTargetAvailable(filter.ExcludeForumIDs)).
+		Where("user_id = ?", userID)
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.FolderID != nil {
+		query = query.Where("folder_id = ?", *filter.FolderID)
+	} else if filter.Unsorted {
+		query = query.Where("folder_id IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&bookmarks).Error
+	return bookmarks, total, err
+}
+
+// bookmarkTargetAvailable keeps bookmarks whose content still exists and can be shown:
+// not soft-deleted, videos active, and topics/replies outside hidden forums
+func bookmarkTargetAvailable(excludeForumIDs []uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		topicVisible := "topics.deleted_at IS NULL"
+		var forumArgs []interface{}
+		if len(excludeForumIDs) > 0 {
+			topicVisible += " AND topics.forum_id NOT IN ?"
+			forumArgs = []interface{}{excludeForumIDs}
+		}
+		videoVisible := "videos.is_active = true AND videos.deleted_at IS NULL"
+
+		conditions := []string{
+			"(bookmarks.target_type = ? AND EXISTS (SELECT 1 FROM topics WHERE topics.id = bookmarks.target_id AND " + topicVisible + "))",
+			"(bookmarks.target_type = ? AND EXISTS (SELECT 1 FROM replies JOIN topics ON topics.id = replies.topic_id WHERE replies.id = bookmarks.target_id AND replies.deleted_at IS NULL AND " + topicVisible + "))",
+			"(bookmarks.target_type = ? AND EXISTS (SELECT 1 FROM videos WHERE videos.id = bookmarks.target_id AND " + videoVisible + "))",
+			"(bookmarks.target_type = ? AND EXISTS (SELECT 1 FROM comments JOIN videos ON videos.id = comments.video_id WHERE comments.id = bookmarks.target_id AND comments.deleted_at IS NULL AND " + videoVisible + "))",
+		}
+
+		args := []interface{}{models.BookmarkTargetTopic}
+		args = append(args, forumArgs...)
+		args = append(args, models.BookmarkTargetReply)
+		args = append(args, forumArgs...)
+		args = append(args, models.BookmarkTargetVideo, models.BookmarkTargetComment)
+
+		return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+}
+
+func (r *bookmarkRepositoryImpl) SetFolder(ctx context.Context, id uuid.UUID, folderID *uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Bookmark{}).
+		Where("id = ?", id).
+		Update("folder_id", folderID).Error
+}
+
+func (r *bookmarkRepositoryImpl) CreateFolder(ctx context.Context, folder *models.BookmarkFolder) error {
+	return r.db.WithContext(ctx).Create(folder).Error
+}
+
+func (r *bookmarkRepositoryImpl) GetFolderByID(ctx context.Context, id uuid.UUID) (*models.BookmarkFolder, error) {
+	var folder models.BookmarkFolder
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&folder).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bookmark folder not found")
+		}
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *bookmarkRepositoryImpl) FindFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*models.BookmarkFolder, error) {
+	var folders []*models.BookmarkFolder
+	err := r.db.WithContext(ctx).
+		Select("bookmark_folders.*, (SELECT COUNT(*) FROM bookmarks WHERE bookmarks.folder_id = bookmark_folders.id) AS item_count").
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&folders).Error
+	return folders, err
+}
+
+func (r *bookmarkRepositoryImpl) FolderNameExists(ctx context.Context, userID uuid.UUID, name string, excludeID *uuid.UUID) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).
+		Model(&models.BookmarkFolder{}).
+		Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name)
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *bookmarkRepositoryImpl) UpdateFolder(ctx context.Context, folder *models.BookmarkFolder) error {
+	return r.db.WithContext(ctx).
+		Model(&models.BookmarkFolder{}).
+		Where("id = ?", folder.ID).
+		Update("name", folder.Name).Error
+}
+
+func (r *bookmarkRepositoryImpl) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Bookmark{}).
+			Where("folder_id = ?", id).
+			Update("folder_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.BookmarkFolder{}, id).Error
+	})
+}
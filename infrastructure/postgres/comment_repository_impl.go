@@ -19,8 +19,8 @@ func NewCommentRepository(db *gorm.DB) repositories.CommentRepository {
 }
 
 // Create creates a new comment
-func (r *commentRepositoryImpl) Create(ctx context.Context, comment *models.Comment) error {
-	return r.db.WithContext(ctx).Create(comment).Error
+func (r *commentRepositoryImpl) Create(ctx context.Context, comment *models.Comment, tasks ...*models.BackgroundTask) error {
+	return createWithTasks(r.db.WithContext(ctx), comment, tasks)
 }
 
 // GetByID retrieves a comment by ID
@@ -44,24 +44,49 @@ func (r *commentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	return &comment, nil
 }
 
+func (r *commentRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	if len(ids) == 0 {
+		return comments, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id IN ?", ids).
+		Find(&comments).Error
+	return comments, err
+}
+
 // Update updates a comment
 func (r *commentRepositoryImpl) Update(ctx context.Context, comment *models.Comment) error {
 	return r.db.WithContext(ctx).Save(comment).Error
 }
 
+// Edit saves an edited comment and its revision
+func (r *commentRepositoryImpl) Edit(ctx context.Context, comment *models.Comment, revision *repositories.RevisionEdit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(comment).Error; err != nil {
+			return err
+		}
+		return appendRevision(tx, revision)
+	})
+}
+
 // Delete soft deletes a comment
-func (r *commentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&models.Comment{}, id)
+func (r *commentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID, tasks ...*models.BackgroundTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Comment{}, id)
 
-	if result.Error != nil {
-		return result.Error
-	}
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("comment not found")
-	}
+		if result.RowsAffected == 0 {
+			return errors.New("comment not found")
+		}
 
-	return nil
+		return insertTasks(tx, tasks)
+	})
 }
 
 // FindByVideoID retrieves all top-level comments for a video (excluding replies)
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unreadCondition selects messages by others that the participant p has not read yet. Messages
+// from before the participant (re)joined never count.
+const unreadCondition = `m.sender_id <> p.user_id AND m.deleted_at IS NULL
+	AND m.created_at >= p.joined_at
+	AND (p.last_read_message_at IS NULL OR m.created_at > p.last_read_message_at)`
+
+type conversationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewConversationRepository(db *gorm.DB) repositories.ConversationRepository {
+	return &conversationRepositoryImpl{db: db}
+}
+
+func (r *conversationRepositoryImpl) Create(ctx context.Context, conversation *models.Conversation) error {
+	return r.db.WithContext(ctx).Create(conversation).Error
+}
+
+func (r *conversationRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Conversation, error) {
+	var conversation models.Conversation
+	err := r.db.WithContext(ctx).
+		Preload("Participants.User").
+		Where("id = ?", id).
+		First(&conversation).Error
+	return &conversation, err
+}
+
+func (r *conversationRepositoryImpl) GetByDirectKey(ctx context.Context, directKey string) (*models.Conversation, error) {
+	var conversation models.Conversation
+	err := r.db.WithContext(ctx).
+		Preload("Participants.User").
+		Where("direct_key = ?", directKey).
+		First(&conversation).Error
+	return &conversation, err
+}
+
+func (r *conversationRepositoryImpl) FindByUser(ctx context.Context, userID uuid.UUID, statuses []models.ParticipantStatus, after *repositories.ConversationCursor, limit int) ([]*models.Conversation, error) {
+	var conversations []*models.Conversation
+	query := r.db.WithContext(ctx).
+		Select("conversations.*").
+		Joins("JOIN conversation_participants p ON p.conversation_id = conversations.id AND p.user_id = ?", userID).
+		Where("p.status IN ?", statuses)
+	if after != nil {
+		query = query.Where("(conversations.last_message_at, conversations.id) < (?, ?)", after.LastMessageAt, after.ID)
+	}
+
+	err := query.
+		Preload("Participants.User").
+		Order("conversations.last_message_at DESC, conversations.id DESC").
+		Limit(limit).
+		Find(&conversations).Error
+	return conversations, err
+}
+
+func (r *conversationRepositoryImpl) CountByUser(ctx context.Context, userID uuid.UUID, status models.ParticipantStatus) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.ConversationParticipant{}).
+		Where("user_id = ? AND status = ?", userID, status).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *conversationRepositoryImpl) AddParticipants(ctx context.Context, participants []*models.ConversationParticipant) error {
+	if len(participants) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "conversation_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "joined_at", "updated_at"}),
+		}).
+		Create(&participants).Error
+}
+
+func (r *conversationRepositoryImpl) UpdateParticipantStatus(ctx context.Context, conversationID, userID uuid.UUID, status models.ParticipantStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("participant not found")
+	}
+	return nil
+}
+
+func (r *conversationRepositoryImpl) MarkRead(ctx context.Context, conversationID, userID uuid.UUID, message *models.Message, readAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Where("last_read_message_at IS NULL OR last_read_message_at < ?", message.CreatedAt).
+		Updates(map[string]interface{}{
+			"last_read_message_id": message.ID,
+			"last_read_message_at": message.CreatedAt,
+			"read_at":              readAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *conversationRepositoryImpl) CountUnread(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ConversationID uuid.UUID
+		Unread         int64
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT m.conversation_id, COUNT(*) AS unread
+		FROM messages m
+		JOIN conversation_participants p ON p.conversation_id = m.conversation_id AND p.user_id = ?
+		WHERE m.conversation_id IN ? AND `+unreadCondition+`
+		GROUP BY m.conversation_id`,
+		userID, conversationIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ConversationID] = row.Unread
+	}
+	return counts, nil
+}
+
+func (r *conversationRepositoryImpl) CountUnreadTotal(ctx context.Context, userID uuid.UUID) (*repositories.UnreadMessageCount, error) {
+	var count repositories.UnreadMessageCount
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) AS messages, COUNT(DISTINCT m.conversation_id) AS conversations
+		FROM messages m
+		JOIN conversation_participants p ON p.conversation_id = m.conversation_id AND p.user_id = ? AND p.status = ?
+		WHERE `+unreadCondition,
+		userID, models.ParticipantActive).
+		Scan(&count).Error
+	return &count, err
+}
@@ -3,9 +3,11 @@ package postgres
 import (
 	"fmt"
 	"gofiber-social/domain/models"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -33,21 +35,139 @@ func NewDatabase(config DatabaseConfig) (*gorm.DB, error) {
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Forum{},
+		&models.ForumModerator{},
 		&models.Topic{},
+		&models.TopicRedirect{},
 		&models.Reply{},
+		&models.Poll{},
+		&models.PollOption{},
+		&models.PollBallot{},
+		&models.PollVote{},
+		&models.BookmarkFolder{},
+		&models.Bookmark{},
+		&models.TopicWatch{},
+		&models.ForumWatch{},
+		&models.TopicReadMarker{},
+		&models.ForumReadMarker{},
+		&models.WatchPreference{},
+		&models.ContentRevision{},
+		&models.LinkPreview{},
 		&models.Task{},
 		&models.File{},
+		&models.FileReference{},
 		&models.Job{},
+		&models.JobRun{},
+		&models.BackgroundTask{},
 		&models.Video{},
+		&models.VideoView{},
+		&models.Playlist{},
+		&models.PlaylistItem{},
 		&models.Like{},
 		&models.Comment{},
 		&models.Share{},
 		&models.Follow{},
+		&models.Block{},
+		&models.Conversation{},
+		&models.ConversationParticipant{},
+		&models.Message{},
+		&models.MessageAttachment{},
 		&models.Notification{},
 		&models.Report{},
 		&models.ActivityLog{},
-	)
+		&models.ContentDailyStat{},
+		&models.ShareDailyStat{},
+		&models.FollowerDailyStat{},
+		&models.DataMigration{},
+	); err != nil {
+		return err
+	}
+
+	// Topics from before unread tracking get their last activity from their replies (runs once)
+	if err := db.Exec(`
+		UPDATE topics SET last_activity_at = COALESCE(
+			(SELECT MAX(replies.created_at) FROM replies WHERE replies.topic_id = topics.id AND replies.deleted_at IS NULL),
+			topics.created_at)
+		WHERE last_activity_at IS NULL`).Error; err != nil {
+		return err
+	}
+
+	if err := backfillReplyLikeCounts(db); err != nil {
+		return err
+	}
+
+	return backfillFileReferences(db)
+}
+
+// backfillReplyLikeCounts counts the likes of replies from before replies kept a like_count (runs once)
+func backfillReplyLikeCounts(db *gorm.DB) error {
+	return applyDataMigration(db, models.DataMigrationReplyLikeCount, []string{
+		`UPDATE replies SET like_count = counts.likes
+			FROM (SELECT reply_id, COUNT(*) AS likes FROM likes WHERE reply_id IS NOT NULL GROUP BY reply_id) counts
+			WHERE replies.id = counts.reply_id AND replies.like_count <> counts.likes`,
+	})
+}
+
+// fileReferenceBackfillSQL attaches the files that existing media points to. Deleted records
+// keep their files too: it is safer to keep an unused file than to guess.
+var fileReferenceBackfillSQL = []string{
+	`INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT files.id, 'video', videos.id, NOW() FROM videos JOIN files ON files.url = videos.video_url
+		ON CONFLICT DO NOTHING`,
+	`INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT files.id, 'video_thumbnail', videos.id, NOW() FROM videos JOIN files ON files.url = videos.thumbnail_url
+		ON CONFLICT DO NOTHING`,
+	`INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT files.id, 'avatar', users.id, NOW() FROM users JOIN files ON files.url = users.avatar
+		ON CONFLICT DO NOTHING`,
+	`INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT files.id, 'topic_thumbnail', topics.id, NOW() FROM topics JOIN files ON files.url = topics.thumbnail
+		ON CONFLICT DO NOTHING`,
+	// Images are embedded as file:<id> since Markdown rendering, and by URL before it
+	`INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT files.id, 'topic_content', topics.id, NOW() FROM topics JOIN files
+			ON POSITION('file:' || files.id::text IN topics.content) > 0 OR POSITION(files.url IN topics.content) > 0
+		ON CONFLICT DO NOTHING`,
+	`INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT files.id, 'reply_content', replies.id, NOW() FROM replies JOIN files
+			ON POSITION('file:' || files.id::text IN replies.content) > 0 OR POSITION(files.url IN replies.content) > 0
+		ON CONFLICT DO NOTHING`,
+	`INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT file_id, 'message', message_id, NOW() FROM message_attachments
+		ON CONFLICT DO NOTHING`,
+}
+
+// backfillFileReferences records the files used by media from before file references were
+// tracked, so the orphan cleanup doesn't take them for abandoned uploads. The cleanup deletes
+// nothing until this has run (runs once).
+func backfillFileReferences(db *gorm.DB) error {
+	return applyDataMigration(db, models.DataMigrationFileReferences, fileReferenceBackfillSQL)
+}
+
+// applyDataMigration runs the statements of a one-off data migration in one transaction and
+// records it, unless it was applied before
+func applyDataMigration(db *gorm.DB, name string, statements []string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var applied int64
+		if err := tx.Model(&models.DataMigration{}).Where("name = ?", name).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			return nil
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("data migration %s failed: %v", name, err)
+			}
+		}
+
+		// Another replica migrating at the same time may have recorded it first
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DataMigration{
+			Name:      name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type FileRepositoryImpl struct {
@@ -30,6 +32,16 @@ func (r *FileRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models
 	return &file, nil
 }
 
+func (r *FileRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.File, error) {
+	var files []*models.File
+	if len(ids) == 0 {
+		return files, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&files).Error
+	return files, err
+}
+
 func (r *FileRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.File, error) {
 	var files []*models.File
 	err := r.db.WithContext(ctx).Preload("User").Where("user_id = ?", userID).Offset(offset).Limit(limit).Find(&files).Error
@@ -61,3 +73,84 @@ func (r *FileRepositoryImpl) CountByUserID(ctx context.Context, userID uuid.UUID
 	err := r.db.WithContext(ctx).Model(&models.File{}).Where("user_id = ?", userID).Count(&count).Error
 	return count, err
 }
+
+func (r *FileRepositoryImpl) GetByURL(ctx context.Context, url string) (*models.File, error) {
+	var file models.File
+	err := r.db.WithContext(ctx).Where("url = ?", url).First(&file).Error
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (r *FileRepositoryImpl) SumSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).
+		Model(&models.File{}).
+		Select("COALESCE(SUM(file_size), 0)").
+		Where("user_id = ?", userID).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *FileRepositoryImpl) CreateReference(ctx context.Context, ref *models.FileReference) error {
+	// Attaching the same file to the same resource twice is a no-op
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(ref).Error
+}
+
+func (r *FileRepositoryImpl) DeleteReferencesByResource(ctx context.Context, resourceType models.FileReferenceType, resourceID uuid.UUID) ([]uuid.UUID, error) {
+	var refs []models.FileReference
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "file_id"}}}).
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Delete(&refs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	fileIDs := make([]uuid.UUID, len(refs))
+	for i, ref := range refs {
+		fileIDs[i] = ref.FileID
+	}
+	return fileIDs, nil
+}
+
+func (r *FileRepositoryImpl) CountReferences(ctx context.Context, fileID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.FileReference{}).Where("file_id = ?", fileID).Count(&count).Error
+	return count, err
+}
+
+func (r *FileRepositoryImpl) FindOrphaned(ctx context.Context, createdBefore time.Time, limit int) ([]*models.File, error) {
+	var files []*models.File
+	err := r.db.WithContext(ctx).
+		Where("created_at < ?", createdBefore).
+		Where("NOT EXISTS (SELECT 1 FROM file_references WHERE file_references.file_id = files.id)").
+		// Until existing media has been backfilled, every file would look orphaned
+		Where("EXISTS (SELECT 1 FROM data_migrations WHERE name = ?)", models.DataMigrationFileReferences).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&files).Error
+	return files, err
+}
+
+func (r *FileRepositoryImpl) FindUnprotectedReferenced(ctx context.Context, resourceTypes []models.FileReferenceType, limit int) ([]*models.File, error) {
+	var files []*models.File
+	err := r.db.WithContext(ctx).
+		Where("protected = ?", false).
+		Where("EXISTS (SELECT 1 FROM file_references WHERE file_references.file_id = files.id AND file_references.resource_type IN ?)", resourceTypes).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&files).Error
+	return files, err
+}
+
+func (r *FileRepositoryImpl) MarkProtected(ctx context.Context, id uuid.UUID, url string) error {
+	return r.db.WithContext(ctx).Model(&models.File{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"protected":  true,
+		"url":        url,
+		"updated_at": time.Now(),
+	}).Error
+}
@@ -2,12 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
 
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ForumRepositoryImpl struct {
@@ -62,14 +64,38 @@ func (r *ForumRepositoryImpl) GetActive(ctx context.Context) ([]*models.Forum, e
 }
 
 func (r *ForumRepositoryImpl) Update(ctx context.Context, id uuid.UUID, forum *models.Forum) error {
+	// ใช้ map เพื่อให้ค่า zero (parent = nil, อายุบัญชี = 0) ถูกบันทึกด้วย
 	return r.db.WithContext(ctx).
+		Model(&models.Forum{}).
 		Where("id = ?", id).
-		Updates(forum).Error
+		Updates(map[string]interface{}{
+			"name":                       forum.Name,
+			"description":                forum.Description,
+			"icon":                       forum.Icon,
+			"order":                      forum.Order,
+			"is_active":                  forum.IsActive,
+			"is_qa":                      forum.IsQA,
+			"parent_id":                  forum.ParentID,
+			"view_audience":              forum.ViewRule.Audience,
+			"view_min_account_age_days":  forum.ViewRule.MinAccountAgeDays,
+			"view_role":                  forum.ViewRule.Role,
+			"post_audience":              forum.PostRule.Audience,
+			"post_min_account_age_days":  forum.PostRule.MinAccountAgeDays,
+			"post_role":                  forum.PostRule.Role,
+			"reply_audience":             forum.ReplyRule.Audience,
+			"reply_min_account_age_days": forum.ReplyRule.MinAccountAgeDays,
+			"reply_role":                 forum.ReplyRule.Role,
+			"updated_at":                 forum.UpdatedAt,
+		}).Error
 }
 
 func (r *ForumRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).
-		Delete(&models.Forum{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("forum_id = ?", id).Delete(&models.ForumModerator{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Forum{}, "id = ?", id).Error
+	})
 }
 
 func (r *ForumRepositoryImpl) UpdateOrder(ctx context.Context, id uuid.UUID, order int) error {
@@ -121,3 +147,41 @@ func (r *ForumRepositoryImpl) Count(ctx context.Context) (int64, error) {
 		Count(&count).Error
 	return count, err
 }
+
+func (r *ForumRepositoryImpl) AddModerator(ctx context.Context, moderator *models.ForumModerator) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(moderator).Error
+}
+
+func (r *ForumRepositoryImpl) RemoveModerator(ctx context.Context, forumID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("forum_id = ? AND user_id = ?", forumID, userID).
+		Delete(&models.ForumModerator{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user is not a moderator of this forum")
+	}
+	return nil
+}
+
+func (r *ForumRepositoryImpl) GetModerators(ctx context.Context, forumID uuid.UUID) ([]*models.ForumModerator, error) {
+	var moderators []*models.ForumModerator
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("forum_id = ?", forumID).
+		Order("created_at ASC").
+		Find(&moderators).Error
+	return moderators, err
+}
+
+func (r *ForumRepositoryImpl) GetModeratedForumIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.ForumModerator{}).
+		Where("user_id = ?", userID).
+		Pluck("forum_id", &ids).Error
+	return ids, err
+}
@@ -47,7 +47,21 @@ func (r *JobRepositoryImpl) GetActiveJobs(ctx context.Context) ([]*models.Job, e
 }
 
 func (r *JobRepositoryImpl) Update(ctx context.Context, id uuid.UUID, job *models.Job) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Updates(job).Error
+	return r.db.WithContext(ctx).Model(&models.Job{}).Where("id = ?", id).
+		Select("name", "type", "cron_expr", "timezone", "payload", "timeout_seconds", "max_concurrency", "is_active", "next_run", "updated_at").
+		Updates(job).Error
+}
+
+func (r *JobRepositoryImpl) SetActive(ctx context.Context, id uuid.UUID, isActive bool) error {
+	return r.db.WithContext(ctx).Model(&models.Job{}).Where("id = ?", id).Update("is_active", isActive).Error
+}
+
+func (r *JobRepositoryImpl) SetPaused(ctx context.Context, id uuid.UUID, paused bool, pausedAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"is_paused":  paused,
+		"paused_at":  pausedAt,
+		"updated_at": time.Now(),
+	}).Error
 }
 
 func (r *JobRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
@@ -73,3 +87,46 @@ func (r *JobRepositoryImpl) UpdateLastRun(ctx context.Context, id uuid.UUID, las
 func (r *JobRepositoryImpl) UpdateNextRun(ctx context.Context, id uuid.UUID, nextRun *time.Time) error {
 	return r.db.WithContext(ctx).Model(&models.Job{}).Where("id = ?", id).Update("next_run", nextRun).Error
 }
+
+func (r *JobRepositoryImpl) MarkRunning(ctx context.Context, id uuid.UUID, startedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":   models.JobStatusRunning,
+		"last_run": startedAt,
+	}).Error
+}
+
+func (r *JobRepositoryImpl) UpdateRunStatus(ctx context.Context, id uuid.UUID, status, lastError string, durationMs int64, nextRun *time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":           status,
+		"last_error":       lastError,
+		"last_duration_ms": durationMs,
+		"next_run":         nextRun,
+	}).Error
+}
+
+func (r *JobRepositoryImpl) CreateRun(ctx context.Context, run *models.JobRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *JobRepositoryImpl) FinishRun(ctx context.Context, run *models.JobRun) error {
+	return r.db.WithContext(ctx).Model(&models.JobRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"status":      run.Status,
+		"result":      run.Result,
+		"error":       run.Error,
+		"finished_at": run.FinishedAt,
+		"duration_ms": run.DurationMs,
+	}).Error
+}
+
+func (r *JobRepositoryImpl) ListRuns(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, int64, error) {
+	var runs []*models.JobRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.JobRun{}).Where("job_id = ?", jobID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("started_at DESC").Offset(offset).Limit(limit).Find(&runs).Error
+	return runs, total, err
+}
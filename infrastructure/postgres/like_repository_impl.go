@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 
@@ -19,7 +20,7 @@ func NewLikeRepository(db *gorm.DB) repositories.LikeRepository {
 }
 
 // LikeTopic creates a like for a topic
-func (r *likeRepositoryImpl) LikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID) (*models.Like, error) {
+func (r *likeRepositoryImpl) LikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error) {
 	// Check if already liked
 	var existingLike models.Like
 	err := r.db.WithContext(ctx).
@@ -45,7 +46,7 @@ func (r *likeRepositoryImpl) LikeTopic(ctx context.Context, userID uuid.UUID, to
 		return nil, err
 	}
 
-	if err := r.db.WithContext(ctx).Create(like).Error; err != nil {
+	if err := createWithTasks(r.db.WithContext(ctx), like, tasks); err != nil {
 		return nil, err
 	}
 
@@ -53,20 +54,22 @@ func (r *likeRepositoryImpl) LikeTopic(ctx context.Context, userID uuid.UUID, to
 }
 
 // UnlikeTopic removes a like from a topic
-func (r *likeRepositoryImpl) UnlikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID) error {
-	result := r.db.WithContext(ctx).
-		Where("user_id = ? AND topic_id = ?", userID, topicID).
-		Delete(&models.Like{})
-
-	if result.Error != nil {
-		return result.Error
-	}
+func (r *likeRepositoryImpl) UnlikeTopic(ctx context.Context, userID uuid.UUID, topicID uuid.UUID, tasks ...*models.BackgroundTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("user_id = ? AND topic_id = ?", userID, topicID).
+			Delete(&models.Like{})
+
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("like not found")
-	}
+		if result.RowsAffected == 0 {
+			return errors.New("like not found")
+		}
 
-	return nil
+		return insertTasks(tx, tasks)
+	})
 }
 
 // IsTopicLikedByUser checks if a user has liked a topic
@@ -124,7 +127,7 @@ func (r *likeRepositoryImpl) GetTopicLikesByUserID(ctx context.Context, userID u
 }
 
 // LikeVideo creates a like for a video
-func (r *likeRepositoryImpl) LikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID) (*models.Like, error) {
+func (r *likeRepositoryImpl) LikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error) {
 	// Check if already liked
 	var existingLike models.Like
 	err := r.db.WithContext(ctx).
@@ -150,7 +153,7 @@ func (r *likeRepositoryImpl) LikeVideo(ctx context.Context, userID uuid.UUID, vi
 		return nil, err
 	}
 
-	if err := r.db.WithContext(ctx).Create(like).Error; err != nil {
+	if err := createWithTasks(r.db.WithContext(ctx), like, tasks); err != nil {
 		return nil, err
 	}
 
@@ -158,20 +161,22 @@ func (r *likeRepositoryImpl) LikeVideo(ctx context.Context, userID uuid.UUID, vi
 }
 
 // UnlikeVideo removes a like from a video
-func (r *likeRepositoryImpl) UnlikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID) error {
-	result := r.db.WithContext(ctx).
-		Where("user_id = ? AND video_id = ?", userID, videoID).
-		Delete(&models.Like{})
-
-	if result.Error != nil {
-		return result.Error
-	}
+func (r *likeRepositoryImpl) UnlikeVideo(ctx context.Context, userID uuid.UUID, videoID uuid.UUID, tasks ...*models.BackgroundTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("user_id = ? AND video_id = ?", userID, videoID).
+			Delete(&models.Like{})
+
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("like not found")
-	}
+		if result.RowsAffected == 0 {
+			return errors.New("like not found")
+		}
 
-	return nil
+		return insertTasks(tx, tasks)
+	})
 }
 
 // IsVideoLikedByUser checks if a user has liked a video
@@ -263,7 +268,7 @@ func (r *likeRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 // Reply Likes
-func (r *likeRepositoryImpl) LikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) (*models.Like, error) {
+func (r *likeRepositoryImpl) LikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error) {
 	// Check if already liked
 	var existingLike models.Like
 	err := r.db.WithContext(ctx).
@@ -289,27 +294,29 @@ func (r *likeRepositoryImpl) LikeReply(ctx context.Context, userID uuid.UUID, re
 		return nil, err
 	}
 
-	if err := r.db.WithContext(ctx).Create(like).Error; err != nil {
+	if err := createWithTasks(r.db.WithContext(ctx), like, tasks); err != nil {
 		return nil, err
 	}
 
 	return like, nil
 }
 
-func (r *likeRepositoryImpl) UnlikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) error {
-	result := r.db.WithContext(ctx).
-		Where("user_id = ? AND reply_id = ?", userID, replyID).
-		Delete(&models.Like{})
+func (r *likeRepositoryImpl) UnlikeReply(ctx context.Context, userID uuid.UUID, replyID uuid.UUID, tasks ...*models.BackgroundTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("user_id = ? AND reply_id = ?", userID, replyID).
+			Delete(&models.Like{})
 
-	if result.Error != nil {
-		return result.Error
-	}
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("like not found")
-	}
+		if result.RowsAffected == 0 {
+			return errors.New("like not found")
+		}
 
-	return nil
+		return insertTasks(tx, tasks)
+	})
 }
 
 func (r *likeRepositoryImpl) IsReplyLikedByUser(ctx context.Context, userID uuid.UUID, replyID uuid.UUID) (bool, error) {
@@ -363,7 +370,7 @@ func (r *likeRepositoryImpl) GetReplyLikesByUserID(ctx context.Context, userID u
 }
 
 // Comment Likes
-func (r *likeRepositoryImpl) LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*models.Like, error) {
+func (r *likeRepositoryImpl) LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, tasks ...*models.BackgroundTask) (*models.Like, error) {
 	// Check if already liked
 	var existingLike models.Like
 	err := r.db.WithContext(ctx).
@@ -389,27 +396,29 @@ func (r *likeRepositoryImpl) LikeComment(ctx context.Context, userID uuid.UUID,
 		return nil, err
 	}
 
-	if err := r.db.WithContext(ctx).Create(like).Error; err != nil {
+	if err := createWithTasks(r.db.WithContext(ctx), like, tasks); err != nil {
 		return nil, err
 	}
 
 	return like, nil
 }
 
-func (r *likeRepositoryImpl) UnlikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) error {
-	result := r.db.WithContext(ctx).
-		Where("user_id = ? AND comment_id = ?", userID, commentID).
-		Delete(&models.Like{})
+func (r *likeRepositoryImpl) UnlikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, tasks ...*models.BackgroundTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("user_id = ? AND comment_id = ?", userID, commentID).
+			Delete(&models.Like{})
 
-	if result.Error != nil {
-		return result.Error
-	}
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("like not found")
-	}
+		if result.RowsAffected == 0 {
+			return errors.New("like not found")
+		}
 
-	return nil
+		return insertTasks(tx, tasks)
+	})
 }
 
 func (r *likeRepositoryImpl) IsCommentLikedByUser(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (bool, error) {
@@ -461,3 +470,28 @@ func (r *likeRepositoryImpl) GetCommentLikesByUserID(ctx context.Context, userID
 
 	return likes, totalCount, err
 }
+
+// likeCountTargets maps a like target to its table and the likes column pointing at it
+var likeCountTargets = map[string]struct{ table, column string }{
+	"topic": {"topics", "topic_id"},
+	"reply": {"replies", "reply_id"},
+	"video": {"videos", "video_id"},
+}
+
+func (r *likeRepositoryImpl) RecomputeLikeCounts(ctx context.Context, targetType string) (int64, error) {
+	target, ok := likeCountTargets[targetType]
+	if !ok {
+		return 0, fmt.Errorf("unknown like target %q", targetType)
+	}
+
+	result := r.db.WithContext(ctx).Exec(fmt.Sprintf(`
+		UPDATE %[1]s t SET like_count = c.likes
+		FROM (
+			SELECT x.id, COUNT(l.id) AS likes
+			FROM %[1]s x LEFT JOIN likes l ON l.%[2]s = x.id
+			GROUP BY x.id
+		) c
+		WHERE t.id = c.id AND t.like_count IS DISTINCT FROM c.likes`,
+		target.table, target.column))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type linkPreviewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewLinkPreviewRepository(db *gorm.DB) repositories.LinkPreviewRepository {
+	return &linkPreviewRepositoryImpl{db: db}
+}
+
+func (r *linkPreviewRepositoryImpl) GetByURLs(ctx context.Context, urls []string) ([]*models.LinkPreview, error) {
+	var previews []*models.LinkPreview
+	if len(urls) == 0 {
+		return previews, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("url IN ?", urls).Find(&previews).Error
+	return previews, err
+}
+
+func (r *linkPreviewRepositoryImpl) Upsert(ctx context.Context, preview *models.LinkPreview) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "url"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "description", "image_url", "site_name", "failed", "fetched_at", "updated_at"}),
+		}).
+		Create(preview).Error
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type messageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewMessageRepository(db *gorm.DB) repositories.MessageRepository {
+	return &messageRepositoryImpl{db: db}
+}
+
+func (r *messageRepositoryImpl) Create(ctx context.Context, message *models.Message) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Conversation{}).
+			Where("id = ?", message.ConversationID).
+			Updates(map[string]interface{}{
+				"last_message_id": message.ID,
+				"last_message_at": message.CreatedAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ? AND user_id = ?", message.ConversationID, message.SenderID).
+			Updates(map[string]interface{}{
+				"last_read_message_id": message.ID,
+				"last_read_message_at": message.CreatedAt,
+				"read_at":              message.CreatedAt,
+			}).Error
+	})
+}
+
+func (r *messageRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	var message models.Message
+	err := r.db.WithContext(ctx).
+		Preload("Sender").
+		Preload("Attachments.File").
+		Where("id = ?", id).
+		First(&message).Error
+	return &message, err
+}
+
+func (r *messageRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	var messages []*models.Message
+	if len(ids) == 0 {
+		return messages, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Sender").
+		Preload("Attachments.File").
+		Where("id IN ?", ids).
+		Find(&messages).Error
+	return messages, err
+}
+
+func (r *messageRepositoryImpl) GetLatest(ctx context.Context, conversationID uuid.UUID) (*models.Message, error) {
+	var message models.Message
+	err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at DESC, id DESC").
+		First(&message).Error
+	return &message, err
+}
+
+func (r *messageRepositoryImpl) UpdateContent(ctx context.Context, id uuid.UUID, content string, editedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"content":   content,
+			"edited_at": editedAt,
+		}).Error
+}
+
+func (r *messageRepositoryImpl) SoftDelete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Message{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"content":    "",
+				"deleted_at": deletedAt,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Where("message_id = ?", id).Delete(&models.MessageAttachment{}).Error
+	})
+}
+
+func (r *messageRepositoryImpl) FindByConversation(ctx context.Context, conversationID uuid.UUID, before *repositories.MessageCursor, limit int) ([]*models.Message, error) {
+	var messages []*models.Message
+	query := r.db.WithContext(ctx).Where("conversation_id = ?", conversationID)
+	if before != nil {
+		query = query.Where("(created_at, id) < (?, ?)", before.CreatedAt, before.ID)
+	}
+
+	err := query.
+		Preload("Sender").
+		Preload("Attachments.File").
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/models"
@@ -96,6 +97,18 @@ func (r *notificationRepositoryImpl) GetUnreadCount(ctx context.Context, userID
 	return count, err
 }
 
+func (r *notificationRepositoryImpl) FindUnreadByResource(ctx context.Context, userIDs []uuid.UUID, notificationType models.NotificationType, resourceID uuid.UUID) ([]models.Notification, error) {
+	var notifications []models.Notification
+	if len(userIDs) == 0 {
+		return notifications, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ? AND type = ? AND resource_id = ? AND is_read = ?", userIDs, notificationType, resourceID, false).
+		Find(&notifications).Error
+	return notifications, err
+}
+
 func (r *notificationRepositoryImpl) MarkAsRead(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).
 		Model(&models.Notification{}).
@@ -120,6 +133,37 @@ func (r *notificationRepositoryImpl) MarkAllAsRead(ctx context.Context, userID u
 		Update("is_read", true).Error
 }
 
+func (r *notificationRepositoryImpl) Fold(ctx context.Context, ids []uuid.UUID, actorID uuid.UUID, message string) error {
+	return fold(r.db.WithContext(ctx), ids, actorID, message)
+}
+
+func (r *notificationRepositoryImpl) FoldAndCreate(ctx context.Context, ids []uuid.UUID, actorID uuid.UUID, message string, notifications []models.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := fold(tx, ids, actorID, message); err != nil {
+			return err
+		}
+		if len(notifications) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(notifications, 100).Error
+	})
+}
+
+func fold(db *gorm.DB, ids []uuid.UUID, actorID uuid.UUID, message string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.
+		Model(&models.Notification{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"count":      gorm.Expr("count + 1"),
+			"actor_id":   actorID,
+			"message":    message,
+			"created_at": time.Now(),
+		}).Error
+}
+
 func (r *notificationRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.Notification{}, id).Error
 }
@@ -129,3 +173,40 @@ func (r *notificationRepositoryImpl) DeleteByUserID(ctx context.Context, userID
 		Where("user_id = ?", userID).
 		Delete(&models.Notification{}).Error
 }
+
+func (r *notificationRepositoryImpl) DeleteOlderThan(ctx context.Context, before time.Time, readOnly bool) (int64, error) {
+	query := r.db.WithContext(ctx).Where("created_at < ?", before)
+	if readOnly {
+		query = query.Where("is_read = ?", true)
+	}
+	result := query.Delete(&models.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepositoryImpl) CountUnreadForDigest(ctx context.Context, since time.Time, minUnread int) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		UserID uuid.UUID
+		Unread int64
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT n.user_id, COUNT(*) AS unread
+		FROM notifications n
+		WHERE n.is_read = false AND n.type <> ? AND n.created_at >= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM notifications d
+				WHERE d.user_id = n.user_id AND d.type = ? AND d.created_at >= ?
+			)
+		GROUP BY n.user_id
+		HAVING COUNT(*) >= ?`,
+		models.NotificationTypeDigest, since, models.NotificationTypeDigest, since, minUnread).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.UserID] = row.Unread
+	}
+	return counts, nil
+}
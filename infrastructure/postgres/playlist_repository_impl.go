@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type playlistRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPlaylistRepository(db *gorm.DB) repositories.PlaylistRepository {
+	return &playlistRepositoryImpl{db: db}
+}
+
+func (r *playlistRepositoryImpl) Create(ctx context.Context, playlist *models.Playlist) error {
+	return r.db.WithContext(ctx).Create(playlist).Error
+}
+
+func (r *playlistRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
+	var playlist models.Playlist
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id = ?", id).
+		First(&playlist).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("playlist not found")
+		}
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func (r *playlistRepositoryImpl) Update(ctx context.Context, playlist *models.Playlist) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Playlist{}).
+		Where("id = ?", playlist.ID).
+		Updates(map[string]interface{}{
+			"title":       playlist.Title,
+			"description": playlist.Description,
+			"visibility":  playlist.Visibility,
+		}).Error
+}
+
+func (r *playlistRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("playlist_id = ?", id).Delete(&models.PlaylistItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Playlist{}, id).Error
+	})
+}
+
+func (r *playlistRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID, publicOnly bool, offset, limit int) ([]*models.Playlist, int64, error) {
+	var playlists []*models.Playlist
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Playlist{}).Where("user_id = ?", userID)
+	if publicOnly {
+		query = query.Where("visibility = ?", models.PlaylistPublic)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&playlists).Error
+	return playlists, total, err
+}
+
+func (r *playlistRepositoryImpl) FindIDsContainingVideo(ctx context.Context, userID, videoID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.PlaylistItem{}).
+		Joins("JOIN playlists ON playlists.id = playlist_items.playlist_id AND playlists.deleted_at IS NULL").
+		Where("playlists.user_id = ? AND playlist_items.video_id = ?", userID, videoID).
+		Pluck("playlist_items.playlist_id", &ids).Error
+	return ids, err
+}
+
+func (r *playlistRepositoryImpl) GetItems(ctx context.Context, playlistID uuid.UUID, offset, limit int) ([]*models.PlaylistItem, int64, error) {
+	var items []*models.PlaylistItem
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PlaylistItem{}).Where("playlist_id = ?", playlistID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("position ASC").Offset(offset).Limit(limit).Find(&items).Error
+	return items, total, err
+}
+
+func (r *playlistRepositoryImpl) GetVideoIDs(ctx context.Context, playlistID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.PlaylistItem{}).
+		Where("playlist_id = ?", playlistID).
+		Order("position ASC").
+		Pluck("video_id", &ids).Error
+	return ids, err
+}
+
+func (r *playlistRepositoryImpl) AddItem(ctx context.Context, playlistID, videoID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the playlist row so concurrent adds don't get the same position
+		var playlist models.Playlist
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", playlistID).First(&playlist).Error; err != nil {
+			return err
+		}
+
+		var exists int64
+		if err := tx.Model(&models.PlaylistItem{}).Where("playlist_id = ? AND video_id = ?", playlistID, videoID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			return errors.New("video is already in this playlist")
+		}
+
+		item := &models.PlaylistItem{
+			PlaylistID: playlistID,
+			VideoID:    videoID,
+			Position:   playlist.ItemCount,
+		}
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Playlist{}).
+			Where("id = ?", playlistID).
+			Updates(map[string]interface{}{"item_count": gorm.Expr("item_count + 1"), "updated_at": gorm.Expr("NOW()")}).Error
+	})
+}
+
+func (r *playlistRepositoryImpl) RemoveItem(ctx context.Context, playlistID, videoID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("playlist_id = ? AND video_id = ?", playlistID, videoID).Delete(&models.PlaylistItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("video is not in this playlist")
+		}
+
+		return compactPlaylist(tx, playlistID)
+	})
+}
+
+func (r *playlistRepositoryImpl) ReorderItems(ctx context.Context, playlistID uuid.UUID, videoIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Positions are unique per playlist only by convention, so they can be rewritten in place
+		for position, videoID := range videoIDs {
+			result := tx.Model(&models.PlaylistItem{}).
+				Where("playlist_id = ? AND video_id = ?", playlistID, videoID).
+				Update("position", position)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("video is not in this playlist")
+			}
+		}
+
+		return tx.Model(&models.Playlist{}).Where("id = ?", playlistID).Update("updated_at", gorm.Expr("NOW()")).Error
+	})
+}
+
+func (r *playlistRepositoryImpl) RemoveVideoFromAll(ctx context.Context, videoID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var playlistIDs []uuid.UUID
+		if err := tx.Model(&models.PlaylistItem{}).Where("video_id = ?", videoID).Pluck("playlist_id", &playlistIDs).Error; err != nil {
+			return err
+		}
+		if len(playlistIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("video_id = ?", videoID).Delete(&models.PlaylistItem{}).Error; err != nil {
+			return err
+		}
+
+		for _, playlistID := range playlistIDs {
+			if err := compactPlaylist(tx, playlistID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// compactPlaylist renumbers positions from 0 and syncs item_count after items were removed
+func compactPlaylist(tx *gorm.DB, playlistID uuid.UUID) error {
+	err := tx.Exec(`
+		UPDATE playlist_items SET position = ranked.rn - 1
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position ASC, created_at ASC) AS rn
+			FROM playlist_items WHERE playlist_id = ?
+		) ranked
+		WHERE playlist_items.id = ranked.id`, playlistID).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		UPDATE playlists SET
+			item_count = (SELECT COUNT(*) FROM playlist_items WHERE playlist_id = ?),
+			updated_at = NOW()
+		WHERE id = ?`, playlistID, playlistID).Error
+}
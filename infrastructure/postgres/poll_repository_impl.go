@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type pollRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPollRepository(db *gorm.DB) repositories.PollRepository {
+	return &pollRepositoryImpl{db: db}
+}
+
+func (r *pollRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Poll, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+func (r *pollRepositoryImpl) GetByTopicID(ctx context.Context, topicID uuid.UUID) (*models.Poll, error) {
+	return r.first(r.db.WithContext(ctx).Where("topic_id = ?", topicID))
+}
+
+func (r *pollRepositoryImpl) first(query *gorm.DB) (*models.Poll, error) {
+	var poll models.Poll
+	err := query.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&poll).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("poll not found")
+		}
+		return nil, err
+	}
+	return &poll, nil
+}
+
+func (r *pollRepositoryImpl) CastVote(ctx context.Context, pollID, userID uuid.UUID, optionIDs []uuid.UUID, replace bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// ล็อกแถว poll ไว้ กันโหวตแทรกระหว่างปิดโพลและให้การนับคะแนนไม่ชนกัน
+		var poll models.Poll
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", pollID).
+			First(&poll).Error; err != nil {
+			return err
+		}
+		if poll.IsClosed(time.Now()) {
+			return errors.New("poll is closed")
+		}
+
+		if replace {
+			if err := deleteBallot(tx, pollID, userID); err != nil {
+				return err
+			}
+		}
+
+		ballot := models.PollBallot{ID: uuid.New(), PollID: pollID, UserID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ballot)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("you have already voted")
+		}
+
+		votes := make([]models.PollVote, len(optionIDs))
+		for i, optionID := range optionIDs {
+			votes[i] = models.PollVote{ID: uuid.New(), BallotID: ballot.ID, OptionID: optionID, UserID: userID}
+		}
+		if err := tx.Create(&votes).Error; err != nil {
+			return err
+		}
+
+		return refreshPollCounts(tx, pollID)
+	})
+}
+
+func (r *pollRepositoryImpl) RetractVote(ctx context.Context, pollID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var poll models.Poll
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", pollID).
+			First(&poll).Error; err != nil {
+			return err
+		}
+		if poll.IsClosed(time.Now()) {
+			return errors.New("poll is closed")
+		}
+
+		if err := deleteBallot(tx, pollID, userID); err != nil {
+			return err
+		}
+		return refreshPollCounts(tx, pollID)
+	})
+}
+
+func deleteBallot(tx *gorm.DB, pollID, userID uuid.UUID) error {
+	ballotIDs := tx.Model(&models.PollBallot{}).
+		Select("id").
+		Where("poll_id = ? AND user_id = ?", pollID, userID)
+	if err := tx.Where("ballot_id IN (?)", ballotIDs).Delete(&models.PollVote{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("poll_id = ? AND user_id = ?", pollID, userID).Delete(&models.PollBallot{}).Error
+}
+
+// refreshPollCounts recounts from the vote rows so the cached totals can't drift
+func refreshPollCounts(tx *gorm.DB, pollID uuid.UUID) error {
+	if err := tx.Exec(`
+		UPDATE poll_options SET vote_count = (
+			SELECT COUNT(*) FROM poll_votes WHERE poll_votes.option_id = poll_options.id
+		) WHERE poll_id = ?`, pollID).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`
+		UPDATE polls SET voter_count = (
+			SELECT COUNT(*) FROM poll_ballots WHERE poll_ballots.poll_id = polls.id
+		), updated_at = ? WHERE id = ?`, time.Now(), pollID).Error
+}
+
+func (r *pollRepositoryImpl) GetUserOptionIDs(ctx context.Context, pollID, userID uuid.UUID) ([]uuid.UUID, error) {
+	var optionIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.PollVote{}).
+		Joins("JOIN poll_ballots ON poll_ballots.id = poll_votes.ballot_id").
+		Where("poll_ballots.poll_id = ? AND poll_ballots.user_id = ?", pollID, userID).
+		Pluck("poll_votes.option_id", &optionIDs).Error
+	return optionIDs, err
+}
+
+func (r *pollRepositoryImpl) GetVoters(ctx context.Context, optionID uuid.UUID, offset, limit int) ([]*models.PollVote, int64, error) {
+	var votes []*models.PollVote
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PollVote{}).Where("option_id = ?", optionID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&votes).Error
+	return votes, total, err
+}
+
+func (r *pollRepositoryImpl) Close(ctx context.Context, pollID uuid.UUID, closedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Poll{}).
+		Where("id = ? AND closed_at IS NULL", pollID).
+		Updates(map[string]interface{}{
+			"closed_at":  closedAt,
+			"updated_at": closedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *pollRepositoryImpl) FindDueForClose(ctx context.Context, now time.Time, limit int) ([]*models.Poll, error) {
+	var polls []*models.Poll
+	err := r.db.WithContext(ctx).
+		Where("closed_at IS NULL AND closes_at IS NOT NULL AND closes_at <= ?", now).
+		Order("closes_at ASC").
+		Limit(limit).
+		Find(&polls).Error
+	return polls, err
+}
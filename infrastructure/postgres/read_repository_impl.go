@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type readRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewReadRepository(db *gorm.DB) repositories.ReadRepository {
+	return &readRepositoryImpl{db: db}
+}
+
+// effectiveReadAt is the SQL for the time up to which a user has read a topic; it expects
+// the topic_read_markers (m) and forum_read_markers (f) joins and the baseline as argument.
+// GREATEST ignores NULLs, so missing markers fall back to the baseline.
+const effectiveReadAt = "GREATEST(m.last_read_at, f.marked_at, ?::timestamptz)"
+
+func (r *readRepositoryImpl) MarkTopicRead(ctx context.Context, userID, topicID uuid.UUID, replyID *uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO topic_read_markers (user_id, topic_id, last_read_at, last_read_reply_id, updated_at)
+		VALUES (?, ?, ?, ?, NOW())
+		ON CONFLICT (user_id, topic_id) DO UPDATE SET
+			last_read_reply_id = CASE
+				WHEN EXCLUDED.last_read_at >= topic_read_markers.last_read_at
+				THEN COALESCE(EXCLUDED.last_read_reply_id, topic_read_markers.last_read_reply_id)
+				ELSE topic_read_markers.last_read_reply_id END,
+			last_read_at = GREATEST(topic_read_markers.last_read_at, EXCLUDED.last_read_at),
+			updated_at = NOW()`,
+		userID, topicID, at, replyID).Error
+}
+
+func (r *readRepositoryImpl) MarkForumRead(ctx context.Context, userID, forumID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO forum_read_markers (user_id, forum_id, marked_at, updated_at)
+		VALUES (?, ?, ?, NOW())
+		ON CONFLICT (user_id, forum_id) DO UPDATE SET
+			marked_at = GREATEST(forum_read_markers.marked_at, EXCLUDED.marked_at),
+			updated_at = NOW()`,
+		userID, forumID, at).Error
+}
+
+func (r *readRepositoryImpl) GetTopicReadStates(ctx context.Context, userID uuid.UUID, baseline time.Time, topicIDs []uuid.UUID) ([]repositories.TopicReadState, error) {
+	var states []repositories.TopicReadState
+	if len(topicIDs) == 0 {
+		return states, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("topics AS t").
+		Select(`t.id AS topic_id, m.last_read_at, m.last_read_reply_id,
+			COALESCE(t.last_activity_at, t.created_at) > `+effectiveReadAt+` AS is_unread,
+			(SELECT COUNT(*) FROM replies r
+				WHERE r.topic_id = t.id AND r.deleted_at IS NULL AND r.user_id <> ?
+				AND r.created_at > `+effectiveReadAt+`) AS new_reply_count`,
+			baseline, userID, baseline).
+		Joins("LEFT JOIN topic_read_markers m ON m.topic_id = t.id AND m.user_id = ?", userID).
+		Joins("LEFT JOIN forum_read_markers f ON f.forum_id = t.forum_id AND f.user_id = ?", userID).
+		Where("t.id IN ?", topicIDs).
+		Scan(&states).Error
+	return states, err
+}
+
+func (r *readRepositoryImpl) CountUnreadTopicsByForum(ctx context.Context, userID uuid.UUID, baseline time.Time, forumIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(forumIDs))
+	if len(forumIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ForumID     uuid.UUID
+		UnreadCount int
+	}
+	// The baseline bound lets idx_topics_forum_activity skip old topics before the marker joins
+	err := r.db.WithContext(ctx).
+		Table("topics AS t").
+		Select("t.forum_id, COUNT(*) AS unread_count").
+		Joins("LEFT JOIN topic_read_markers m ON m.topic_id = t.id AND m.user_id = ?", userID).
+		Joins("LEFT JOIN forum_read_markers f ON f.forum_id = t.forum_id AND f.user_id = ?", userID).
+		Where("t.forum_id IN ? AND t.deleted_at IS NULL AND t.last_activity_at > ?", forumIDs, baseline).
+		Where("t.last_activity_at > "+effectiveReadAt, baseline).
+		Group("t.forum_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ForumID] = row.UnreadCount
+	}
+	return counts, nil
+}
+
+func (r *readRepositoryImpl) FindFirstUnreadReply(ctx context.Context, userID, topicID uuid.UUID, baseline time.Time) (*models.Reply, error) {
+	var reply models.Reply
+	err := r.db.WithContext(ctx).
+		Table("replies AS r").
+		Select("r.*").
+		Joins("JOIN topics t ON t.id = r.topic_id").
+		Joins("LEFT JOIN topic_read_markers m ON m.topic_id = t.id AND m.user_id = ?", userID).
+		Joins("LEFT JOIN forum_read_markers f ON f.forum_id = t.forum_id AND f.user_id = ?", userID).
+		Where("r.topic_id = ? AND r.deleted_at IS NULL AND r.user_id <> ?", topicID, userID).
+		Where("r.created_at > "+effectiveReadAt, baseline).
+		Order("r.created_at ASC, r.id ASC").
+		Limit(1).
+		Scan(&reply).Error
+	if err != nil {
+		return nil, err
+	}
+	if reply.ID == uuid.Nil {
+		return nil, errors.New("no unread replies")
+	}
+	return &reply, nil
+}
+
+func (r *readRepositoryImpl) CountRepliesBefore(ctx context.Context, topicID uuid.UUID, at time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Reply{}).
+		Where("topic_id = ? AND deleted_at IS NULL AND created_at < ?", topicID, at).
+		Count(&count).Error
+	return count, err
+}
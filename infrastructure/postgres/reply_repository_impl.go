@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReplyRepositoryImpl struct {
@@ -16,8 +19,21 @@ func NewReplyRepository(db *gorm.DB) repositories.ReplyRepository {
 	return &ReplyRepositoryImpl{db: db}
 }
 
-func (r *ReplyRepositoryImpl) Create(ctx context.Context, reply *models.Reply) error {
-	return r.db.WithContext(ctx).Create(reply).Error
+func (r *ReplyRepositoryImpl) Create(ctx context.Context, reply *models.Reply, tasks ...*models.BackgroundTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reply).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Topic{}).
+			Where("id = ?", reply.TopicID).
+			UpdateColumns(map[string]interface{}{
+				"reply_count":      gorm.Expr("reply_count + ?", 1),
+				"last_activity_at": gorm.Expr("GREATEST(last_activity_at, ?)", reply.CreatedAt),
+			}).Error; err != nil {
+			return err
+		}
+		return insertTasks(tx, tasks)
+	})
 }
 
 func (r *ReplyRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Reply, error) {
@@ -65,6 +81,22 @@ func (r *ReplyRepositoryImpl) Update(ctx context.Context, id uuid.UUID, reply *m
 		Updates(reply).Error
 }
 
+func (r *ReplyRepositoryImpl) Edit(ctx context.Context, reply *models.Reply, revision *repositories.RevisionEdit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).
+			Where("id = ?", reply.ID).
+			Where("deleted_at IS NULL").
+			Updates(reply)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("reply not found")
+		}
+		return appendRevision(tx, revision)
+	})
+}
+
 func (r *ReplyRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).
 		Where("id = ?", id).
@@ -80,3 +112,150 @@ func (r *ReplyRepositoryImpl) Count(ctx context.Context, topicID uuid.UUID) (int
 		Count(&count).Error
 	return count, err
 }
+
+func (r *ReplyRepositoryImpl) UpdateLikeCount(ctx context.Context, id uuid.UUID, count int) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Reply{}).
+		Where("id = ?", id).
+		Update("like_count", count).Error
+}
+
+func (r *ReplyRepositoryImpl) GetThreadPage(ctx context.Context, topicID uuid.UUID, parentID *uuid.UUID, query repositories.ReplyThreadQuery) ([]*models.Reply, error) {
+	var replies []*models.Reply
+	db := r.db.WithContext(ctx).
+		Preload("User").
+		Where("topic_id = ?", topicID).
+		Where("deleted_at IS NULL")
+
+	if parentID != nil {
+		db = db.Where("parent_id = ?", *parentID)
+	} else {
+		db = db.Where("parent_id IS NULL")
+	}
+
+	if query.After != nil {
+		db = db.Where(replyAfterCursor(query.Sort, query.After))
+	} else {
+		db = db.Offset(query.Offset)
+	}
+
+	err := db.Order(replyOrder(query.Sort)).Limit(query.Limit).Find(&replies).Error
+	return replies, err
+}
+
+func (r *ReplyRepositoryImpl) GetChildren(ctx context.Context, parentIDs []uuid.UUID, sort models.ReplySort, perParent int) ([]*models.Reply, error) {
+	var replies []*models.Reply
+	if len(parentIDs) == 0 {
+		return replies, nil
+	}
+
+	// First perParent children of every parent in one query
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY %s) AS thread_rank
+			FROM replies WHERE parent_id IN ? AND deleted_at IS NULL
+		) ranked WHERE thread_rank <= ?`, replyOrder(sort)), parentIDs, perParent).
+		Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return replies, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Preload("User").
+		Where("id IN ?", ids).
+		Order(replyOrder(sort)).
+		Find(&replies).Error
+	return replies, err
+}
+
+func (r *ReplyRepositoryImpl) CountChildren(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ParentID uuid.UUID
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.Reply{}).
+		Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ?", parentIDs).
+		Where("deleted_at IS NULL").
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ParentID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *ReplyRepositoryImpl) CountSiblingsBefore(ctx context.Context, reply *models.Reply, sort models.ReplySort) (int64, error) {
+	var count int64
+	db := r.db.WithContext(ctx).
+		Model(&models.Reply{}).
+		Where("topic_id = ?", reply.TopicID).
+		Where("deleted_at IS NULL")
+
+	if reply.ParentID != nil {
+		db = db.Where("parent_id = ?", *reply.ParentID)
+	} else {
+		db = db.Where("parent_id IS NULL")
+	}
+
+	switch sort {
+	case models.ReplySortNewest:
+		db = db.Where("(created_at, id) > (?, ?)", reply.CreatedAt, reply.ID)
+	case models.ReplySortTop:
+		db = db.Where("(like_count > ? OR (like_count = ? AND (created_at, id) < (?, ?)))",
+			reply.LikeCount, reply.LikeCount, reply.CreatedAt, reply.ID)
+	default:
+		db = db.Where("(created_at, id) < (?, ?)", reply.CreatedAt, reply.ID)
+	}
+
+	err := db.Count(&count).Error
+	return count, err
+}
+
+func (r *ReplyRepositoryImpl) GetByIDsIncludingDeleted(ctx context.Context, ids []uuid.UUID) ([]*models.Reply, error) {
+	var replies []*models.Reply
+	if len(ids) == 0 {
+		return replies, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id IN ?", ids).
+		Find(&replies).Error
+	return replies, err
+}
+
+func replyOrder(sort models.ReplySort) string {
+	switch sort {
+	case models.ReplySortNewest:
+		return "created_at DESC, id DESC"
+	case models.ReplySortTop:
+		return "like_count DESC, created_at ASC, id ASC"
+	default:
+		return "created_at ASC, id ASC"
+	}
+}
+
+// replyAfterCursor matches the replies that come after the cursor in the given order
+func replyAfterCursor(sort models.ReplySort, cursor *repositories.ReplyCursor) clause.Expr {
+	switch sort {
+	case models.ReplySortNewest:
+		return gorm.Expr("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	case models.ReplySortTop:
+		return gorm.Expr("(like_count < ? OR (like_count = ? AND (created_at, id) > (?, ?)))",
+			cursor.LikeCount, cursor.LikeCount, cursor.CreatedAt, cursor.ID)
+	default:
+		return gorm.Expr("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type revisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) repositories.RevisionRepository {
+	return &revisionRepositoryImpl{db: db}
+}
+
+// appendRevision stores an edit's revision after its content update. That update holds the
+// content row's lock until commit, so concurrent edits number their versions one after another.
+func appendRevision(tx *gorm.DB, edit *repositories.RevisionEdit) error {
+	if edit == nil {
+		return nil
+	}
+
+	var latest int
+	if err := tx.Model(&models.ContentRevision{}).
+		Where("content_type = ? AND content_id = ?", edit.Revision.ContentType, edit.Revision.ContentID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	// Content posted before its first edit has no revisions yet, keep the original as version 1
+	if latest == 0 && edit.Original != nil {
+		edit.Original.Version = 1
+		if err := tx.Create(edit.Original).Error; err != nil {
+			return err
+		}
+		latest = 1
+	}
+
+	edit.Revision.Version = latest + 1
+	return tx.Create(edit.Revision).Error
+}
+
+func (r *revisionRepositoryImpl) Create(ctx context.Context, revision *models.ContentRevision) error {
+	return r.db.WithContext(ctx).Create(revision).Error
+}
+
+func (r *revisionRepositoryImpl) GetByVersion(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID, version int) (*models.ContentRevision, error) {
+	var revision models.ContentRevision
+	err := r.db.WithContext(ctx).
+		Preload("Editor").
+		Where("content_type = ? AND content_id = ? AND version = ?", contentType, contentID, version).
+		First(&revision).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *revisionRepositoryImpl) LatestVersion(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID) (int, error) {
+	var version int
+	err := r.db.WithContext(ctx).
+		Model(&models.ContentRevision{}).
+		Where("content_type = ? AND content_id = ?", contentType, contentID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	return version, err
+}
+
+func (r *revisionRepositoryImpl) FindByContent(ctx context.Context, contentType models.RevisionContentType, contentID uuid.UUID, offset, limit int) ([]*models.ContentRevision, int64, error) {
+	var revisions []*models.ContentRevision
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Model(&models.ContentRevision{}).
+		Where("content_type = ? AND content_id = ?", contentType, contentID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Editor").
+		Order("version DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&revisions).Error
+	return revisions, total, err
+}
@@ -2,10 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"gofiber-social/domain/models"
 	"gofiber-social/domain/repositories"
+	"time"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TopicRepositoryImpl struct {
@@ -16,8 +20,18 @@ func NewTopicRepository(db *gorm.DB) repositories.TopicRepository {
 	return &TopicRepositoryImpl{db: db}
 }
 
-func (r *TopicRepositoryImpl) Create(ctx context.Context, topic *models.Topic) error {
-	return r.db.WithContext(ctx).Create(topic).Error
+func (r *TopicRepositoryImpl) Create(ctx context.Context, topic *models.Topic, tasks ...*models.BackgroundTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(topic).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Forum{}).
+			Where("id = ?", topic.ForumID).
+			UpdateColumn("topic_count", gorm.Expr("topic_count + ?", 1)).Error; err != nil {
+			return err
+		}
+		return insertTasks(tx, tasks)
+	})
 }
 
 func (r *TopicRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Topic, error) {
@@ -31,9 +45,24 @@ func (r *TopicRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model
 	return &topic, err
 }
 
-func (r *TopicRepositoryImpl) GetByForumID(ctx context.Context, forumID uuid.UUID, offset, limit int) ([]*models.Topic, error) {
+func (r *TopicRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Topic, error) {
 	var topics []*models.Topic
+	if len(ids) == 0 {
+		return topics, nil
+	}
+
 	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Forum").
+		Where("id IN ?", ids).
+		Find(&topics).Error
+	return topics, err
+}
+
+func (r *TopicRepositoryImpl) GetByForumID(ctx context.Context, forumID uuid.UUID, filter repositories.TopicFilter, offset, limit int) ([]*models.Topic, error) {
+	var topics []*models.Topic
+	err := r.db.WithContext(ctx).
+		Scopes(withTopicFilter(filter)).
 		Preload("User").
 		Preload("Forum").
 		Where("forum_id = ?", forumID).
@@ -45,9 +74,10 @@ func (r *TopicRepositoryImpl) GetByForumID(ctx context.Context, forumID uuid.UUI
 	return topics, err
 }
 
-func (r *TopicRepositoryImpl) GetByTag(ctx context.Context, tag string, offset, limit int) ([]*models.Topic, error) {
+func (r *TopicRepositoryImpl) GetByTag(ctx context.Context, tag string, excludeForumIDs []uuid.UUID, offset, limit int) ([]*models.Topic, error) {
 	var topics []*models.Topic
 	err := r.db.WithContext(ctx).
+		Scopes(withoutForums(excludeForumIDs)).
 		Preload("User").
 		Preload("Forum").
 		Preload("Tags").
@@ -62,7 +92,7 @@ func (r *TopicRepositoryImpl) GetByTag(ctx context.Context, tag string, offset,
 	return topics, err
 }
 
-func (r *TopicRepositoryImpl) GetByTags(ctx context.Context, tags []string, offset, limit int) ([]*models.Topic, error) {
+func (r *TopicRepositoryImpl) GetByTags(ctx context.Context, tags []string, excludeForumIDs []uuid.UUID, offset, limit int) ([]*models.Topic, error) {
 	var topics []*models.Topic
 
 	// For multiple tags, we want topics that have ALL the specified tags (AND logic)
@@ -75,6 +105,7 @@ func (r *TopicRepositoryImpl) GetByTags(ctx context.Context, tags []string, offs
 		Having("COUNT(DISTINCT tags.name) = ?", len(tags))
 
 	err := r.db.WithContext(ctx).
+		Scopes(withoutForums(excludeForumIDs)).
 		Preload("User").
 		Preload("Forum").
 		Preload("Tags").
@@ -87,9 +118,10 @@ func (r *TopicRepositoryImpl) GetByTags(ctx context.Context, tags []string, offs
 	return topics, err
 }
 
-func (r *TopicRepositoryImpl) List(ctx context.Context, offset, limit int) ([]*models.Topic, error) {
+func (r *TopicRepositoryImpl) List(ctx context.Context, excludeForumIDs []uuid.UUID, filter repositories.TopicFilter, offset, limit int) ([]*models.Topic, error) {
 	var topics []*models.Topic
 	err := r.db.WithContext(ctx).
+		Scopes(withoutForums(excludeForumIDs), withTopicFilter(filter)).
 		Preload("User").
 		Preload("Forum").
 		Where("deleted_at IS NULL").
@@ -107,6 +139,35 @@ func (r *TopicRepositoryImpl) Update(ctx context.Context, id uuid.UUID, topic *m
 		Updates(topic).Error
 }
 
+func (r *TopicRepositoryImpl) Edit(ctx context.Context, topic *models.Topic, tags []*models.Tag, revision *repositories.RevisionEdit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).
+			Where("id = ?", topic.ID).
+			Where("deleted_at IS NULL").
+			Updates(topic)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("topic not found")
+		}
+
+		if tags != nil {
+			current := &models.Topic{ID: topic.ID}
+			if err := tx.Model(current).Association("Tags").Clear(); err != nil {
+				return err
+			}
+			if len(tags) > 0 {
+				if err := tx.Model(current).Association("Tags").Append(tags); err != nil {
+					return err
+				}
+			}
+		}
+
+		return appendRevision(tx, revision)
+	})
+}
+
 func (r *TopicRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).
 		Delete(&models.Topic{}, id).Error
@@ -123,7 +184,10 @@ func (r *TopicRepositoryImpl) IncrementReplyCount(ctx context.Context, id uuid.U
 	return r.db.WithContext(ctx).
 		Model(&models.Topic{}).
 		Where("id = ?", id).
-		UpdateColumn("reply_count", gorm.Expr("reply_count + ?", 1)).Error
+		UpdateColumns(map[string]interface{}{
+			"reply_count":      gorm.Expr("reply_count + ?", 1),
+			"last_activity_at": gorm.Expr("GREATEST(last_activity_at, ?)", time.Now()),
+		}).Error
 }
 
 func (r *TopicRepositoryImpl) DecrementReplyCount(ctx context.Context, id uuid.UUID) error {
@@ -162,29 +226,32 @@ func (r *TopicRepositoryImpl) Unlock(ctx context.Context, id uuid.UUID) error {
 		Update("is_locked", false).Error
 }
 
-func (r *TopicRepositoryImpl) Count(ctx context.Context) (int64, error) {
+func (r *TopicRepositoryImpl) Count(ctx context.Context, excludeForumIDs []uuid.UUID, filter repositories.TopicFilter) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&models.Topic{}).
+		Scopes(withoutForums(excludeForumIDs), withTopicFilter(filter)).
 		Where("deleted_at IS NULL").
 		Count(&count).Error
 	return count, err
 }
 
-func (r *TopicRepositoryImpl) CountByForumID(ctx context.Context, forumID uuid.UUID) (int64, error) {
+func (r *TopicRepositoryImpl) CountByForumID(ctx context.Context, forumID uuid.UUID, filter repositories.TopicFilter) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&models.Topic{}).
+		Scopes(withTopicFilter(filter)).
 		Where("forum_id = ?", forumID).
 		Where("deleted_at IS NULL").
 		Count(&count).Error
 	return count, err
 }
 
-func (r *TopicRepositoryImpl) CountByTag(ctx context.Context, tag string) (int64, error) {
+func (r *TopicRepositoryImpl) CountByTag(ctx context.Context, tag string, excludeForumIDs []uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&models.Topic{}).
+		Scopes(withoutForums(excludeForumIDs)).
 		Joins("JOIN topic_tags ON topics.id = topic_tags.topic_id").
 		Joins("JOIN tags ON topic_tags.tag_id = tags.id").
 		Where("tags.name = ?", tag).
@@ -193,7 +260,7 @@ func (r *TopicRepositoryImpl) CountByTag(ctx context.Context, tag string) (int64
 	return count, err
 }
 
-func (r *TopicRepositoryImpl) CountByTags(ctx context.Context, tags []string) (int64, error) {
+func (r *TopicRepositoryImpl) CountByTags(ctx context.Context, tags []string, excludeForumIDs []uuid.UUID) (int64, error) {
 	var count int64
 
 	// Count topics that have ALL the specified tags
@@ -206,19 +273,21 @@ func (r *TopicRepositoryImpl) CountByTags(ctx context.Context, tags []string) (i
 
 	err := r.db.WithContext(ctx).
 		Model(&models.Topic{}).
+		Scopes(withoutForums(excludeForumIDs)).
 		Where("topics.id IN (?)", subQuery).
 		Where("topics.deleted_at IS NULL").
 		Count(&count).Error
 	return count, err
 }
 
-func (r *TopicRepositoryImpl) Search(ctx context.Context, query string, offset, limit int) ([]*models.Topic, int64, error) {
+func (r *TopicRepositoryImpl) Search(ctx context.Context, query string, excludeForumIDs []uuid.UUID, offset, limit int) ([]*models.Topic, int64, error) {
 	var topics []*models.Topic
 	var count int64
 
 	searchQuery := "%" + query + "%"
 
 	dbQuery := r.db.WithContext(ctx).
+		Scopes(withoutForums(excludeForumIDs)).
 		Preload("User").
 		Preload("Forum").
 		Where("deleted_at IS NULL").
@@ -246,6 +315,23 @@ func (r *TopicRepositoryImpl) UpdateLikeCount(ctx context.Context, topicID uuid.
 		Update("like_count", count).Error
 }
 
+// SetAcceptedReply marks (or with nil clears) the accepted answer of a topic
+func (r *TopicRepositoryImpl) SetAcceptedReply(ctx context.Context, topicID uuid.UUID, replyID *uuid.UUID) error {
+	var acceptedAt *time.Time
+	if replyID != nil {
+		now := time.Now()
+		acceptedAt = &now
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&models.Topic{}).
+		Where("id = ?", topicID).
+		UpdateColumns(map[string]interface{}{
+			"accepted_reply_id": replyID,
+			"accepted_at":       acceptedAt,
+		}).Error
+}
+
 func (r *TopicRepositoryImpl) GetTotalCount(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -280,6 +366,16 @@ func (r *TopicRepositoryImpl) RemoveAllTags(ctx context.Context, topicID uuid.UU
 	return r.db.WithContext(ctx).Model(&topic).Association("Tags").Clear()
 }
 
+func (r *TopicRepositoryImpl) GetTags(ctx context.Context, topicID uuid.UUID) ([]*models.Tag, error) {
+	var tags []*models.Tag
+	err := r.db.WithContext(ctx).
+		Joins("JOIN topic_tags ON topic_tags.tag_id = tags.id").
+		Where("topic_tags.topic_id = ?", topicID).
+		Order("tags.name ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
 func (r *TopicRepositoryImpl) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -289,3 +385,277 @@ func (r *TopicRepositoryImpl) CountByUserID(ctx context.Context, userID uuid.UUI
 		Count(&count).Error
 	return count, err
 }
+
+// MoveToForum moves a topic and shifts one from the old forum's topic count to the new one's
+func (r *TopicRepositoryImpl) MoveToForum(ctx context.Context, topic *models.Topic, forumID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Topic{}).
+			Where("id = ?", topic.ID).
+			UpdateColumn("forum_id", forumID).Error; err != nil {
+			return err
+		}
+		if err := decrementTopicCount(tx, topic.ForumID); err != nil {
+			return err
+		}
+		return tx.Model(&models.Forum{}).
+			Where("id = ?", forumID).
+			UpdateColumn("topic_count", gorm.Expr("topic_count + ?", 1)).Error
+	})
+}
+
+func (r *TopicRepositoryImpl) Merge(ctx context.Context, source *models.Topic, opening *models.Reply, redirect *models.TopicRedirect) error {
+	targetID := redirect.ToTopicID
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(opening).Error; err != nil {
+			return err
+		}
+
+		// Source's thread hangs under its former first post, so it stays together in the target
+		if err := tx.Model(&models.Reply{}).
+			Where("topic_id = ? AND parent_id IS NULL", source.ID).
+			UpdateColumn("parent_id", opening.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Reply{}).
+			Where("topic_id = ?", source.ID).
+			UpdateColumn("topic_id", targetID).Error; err != nil {
+			return err
+		}
+
+		// Watchers of the source keep following the conversation
+		if err := tx.Exec(`
+			INSERT INTO topic_watches (user_id, topic_id, is_watching, created_at, updated_at)
+			SELECT user_id, ?, is_watching, NOW(), NOW() FROM topic_watches WHERE topic_id = ?
+			ON CONFLICT (user_id, topic_id) DO NOTHING`, targetID, source.ID).Error; err != nil {
+			return err
+		}
+
+		// What belonged to the source's first post follows it to the opening reply
+		if err := tx.Exec("UPDATE likes SET topic_id = NULL, reply_id = ? WHERE topic_id = ?", opening.ID, source.ID).Error; err != nil {
+			return err
+		}
+		if err := moveRevisions(tx, models.RevisionContentTopic, source.ID, models.RevisionContentReply, opening.ID); err != nil {
+			return err
+		}
+		if err := moveFileReferences(tx, models.FileReferenceTopicContent, source.ID, models.FileReferenceReplyContent, opening.ID); err != nil {
+			return err
+		}
+		if err := recountLikes(tx, "replies", "reply_id", opening.ID); err != nil {
+			return err
+		}
+
+		// The poll and bookmarks of the topic itself go to the target (callers make sure it has no poll)
+		if err := tx.Model(&models.Poll{}).
+			Where("topic_id = ?", source.ID).
+			UpdateColumn("topic_id", targetID).Error; err != nil {
+			return err
+		}
+		if err := moveBookmarks(tx, models.BookmarkTargetTopic, source.ID, models.BookmarkTargetTopic, targetID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ?", source.ID).Delete(&models.Topic{}).Error; err != nil {
+			return err
+		}
+		if err := decrementTopicCount(tx, source.ForumID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.TopicRedirect{}).
+			Where("to_topic_id = ?", source.ID).
+			UpdateColumn("to_topic_id", targetID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(redirect).Error; err != nil {
+			return err
+		}
+
+		return recountReplies(tx, targetID)
+	})
+}
+
+func (r *TopicRepositoryImpl) Split(ctx context.Context, source, topic *models.Topic, openingID uuid.UUID, replyIDs []uuid.UUID) error {
+	moved := make([]uuid.UUID, 0, len(replyIDs))
+	for _, id := range replyIDs {
+		if id != openingID {
+			moved = append(moved, id)
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(topic).Error; err != nil {
+			return err
+		}
+
+		// Replies left behind whose parent is leaving climb to the nearest ancestor that stays,
+		// one level per pass
+		for i := 0; i < maxSplitDepth; i++ {
+			result := tx.Exec(`
+				UPDATE replies c SET parent_id = p.parent_id
+				FROM replies p
+				WHERE c.parent_id = p.id AND p.id IN ? AND c.id NOT IN ?`, replyIDs, replyIDs)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				break
+			}
+		}
+
+		if len(moved) > 0 {
+			// Replies whose parent didn't come along start a new thread in the new topic
+			if err := tx.Model(&models.Reply{}).
+				Where("id IN ?", moved).
+				Where("parent_id IS NULL OR parent_id NOT IN ?", moved).
+				UpdateColumn("parent_id", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Reply{}).
+				Where("id IN ?", moved).
+				UpdateColumn("topic_id", topic.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		// The opening reply lives on as the new topic's first post: its row stays (soft-deleted)
+		// for quotes pointing at it, everything else moves to the topic
+		if err := tx.Model(&models.Reply{}).
+			Where("id = ?", openingID).
+			UpdateColumn("deleted_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE likes SET reply_id = NULL, topic_id = ? WHERE reply_id = ?", topic.ID, openingID).Error; err != nil {
+			return err
+		}
+		if err := moveRevisions(tx, models.RevisionContentReply, openingID, models.RevisionContentTopic, topic.ID); err != nil {
+			return err
+		}
+		if err := moveBookmarks(tx, models.BookmarkTargetReply, openingID, models.BookmarkTargetTopic, topic.ID); err != nil {
+			return err
+		}
+		if err := moveFileReferences(tx, models.FileReferenceReplyContent, openingID, models.FileReferenceTopicContent, topic.ID); err != nil {
+			return err
+		}
+		if err := recountLikes(tx, "topics", "topic_id", topic.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Topic{}).
+			Where("id = ? AND accepted_reply_id IN ?", source.ID, replyIDs).
+			UpdateColumns(map[string]interface{}{
+				"accepted_reply_id": nil,
+				"accepted_at":       nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Forum{}).
+			Where("id = ?", topic.ForumID).
+			UpdateColumn("topic_count", gorm.Expr("topic_count + ?", 1)).Error; err != nil {
+			return err
+		}
+
+		if err := recountReplies(tx, source.ID); err != nil {
+			return err
+		}
+		return recountReplies(tx, topic.ID)
+	})
+}
+
+func (r *TopicRepositoryImpl) GetRedirect(ctx context.Context, fromTopicID uuid.UUID) (*models.TopicRedirect, error) {
+	var redirect models.TopicRedirect
+	err := r.db.WithContext(ctx).
+		Where("from_topic_id = ?", fromTopicID).
+		First(&redirect).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("topic redirect not found")
+		}
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// maxSplitDepth bounds the re-parenting passes of Split (reply trees are far shallower)
+const maxSplitDepth = 100
+
+func decrementTopicCount(tx *gorm.DB, forumID uuid.UUID) error {
+	return tx.Model(&models.Forum{}).
+		Where("id = ?", forumID).
+		Where("topic_count > ?", 0).
+		UpdateColumn("topic_count", gorm.Expr("topic_count - ?", 1)).Error
+}
+
+// recountReplies recomputes a topic's reply count and last activity after replies moved in or out
+// moveRevisions hands the edit history of a post to the post that replaces it
+func moveRevisions(tx *gorm.DB, fromType models.RevisionContentType, fromID uuid.UUID, toType models.RevisionContentType, toID uuid.UUID) error {
+	return tx.Model(&models.ContentRevision{}).
+		Where("content_type = ? AND content_id = ?", fromType, fromID).
+		UpdateColumns(map[string]interface{}{
+			"content_type": toType,
+			"content_id":   toID,
+		}).Error
+}
+
+// moveBookmarks re-points bookmarks; a user who already bookmarked the destination keeps that one
+func moveBookmarks(tx *gorm.DB, fromType models.BookmarkTargetType, fromID uuid.UUID, toType models.BookmarkTargetType, toID uuid.UUID) error {
+	if err := tx.Exec(`
+		UPDATE bookmarks SET target_type = ?, target_id = ?
+		WHERE target_type = ? AND target_id = ?
+			AND NOT EXISTS (SELECT 1 FROM bookmarks b WHERE b.user_id = bookmarks.user_id AND b.target_type = ? AND b.target_id = ?)`,
+		toType, toID, fromType, fromID, toType, toID).Error; err != nil {
+		return err
+	}
+	return tx.Where("target_type = ? AND target_id = ?", fromType, fromID).Delete(&models.Bookmark{}).Error
+}
+
+// moveFileReferences keeps embedded images referenced by the post that now shows them
+func moveFileReferences(tx *gorm.DB, fromType models.FileReferenceType, fromID uuid.UUID, toType models.FileReferenceType, toID uuid.UUID) error {
+	if err := tx.Exec(`
+		INSERT INTO file_references (file_id, resource_type, resource_id, created_at)
+		SELECT file_id, ?, ?, NOW() FROM file_references WHERE resource_type = ? AND resource_id = ?
+		ON CONFLICT (file_id, resource_type, resource_id) DO NOTHING`, toType, toID, fromType, fromID).Error; err != nil {
+		return err
+	}
+	return tx.Where("resource_type = ? AND resource_id = ?", fromType, fromID).Delete(&models.FileReference{}).Error
+}
+
+// recountLikes sets like_count of a topic or reply from the likes pointing at it
+func recountLikes(tx *gorm.DB, table, column string, id uuid.UUID) error {
+	return tx.Exec(fmt.Sprintf("UPDATE %s SET like_count = (SELECT COUNT(*) FROM likes WHERE likes.%s = %s.id) WHERE id = ?", table, column, table), id).Error
+}
+
+func recountReplies(tx *gorm.DB, topicID uuid.UUID) error {
+	return tx.Exec(`
+		UPDATE topics SET
+			reply_count = (SELECT COUNT(*) FROM replies WHERE replies.topic_id = topics.id AND replies.deleted_at IS NULL),
+			last_activity_at = GREATEST(topics.created_at,
+				(SELECT MAX(replies.created_at) FROM replies WHERE replies.topic_id = topics.id AND replies.deleted_at IS NULL))
+		WHERE id = ?`, topicID).Error
+}
+
+// withoutForums hides topics of forums the viewer may not see
+func withoutForums(forumIDs []uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(forumIDs) == 0 {
+			return db
+		}
+		return db.Where("topics.forum_id NOT IN ?", forumIDs)
+	}
+}
+
+// withTopicFilter applies the solved filter; unsolved means a question in a Q&A forum without an accepted answer
+func withTopicFilter(filter repositories.TopicFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Solved == nil {
+			return db
+		}
+		if *filter.Solved {
+			return db.Where("topics.accepted_reply_id IS NOT NULL")
+		}
+		return db.Where("topics.accepted_reply_id IS NULL").
+			Where("topics.forum_id IN (SELECT id FROM forums WHERE is_qa = ?)", true)
+	}
+}
+
@@ -79,6 +79,15 @@ func (r *UserRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model
 	return &user, nil
 }
 
+func (r *UserRepositoryImpl) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error) {
+	var users []*models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
 func (r *UserRepositoryImpl) UpdateFollowerCount(ctx context.Context, userID uuid.UUID, count int) error {
 	return r.db.WithContext(ctx).
 		Model(&models.User{}).
@@ -111,11 +111,47 @@ func (r *videoRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID
 	return videos, totalCount, err
 }
 
-func (r *videoRepositoryImpl) IncrementViewCount(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).
-		Model(&models.Video{}).
+func (r *videoRepositoryImpl) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Video, error) {
+	var videos []models.Video
+	if len(ids) == 0 {
+		return videos, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id IN ? AND is_active = ?", ids, true).
+		Find(&videos).Error
+	return videos, err
+}
+
+func (r *videoRepositoryImpl) AddViewCounts(ctx context.Context, counts map[uuid.UUID]int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, count := range counts {
+			err := tx.Model(&models.Video{}).
+				Where("id = ?", id).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", count)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *videoRepositoryImpl) FindByIDIncludingInactive(ctx context.Context, id uuid.UUID) (*models.Video, error) {
+	var video models.Video
+	err := r.db.WithContext(ctx).
+		Preload("User").
 		Where("id = ?", id).
-		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
+		First(&video).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("video not found")
+		}
+		return nil, err
+	}
+	return &video, nil
 }
 
 func (r *videoRepositoryImpl) FindAllIncludingInactive(ctx context.Context, params *dto.VideoQueryParams) ([]models.Video, int64, error) {
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type videoViewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewVideoViewRepository(db *gorm.DB) repositories.VideoViewRepository {
+	return &videoViewRepositoryImpl{db: db}
+}
+
+func (r *videoViewRepositoryImpl) Create(ctx context.Context, view *models.VideoView) error {
+	return r.db.WithContext(ctx).Create(view).Error
+}
+
+func (r *videoViewRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.VideoView, error) {
+	var view models.VideoView
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&view).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("view not found")
+		}
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (r *videoViewRepositoryImpl) RecordProgress(ctx context.Context, id uuid.UUID, watchedSeconds, position int, completed bool, at time.Time) error {
+	updates := map[string]interface{}{
+		"watch_time":    gorm.Expr("watch_time + ?", watchedSeconds),
+		"max_position":  gorm.Expr("GREATEST(max_position, ?)", position),
+		"last_event_at": at,
+	}
+	if completed {
+		updates["completed"] = true
+		updates["completed_at"] = gorm.Expr("COALESCE(completed_at, ?)", at)
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&models.VideoView{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type watchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWatchRepository(db *gorm.DB) repositories.WatchRepository {
+	return &watchRepositoryImpl{db: db}
+}
+
+func (r *watchRepositoryImpl) WatchTopic(ctx context.Context, userID, topicID uuid.UUID) error {
+	return r.setTopicWatch(ctx, userID, topicID, true)
+}
+
+func (r *watchRepositoryImpl) UnwatchTopic(ctx context.Context, userID, topicID uuid.UUID) error {
+	return r.setTopicWatch(ctx, userID, topicID, false)
+}
+
+// setTopicWatch is raw SQL because GORM would replace a false IsWatching with the column default
+func (r *watchRepositoryImpl) setTopicWatch(ctx context.Context, userID, topicID uuid.UUID, watching bool) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO topic_watches (user_id, topic_id, is_watching, created_at, updated_at)
+		VALUES (?, ?, ?, NOW(), NOW())
+		ON CONFLICT (user_id, topic_id) DO UPDATE SET is_watching = EXCLUDED.is_watching, updated_at = NOW()`,
+		userID, topicID, watching).Error
+}
+
+func (r *watchRepositoryImpl) AutoWatchTopic(ctx context.Context, userID, topicID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.TopicWatch{UserID: userID, TopicID: topicID, IsWatching: true}).Error
+}
+
+func (r *watchRepositoryImpl) IsWatchingTopic(ctx context.Context, userID, topicID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.TopicWatch{}).
+		Where("user_id = ? AND topic_id = ? AND is_watching = ?", userID, topicID, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *watchRepositoryImpl) GetTopicWatcherIDs(ctx context.Context, topicID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.TopicWatch{}).
+		Where("topic_id = ? AND is_watching = ?", topicID, true).
+		Order("user_id").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *watchRepositoryImpl) FindWatchedTopics(ctx context.Context, userID uuid.UUID, filter repositories.WatchedTopicFilter, offset, limit int) ([]repositories.WatchedTopic, int64, error) {
+	// Unread = replies by others after the topic or forum read marker, or after the watch
+	// started when the topic was never opened since
+	query := r.db.WithContext(ctx).
+		Table("topic_watches AS w").
+		Select("w.topic_id, COUNT(r.id) AS unread_count, m.last_read_at").
+		Joins("JOIN topics t ON t.id = w.topic_id AND t.deleted_at IS NULL").
+		Joins("LEFT JOIN topic_read_markers m ON m.user_id = w.user_id AND m.topic_id = w.topic_id").
+		Joins("LEFT JOIN forum_read_markers f ON f.user_id = w.user_id AND f.forum_id = t.forum_id").
+		Joins("LEFT JOIN replies r ON r.topic_id = w.topic_id AND r.deleted_at IS NULL AND r.user_id <> w.user_id AND r.created_at > GREATEST(m.last_read_at, f.marked_at, w.created_at)").
+		Where("w.user_id = ? AND w.is_watching = ?", userID, true).
+		Group("w.topic_id, m.last_read_at, t.updated_at")
+	if len(filter.ExcludeForumIDs) > 0 {
+		query = query.Where("t.forum_id NOT IN ?", filter.ExcludeForumIDs)
+	}
+	if filter.UnreadOnly {
+		query = query.Having("COUNT(r.id) > 0")
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS watched", query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var watched []repositories.WatchedTopic
+	err := query.
+		Order("MAX(r.created_at) DESC NULLS LAST, t.updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&watched).Error
+	return watched, total, err
+}
+
+func (r *watchRepositoryImpl) WatchForum(ctx context.Context, userID, forumID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ForumWatch{UserID: userID, ForumID: forumID}).Error
+}
+
+func (r *watchRepositoryImpl) UnwatchForum(ctx context.Context, userID, forumID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND forum_id = ?", userID, forumID).
+		Delete(&models.ForumWatch{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *watchRepositoryImpl) IsWatchingForum(ctx context.Context, userID, forumID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.ForumWatch{}).
+		Where("user_id = ? AND forum_id = ?", userID, forumID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *watchRepositoryImpl) GetForumWatcherIDs(ctx context.Context, forumID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.ForumWatch{}).
+		Where("forum_id = ?", forumID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *watchRepositoryImpl) FindWatchedForumIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var forumIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.ForumWatch{}).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Pluck("forum_id", &forumIDs).Error
+	return forumIDs, err
+}
+
+func (r *watchRepositoryImpl) GetPreference(ctx context.Context, userID uuid.UUID) (*models.WatchPreference, error) {
+	var preference models.WatchPreference
+	if err := r.db.WithContext(ctx).First(&preference, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrWatchPreferenceNotFound
+		}
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *watchRepositoryImpl) SavePreference(ctx context.Context, preference *models.WatchPreference) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"auto_watch_on_post", "auto_watch_on_reply", "updated_at"}),
+		}).
+		Create(preference).Error
+}
@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// LeaderElector keeps one of several replicas as leader through a Redis lease. The leader
+// renews the lease every third of its TTL; when it stops, another replica takes over once the
+// lease expires.
+//
+// When Redis can't be reached at start-up the node runs standalone: it leads without a lease
+// and keeps trying Redis, joining the election as soon as Redis answers again.
+type LeaderElector struct {
+	redis  *RedisClient
+	key    string
+	nodeID string
+	ttl    time.Duration
+
+	mu         sync.RWMutex
+	isLeader   bool
+	standalone bool
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+const defaultLeaderTTL = 15 * time.Second
+
+// NewLeaderElector campaigns for key as nodeID; a node ID is generated from the hostname when empty
+func NewLeaderElector(client *RedisClient, key, nodeID string, ttl time.Duration) *LeaderElector {
+	if ttl <= 0 {
+		ttl = defaultLeaderTTL
+	}
+	if nodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			hostname = "node"
+		}
+		nodeID = fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+	}
+
+	return &LeaderElector{
+		redis:  client,
+		key:    key,
+		nodeID: nodeID,
+		ttl:    ttl,
+	}
+}
+
+// Start campaigns right away and then keeps campaigning or renewing in the background
+func (e *LeaderElector) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.mu.Lock()
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	e.mu.Unlock()
+
+	if err := e.campaign(ctx); err != nil {
+		log.Printf("Warning: Redis unavailable for leader election of %s, running node %s standalone until it is back: %v", e.key, e.nodeID, err)
+		e.mu.Lock()
+		e.isLeader = true
+		e.standalone = true
+		e.mu.Unlock()
+	}
+
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = e.campaign(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends the campaign and hands the lease back so another replica can take over at once
+func (e *LeaderElector) Stop() {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel = nil
+	e.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+
+	if e.IsLeader() && !e.Standalone() {
+		ctx, cancelResign := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancelResign()
+		if _, err := e.redis.DeleteIfEquals(ctx, e.key, e.nodeID); err != nil {
+			log.Printf("Warning: Failed to resign leadership of %s: %v", e.key, err)
+		}
+	}
+	e.mu.Lock()
+	e.isLeader = false
+	e.standalone = false
+	e.mu.Unlock()
+}
+
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.isLeader
+}
+
+// Standalone reports leading without a Redis lease because Redis was down at start-up
+func (e *LeaderElector) Standalone() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.standalone
+}
+
+func (e *LeaderElector) NodeID() string {
+	return e.nodeID
+}
+
+// Leader returns the node holding the lease, or "" while nobody does
+func (e *LeaderElector) Leader(ctx context.Context) (string, error) {
+	var leader string
+	err := e.redis.Get(ctx, e.key, &leader)
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return leader, err
+}
+
+// campaign returns the Redis error when the lease could not be asked for
+func (e *LeaderElector) campaign(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, e.ttl/3)
+	defer cancel()
+
+	if e.Standalone() {
+		acquired, err := e.redis.SetNX(ctx, e.key, e.nodeID, e.ttl)
+		if err != nil {
+			return err
+		}
+		// Redis is back: keep leading under a lease, or step down to the replica that holds it
+		log.Printf("Redis is back for %s, node %s leaves standalone mode (leader: %t)", e.key, e.nodeID, acquired)
+		e.mu.Lock()
+		e.isLeader = acquired
+		e.standalone = false
+		e.mu.Unlock()
+		return nil
+	}
+
+	if e.IsLeader() {
+		renewed, err := e.redis.ExpireIfEquals(ctx, e.key, e.nodeID, e.ttl)
+		if err != nil || !renewed {
+			// Without a confirmed lease another replica may already lead; step down to be safe
+			log.Printf("Lost leadership of %s (node %s): renewed=%t err=%v", e.key, e.nodeID, renewed, err)
+			e.setLeader(false)
+		}
+		return err
+	}
+
+	acquired, err := e.redis.SetNX(ctx, e.key, e.nodeID, e.ttl)
+	if err != nil {
+		log.Printf("Warning: Leader election for %s failed: %v", e.key, err)
+		return err
+	}
+	if acquired {
+		log.Printf("✓ Node %s is now leader of %s", e.nodeID, e.key)
+		e.setLeader(true)
+	}
+	return nil
+}
+
+func (e *LeaderElector) setLeader(isLeader bool) {
+	e.mu.Lock()
+	e.isLeader = isLeader
+	e.mu.Unlock()
+}
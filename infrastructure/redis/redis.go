@@ -65,6 +65,15 @@ func (r *RedisClient) Increment(ctx context.Context, key string) (int64, error)
 	return r.client.Incr(ctx, key).Result()
 }
 
+// GetInt64 reads a counter written by Increment; a missing key reads as 0
+func (r *RedisClient) GetInt64(ctx context.Context, key string) (int64, error) {
+	value, err := r.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return value, err
+}
+
 func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
 	return r.client.Expire(ctx, key, expiration).Err()
 }
@@ -73,6 +82,156 @@ func (r *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error
 	return r.client.TTL(ctx, key).Result()
 }
 
+func (r *RedisClient) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	return r.client.HIncrBy(ctx, key, field, incr).Result()
+}
+
+func (r *RedisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
+}
+
+func (r *RedisClient) Rename(ctx context.Context, key, newKey string) error {
+	return r.client.Rename(ctx, key, newKey).Err()
+}
+
+// HGetFromKeys reads one field of several hashes in a single round trip; missing values are ""
+func (r *RedisClient) HGetFromKeys(ctx context.Context, keys []string, field string) ([]string, error) {
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.HGet(ctx, key, field)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	values := make([]string, len(keys))
+	for i, cmd := range cmds {
+		values[i] = cmd.Val()
+	}
+	return values, nil
+}
+
+// ReplaceHash swaps all fields of a hash at once and sets its expiration
+func (r *RedisClient) ReplaceHash(ctx context.Context, key string, fields map[string]interface{}, expiration time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(fields) > 0 {
+			pipe.HSet(ctx, key, fields)
+			pipe.Expire(ctx, key, expiration)
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *RedisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// ZAddCapped adds a member and keeps only the maxLen highest-scored members, refreshing the
+// expiration of the set
+func (r *RedisClient) ZAddCapped(ctx context.Context, key string, score float64, member string, maxLen int64, expiration time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: score, Member: member})
+		pipe.ZRemRangeByRank(ctx, key, 0, -maxLen-1)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	return err
+}
+
+func (r *RedisClient) ZRangeByScore(ctx context.Context, key, min, max string) ([]string, error) {
+	return r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: max}).Result()
+}
+
+func (r *RedisClient) ZRem(ctx context.Context, key string, members ...interface{}) error {
+	return r.client.ZRem(ctx, key, members...).Err()
+}
+
+func (r *RedisClient) ZRemRangeByScore(ctx context.Context, key, min, max string) error {
+	return r.client.ZRemRangeByScore(ctx, key, min, max).Err()
+}
+
+// Publish sends value as JSON to a pub/sub channel
+func (r *RedisClient) Publish(ctx context.Context, channel string, value interface{}) error {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return r.client.Publish(ctx, channel, jsonValue).Err()
+}
+
+// Subscribe delivers the payloads published on channel until ctx is cancelled. The connection
+// is re-established by the driver if Redis restarts; messages sent meanwhile are lost.
+func (r *RedisClient) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	pubsub := r.client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	payloads := make(chan string)
+	go func() {
+		defer close(payloads)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case payloads <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return payloads, nil
+}
+
+// compareAndExpire renews a key's TTL only while it still holds the expected value
+var compareAndExpire = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// compareAndDelete deletes a key only while it still holds the expected value
+var compareAndDelete = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// ExpireIfEquals renews key's TTL if it holds value (as stored by Set/SetNX); reports whether it did
+func (r *RedisClient) ExpireIfEquals(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	result, err := compareAndExpire.Run(ctx, r.client, []string{key}, string(jsonValue), expiration.Milliseconds()).Int64()
+	return result == 1, err
+}
+
+// DeleteIfEquals deletes key if it holds value (as stored by Set/SetNX); reports whether it did
+func (r *RedisClient) DeleteIfEquals(ctx context.Context, key string, value interface{}) (bool, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	result, err := compareAndDelete.Run(ctx, r.client, []string{key}, string(jsonValue)).Int64()
+	return result == 1, err
+}
+
 func (r *RedisClient) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
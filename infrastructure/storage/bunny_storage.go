@@ -2,86 +2,134 @@ package storage
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
 type BunnyStorage interface {
-	UploadFile(file io.Reader, path string, contentType string) (string, error)
-	DeleteFile(path string) error
-	GetFileURL(path string) string
+	// UploadFile stores a file in the public zone, or in the secure zone when protected, and
+	// returns its URL. Secure URLs only work once signed with GetSignedURL.
+	UploadFile(file io.Reader, path string, contentType string, protected bool) (string, error)
+	DeleteFile(path string, protected bool) error
+	// MoveToSecure moves a public file into the secure zone and returns its new URL
+	MoveToSecure(path string) (string, error)
+	GetFileURL(path string, protected bool) string
+	// GetSignedURL returns a token-authenticated URL that stops working at expiresAt
+	GetSignedURL(path string, expiresAt time.Time) string
+	// GetPathFromURL returns the storage path of a URL served by either of our CDNs, or "" for foreign URLs
+	GetPathFromURL(fileURL string) string
 }
 
 type BunnyStorageImpl struct {
-	storageZone string
-	accessKey   string
-	baseURL     string
-	cdnURL      string
+	storageZone       string
+	accessKey         string
+	baseURL           string
+	cdnURL            string
+	secureStorageZone string
+	secureAccessKey   string
+	secureCDNURL      string
+	tokenKey          string
 }
 
 type BunnyConfig struct {
-	StorageZone string
-	AccessKey   string
-	BaseURL     string
-	CDNUrl      string
+	StorageZone       string
+	AccessKey         string
+	BaseURL           string
+	CDNUrl            string
+	SecureStorageZone string // storage zone whose only pull zone is SecureCDNUrl
+	SecureAccessKey   string
+	SecureCDNUrl      string // pull zone with token authentication enabled
+	TokenKey          string // token authentication key of the secure pull zone
 }
 
-func NewBunnyStorage(config BunnyConfig) BunnyStorage {
-	return &BunnyStorageImpl{
-		storageZone: config.StorageZone,
-		accessKey:   config.AccessKey,
-		baseURL:     config.BaseURL,
-		cdnURL:      config.CDNUrl,
+// NewBunnyStorage refuses a configuration without the secure zone: protected media would
+// otherwise be served by permanent, unsigned URLs.
+func NewBunnyStorage(config BunnyConfig) (BunnyStorage, error) {
+	if config.SecureStorageZone == "" || config.SecureAccessKey == "" || config.SecureCDNUrl == "" || config.TokenKey == "" {
+		return nil, errors.New("secure storage zone, access key, CDN URL and token key are required for protected media")
 	}
+
+	return &BunnyStorageImpl{
+		storageZone:       config.StorageZone,
+		accessKey:         config.AccessKey,
+		baseURL:           config.BaseURL,
+		cdnURL:            config.CDNUrl,
+		secureStorageZone: config.SecureStorageZone,
+		secureAccessKey:   config.SecureAccessKey,
+		secureCDNURL:      config.SecureCDNUrl,
+		tokenKey:          config.TokenKey,
+	}, nil
 }
 
-func (b *BunnyStorageImpl) UploadFile(file io.Reader, path string, contentType string) (string, error) {
-	url := fmt.Sprintf("%s/%s/%s", b.baseURL, b.storageZone, path)
+// zone returns the storage zone and its access key
+func (b *BunnyStorageImpl) zone(protected bool) (string, string) {
+	if protected {
+		return b.secureStorageZone, b.secureAccessKey
+	}
+	return b.storageZone, b.accessKey
+}
 
+func (b *BunnyStorageImpl) UploadFile(file io.Reader, path string, contentType string, protected bool) (string, error) {
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(fileBytes))
-	if err != nil {
+	if err := b.put(path, bytes.NewReader(fileBytes), int64(len(fileBytes)), contentType, protected); err != nil {
 		return "", err
 	}
 
+	fileURL := b.GetFileURL(path, protected)
+	return fileURL, nil
+}
+
+func (b *BunnyStorageImpl) put(path string, body io.Reader, size int64, contentType string, protected bool) error {
+	zone, accessKey := b.zone(protected)
+	url := fmt.Sprintf("%s/%s/%s", b.baseURL, zone, path)
+
+	req, err := http.NewRequest("PUT", url, body)
+	if err != nil {
+		return err
+	}
+
 	// แก้ไข header name
-	req.Header.Set("AccessKey", b.accessKey) // หรือลอง Authorization
+	req.Header.Set("AccessKey", accessKey) // หรือลอง Authorization
 	// req.Header.Set("Authorization", "Bearer " + b.accessKey)  // ทางเลือก
 	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Content-Length", fmt.Sprintf("%d", len(fileBytes)))
+	req.ContentLength = size
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return err
 	}
 	defer resp.Body.Close()
 
 	// เพิ่ม debug
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("upload failed with status: %d, body: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("upload failed with status: %d, body: %s", resp.StatusCode, string(body))
 	}
 
-	fileURL := b.GetFileURL(path)
-	return fileURL, nil
+	return nil
 }
 
-func (b *BunnyStorageImpl) DeleteFile(path string) error {
-	url := fmt.Sprintf("%s/%s/%s", b.baseURL, b.storageZone, path)
+func (b *BunnyStorageImpl) DeleteFile(path string, protected bool) error {
+	zone, accessKey := b.zone(protected)
+	url := fmt.Sprintf("%s/%s/%s", b.baseURL, zone, path)
 
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("AccessKey", b.accessKey)
+	req.Header.Set("AccessKey", accessKey)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -90,16 +138,79 @@ func (b *BunnyStorageImpl) DeleteFile(path string) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+	// 404 means the object is already gone, which is what the caller wanted
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
 		return fmt.Errorf("delete failed with status: %d", resp.StatusCode)
 	}
 
 	return nil
 }
 
-func (b *BunnyStorageImpl) GetFileURL(path string) string {
+// MoveToSecure copies the object into the secure zone before deleting the public one, so a
+// failure leaves it readable where it was
+func (b *BunnyStorageImpl) MoveToSecure(path string) (string, error) {
+	url := fmt.Sprintf("%s/%s/%s", b.baseURL, b.storageZone, path)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("AccessKey", b.accessKey)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// Already moved by an earlier attempt that failed to record it
+	if resp.StatusCode == http.StatusNotFound {
+		return b.GetFileURL(path, true), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+
+	if err := b.put(path, resp.Body, resp.ContentLength, resp.Header.Get("Content-Type"), true); err != nil {
+		return "", err
+	}
+	if err := b.DeleteFile(path, false); err != nil {
+		return "", err
+	}
+
+	return b.GetFileURL(path, true), nil
+}
+
+func (b *BunnyStorageImpl) GetFileURL(path string, protected bool) string {
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
+	if protected {
+		return b.secureCDNURL + path
+	}
 	return b.cdnURL + path
 }
+
+// GetSignedURL ใช้ Bunny token authentication: token = base64url(sha256(key + path + expires))
+func (b *BunnyStorageImpl) GetSignedURL(path string, expiresAt time.Time) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	expires := expiresAt.Unix()
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s%s%d", b.tokenKey, path, expires)))
+	token := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	return fmt.Sprintf("%s%s?token=%s&expires=%d", b.secureCDNURL, path, token, expires)
+}
+
+// GetPathFromURL accepts URLs of both zones: a moved file keeps its path
+func (b *BunnyStorageImpl) GetPathFromURL(fileURL string) string {
+	for _, cdnURL := range []string{b.secureCDNURL, b.cdnURL} {
+		if cdnURL != "" && strings.HasPrefix(fileURL, cdnURL+"/") {
+			return strings.TrimPrefix(fileURL, cdnURL)
+		}
+	}
+	return ""
+}
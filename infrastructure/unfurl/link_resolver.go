@@ -0,0 +1,195 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Metadata is what a page says about itself (Open Graph tags, falling back to <title>)
+type Metadata struct {
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+// LinkResolver fetches preview metadata for an external URL. The HTTP implementation is
+// used in production; tests and environments without outbound access can plug in their own.
+type LinkResolver interface {
+	Resolve(ctx context.Context, rawURL string) (*Metadata, error)
+}
+
+type Config struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	UserAgent    string
+}
+
+type httpLinkResolver struct {
+	client       *http.Client
+	maxBodyBytes int64
+	userAgent    string
+}
+
+func NewHTTPLinkResolver(config Config) LinkResolver {
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		// Links are user supplied, never let them reach internal services
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("refusing to fetch non-public address %s", host)
+			}
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   config.Timeout,
+		ResponseHeaderTimeout: config.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &httpLinkResolver{
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 3 {
+					return errors.New("too many redirects")
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return errors.New("unsupported redirect scheme")
+				}
+				return nil
+			},
+		},
+		maxBodyBytes: config.MaxBodyBytes,
+		userAgent:    config.UserAgent,
+	}
+}
+
+func (r *httpLinkResolver) Resolve(ctx context.Context, rawURL string) (*Metadata, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New("invalid url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", r.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.Contains(contentType, "html") {
+		return nil, errors.New("not an html page")
+	}
+
+	meta := parseMetadata(io.LimitReader(resp.Body, r.maxBodyBytes))
+	if meta.SiteName == "" {
+		meta.SiteName = resp.Request.URL.Hostname()
+	}
+	meta.ImageURL = absoluteURL(resp.Request.URL, meta.ImageURL)
+
+	return meta, nil
+}
+
+// parseMetadata reads the <head> of a page and stops at <body>
+func parseMetadata(body io.Reader) *Metadata {
+	meta := &Metadata{}
+	var title string
+	tokenizer := html.NewTokenizer(body)
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return finish(meta, title)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				return finish(meta, title)
+			case "title":
+				if tokenizer.Next() == html.TextToken {
+					title = strings.TrimSpace(tokenizer.Token().Data)
+				}
+			case "meta":
+				var key, content string
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "property", "name":
+						key = strings.ToLower(attr.Val)
+					case "content":
+						content = strings.TrimSpace(attr.Val)
+					}
+				}
+				switch key {
+				case "og:title", "twitter:title":
+					if meta.Title == "" {
+						meta.Title = content
+					}
+				case "og:description", "twitter:description", "description":
+					if meta.Description == "" {
+						meta.Description = content
+					}
+				case "og:image", "twitter:image":
+					if meta.ImageURL == "" {
+						meta.ImageURL = content
+					}
+				case "og:site_name":
+					meta.SiteName = content
+				}
+			}
+		}
+	}
+}
+
+func finish(meta *Metadata, title string) *Metadata {
+	if meta.Title == "" {
+		meta.Title = title
+	}
+	return meta
+}
+
+func absoluteURL(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return ""
+	}
+	return resolved.String()
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
@@ -0,0 +1,263 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"gofiber-social/infrastructure/redis"
+
+	"github.com/google/uuid"
+)
+
+const (
+	clusterNodesKey       = "ws:nodes"     // sorted set of node IDs scored by last presence refresh
+	clusterPresencePrefix = "ws:presence:" // hash per node: total, room:<id>, user:<id> -> connections
+	clusterPublishTimeout = 2 * time.Second
+)
+
+// ClusterConfig connects the managers of several API replicas through Redis
+type ClusterConfig struct {
+	NodeID      string        // unique per process; generated from the hostname when empty
+	Channel     string        // pub/sub channel shared by all nodes
+	PresenceTTL time.Duration // a node that stops refreshing its presence drops out of the counts after this
+}
+
+// cluster publishes broadcasts to the other nodes and shares this node's presence. Every node
+// delivers to its own connections only, so a broadcast is sent locally and published once.
+type cluster struct {
+	redis  *redis.RedisClient
+	config ClusterConfig
+	cancel context.CancelFunc
+	dirty  chan struct{} // presence changed; buffered so bursts collapse into one write
+}
+
+// clusterEnvelope is a broadcast as it travels between nodes
+type clusterEnvelope struct {
+	Origin  string     `json:"origin"`
+	Message Message    `json:"message"`
+	RoomID  string     `json:"roomId,omitempty"`
+	UserID  *uuid.UUID `json:"userId,omitempty"`
+}
+
+// EnableCluster starts relaying broadcasts through Redis pub/sub. Without it (or when Redis is
+// unreachable at startup) the manager only knows the connections of this process.
+func (m *WebSocketManager) EnableCluster(redisClient *redis.RedisClient, config ClusterConfig) error {
+	if config.NodeID == "" {
+		config.NodeID = defaultNodeID()
+	}
+	if config.PresenceTTL <= 0 {
+		return errors.New("presence TTL must be positive")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	payloads, err := redisClient.Subscribe(ctx, config.Channel)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	c := &cluster{
+		redis:  redisClient,
+		config: config,
+		cancel: cancel,
+		dirty:  make(chan struct{}, 1),
+	}
+
+	m.mutex.Lock()
+	m.cluster = c
+	m.mutex.Unlock()
+
+	go m.receiveClusterBroadcasts(payloads, config.NodeID)
+	go m.refreshPresence(ctx, c)
+
+	log.Printf("WebSocket cluster enabled: node=%s channel=%s", config.NodeID, config.Channel)
+	return nil
+}
+
+// Close leaves the cluster and withdraws this node's presence right away instead of waiting
+// for it to expire
+func (m *WebSocketManager) Close() {
+	m.mutex.Lock()
+	c := m.cluster
+	m.cluster = nil
+	m.mutex.Unlock()
+
+	if c == nil {
+		return
+	}
+	c.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), clusterPublishTimeout)
+	defer cancel()
+	_ = c.redis.Delete(ctx, clusterPresencePrefix+c.config.NodeID)
+	_ = c.redis.ZRem(ctx, clusterNodesKey, c.config.NodeID)
+}
+
+// IsUserOnline reports whether the user has a connection on any node
+func (m *WebSocketManager) IsUserOnline(userID uuid.UUID) bool {
+	m.mutex.RLock()
+	for _, client := range m.clients {
+		if client.UserID == userID {
+			m.mutex.RUnlock()
+			return true
+		}
+	}
+	c := m.cluster
+	m.mutex.RUnlock()
+
+	return c != nil && c.remoteCount("user:"+userID.String()) > 0
+}
+
+func (m *WebSocketManager) publish(broadcast BroadcastMessage) {
+	m.mutex.RLock()
+	c := m.cluster
+	m.mutex.RUnlock()
+	if c == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clusterPublishTimeout)
+	defer cancel()
+
+	envelope := clusterEnvelope{
+		Origin:  c.config.NodeID,
+		Message: broadcast.Message,
+		RoomID:  broadcast.RoomID,
+		UserID:  broadcast.UserID,
+	}
+	if err := c.redis.Publish(ctx, c.config.Channel, envelope); err != nil {
+		log.Printf("WebSocket cluster publish failed: %v", err)
+	}
+}
+
+func (m *WebSocketManager) receiveClusterBroadcasts(payloads <-chan string, nodeID string) {
+	for payload := range payloads {
+		var envelope clusterEnvelope
+		if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+			log.Printf("WebSocket cluster: invalid broadcast: %v", err)
+			continue
+		}
+		// Our own broadcasts were already delivered locally
+		if envelope.Origin == nodeID {
+			continue
+		}
+
+		m.broadcast <- BroadcastMessage{
+			Message: envelope.Message,
+			RoomID:  envelope.RoomID,
+			UserID:  envelope.UserID,
+		}
+	}
+}
+
+// markPresenceDirty asks for this node's presence to be written; never blocks. Callers hold
+// the mutex.
+func (m *WebSocketManager) markPresenceDirty() {
+	if m.cluster == nil {
+		return
+	}
+	select {
+	case m.cluster.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// refreshPresence writes this node's connection counts whenever they change, and at a third of
+// the TTL so the entry stays alive while the node is
+func (m *WebSocketManager) refreshPresence(ctx context.Context, c *cluster) {
+	ticker := time.NewTicker(c.config.PresenceTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		m.writePresence(ctx, c)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.dirty:
+		}
+	}
+}
+
+func (m *WebSocketManager) writePresence(ctx context.Context, c *cluster) {
+	m.mutex.RLock()
+	fields := map[string]interface{}{"total": len(m.clients)}
+	users := make(map[uuid.UUID]int)
+	for _, client := range m.clients {
+		if !client.IsGuest() {
+			users[client.UserID]++
+		}
+	}
+	for roomID, conns := range m.rooms {
+		fields["room:"+roomID] = len(conns)
+	}
+	m.mutex.RUnlock()
+
+	for userID, count := range users {
+		fields["user:"+userID.String()] = count
+	}
+
+	now := time.Now()
+	if err := c.redis.ReplaceHash(ctx, clusterPresencePrefix+c.config.NodeID, fields, c.config.PresenceTTL); err != nil {
+		log.Printf("WebSocket cluster: failed to write presence: %v", err)
+		return
+	}
+	_ = c.redis.ZAdd(ctx, clusterNodesKey, float64(now.Unix()), c.config.NodeID)
+
+	// Forget nodes that died without leaving
+	cutoff := now.Add(-c.config.PresenceTTL).Unix()
+	_ = c.redis.ZRemRangeByScore(ctx, clusterNodesKey, "-inf", fmt.Sprintf("(%d", cutoff))
+}
+
+// remoteCount sums one presence field over the other live nodes; Redis errors count as zero
+// so callers still get this node's numbers
+func (c *cluster) remoteCount(field string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterPublishTimeout)
+	defer cancel()
+
+	cutoff := time.Now().Add(-c.config.PresenceTTL).Unix()
+	nodes, err := c.redis.ZRangeByScore(ctx, clusterNodesKey, strconv.FormatInt(cutoff, 10), "+inf")
+	if err != nil {
+		log.Printf("WebSocket cluster: failed to list nodes: %v", err)
+		return 0
+	}
+
+	keys := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if node != c.config.NodeID {
+			keys = append(keys, clusterPresencePrefix+node)
+		}
+	}
+	if len(keys) == 0 {
+		return 0
+	}
+
+	values, err := c.redis.HGetFromKeys(ctx, keys, field)
+	if err != nil {
+		log.Printf("WebSocket cluster: failed to read presence: %v", err)
+		return 0
+	}
+
+	total := 0
+	for _, value := range values {
+		if n, err := strconv.Atoi(value); err == nil {
+			total += n
+		}
+	}
+	return total
+}
+
+func defaultNodeID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "node"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+}
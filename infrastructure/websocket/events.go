@@ -0,0 +1,30 @@
+package websocket
+
+// Live events broadcast to rooms so open pages update without polling. Payloads are the DTOs
+// the REST endpoints return, or the small event structs in dto/live_event.go.
+const (
+	// topic:<id>
+	EventReplyCreated = "reply_created" // dto.ReplyResponse
+	EventReplyUpdated = "reply_updated" // dto.ReplyResponse
+	EventReplyDeleted = "reply_deleted" // dto.ReplyDeletedEvent
+	EventTopicStatus  = "topic_status"  // dto.TopicStatusEvent: pinned, locked, accepted answer
+	EventTyping       = "typing"        // dto.TypingEvent
+
+	// video:<id>
+	EventCommentCreated = "comment_created" // dto.CommentResponse
+	EventCommentUpdated = "comment_updated" // dto.CommentResponse
+	EventCommentDeleted = "comment_deleted" // dto.CommentDeletedEvent
+
+	// Both
+	EventLikeCount = "like_count" // dto.LikeCountEvent
+	EventPresence  = "presence"   // dto.PresenceEvent: how many are viewing
+
+	// Direct messages go to each participant's user channel, so they are sequenced for resume
+	EventConversationCreated = "conversation_created" // dto.ConversationResponse
+	EventConversationUpdated = "conversation_updated" // dto.ConversationResponse: participants changed
+	EventMessageRequest      = "message_request"      // dto.ConversationResponse, to the recipient of a request
+	EventMessageCreated      = "message_created"      // dto.MessageResponse
+	EventMessageUpdated      = "message_updated"      // dto.MessageResponse
+	EventMessageDeleted      = "message_deleted"      // dto.MessageDeletedEvent
+	EventMessageRead         = "message_read"         // dto.MessageReadEvent: a read receipt moved
+)
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gofiber-social/infrastructure/redis"
+
+	"github.com/google/uuid"
+)
+
+const (
+	replaySeqPrefix = "ws:seq:" // per-user counter of user-directed messages
+	replayLogPrefix = "ws:log:" // per-user sorted set of the latest messages scored by sequence
+	replayTimeout   = 2 * time.Second
+)
+
+var ErrReplayUnavailable = errors.New("event replay is not enabled")
+
+// ReplayConfig bounds the per-user event log used to resume after a reconnect
+type ReplayConfig struct {
+	LogSize int           // messages kept per user; keep it below the send queue size
+	TTL     time.Duration // the log and counter of a user who receives nothing for this long are dropped
+}
+
+type replayLog struct {
+	redis  *redis.RedisClient
+	config ReplayConfig
+}
+
+// ResumeResult is what a reconnecting client missed
+type ResumeResult struct {
+	Messages []Message // in sequence order
+	LastSeq  int64     // the user's latest sequence number
+	Resync   bool      // the gap can't be filled from the log; the client has to refetch
+}
+
+// EnableReplay numbers every user-directed message and keeps the latest ones in Redis so a
+// client can resume after a reconnect. Without it messages go out unnumbered.
+func (m *WebSocketManager) EnableReplay(redisClient *redis.RedisClient, config ReplayConfig) error {
+	if config.LogSize <= 0 || config.TTL <= 0 {
+		return errors.New("event log size and TTL must be positive")
+	}
+
+	m.mutex.Lock()
+	m.replay = &replayLog{redis: redisClient, config: config}
+	m.mutex.Unlock()
+	return nil
+}
+
+func (m *WebSocketManager) replayLog() *replayLog {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.replay
+}
+
+// sequence numbers a message for the user and appends it to the user's log. On Redis errors
+// the message is still delivered, unnumbered.
+func (m *WebSocketManager) sequence(userID uuid.UUID, message *Message) {
+	r := m.replayLog()
+	if r == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+	defer cancel()
+
+	seqKey := replaySeqPrefix + userID.String()
+	seq, err := r.redis.Increment(ctx, seqKey)
+	if err != nil {
+		log.Printf("WebSocket replay: failed to number message: %v", err)
+		return
+	}
+	_ = r.redis.Expire(ctx, seqKey, r.config.TTL)
+	message.Seq = seq
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	if err := r.redis.ZAddCapped(ctx, replayLogPrefix+userID.String(), float64(seq), string(payload), int64(r.config.LogSize), r.config.TTL); err != nil {
+		log.Printf("WebSocket replay: failed to log message: %v", err)
+	}
+}
+
+// CurrentSeq returns the user's latest sequence number, 0 if nothing was sent recently
+func (m *WebSocketManager) CurrentSeq(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r := m.replayLog()
+	if r == nil {
+		return 0, ErrReplayUnavailable
+	}
+	return r.redis.GetInt64(ctx, replaySeqPrefix+userID.String())
+}
+
+// Resume returns the user's messages after lastSeq. When some of them are no longer in the
+// log (or lastSeq is from before the counter expired) Resync is set instead.
+func (m *WebSocketManager) Resume(ctx context.Context, userID uuid.UUID, lastSeq int64) (*ResumeResult, error) {
+	r := m.replayLog()
+	if r == nil {
+		return nil, ErrReplayUnavailable
+	}
+
+	current, err := r.redis.GetInt64(ctx, replaySeqPrefix+userID.String())
+	if err != nil {
+		return nil, err
+	}
+	result := &ResumeResult{LastSeq: current}
+	if lastSeq == current {
+		return result, nil
+	}
+	if lastSeq > current || lastSeq < 0 {
+		result.Resync = true
+		return result, nil
+	}
+
+	members, err := r.redis.ZRangeByScore(ctx, replayLogPrefix+userID.String(), fmt.Sprintf("(%d", lastSeq), "+inf")
+	if err != nil {
+		return nil, err
+	}
+
+	expected := lastSeq + 1
+	for _, member := range members {
+		var message Message
+		if err := json.Unmarshal([]byte(member), &message); err != nil || message.Seq != expected {
+			result.Resync = true
+			result.Messages = nil
+			return result, nil
+		}
+		result.Messages = append(result.Messages, message)
+		expected++
+	}
+
+	// Messages sent meanwhile may be past the counter read above
+	if expected-1 < current {
+		result.Resync = true
+		result.Messages = nil
+		return result, nil
+	}
+	result.LastSeq = expected - 1
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Room types. Rooms are named "<type>:<id>" except the admin room, which has no ID.
+const (
+	RoomTypeTopic = "topic" // topic:<topicID> - viewers of a topic
+	RoomTypeVideo = "video" // video:<videoID> - viewers of a video
+	RoomTypeUser  = "user"  // user:<userID> - the user's own channel
+	RoomAdmin     = "admin" // admins only
+)
+
+// MaxRoomsPerConnection bounds how many rooms one connection may listen to
+const MaxRoomsPerConnection = 50
+
+var (
+	ErrNotConnected = errors.New("connection is not registered")
+	ErrTooManyRooms = errors.New("too many rooms joined")
+	ErrInvalidRoom  = errors.New("invalid room name")
+)
+
+func TopicRoom(topicID uuid.UUID) string {
+	return RoomTypeTopic + ":" + topicID.String()
+}
+
+func VideoRoom(videoID uuid.UUID) string {
+	return RoomTypeVideo + ":" + videoID.String()
+}
+
+func UserRoom(userID uuid.UUID) string {
+	return RoomTypeUser + ":" + userID.String()
+}
+
+// ParseRoom splits a room name into its type and ID (uuid.Nil for the admin room)
+func ParseRoom(roomID string) (string, uuid.UUID, error) {
+	if roomID == RoomAdmin {
+		return RoomAdmin, uuid.Nil, nil
+	}
+
+	roomType, rawID, ok := strings.Cut(roomID, ":")
+	if !ok {
+		return "", uuid.Nil, ErrInvalidRoom
+	}
+	switch roomType {
+	case RoomTypeTopic, RoomTypeVideo, RoomTypeUser:
+	default:
+		return "", uuid.Nil, ErrInvalidRoom
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return "", uuid.Nil, ErrInvalidRoom
+	}
+	return roomType, id, nil
+}
+
+// RoomName is the canonical name of a parsed room, so "topic:ABC..." and "topic:abc..."
+// end up in the same room
+func RoomName(roomType string, id uuid.UUID) string {
+	if roomType == RoomAdmin {
+		return RoomAdmin
+	}
+	return roomType + ":" + id.String()
+}
@@ -1,34 +1,111 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/gofiber/websocket/v2"
 	"github.com/google/uuid"
 )
 
+// What happens to a connection whose send queue is full
+const (
+	SlowClientDrop       = "drop"       // the message is skipped for that connection only
+	SlowClientDisconnect = "disconnect" // the connection is closed so the client reconnects and resyncs
+)
+
+const broadcastBacklog = 1024
+
+var (
+	ErrQueueFull    = errors.New("send queue is full")
+	ErrShuttingDown = errors.New("server is shutting down")
+)
+
+// Options tune delivery to connections; see DefaultOptions
+type Options struct {
+	QueueSize        int           // messages buffered per connection
+	SlowClientPolicy string        // SlowClientDrop or SlowClientDisconnect
+	PingInterval     time.Duration // how often the server pings
+	IdleTimeout      time.Duration // a connection that sends nothing (not even a pong) for this long is closed
+	WriteTimeout     time.Duration // a single write that takes longer closes the connection
+}
+
+func DefaultOptions() Options {
+	return Options{
+		QueueSize:        256,
+		SlowClientPolicy: SlowClientDisconnect,
+		PingInterval:     25 * time.Second,
+		IdleTimeout:      60 * time.Second,
+		WriteTimeout:     10 * time.Second,
+	}
+}
+
+func (o Options) validate() error {
+	if o.QueueSize <= 0 {
+		return errors.New("queue size must be positive")
+	}
+	if o.SlowClientPolicy != SlowClientDrop && o.SlowClientPolicy != SlowClientDisconnect {
+		return fmt.Errorf("unknown slow client policy: %s", o.SlowClientPolicy)
+	}
+	if o.PingInterval <= 0 || o.WriteTimeout <= 0 {
+		return errors.New("ping interval and write timeout must be positive")
+	}
+	if o.IdleTimeout <= o.PingInterval {
+		return errors.New("idle timeout must be longer than the ping interval")
+	}
+	return nil
+}
+
 type WebSocketManager struct {
-	clients    map[*websocket.Conn]Client
-	rooms      map[string]map[*websocket.Conn]bool
-	register   chan Client
-	unregister chan *websocket.Conn
-	broadcast  chan BroadcastMessage
-	mutex      sync.RWMutex
+	clients      map[*websocket.Conn]*Client
+	rooms        map[string]map[*websocket.Conn]bool
+	broadcast    chan BroadcastMessage
+	mutex        sync.RWMutex
+	cluster      *cluster   // nil when running as a single node
+	replay       *replayLog // nil when user-directed messages aren't numbered
+	options      Options
+	shuttingDown bool
+	counters     deliveryCounters
+}
+
+type deliveryCounters struct {
+	sent            atomic.Uint64
+	dropped         atomic.Uint64
+	slowDisconnects atomic.Uint64
+	writeErrors     atomic.Uint64
 }
 
+// Client is one connection. Guests have UserID uuid.Nil until they authenticate.
 type Client struct {
 	Conn   *websocket.Conn
 	UserID uuid.UUID
-	RoomID string
+	Role   string
+	Rooms  map[string]bool
+
+	send        chan []byte   // bounded queue drained by writePump
+	done        chan struct{} // closed once writePump has closed the connection
+	closed      bool          // send is closed; guarded by the manager mutex
+	discard     chan struct{} // closed to skip what is still queued instead of flushing it
+	closeCode   int
+	closeReason string
+}
+
+func (c *Client) IsGuest() bool {
+	return c.UserID == uuid.Nil
 }
 
 type Message struct {
-	Type    string      `json:"type"`
-	Data    interface{} `json:"data"`
-	UserID  string      `json:"userId,omitempty"`
-	RoomID  string      `json:"roomId,omitempty"`
+	Type   string      `json:"type"`
+	Data   interface{} `json:"data"`
+	UserID string      `json:"userId,omitempty"`
+	RoomID string      `json:"roomId,omitempty"`
+	Seq    int64       `json:"seq,omitempty"` // per-user sequence of user-directed messages, see Resume
 }
 
 type BroadcastMessage struct {
@@ -37,101 +114,427 @@ type BroadcastMessage struct {
 	UserID  *uuid.UUID
 }
 
+// Stats describes the delivery state of this node; counters are totals since start
+type Stats struct {
+	Connections      int    `json:"connections"`
+	Rooms            int    `json:"rooms"`
+	QueuedMessages   int    `json:"queuedMessages"`
+	MaxQueueDepth    int    `json:"maxQueueDepth"`
+	QueueCapacity    int    `json:"queueCapacity"`
+	BroadcastBacklog int    `json:"broadcastBacklog"`
+	SlowClientPolicy string `json:"slowClientPolicy"`
+	Sent             uint64 `json:"sent"`
+	Dropped          uint64 `json:"dropped"`
+	SlowDisconnects  uint64 `json:"slowDisconnects"`
+	WriteErrors      uint64 `json:"writeErrors"`
+}
+
 var Manager *WebSocketManager
 
 func init() {
 	Manager = &WebSocketManager{
-		clients:    make(map[*websocket.Conn]Client),
-		rooms:      make(map[string]map[*websocket.Conn]bool),
-		register:   make(chan Client),
-		unregister: make(chan *websocket.Conn),
-		broadcast:  make(chan BroadcastMessage),
+		clients:   make(map[*websocket.Conn]*Client),
+		rooms:     make(map[string]map[*websocket.Conn]bool),
+		broadcast: make(chan BroadcastMessage, broadcastBacklog),
+		options:   DefaultOptions(),
 	}
 	go Manager.run()
 }
 
+// Configure replaces the delivery options; connections opened earlier keep theirs
+func (m *WebSocketManager) Configure(options Options) error {
+	if err := options.validate(); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	m.options = options
+	m.mutex.Unlock()
+	return nil
+}
+
+// run fans broadcasts out to the send queues. It never writes to a connection itself, so a
+// slow client can't hold up the others.
 func (m *WebSocketManager) run() {
-	for {
-		select {
-		case client := <-m.register:
-			m.mutex.Lock()
-			m.clients[client.Conn] = client
+	for broadcast := range m.broadcast {
+		m.deliver(broadcast)
+	}
+}
 
-			if client.RoomID != "" {
-				if m.rooms[client.RoomID] == nil {
-					m.rooms[client.RoomID] = make(map[*websocket.Conn]bool)
-				}
-				m.rooms[client.RoomID][client.Conn] = true
+func (m *WebSocketManager) deliver(broadcast BroadcastMessage) {
+	payload, err := json.Marshal(broadcast.Message)
+	if err != nil {
+		log.Printf("WebSocket: failed to encode %s message: %v", broadcast.Message.Type, err)
+		return
+	}
+
+	var slow []*Client
+	m.mutex.RLock()
+	if broadcast.RoomID != "" {
+		for conn := range m.rooms[broadcast.RoomID] {
+			if client := m.clients[conn]; client != nil && !m.enqueue(client, payload) {
+				slow = append(slow, client)
+			}
+		}
+	} else if broadcast.UserID != nil {
+		for _, client := range m.clients {
+			if client.UserID == *broadcast.UserID && !m.enqueue(client, payload) {
+				slow = append(slow, client)
+			}
+		}
+	} else {
+		for _, client := range m.clients {
+			if !m.enqueue(client, payload) {
+				slow = append(slow, client)
 			}
-			m.mutex.Unlock()
+		}
+	}
+	m.mutex.RUnlock()
 
-			log.Printf("Client connected: UserID=%s, RoomID=%s", client.UserID, client.RoomID)
+	for _, client := range slow {
+		m.handleSlowClient(client)
+	}
+}
 
-		case conn := <-m.unregister:
-			m.mutex.Lock()
-			if client, ok := m.clients[conn]; ok {
-				delete(m.clients, conn)
+// enqueue never blocks and reports false when the queue is full. Callers hold the mutex, a
+// read lock is enough.
+func (m *WebSocketManager) enqueue(client *Client, payload []byte) bool {
+	if client.closed {
+		return true
+	}
+	select {
+	case client.send <- payload:
+		return true
+	default:
+		m.counters.dropped.Add(1)
+		return false
+	}
+}
 
-				if client.RoomID != "" && m.rooms[client.RoomID] != nil {
-					delete(m.rooms[client.RoomID], conn)
-					if len(m.rooms[client.RoomID]) == 0 {
-						delete(m.rooms, client.RoomID)
-					}
-				}
+func (m *WebSocketManager) handleSlowClient(client *Client) {
+	m.mutex.RLock()
+	policy := m.options.SlowClientPolicy
+	m.mutex.RUnlock()
+
+	if policy != SlowClientDisconnect {
+		return
+	}
+	if m.dropClient(client, websocket.CloseTryAgainLater, "send queue full", true) {
+		m.counters.slowDisconnects.Add(1)
+		log.Printf("WebSocket: disconnected a slow client")
+	}
+}
+
+// dropClient stops delivery to the client and has its writer close the connection. The client
+// stays registered until UnregisterClient. Reports whether this call closed it.
+func (m *WebSocketManager) dropClient(client *Client, code int, reason string, discard bool) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.closeClient(client, code, reason, discard)
+}
+
+// closeClient is dropClient for callers that hold the mutex
+func (m *WebSocketManager) closeClient(client *Client, code int, reason string, discard bool) bool {
+	if client.closed {
+		return false
+	}
+	client.closed = true
+	client.closeCode = code
+	client.closeReason = reason
+	if discard {
+		close(client.discard)
+	}
+	for roomID := range client.Rooms {
+		m.removeFromRoom(client.Conn, roomID)
+	}
+	close(client.send)
+	m.markPresenceDirty()
+	return true
+}
+
+// writePump is the only goroutine that writes data frames to the connection. When the queue
+// is closed it flushes what is left (unless discarding), sends a close frame and closes the
+// connection, which also ends the handler's read loop.
+func (m *WebSocketManager) writePump(client *Client, options Options) {
+	defer close(client.done)
 
+	conn := client.Conn
+	ticker := time.NewTicker(options.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case payload, ok := <-client.send:
+			if !ok {
+				code := client.closeCode
+				if code == 0 {
+					code = websocket.CloseNormalClosure
+				}
+				deadline := time.Now().Add(options.WriteTimeout)
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, client.closeReason), deadline)
 				conn.Close()
-				log.Printf("Client disconnected: UserID=%s, RoomID=%s", client.UserID, client.RoomID)
+				return
 			}
-			m.mutex.Unlock()
-
-		case message := <-m.broadcast:
-			m.mutex.RLock()
-			if message.RoomID != "" {
-				if clients, ok := m.rooms[message.RoomID]; ok {
-					for conn := range clients {
-						m.sendMessage(conn, message.Message)
-					}
-				}
-			} else if message.UserID != nil {
-				for conn, client := range m.clients {
-					if client.UserID == *message.UserID {
-						m.sendMessage(conn, message.Message)
-					}
-				}
-			} else {
-				for conn := range m.clients {
-					m.sendMessage(conn, message.Message)
-				}
+			select {
+			case <-client.discard:
+				continue
+			default:
+			}
+
+			_ = conn.SetWriteDeadline(time.Now().Add(options.WriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				m.writeFailed(client, err)
+				return
+			}
+			m.counters.sent.Add(1)
+
+		case <-ticker.C:
+			deadline := time.Now().Add(options.WriteTimeout)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				m.writeFailed(client, err)
+				return
 			}
-			m.mutex.RUnlock()
 		}
 	}
 }
 
-func (m *WebSocketManager) sendMessage(conn *websocket.Conn, message Message) {
-	if err := conn.WriteJSON(message); err != nil {
-		log.Printf("Error sending message: %v", err)
-		m.unregister <- conn
+func (m *WebSocketManager) writeFailed(client *Client, err error) {
+	m.counters.writeErrors.Add(1)
+	m.dropClient(client, 0, "", true)
+	client.Conn.Close()
+	log.Printf("WebSocket write error: %v", err)
+}
+
+// RegisterClient adds a connection and starts its writer; userID is uuid.Nil for guests. The
+// connection is closed after IdleTimeout unless it answers pings or calls Touch.
+func (m *WebSocketManager) RegisterClient(conn *websocket.Conn, userID uuid.UUID, role string) error {
+	m.mutex.Lock()
+	if m.shuttingDown {
+		m.mutex.Unlock()
+		return ErrShuttingDown
 	}
+
+	options := m.options
+	client := &Client{
+		Conn:    conn,
+		UserID:  userID,
+		Role:    role,
+		Rooms:   make(map[string]bool),
+		send:    make(chan []byte, options.QueueSize),
+		done:    make(chan struct{}),
+		discard: make(chan struct{}),
+	}
+	m.clients[conn] = client
+	m.markPresenceDirty()
+	m.mutex.Unlock()
+
+	_ = conn.SetReadDeadline(time.Now().Add(options.IdleTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(options.IdleTimeout))
+	})
+
+	go m.writePump(client, options)
+
+	log.Printf("Client connected: UserID=%s", userID)
+	return nil
 }
 
-func (m *WebSocketManager) RegisterClient(conn *websocket.Conn, userID uuid.UUID, roomID string) {
-	client := Client{
-		Conn:   conn,
-		UserID: userID,
-		RoomID: roomID,
+// Touch extends the idle timeout after the client sent something; call it from the read loop
+func (m *WebSocketManager) Touch(conn *websocket.Conn) {
+	m.mutex.RLock()
+	idleTimeout := m.options.IdleTimeout
+	m.mutex.RUnlock()
+
+	_ = conn.SetReadDeadline(time.Now().Add(idleTimeout))
+}
+
+// Send queues a message for one connection. A full queue is handled like for broadcasts.
+func (m *WebSocketManager) Send(conn *websocket.Conn, message Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.RLock()
+	client, ok := m.clients[conn]
+	if !ok || client.closed {
+		m.mutex.RUnlock()
+		return ErrNotConnected
+	}
+	queued := m.enqueue(client, payload)
+	m.mutex.RUnlock()
+
+	if !queued {
+		m.handleSlowClient(client)
+		return ErrQueueFull
+	}
+	return nil
+}
+
+// Authenticate attaches a user to a guest connection (token sent as the first message)
+func (m *WebSocketManager) Authenticate(conn *websocket.Conn, userID uuid.UUID, role string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	client, ok := m.clients[conn]
+	if !ok || client.closed {
+		return ErrNotConnected
+	}
+	if !client.IsGuest() {
+		return errors.New("connection is already authenticated")
+	}
+
+	client.UserID = userID
+	client.Role = role
+	m.markPresenceDirty()
+	return nil
+}
+
+// GetClient returns a copy of the connection's identity and rooms
+func (m *WebSocketManager) GetClient(conn *websocket.Conn) (Client, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	client, ok := m.clients[conn]
+	if !ok {
+		return Client{}, false
+	}
+
+	copied := Client{
+		Conn:   client.Conn,
+		UserID: client.UserID,
+		Role:   client.Role,
+		Rooms:  make(map[string]bool, len(client.Rooms)),
 	}
-	m.register <- client
+	for roomID := range client.Rooms {
+		copied.Rooms[roomID] = true
+	}
+	return copied, true
 }
 
+// JoinRoom adds the connection to a room; authorization is up to the caller
+func (m *WebSocketManager) JoinRoom(conn *websocket.Conn, roomID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	client, ok := m.clients[conn]
+	if !ok || client.closed {
+		return ErrNotConnected
+	}
+	if client.Rooms[roomID] {
+		return nil
+	}
+	if len(client.Rooms) >= MaxRoomsPerConnection {
+		return ErrTooManyRooms
+	}
+
+	client.Rooms[roomID] = true
+	if m.rooms[roomID] == nil {
+		m.rooms[roomID] = make(map[*websocket.Conn]bool)
+	}
+	m.rooms[roomID][conn] = true
+	m.markPresenceDirty()
+	return nil
+}
+
+// LeaveRoom removes the connection from a room; leaving a room it isn't in is not an error
+func (m *WebSocketManager) LeaveRoom(conn *websocket.Conn, roomID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	client, ok := m.clients[conn]
+	if !ok || !client.Rooms[roomID] {
+		return
+	}
+
+	delete(client.Rooms, roomID)
+	m.removeFromRoom(conn, roomID)
+	m.markPresenceDirty()
+}
+
+// removeFromRoom drops the connection from the room index; callers hold the mutex
+func (m *WebSocketManager) removeFromRoom(conn *websocket.Conn, roomID string) {
+	if m.rooms[roomID] == nil {
+		return
+	}
+	delete(m.rooms[roomID], conn)
+	if len(m.rooms[roomID]) == 0 {
+		delete(m.rooms, roomID)
+	}
+}
+
+// UnregisterClient flushes what is queued, closes the connection and waits for the writer to
+// finish. Handlers must call it before returning since the connection object is reused.
 func (m *WebSocketManager) UnregisterClient(conn *websocket.Conn) {
-	m.unregister <- conn
+	m.mutex.Lock()
+	client, ok := m.clients[conn]
+	if ok {
+		delete(m.clients, conn)
+		m.closeClient(client, websocket.CloseNormalClosure, "", false)
+		m.markPresenceDirty()
+	}
+	m.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+	<-client.done
+	log.Printf("Client disconnected: UserID=%s, Rooms=%d", client.UserID, len(client.Rooms))
+}
+
+// Shutdown refuses new connections, then flushes and closes every open one with "going away"
+// so clients reconnect to another replica. It returns when all writers are done or ctx ends.
+func (m *WebSocketManager) Shutdown(ctx context.Context) error {
+	m.mutex.Lock()
+	m.shuttingDown = true
+	clients := make([]*Client, 0, len(m.clients))
+	for _, client := range m.clients {
+		m.closeClient(client, websocket.CloseGoingAway, "server shutting down", false)
+		clients = append(clients, client)
+	}
+	m.mutex.Unlock()
+
+	for _, client := range clients {
+		select {
+		case <-client.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Stats reports connections, queue depth and delivery counters of this node
+func (m *WebSocketManager) Stats() Stats {
+	m.mutex.RLock()
+	stats := Stats{
+		Connections:      len(m.clients),
+		Rooms:            len(m.rooms),
+		QueueCapacity:    m.options.QueueSize,
+		SlowClientPolicy: m.options.SlowClientPolicy,
+	}
+	for _, client := range m.clients {
+		depth := len(client.send)
+		stats.QueuedMessages += depth
+		if depth > stats.MaxQueueDepth {
+			stats.MaxQueueDepth = depth
+		}
+	}
+	m.mutex.RUnlock()
+
+	stats.BroadcastBacklog = len(m.broadcast)
+	stats.Sent = m.counters.sent.Load()
+	stats.Dropped = m.counters.dropped.Load()
+	stats.SlowDisconnects = m.counters.slowDisconnects.Load()
+	stats.WriteErrors = m.counters.writeErrors.Load()
+	return stats
 }
 
 func (m *WebSocketManager) BroadcastToRoom(roomID string, messageType string, data interface{}) {
 	message := Message{
-		Type: messageType,
-		Data: data,
+		Type:   messageType,
+		Data:   data,
+		RoomID: roomID,
 	}
 
 	broadcast := BroadcastMessage{
@@ -140,13 +543,17 @@ func (m *WebSocketManager) BroadcastToRoom(roomID string, messageType string, da
 	}
 
 	m.broadcast <- broadcast
+	m.publish(broadcast)
 }
 
+// BroadcastToUser sends to every connection of the user. The message is numbered and logged
+// first when replay is enabled, so a client that was offline can resume.
 func (m *WebSocketManager) BroadcastToUser(userID uuid.UUID, messageType string, data interface{}) {
 	message := Message{
 		Type: messageType,
 		Data: data,
 	}
+	m.sequence(userID, &message)
 
 	broadcast := BroadcastMessage{
 		Message: message,
@@ -154,6 +561,7 @@ func (m *WebSocketManager) BroadcastToUser(userID uuid.UUID, messageType string,
 	}
 
 	m.broadcast <- broadcast
+	m.publish(broadcast)
 }
 
 func (m *WebSocketManager) BroadcastToAll(messageType string, data interface{}) {
@@ -167,95 +575,31 @@ func (m *WebSocketManager) BroadcastToAll(messageType string, data interface{})
 	}
 
 	m.broadcast <- broadcast
+	m.publish(broadcast)
 }
 
+// GetRoomClients counts the room's connections on every node of the cluster
 func (m *WebSocketManager) GetRoomClients(roomID string) int {
 	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	count := len(m.rooms[roomID])
+	c := m.cluster
+	m.mutex.RUnlock()
 
-	if clients, ok := m.rooms[roomID]; ok {
-		return len(clients)
+	if c != nil {
+		count += c.remoteCount("room:" + roomID)
 	}
-	return 0
+	return count
 }
 
+// GetTotalClients counts connections on every node of the cluster
 func (m *WebSocketManager) GetTotalClients() int {
 	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	count := len(m.clients)
+	c := m.cluster
+	m.mutex.RUnlock()
 
-	return len(m.clients)
-}
-
-func HandleWebSocketMessage(conn *websocket.Conn, messageType int, data []byte) {
-	var message Message
-	if err := json.Unmarshal(data, &message); err != nil {
-		log.Printf("Error unmarshaling message: %v", err)
-		return
-	}
-
-	switch message.Type {
-	case "ping":
-		response := Message{
-			Type: "pong",
-			Data: "pong",
-		}
-		conn.WriteJSON(response)
-
-	case "join_room":
-		if roomData, ok := message.Data.(map[string]interface{}); ok {
-			if roomID, ok := roomData["roomId"].(string); ok {
-				Manager.mutex.Lock()
-				if client, exists := Manager.clients[conn]; exists {
-					if client.RoomID != "" && Manager.rooms[client.RoomID] != nil {
-						delete(Manager.rooms[client.RoomID], conn)
-						if len(Manager.rooms[client.RoomID]) == 0 {
-							delete(Manager.rooms, client.RoomID)
-						}
-					}
-
-					client.RoomID = roomID
-					Manager.clients[conn] = client
-
-					if Manager.rooms[roomID] == nil {
-						Manager.rooms[roomID] = make(map[*websocket.Conn]bool)
-					}
-					Manager.rooms[roomID][conn] = true
-				}
-				Manager.mutex.Unlock()
-
-				response := Message{
-					Type: "room_joined",
-					Data: map[string]interface{}{
-						"roomId": roomID,
-						"message": fmt.Sprintf("Joined room %s", roomID),
-					},
-				}
-				conn.WriteJSON(response)
-			}
-		}
-
-	case "leave_room":
-		Manager.mutex.Lock()
-		if client, exists := Manager.clients[conn]; exists {
-			if client.RoomID != "" && Manager.rooms[client.RoomID] != nil {
-				delete(Manager.rooms[client.RoomID], conn)
-				if len(Manager.rooms[client.RoomID]) == 0 {
-					delete(Manager.rooms, client.RoomID)
-				}
-
-				client.RoomID = ""
-				Manager.clients[conn] = client
-			}
-		}
-		Manager.mutex.Unlock()
-
-		response := Message{
-			Type: "room_left",
-			Data: "Left room successfully",
-		}
-		conn.WriteJSON(response)
-
-	default:
-		log.Printf("Unknown message type: %s", message.Type)
+	if c != nil {
+		count += c.remoteCount("total")
 	}
-}
\ No newline at end of file
+	return count
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AnalyticsHandler struct {
+	analyticsService services.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService}
+}
+
+// GetCreatorAnalytics handles the creator dashboard for the current user
+// GET /api/v1/analytics/creator?from=2025-01-01&to=2025-01-28
+func (h *AnalyticsHandler) GetCreatorAnalytics(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	params := &dto.AnalyticsQueryParams{From: c.Query("from"), To: c.Query("to")}
+	if err := utils.ValidateStruct(params); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	analytics, err := h.analyticsService.GetCreatorAnalytics(c.Context(), user.ID, params)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to retrieve analytics", err)
+	}
+
+	return utils.SuccessResponse(c, "Analytics retrieved successfully", analytics)
+}
+
+// GetVideoAnalytics handles analytics of one of the current user's videos
+// GET /api/v1/analytics/videos/:id
+func (h *AnalyticsHandler) GetVideoAnalytics(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	videoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid video ID")
+	}
+
+	params := &dto.AnalyticsQueryParams{From: c.Query("from"), To: c.Query("to")}
+	if err := utils.ValidateStruct(params); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	analytics, err := h.analyticsService.GetVideoAnalytics(c.Context(), user.ID, videoID, params)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to retrieve video analytics", err)
+	}
+
+	return utils.SuccessResponse(c, "Video analytics retrieved successfully", analytics)
+}
+
+// GetTopicAnalytics handles analytics of one of the current user's topics
+// GET /api/v1/analytics/topics/:id
+func (h *AnalyticsHandler) GetTopicAnalytics(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	params := &dto.AnalyticsQueryParams{From: c.Query("from"), To: c.Query("to")}
+	if err := utils.ValidateStruct(params); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	analytics, err := h.analyticsService.GetTopicAnalytics(c.Context(), user.ID, topicID, params)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to retrieve topic analytics", err)
+	}
+
+	return utils.SuccessResponse(c, "Topic analytics retrieved successfully", analytics)
+}
+
+// RollupDay recomputes the daily rollup of a given date, e.g. to backfill (admin)
+// POST /api/v1/admin/analytics/rollup
+func (h *AnalyticsHandler) RollupDay(c *fiber.Ctx) error {
+	var req dto.AnalyticsRollupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	day, _ := time.Parse("2006-01-02", req.Date)
+	if err := h.analyticsService.RollupDay(c.Context(), day); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to roll up analytics", err)
+	}
+
+	return utils.SuccessResponse(c, "Analytics rolled up successfully", nil)
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type BookmarkHandler struct {
+	bookmarkService services.BookmarkService
+}
+
+func NewBookmarkHandler(bookmarkService services.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{bookmarkService: bookmarkService}
+}
+
+// AddBookmark handles saving a topic, video, reply or comment
+// POST /api/v1/bookmarks
+func (h *BookmarkHandler) AddBookmark(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.CreateBookmarkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	bookmark, err := h.bookmarkService.AddBookmark(c.Context(), user.ID, &req)
+	if err != nil {
+		return bookmarkErrorResponse(c, "Failed to add bookmark", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark added successfully", bookmark)
+}
+
+// RemoveBookmark handles removing a bookmark by its target
+// DELETE /api/v1/bookmarks/:type/:targetId
+func (h *BookmarkHandler) RemoveBookmark(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	targetType, targetID, err := parseBookmarkTarget(c)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	if err := h.bookmarkService.RemoveBookmark(c.Context(), user.ID, targetType, targetID); err != nil {
+		return bookmarkErrorResponse(c, "Failed to remove bookmark", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark removed successfully", nil)
+}
+
+// GetBookmarkStatus handles checking whether the user saved a target
+// GET /api/v1/bookmarks/:type/:targetId/status
+func (h *BookmarkHandler) GetBookmarkStatus(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	targetType, targetID, err := parseBookmarkTarget(c)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	status, err := h.bookmarkService.GetBookmarkStatus(c.Context(), user.ID, targetType, targetID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get bookmark status", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark status retrieved successfully", status)
+}
+
+// GetBookmarks handles listing the user's saved items
+// GET /api/v1/bookmarks?type=topic&folderId=<id|none>&page=1&limit=20
+func (h *BookmarkHandler) GetBookmarks(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var params dto.BookmarkQueryParams
+	if err := c.QueryParser(&params); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	if err := utils.ValidateStruct(&params); err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	bookmarks, err := h.bookmarkService.GetBookmarks(c.Context(), user.ID, &params)
+	if err != nil {
+		return bookmarkErrorResponse(c, "Failed to get bookmarks", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmarks retrieved successfully", bookmarks)
+}
+
+// MoveBookmark handles moving a bookmark into a folder or out of one
+// PUT /api/v1/bookmarks/:id/folder
+func (h *BookmarkHandler) MoveBookmark(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	bookmarkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid bookmark ID")
+	}
+
+	var req dto.MoveBookmarkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	bookmark, err := h.bookmarkService.MoveBookmark(c.Context(), user.ID, bookmarkID, &req)
+	if err != nil {
+		return bookmarkErrorResponse(c, "Failed to move bookmark", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark moved successfully", bookmark)
+}
+
+// GetFolders handles listing the user's bookmark folders
+// GET /api/v1/bookmarks/folders
+func (h *BookmarkHandler) GetFolders(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	folders, err := h.bookmarkService.GetFolders(c.Context(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get bookmark folders", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark folders retrieved successfully", folders)
+}
+
+// CreateFolder handles creating a bookmark folder
+// POST /api/v1/bookmarks/folders
+func (h *BookmarkHandler) CreateFolder(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.CreateBookmarkFolderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	folder, err := h.bookmarkService.CreateFolder(c.Context(), user.ID, &req)
+	if err != nil {
+		return bookmarkErrorResponse(c, "Failed to create bookmark folder", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark folder created successfully", folder)
+}
+
+// UpdateFolder handles renaming a bookmark folder
+// PUT /api/v1/bookmarks/folders/:id
+func (h *BookmarkHandler) UpdateFolder(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid folder ID")
+	}
+
+	var req dto.UpdateBookmarkFolderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	folder, err := h.bookmarkService.UpdateFolder(c.Context(), user.ID, folderID, &req)
+	if err != nil {
+		return bookmarkErrorResponse(c, "Failed to update bookmark folder", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark folder updated successfully", folder)
+}
+
+// DeleteFolder handles deleting a bookmark folder; its bookmarks are kept outside any folder
+// DELETE /api/v1/bookmarks/folders/:id
+func (h *BookmarkHandler) DeleteFolder(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid folder ID")
+	}
+
+	if err := h.bookmarkService.DeleteFolder(c.Context(), user.ID, folderID); err != nil {
+		return bookmarkErrorResponse(c, "Failed to delete bookmark folder", err)
+	}
+
+	return utils.SuccessResponse(c, "Bookmark folder deleted successfully", nil)
+}
+
+func parseBookmarkTarget(c *fiber.Ctx) (string, uuid.UUID, error) {
+	targetType := c.Params("type")
+	switch targetType {
+	case "topic", "video", "reply", "comment":
+	default:
+		return "", uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid bookmark type")
+	}
+
+	targetID, err := uuid.Parse(c.Params("targetId"))
+	if err != nil {
+		return "", uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid target ID")
+	}
+
+	return targetType, targetID, nil
+}
+
+func bookmarkErrorResponse(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "you don't have permission"):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, message, err)
+	case strings.HasSuffix(err.Error(), "already bookmarked"), strings.HasSuffix(err.Error(), "already exists"):
+		return utils.ErrorResponse(c, fiber.StatusConflict, message, err)
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, message, err)
+	}
+}
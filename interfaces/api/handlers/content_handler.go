@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ContentHandler struct {
+	contentService services.ContentService
+}
+
+func NewContentHandler(contentService services.ContentService) *ContentHandler {
+	return &ContentHandler{contentService: contentService}
+}
+
+// PreviewContent validates a draft and returns how it will be rendered
+// POST /api/v1/content/preview
+func (h *ContentHandler) PreviewContent(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.PreviewContentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	rendered, err := h.contentService.Render(c.Context(), user.ID, req.Content)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid content", err)
+	}
+
+	return utils.SuccessResponse(c, "Content rendered successfully", dto.PreviewContentResponse{
+		ContentHTML: rendered.HTML,
+	})
+}
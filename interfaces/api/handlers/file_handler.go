@@ -54,6 +54,7 @@ func (h *FileHandler) UploadFile(c *fiber.Ctx) error {
 		Category:   c.FormValue("category"),
 		EntityID:   c.FormValue("entity_id"),
 		FileType:   c.FormValue("file_type"),
+		Protected:  c.FormValue("protected") == "true",
 	}
 
 	// Validate the upload options
@@ -88,7 +89,7 @@ func (h *FileHandler) UploadFile(c *fiber.Ctx) error {
 	uploadResponse := &dto.UploadResponse{
 		FileID:   fileModel.ID,
 		FileName: fileModel.FileName,
-		URL:      fileModel.URL,
+		URL:      h.fileService.FileURL(fileModel),
 		CDNPath:  fileModel.CDNPath,
 		FileSize: fileModel.FileSize,
 		MimeType: fileModel.MimeType,
@@ -99,6 +100,11 @@ func (h *FileHandler) UploadFile(c *fiber.Ctx) error {
 }
 
 func (h *FileHandler) GetFile(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	fileIDStr := c.Params("id")
 	fileID, err := uuid.Parse(fileIDStr)
 	if err != nil {
@@ -110,7 +116,13 @@ func (h *FileHandler) GetFile(c *fiber.Ctx) error {
 		return utils.NotFoundResponse(c, "File not found")
 	}
 
+	// Protected files are only handed to their owner
+	if file.Protected && file.UserID != user.ID && user.Role != "admin" {
+		return utils.NotFoundResponse(c, "File not found")
+	}
+
 	fileResponse := dto.FileToFileResponse(file)
+	fileResponse.URL = h.fileService.FileURL(file)
 	return utils.SuccessResponse(c, "File retrieved successfully", fileResponse)
 }
 
@@ -156,11 +168,18 @@ func (h *FileHandler) GetUserFiles(c *fiber.Ctx) error {
 	fileResponses := make([]dto.FileResponse, len(files))
 	for i, file := range files {
 		fileResponses[i] = *dto.FileToFileResponse(file)
+		fileResponses[i].URL = h.fileService.FileURL(file)
+	}
+
+	usage, err := h.fileService.GetStorageUsage(c.Context(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve storage usage", err)
 	}
 
 	response := &dto.FileListResponse{
 		Files: fileResponses,
 		Meta:  dto.NewPaginationMeta(total, offset, limit),
+		Usage: usage,
 	}
 
 	return utils.SuccessResponse(c, "Files retrieved successfully", response)
@@ -188,6 +207,7 @@ func (h *FileHandler) ListFiles(c *fiber.Ctx) error {
 	fileResponses := make([]dto.FileResponse, len(files))
 	for i, file := range files {
 		fileResponses[i] = *dto.FileToFileResponse(file)
+		fileResponses[i].URL = h.fileService.FileURL(file)
 	}
 
 	response := &dto.FileListResponse{
@@ -197,3 +217,16 @@ func (h *FileHandler) ListFiles(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, "Files retrieved successfully", response)
 }
+
+// CleanupOrphanedFiles runs the orphaned file GC immediately (admin)
+// POST /api/v1/files/cleanup
+func (h *FileHandler) CleanupOrphanedFiles(c *fiber.Ctx) error {
+	deleted, err := h.fileService.CleanupOrphanedFiles(c.Context())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to clean up orphaned files", err)
+	}
+
+	return utils.SuccessResponse(c, "Orphaned files cleaned up successfully", &dto.CleanupFilesResponse{
+		DeletedCount: deleted,
+	})
+}
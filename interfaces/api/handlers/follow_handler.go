@@ -147,3 +147,65 @@ func (h *FollowHandler) GetUserStats(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, "User stats retrieved successfully", stats)
 }
+
+// BlockUser handles blocking a user
+// POST /api/v1/users/:userId/block
+func (h *FollowHandler) BlockUser(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	blockedID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid user ID")
+	}
+
+	if err := h.followService.BlockUser(c.Context(), user.ID, blockedID); err != nil {
+		if err.Error() == "user not found" {
+			return utils.NotFoundResponse(c, "User not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to block user", err)
+	}
+
+	return utils.SuccessResponse(c, "User blocked successfully", nil)
+}
+
+// UnblockUser handles unblocking a user
+// DELETE /api/v1/users/:userId/block
+func (h *FollowHandler) UnblockUser(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	blockedID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid user ID")
+	}
+
+	if err := h.followService.UnblockUser(c.Context(), user.ID, blockedID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to unblock user", err)
+	}
+
+	return utils.SuccessResponse(c, "User unblocked successfully", nil)
+}
+
+// GetBlockedUsers handles listing the users the current user has blocked
+// GET /api/v1/blocks
+func (h *FollowHandler) GetBlockedUsers(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+
+	blocked, err := h.followService.GetBlockedUsers(c.Context(), user.ID, page, limit)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get blocked users", err)
+	}
+
+	return utils.SuccessResponse(c, "Blocked users retrieved successfully", blocked)
+}
@@ -4,6 +4,7 @@ import (
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/services"
 	"gofiber-social/pkg/utils"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -120,7 +121,7 @@ func (h *ForumHandler) ReorderForums(c *fiber.Ctx) error {
 
 // Public Handlers
 func (h *ForumHandler) GetActiveForums(c *fiber.Ctx) error {
-	forums, err := h.forumService.GetActiveForums(c.Context())
+	forums, err := h.forumService.GetActiveForums(c.Context(), viewerIDFromContext(c))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get forums", err)
 	}
@@ -137,9 +138,9 @@ func (h *ForumHandler) GetForumByID(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid forum ID")
 	}
 
-	forum, err := h.forumService.GetForumByID(c.Context(), forumID)
+	forum, err := h.forumService.GetForumByID(c.Context(), forumID, viewerIDFromContext(c))
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "Forum not found", err)
+		return forumAccessErrorResponse(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Forum retrieved successfully", forum)
@@ -148,9 +149,9 @@ func (h *ForumHandler) GetForumByID(c *fiber.Ctx) error {
 func (h *ForumHandler) GetForumBySlug(c *fiber.Ctx) error {
 	slug := c.Params("slug")
 
-	forum, err := h.forumService.GetForumBySlug(c.Context(), slug)
+	forum, err := h.forumService.GetForumBySlug(c.Context(), slug, viewerIDFromContext(c))
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "Forum not found", err)
+		return forumAccessErrorResponse(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Forum retrieved successfully", forum)
@@ -178,3 +179,81 @@ func (h *ForumHandler) SyncAllTopicCounts(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, "All topic counts synchronized successfully", nil)
 }
+
+// GetModerators handles listing the moderators of a forum
+// GET /api/v1/forums/:id/moderators
+func (h *ForumHandler) GetModerators(c *fiber.Ctx) error {
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid forum ID")
+	}
+
+	moderators, err := h.forumService.GetModerators(c.Context(), forumID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get moderators", err)
+	}
+
+	return utils.SuccessResponse(c, "Moderators retrieved successfully", moderators)
+}
+
+// AddModerator handles assigning a forum moderator (admin only)
+// POST /api/v1/admin/forums/:id/moderators
+func (h *ForumHandler) AddModerator(c *fiber.Ctx) error {
+	admin, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid forum ID")
+	}
+
+	var req dto.AddForumModeratorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	if err := h.forumService.AddModerator(c.Context(), admin.ID, forumID, req.UserID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to add moderator", err)
+	}
+
+	return utils.SuccessResponse(c, "Moderator added successfully", nil)
+}
+
+// RemoveModerator handles removing a forum moderator (admin only)
+// DELETE /api/v1/admin/forums/:id/moderators/:userId
+func (h *ForumHandler) RemoveModerator(c *fiber.Ctx) error {
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid forum ID")
+	}
+
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid user ID")
+	}
+
+	if err := h.forumService.RemoveModerator(c.Context(), forumID, userID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to remove moderator", err)
+	}
+
+	return utils.SuccessResponse(c, "Moderator removed successfully", nil)
+}
+
+// forumAccessErrorResponse maps forum permission errors: unknown/closed forums are 404, the rest 403
+func forumAccessErrorResponse(c *fiber.Ctx, err error) error {
+	if strings.HasSuffix(err.Error(), "not found") {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Not found", err)
+	}
+	return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied", err)
+}
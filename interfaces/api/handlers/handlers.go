@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"gofiber-social/domain/services"
+	websocketHandler "gofiber-social/interfaces/api/websocket"
 )
 
 // Services contains all the services needed for handlers
@@ -13,8 +14,12 @@ type Services struct {
 	ForumService        services.ForumService
 	TopicService        services.TopicService
 	ReplyService        services.ReplyService
+	PollService         services.PollService
 	TagService          services.TagService
 	VideoService        services.VideoService
+	VideoViewService    services.VideoViewService
+	AnalyticsService    services.AnalyticsService
+	PlaylistService     services.PlaylistService
 	LikeService         services.LikeService
 	CommentService      services.CommentService
 	ShareService        services.ShareService
@@ -22,6 +27,14 @@ type Services struct {
 	NotificationService services.NotificationService
 	AdminService        services.AdminService
 	ReportService       services.ReportService
+	RevisionService     services.RevisionService
+	ContentService      services.ContentService
+	BookmarkService     services.BookmarkService
+	WatchService        services.WatchService
+	ReadService         services.ReadService
+	RealtimeService     services.RealtimeService
+	MessageService      services.MessageService
+	QueueService        services.QueueService
 }
 
 // Handlers contains all HTTP handlers
@@ -33,8 +46,12 @@ type Handlers struct {
 	ForumHandler        *ForumHandler
 	TopicHandler        *TopicHandler
 	ReplyHandler        *ReplyHandler
+	PollHandler         *PollHandler
 	TagHandler          *TagHandler
 	VideoHandler        *VideoHandler
+	VideoViewHandler    *VideoViewHandler
+	AnalyticsHandler    *AnalyticsHandler
+	PlaylistHandler     *PlaylistHandler
 	LikeHandler         *LikeHandler
 	CommentHandler      *CommentHandler
 	ShareHandler        *ShareHandler
@@ -42,6 +59,14 @@ type Handlers struct {
 	NotificationHandler *NotificationHandler
 	AdminHandler        *AdminHandler
 	ReportHandler       *ReportHandler
+	RevisionHandler     *RevisionHandler
+	ContentHandler      *ContentHandler
+	BookmarkHandler     *BookmarkHandler
+	WatchHandler        *WatchHandler
+	ReadHandler         *ReadHandler
+	MessageHandler      *MessageHandler
+	QueueHandler        *QueueHandler
+	WebSocketHandler    *websocketHandler.WebSocketHandler
 }
 
 // NewHandlers creates a new instance of Handlers with all dependencies
@@ -54,8 +79,12 @@ func NewHandlers(services *Services) *Handlers {
 		ForumHandler:        NewForumHandler(services.ForumService),
 		TopicHandler:        NewTopicHandler(services.TopicService),
 		ReplyHandler:        NewReplyHandler(services.ReplyService),
+		PollHandler:         NewPollHandler(services.PollService),
 		TagHandler:          NewTagHandler(services.TagService),
 		VideoHandler:        NewVideoHandler(services.VideoService),
+		VideoViewHandler:    NewVideoViewHandler(services.VideoViewService),
+		AnalyticsHandler:    NewAnalyticsHandler(services.AnalyticsService),
+		PlaylistHandler:     NewPlaylistHandler(services.PlaylistService),
 		LikeHandler:         NewLikeHandler(services.LikeService),
 		CommentHandler:      NewCommentHandler(services.CommentService),
 		ShareHandler:        NewShareHandler(services.ShareService),
@@ -63,5 +92,13 @@ func NewHandlers(services *Services) *Handlers {
 		NotificationHandler: NewNotificationHandler(services.NotificationService),
 		AdminHandler:        NewAdminHandler(services.AdminService),
 		ReportHandler:       NewReportHandler(services.ReportService),
+		RevisionHandler:     NewRevisionHandler(services.RevisionService),
+		ContentHandler:      NewContentHandler(services.ContentService),
+		BookmarkHandler:     NewBookmarkHandler(services.BookmarkService),
+		WatchHandler:        NewWatchHandler(services.WatchService),
+		ReadHandler:         NewReadHandler(services.ReadService),
+		MessageHandler:      NewMessageHandler(services.MessageService),
+		QueueHandler:        NewQueueHandler(services.QueueService),
+		WebSocketHandler:    websocketHandler.NewWebSocketHandler(services.RealtimeService),
 	}
 }
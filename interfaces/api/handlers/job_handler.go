@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/services"
+	"gofiber-social/pkg/scheduler"
 	"gofiber-social/pkg/utils"
 	"strconv"
 
@@ -72,6 +74,15 @@ func (h *JobHandler) UpdateJob(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid request body")
 	}
 
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
 	job, err := h.jobService.UpdateJob(c.Context(), jobID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Job update failed", err)
@@ -126,6 +137,85 @@ func (h *JobHandler) StopJob(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "Job stopped successfully", nil)
 }
 
+// RunJob handles starting a run of a job right away, in the background
+// POST /api/v1/jobs/:id/run
+func (h *JobHandler) RunJob(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid job ID")
+	}
+
+	err = h.jobService.TriggerJob(c.Context(), jobID)
+	if err != nil {
+		if err.Error() == "job not found" {
+			return utils.NotFoundResponse(c, "Job not found")
+		}
+		if errors.Is(err, scheduler.ErrMaxConcurrency) {
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Job is already running", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to run job", err)
+	}
+
+	return utils.SuccessResponse(c, "Job run started successfully", nil)
+}
+
+// PauseJob handles skipping a job's firings until it is resumed
+// POST /api/v1/jobs/:id/pause
+func (h *JobHandler) PauseJob(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid job ID")
+	}
+
+	job, err := h.jobService.PauseJob(c.Context(), jobID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to pause job", err)
+	}
+
+	return utils.SuccessResponse(c, "Job paused successfully", dto.JobToJobResponse(job))
+}
+
+// ResumeJob handles running a paused job on its schedule again
+// POST /api/v1/jobs/:id/resume
+func (h *JobHandler) ResumeJob(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid job ID")
+	}
+
+	job, err := h.jobService.ResumeJob(c.Context(), jobID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to resume job", err)
+	}
+
+	return utils.SuccessResponse(c, "Job resumed successfully", dto.JobToJobResponse(job))
+}
+
+// PreviewCron handles validating a cron expression and listing its next run times
+// POST /api/v1/jobs/cron/preview
+func (h *JobHandler) PreviewCron(c *fiber.Ctx) error {
+	var req dto.CronPreviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	preview, err := h.jobService.PreviewCron(c.Context(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid cron expression", err)
+	}
+
+	return utils.SuccessResponse(c, "Cron expression is valid", preview)
+}
+
 func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
 	offsetStr := c.Query("offset", "0")
 	limitStr := c.Query("limit", "10")
@@ -151,9 +241,53 @@ func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
 	}
 
 	response := &dto.JobListResponse{
-		Jobs: jobResponses,
-		Meta: dto.NewPaginationMeta(total, offset, limit),
+		Jobs:      jobResponses,
+		Meta:      dto.NewPaginationMeta(total, offset, limit),
+		Scheduler: h.jobService.GetSchedulerStatus(c.Context()),
 	}
 
 	return utils.SuccessResponse(c, "Jobs retrieved successfully", response)
 }
+
+func (h *JobHandler) GetJobTypes(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, "Job types retrieved successfully", fiber.Map{
+		"types": h.jobService.GetJobTypes(),
+	})
+}
+
+func (h *JobHandler) GetJobRuns(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid job ID")
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid offset parameter")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
+	}
+
+	runs, total, err := h.jobService.GetJobRuns(c.Context(), jobID, offset, limit)
+	if err != nil {
+		if err.Error() == "job not found" {
+			return utils.NotFoundResponse(c, "Job not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve job runs", err)
+	}
+
+	runResponses := make([]dto.JobRunResponse, len(runs))
+	for i, run := range runs {
+		runResponses[i] = *dto.JobRunToJobRunResponse(run)
+	}
+
+	response := &dto.JobRunListResponse{
+		Runs: runResponses,
+		Meta: dto.NewPaginationMeta(total, offset, limit),
+	}
+
+	return utils.SuccessResponse(c, "Job runs retrieved successfully", response)
+}
@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"strings"
+
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type MessageHandler struct {
+	messageService services.MessageService
+}
+
+func NewMessageHandler(messageService services.MessageService) *MessageHandler {
+	return &MessageHandler{messageService: messageService}
+}
+
+// CreateConversation opens a direct conversation or creates a group, optionally with a first message
+// POST /api/v1/conversations
+func (h *MessageHandler) CreateConversation(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.CreateConversationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+	if req.Message != nil {
+		if err := utils.ValidateStruct(req.Message); err != nil {
+			errors := utils.GetValidationErrors(err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"message": "Validation failed",
+				"errors":  errors,
+			})
+		}
+	}
+
+	conversation, err := h.messageService.CreateConversation(c.Context(), user.ID, &req)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Conversation created successfully", conversation)
+}
+
+// GetConversations lists the inbox, or pending message requests with status=requests
+// GET /api/v1/conversations
+func (h *MessageHandler) GetConversations(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var params dto.ConversationListParams
+	if err := c.QueryParser(&params); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+	if err := utils.ValidateStruct(&params); err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	conversations, err := h.messageService.GetConversations(c.Context(), user.ID, &params)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Conversations retrieved successfully", conversations)
+}
+
+// GET /api/v1/conversations/unread-count
+func (h *MessageHandler) GetUnreadCount(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	count, err := h.messageService.GetUnreadCount(c.Context(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get unread count", err)
+	}
+
+	return utils.SuccessResponse(c, "Unread count retrieved successfully", count)
+}
+
+// GET /api/v1/conversations/:id
+func (h *MessageHandler) GetConversation(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	conversation, err := h.messageService.GetConversation(c.Context(), user.ID, conversationID)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Conversation retrieved successfully", conversation)
+}
+
+// POST /api/v1/conversations/:id/accept
+func (h *MessageHandler) AcceptRequest(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	conversation, err := h.messageService.AcceptRequest(c.Context(), user.ID, conversationID)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Message request accepted", conversation)
+}
+
+// POST /api/v1/conversations/:id/decline
+func (h *MessageHandler) DeclineRequest(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	if err := h.messageService.DeclineRequest(c.Context(), user.ID, conversationID); err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Message request declined", nil)
+}
+
+// POST /api/v1/conversations/:id/participants
+func (h *MessageHandler) AddParticipants(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	var req dto.AddParticipantsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	conversation, err := h.messageService.AddParticipants(c.Context(), user.ID, conversationID, &req)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Participants added successfully", conversation)
+}
+
+// RemoveParticipant removes someone from a group; removing yourself leaves it
+// DELETE /api/v1/conversations/:id/participants/:userId
+func (h *MessageHandler) RemoveParticipant(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	participantID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid user ID")
+	}
+
+	if err := h.messageService.RemoveParticipant(c.Context(), user.ID, conversationID, participantID); err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Participant removed successfully", nil)
+}
+
+// GetMessages returns history newest first; pass nextCursor as cursor for older messages
+// GET /api/v1/conversations/:id/messages
+func (h *MessageHandler) GetMessages(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	var params dto.MessageListParams
+	if err := c.QueryParser(&params); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+	if err := utils.ValidateStruct(&params); err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	messages, err := h.messageService.GetMessages(c.Context(), user.ID, conversationID, &params)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Messages retrieved successfully", messages)
+}
+
+// POST /api/v1/conversations/:id/messages
+func (h *MessageHandler) SendMessage(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	var req dto.SendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	message, err := h.messageService.SendMessage(c.Context(), user.ID, conversationID, &req)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Message sent successfully", message)
+}
+
+// PUT /api/v1/conversations/:id/read
+func (h *MessageHandler) MarkRead(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	conversationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid conversation ID")
+	}
+
+	// The body is optional
+	var req dto.MarkConversationReadRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ValidationErrorResponse(c, "Invalid request body")
+		}
+	}
+
+	if err := h.messageService.MarkRead(c.Context(), user.ID, conversationID, &req); err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Conversation marked as read", nil)
+}
+
+// PUT /api/v1/messages/:id
+func (h *MessageHandler) UpdateMessage(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	messageID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid message ID")
+	}
+
+	var req dto.UpdateMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	message, err := h.messageService.UpdateMessage(c.Context(), user.ID, messageID, &req)
+	if err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Message updated successfully", message)
+}
+
+// DELETE /api/v1/messages/:id
+func (h *MessageHandler) DeleteMessage(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	messageID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid message ID")
+	}
+
+	if err := h.messageService.DeleteMessage(c.Context(), user.ID, messageID); err != nil {
+		return messageErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Message deleted successfully", nil)
+}
+
+func messageErrorResponse(c *fiber.Ctx, err error) error {
+	message := err.Error()
+	switch {
+	case message == "invalid cursor":
+		return utils.ValidationErrorResponse(c, "Invalid cursor")
+	case strings.HasSuffix(message, "not found"):
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Not found", err)
+	case strings.HasPrefix(message, "you can't"),
+		strings.HasPrefix(message, "you can only"),
+		strings.HasPrefix(message, "you don't have permission"),
+		strings.HasPrefix(message, "only the creator"):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied", err)
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Request failed", err)
+	}
+}
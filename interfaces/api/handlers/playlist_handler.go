@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PlaylistHandler struct {
+	playlistService services.PlaylistService
+}
+
+func NewPlaylistHandler(playlistService services.PlaylistService) *PlaylistHandler {
+	return &PlaylistHandler{playlistService: playlistService}
+}
+
+// CreatePlaylist handles creating a playlist
+// POST /api/v1/playlists
+func (h *PlaylistHandler) CreatePlaylist(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.CreatePlaylistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	playlist, err := h.playlistService.CreatePlaylist(c.Context(), user.ID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to create playlist", err)
+	}
+
+	return utils.SuccessResponse(c, "Playlist created successfully", playlist)
+}
+
+// GetPlaylist handles getting a playlist with a page of its videos
+// GET /api/v1/playlists/:id
+func (h *PlaylistHandler) GetPlaylist(c *fiber.Ctx) error {
+	playlistID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid playlist ID")
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid page parameter")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
+	}
+
+	playlist, err := h.playlistService.GetPlaylist(c.Context(), playlistID, viewerIDFromContext(c), page, limit)
+	if err != nil {
+		return utils.NotFoundResponse(c, "Playlist not found")
+	}
+
+	return utils.SuccessResponse(c, "Playlist retrieved successfully", playlist)
+}
+
+// GetMyPlaylists handles getting the current user's playlists (all visibilities)
+// GET /api/v1/playlists/my
+func (h *PlaylistHandler) GetMyPlaylists(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	return h.listPlaylists(c, user.ID)
+}
+
+// GetUserPlaylists handles getting a user's public playlists
+// GET /api/v1/playlists/user/:userId
+func (h *PlaylistHandler) GetUserPlaylists(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid user ID")
+	}
+
+	return h.listPlaylists(c, userID)
+}
+
+// UpdatePlaylist handles updating a playlist
+// PUT /api/v1/playlists/:id
+func (h *PlaylistHandler) UpdatePlaylist(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	playlistID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid playlist ID")
+	}
+
+	var req dto.UpdatePlaylistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	playlist, err := h.playlistService.UpdatePlaylist(c.Context(), user.ID, playlistID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to update playlist", err)
+	}
+
+	return utils.SuccessResponse(c, "Playlist updated successfully", playlist)
+}
+
+// DeletePlaylist handles deleting a playlist
+// DELETE /api/v1/playlists/:id
+func (h *PlaylistHandler) DeletePlaylist(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	playlistID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid playlist ID")
+	}
+
+	if err := h.playlistService.DeletePlaylist(c.Context(), user.ID, playlistID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete playlist", err)
+	}
+
+	return utils.SuccessResponse(c, "Playlist deleted successfully", nil)
+}
+
+// AddVideo handles adding a video to the end of a playlist
+// POST /api/v1/playlists/:id/items
+func (h *PlaylistHandler) AddVideo(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	playlistID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid playlist ID")
+	}
+
+	var req dto.AddPlaylistItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	if err := h.playlistService.AddVideo(c.Context(), user.ID, playlistID, req.VideoID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to add video to playlist", err)
+	}
+
+	return utils.SuccessResponse(c, "Video added to playlist", nil)
+}
+
+// RemoveVideo handles removing a video from a playlist
+// DELETE /api/v1/playlists/:id/items/:videoId
+func (h *PlaylistHandler) RemoveVideo(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	playlistID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid playlist ID")
+	}
+
+	videoID, err := uuid.Parse(c.Params("videoId"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid video ID")
+	}
+
+	if err := h.playlistService.RemoveVideo(c.Context(), user.ID, playlistID, videoID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to remove video from playlist", err)
+	}
+
+	return utils.SuccessResponse(c, "Video removed from playlist", nil)
+}
+
+// ReorderVideos handles setting a new order for all videos in a playlist
+// PUT /api/v1/playlists/:id/items/order
+func (h *PlaylistHandler) ReorderVideos(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	playlistID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid playlist ID")
+	}
+
+	var req dto.ReorderPlaylistItemsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	if err := h.playlistService.ReorderVideos(c.Context(), user.ID, playlistID, &req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to reorder playlist", err)
+	}
+
+	return utils.SuccessResponse(c, "Playlist reordered successfully", nil)
+}
+
+// GetPlaylistsForVideo handles the "add to playlist" picker on the video detail
+// GET /api/v1/videos/:id/playlists
+func (h *PlaylistHandler) GetPlaylistsForVideo(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	videoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid video ID")
+	}
+
+	playlists, err := h.playlistService.GetPlaylistsForVideo(c.Context(), user.ID, videoID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve playlists", err)
+	}
+
+	return utils.SuccessResponse(c, "Playlists retrieved successfully", playlists)
+}
+
+func (h *PlaylistHandler) listPlaylists(c *fiber.Ctx, userID uuid.UUID) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid page parameter")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
+	}
+
+	playlists, err := h.playlistService.GetUserPlaylists(c.Context(), userID, viewerIDFromContext(c), page, limit)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve playlists", err)
+	}
+
+	return utils.SuccessResponse(c, "Playlists retrieved successfully", playlists)
+}
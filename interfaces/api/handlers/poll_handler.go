@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PollHandler struct {
+	pollService services.PollService
+}
+
+func NewPollHandler(pollService services.PollService) *PollHandler {
+	return &PollHandler{pollService: pollService}
+}
+
+// GetPoll handles getting the poll of a topic with the viewer's choices
+// GET /api/v1/topics/:id/poll
+func (h *PollHandler) GetPoll(c *fiber.Ctx) error {
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	poll, err := h.pollService.GetPoll(c.Context(), topicID, viewerIDFromContext(c))
+	if err != nil {
+		return pollErrorResponse(c, "Failed to get poll", err)
+	}
+
+	return utils.SuccessResponse(c, "Poll retrieved successfully", poll)
+}
+
+// Vote handles casting (or, when the poll allows it, changing) a vote
+// POST /api/v1/topics/:id/poll/votes
+func (h *PollHandler) Vote(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	var req dto.VotePollRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	poll, err := h.pollService.Vote(c.Context(), topicID, user.ID, &req)
+	if err != nil {
+		return pollErrorResponse(c, "Failed to vote", err)
+	}
+
+	return utils.SuccessResponse(c, "Vote recorded successfully", poll)
+}
+
+// RetractVote handles removing the user's vote
+// DELETE /api/v1/topics/:id/poll/votes
+func (h *PollHandler) RetractVote(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	poll, err := h.pollService.RetractVote(c.Context(), topicID, user.ID)
+	if err != nil {
+		return pollErrorResponse(c, "Failed to retract vote", err)
+	}
+
+	return utils.SuccessResponse(c, "Vote retracted successfully", poll)
+}
+
+// GetVoters handles listing who picked an option of a public poll
+// GET /api/v1/topics/:id/poll/options/:optionId/voters
+func (h *PollHandler) GetVoters(c *fiber.Ctx) error {
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	optionID, err := uuid.Parse(c.Params("optionId"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid option ID")
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+
+	voters, err := h.pollService.GetVoters(c.Context(), topicID, optionID, viewerIDFromContext(c), page, limit)
+	if err != nil {
+		return pollErrorResponse(c, "Failed to get voters", err)
+	}
+
+	return utils.SuccessResponse(c, "Voters retrieved successfully", voters)
+}
+
+// ClosePoll handles closing a poll early (topic author or moderator)
+// POST /api/v1/topics/:id/poll/close
+func (h *PollHandler) ClosePoll(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	poll, err := h.pollService.ClosePoll(c.Context(), topicID, user.ID)
+	if err != nil {
+		return pollErrorResponse(c, "Failed to close poll", err)
+	}
+
+	return utils.SuccessResponse(c, "Poll closed successfully", poll)
+}
+
+func pollErrorResponse(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "you don't have permission"), err.Error() == "votes on this poll are anonymous":
+		return utils.ErrorResponse(c, fiber.StatusForbidden, message, err)
+	case err.Error() == "you have already voted":
+		return utils.ErrorResponse(c, fiber.StatusConflict, message, err)
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, message, err)
+	}
+}
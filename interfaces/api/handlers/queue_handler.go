@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type QueueHandler struct {
+	queueService services.QueueService
+}
+
+func NewQueueHandler(queueService services.QueueService) *QueueHandler {
+	return &QueueHandler{queueService: queueService}
+}
+
+// GetStats handles counting background tasks by status
+// GET /api/v1/admin/queue/stats
+func (h *QueueHandler) GetStats(c *fiber.Ctx) error {
+	stats, err := h.queueService.GetStats(c.Context())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get queue stats", err)
+	}
+
+	return utils.SuccessResponse(c, "Queue stats retrieved successfully", stats)
+}
+
+// ListTasks handles listing background tasks, most recently changed first
+// GET /api/v1/admin/queue/tasks
+func (h *QueueHandler) ListTasks(c *fiber.Ctx) error {
+	var params dto.BackgroundTaskListParams
+	if err := c.QueryParser(&params); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+	if err := utils.ValidateStruct(&params); err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	tasks, err := h.queueService.ListTasks(c.Context(), &params)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve tasks", err)
+	}
+
+	return utils.SuccessResponse(c, "Tasks retrieved successfully", tasks)
+}
+
+// GetTask handles getting one background task with its last error
+// GET /api/v1/admin/queue/tasks/:id
+func (h *QueueHandler) GetTask(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid task ID")
+	}
+
+	task, err := h.queueService.GetTask(c.Context(), taskID)
+	if err != nil {
+		return utils.NotFoundResponse(c, "Task not found")
+	}
+
+	return utils.SuccessResponse(c, "Task retrieved successfully", task)
+}
+
+// ReplayTask handles queueing a dead task again
+// POST /api/v1/admin/queue/tasks/:id/replay
+func (h *QueueHandler) ReplayTask(c *fiber.Ctx) error {
+	taskID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid task ID")
+	}
+
+	task, err := h.queueService.GetTask(c.Context(), taskID)
+	if err != nil {
+		return utils.NotFoundResponse(c, "Task not found")
+	}
+	if task.Status != models.BackgroundTaskDead {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Only dead tasks can be replayed", nil)
+	}
+
+	result, err := h.queueService.ReplayTasks(c.Context(), &dto.ReplayTasksRequest{TaskIDs: []uuid.UUID{taskID}})
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to replay task", err)
+	}
+
+	return utils.SuccessResponse(c, "Task replayed successfully", result)
+}
+
+// ReplayTasks handles queueing dead tasks again: the given ones, or all of a type, or all
+// POST /api/v1/admin/queue/tasks/replay
+func (h *QueueHandler) ReplayTasks(c *fiber.Ctx) error {
+	var req dto.ReplayTasksRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	result, err := h.queueService.ReplayTasks(c.Context(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to replay tasks", err)
+	}
+
+	return utils.SuccessResponse(c, "Tasks replayed successfully", result)
+}
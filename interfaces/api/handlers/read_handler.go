@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ReadHandler struct {
+	readService services.ReadService
+}
+
+func NewReadHandler(readService services.ReadService) *ReadHandler {
+	return &ReadHandler{readService: readService}
+}
+
+// MarkTopicRead handles moving the user's read marker in a topic
+// PUT /api/v1/topics/:id/read
+func (h *ReadHandler) MarkTopicRead(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	// The body is optional: without replyId the whole topic is marked read
+	var req dto.MarkTopicReadRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ValidationErrorResponse(c, "Invalid request body")
+		}
+	}
+
+	if err := h.readService.MarkTopicRead(c.Context(), user.ID, topicID, &req); err != nil {
+		return forumAccessErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Topic marked as read", nil)
+}
+
+// GetFirstUnread handles locating the first reply the user has not read
+// GET /api/v1/topics/:id/first-unread
+func (h *ReadHandler) GetFirstUnread(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	resp, err := h.readService.GetFirstUnread(c.Context(), user.ID, topicID)
+	if err != nil {
+		return forumAccessErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "First unread reply retrieved successfully", resp)
+}
+
+// MarkForumRead handles marking every topic in a forum as read
+// PUT /api/v1/forums/:id/read
+func (h *ReadHandler) MarkForumRead(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid forum ID")
+	}
+
+	if err := h.readService.MarkForumRead(c.Context(), user.ID, forumID); err != nil {
+		return forumAccessErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Forum marked as read", nil)
+}
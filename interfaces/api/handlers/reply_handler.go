@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"gofiber-social/domain/dto"
 	"gofiber-social/domain/services"
 	"gofiber-social/pkg/utils"
 	"strconv"
+	"strings"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -44,6 +46,9 @@ func (h *ReplyHandler) CreateReply(c *fiber.Ctx) error {
 
 	reply, err := h.replyService.CreateReply(c.Context(), topicID, user.ID, &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "you don't have permission") {
+			return forumAccessErrorResponse(c, err)
+		}
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to create reply", err)
 	}
 
@@ -60,9 +65,9 @@ func (h *ReplyHandler) GetReplies(c *fiber.Ctx) error {
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 	limit, _ := strconv.Atoi(c.Query("limit", "50"))
 
-	replies, total, err := h.replyService.GetReplies(c.Context(), topicID, offset, limit)
+	replies, total, err := h.replyService.GetReplies(c.Context(), topicID, viewerIDFromContext(c), offset, limit)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get replies", err)
+		return forumAccessErrorResponse(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Replies retrieved successfully", fiber.Map{
@@ -71,6 +76,84 @@ func (h *ReplyHandler) GetReplies(c *fiber.Ctx) error {
 	})
 }
 
+// GET /api/v1/topics/:id/thread
+func (h *ReplyHandler) GetThread(c *fiber.Ctx) error {
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	params, err := parseReplyThreadParams(c)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	thread, err := h.replyService.GetThread(c.Context(), topicID, viewerIDFromContext(c), params)
+	if err != nil {
+		return replyThreadErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Thread retrieved successfully", thread)
+}
+
+// GET /api/v1/replies/:id/replies
+func (h *ReplyHandler) GetChildReplies(c *fiber.Ctx) error {
+	replyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid reply ID")
+	}
+
+	params, err := parseReplyThreadParams(c)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	thread, err := h.replyService.GetChildReplies(c.Context(), replyID, viewerIDFromContext(c), params)
+	if err != nil {
+		return replyThreadErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Replies retrieved successfully", thread)
+}
+
+// GET /api/v1/replies/:id/context
+func (h *ReplyHandler) GetReplyContext(c *fiber.Ctx) error {
+	replyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid reply ID")
+	}
+
+	params, err := parseReplyThreadParams(c)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	replyContext, err := h.replyService.GetReplyContext(c.Context(), replyID, viewerIDFromContext(c), params)
+	if err != nil {
+		return replyThreadErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Reply context retrieved successfully", replyContext)
+}
+
+func parseReplyThreadParams(c *fiber.Ctx) (*dto.ReplyThreadParams, error) {
+	var params dto.ReplyThreadParams
+	if err := c.QueryParser(&params); err != nil {
+		return nil, errors.New("invalid query parameters")
+	}
+	if err := utils.ValidateStruct(&params); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+func replyThreadErrorResponse(c *fiber.Ctx, err error) error {
+	if err.Error() == "invalid cursor" {
+		return utils.ValidationErrorResponse(c, "Invalid cursor")
+	}
+	return forumAccessErrorResponse(c, err)
+}
+
 func (h *ReplyHandler) UpdateReply(c *fiber.Ctx) error {
 	user, err := utils.GetUserFromContext(c)
 	if err != nil {
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"gofiber-social/domain/models"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RevisionHandler struct {
+	revisionService services.RevisionService
+}
+
+func NewRevisionHandler(revisionService services.RevisionService) *RevisionHandler {
+	return &RevisionHandler{revisionService: revisionService}
+}
+
+// GetTopicRevisions handles getting the edit history of a topic
+// GET /api/v1/topics/:id/revisions
+func (h *RevisionHandler) GetTopicRevisions(c *fiber.Ctx) error {
+	return h.listRevisions(c, models.RevisionContentTopic)
+}
+
+// GetReplyRevisions handles getting the edit history of a reply
+// GET /api/v1/replies/:id/revisions
+func (h *RevisionHandler) GetReplyRevisions(c *fiber.Ctx) error {
+	return h.listRevisions(c, models.RevisionContentReply)
+}
+
+// GetCommentRevisions handles getting the edit history of a comment
+// GET /api/v1/comments/:id/revisions
+func (h *RevisionHandler) GetCommentRevisions(c *fiber.Ctx) error {
+	return h.listRevisions(c, models.RevisionContentComment)
+}
+
+// RollbackTopic handles restoring an older version of a topic (admin only)
+// POST /api/v1/admin/topics/:id/revisions/:version/rollback
+func (h *RevisionHandler) RollbackTopic(c *fiber.Ctx) error {
+	return h.rollback(c, models.RevisionContentTopic)
+}
+
+// RollbackReply handles restoring an older version of a reply (admin only)
+// POST /api/v1/admin/replies/:id/revisions/:version/rollback
+func (h *RevisionHandler) RollbackReply(c *fiber.Ctx) error {
+	return h.rollback(c, models.RevisionContentReply)
+}
+
+// RollbackComment handles restoring an older version of a comment (admin only)
+// POST /api/v1/admin/comments/:id/revisions/:version/rollback
+func (h *RevisionHandler) RollbackComment(c *fiber.Ctx) error {
+	return h.rollback(c, models.RevisionContentComment)
+}
+
+func (h *RevisionHandler) listRevisions(c *fiber.Ctx, contentType models.RevisionContentType) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	contentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid "+string(contentType)+" ID")
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid page parameter")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
+	}
+
+	revisions, err := h.revisionService.GetRevisions(c.Context(), contentType, contentID, user.ID, user.Role == "admin", page, limit)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied", err)
+	}
+
+	return utils.SuccessResponse(c, "Revisions retrieved successfully", revisions)
+}
+
+func (h *RevisionHandler) rollback(c *fiber.Ctx, contentType models.RevisionContentType) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	contentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid "+string(contentType)+" ID")
+	}
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil || version < 1 {
+		return utils.ValidationErrorResponse(c, "Invalid revision version")
+	}
+
+	revision, err := h.revisionService.RollbackRevision(c.Context(), contentType, contentID, version, user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to roll back revision", err)
+	}
+
+	return utils.SuccessResponse(c, "Revision restored successfully", revision)
+}
@@ -44,6 +44,9 @@ func (h *TopicHandler) CreateTopic(c *fiber.Ctx) error {
 
 	topic, err := h.topicService.CreateTopic(c.Context(), user.ID, &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "you don't have permission") {
+			return forumAccessErrorResponse(c, err)
+		}
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to create topic", err)
 	}
 
@@ -57,9 +60,9 @@ func (h *TopicHandler) GetTopic(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid topic ID")
 	}
 
-	topic, err := h.topicService.GetTopic(c.Context(), topicID)
+	topic, err := h.topicService.GetTopic(c.Context(), topicID, viewerIDFromContext(c))
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "Topic not found", err)
+		return forumAccessErrorResponse(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Topic retrieved successfully", topic)
@@ -69,7 +72,12 @@ func (h *TopicHandler) GetTopics(c *fiber.Ctx) error {
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
 
-	topics, total, err := h.topicService.GetTopics(c.Context(), offset, limit)
+	var filter dto.TopicFilterParams
+	if err := c.QueryParser(&filter); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	topics, total, err := h.topicService.GetTopics(c.Context(), viewerIDFromContext(c), &filter, offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get topics", err)
 	}
@@ -89,9 +97,14 @@ func (h *TopicHandler) GetTopicsByForum(c *fiber.Ctx) error {
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
 
-	topics, total, err := h.topicService.GetTopicsByForum(c.Context(), forumID, offset, limit)
+	var filter dto.TopicFilterParams
+	if err := c.QueryParser(&filter); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	topics, total, err := h.topicService.GetTopicsByForum(c.Context(), forumID, viewerIDFromContext(c), &filter, offset, limit)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get topics", err)
+		return forumAccessErrorResponse(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Topics retrieved successfully", fiber.Map{
@@ -109,9 +122,14 @@ func (h *TopicHandler) GetTopicsByForumSlug(c *fiber.Ctx) error {
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
 
-	topics, total, err := h.topicService.GetTopicsByForumSlug(c.Context(), slug, offset, limit)
+	var filter dto.TopicFilterParams
+	if err := c.QueryParser(&filter); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	topics, total, err := h.topicService.GetTopicsByForumSlug(c.Context(), slug, viewerIDFromContext(c), &filter, offset, limit)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get topics", err)
+		return forumAccessErrorResponse(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Topics retrieved successfully", fiber.Map{
@@ -176,7 +194,7 @@ func (h *TopicHandler) SearchTopics(c *fiber.Ctx) error {
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
 
-	topics, total, err := h.topicService.SearchTopics(c.Context(), query, offset, limit)
+	topics, total, err := h.topicService.SearchTopics(c.Context(), query, viewerIDFromContext(c), offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to search topics", err)
 	}
@@ -196,7 +214,7 @@ func (h *TopicHandler) GetTopicsByTag(c *fiber.Ctx) error {
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
 
-	topics, total, err := h.topicService.GetTopicsByTag(c.Context(), tag, offset, limit)
+	topics, total, err := h.topicService.GetTopicsByTag(c.Context(), tag, viewerIDFromContext(c), offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get topics by tag", err)
 	}
@@ -229,7 +247,7 @@ func (h *TopicHandler) GetTopicsByTags(c *fiber.Ctx) error {
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
 
-	topics, total, err := h.topicService.GetTopicsByTags(c.Context(), tags, offset, limit)
+	topics, total, err := h.topicService.GetTopicsByTags(c.Context(), tags, viewerIDFromContext(c), offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get topics by tags", err)
 	}
@@ -240,14 +258,19 @@ func (h *TopicHandler) GetTopicsByTags(c *fiber.Ctx) error {
 	})
 }
 
-// Admin Handlers
+// Moderator Handlers (admin หรือ moderator ของ forum)
 func (h *TopicHandler) PinTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	topicID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid topic ID")
 	}
 
-	if err := h.topicService.PinTopic(c.Context(), topicID); err != nil {
+	if err := h.topicService.PinTopic(c.Context(), topicID, user.ID); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to pin topic", err)
 	}
 
@@ -255,12 +278,17 @@ func (h *TopicHandler) PinTopic(c *fiber.Ctx) error {
 }
 
 func (h *TopicHandler) UnpinTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	topicID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid topic ID")
 	}
 
-	if err := h.topicService.UnpinTopic(c.Context(), topicID); err != nil {
+	if err := h.topicService.UnpinTopic(c.Context(), topicID, user.ID); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to unpin topic", err)
 	}
 
@@ -268,12 +296,17 @@ func (h *TopicHandler) UnpinTopic(c *fiber.Ctx) error {
 }
 
 func (h *TopicHandler) LockTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	topicID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid topic ID")
 	}
 
-	if err := h.topicService.LockTopic(c.Context(), topicID); err != nil {
+	if err := h.topicService.LockTopic(c.Context(), topicID, user.ID); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to lock topic", err)
 	}
 
@@ -281,12 +314,17 @@ func (h *TopicHandler) LockTopic(c *fiber.Ctx) error {
 }
 
 func (h *TopicHandler) UnlockTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	topicID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid topic ID")
 	}
 
-	if err := h.topicService.UnlockTopic(c.Context(), topicID); err != nil {
+	if err := h.topicService.UnlockTopic(c.Context(), topicID, user.ID); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to unlock topic", err)
 	}
 
@@ -305,3 +343,182 @@ func (h *TopicHandler) DeleteTopicByAdmin(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, "Topic deleted successfully", nil)
 }
+
+// PUT /api/v1/topics/:id/accepted-answer
+func (h *TopicHandler) AcceptAnswer(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	var req dto.AcceptAnswerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	topic, err := h.topicService.AcceptAnswer(c.Context(), topicID, user.ID, &req)
+	if err != nil {
+		return acceptedAnswerErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Answer accepted successfully", topic)
+}
+
+// DELETE /api/v1/topics/:id/accepted-answer
+func (h *TopicHandler) UnacceptAnswer(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	topic, err := h.topicService.UnacceptAnswer(c.Context(), topicID, user.ID)
+	if err != nil {
+		return acceptedAnswerErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Accepted answer removed successfully", topic)
+}
+
+// POST /api/v1/topics/:id/move
+func (h *TopicHandler) MoveTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	var req dto.MoveTopicRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	topic, err := h.topicService.MoveTopic(c.Context(), topicID, user.ID, &req)
+	if err != nil {
+		return topicModerationErrorResponse(c, err, "Failed to move topic")
+	}
+
+	return utils.SuccessResponse(c, "Topic moved successfully", topic)
+}
+
+// POST /api/v1/topics/:id/merge
+func (h *TopicHandler) MergeTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	var req dto.MergeTopicRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	topic, err := h.topicService.MergeTopic(c.Context(), topicID, user.ID, &req)
+	if err != nil {
+		return topicModerationErrorResponse(c, err, "Failed to merge topic")
+	}
+
+	return utils.SuccessResponse(c, "Topic merged successfully", topic)
+}
+
+// POST /api/v1/topics/:id/split
+func (h *TopicHandler) SplitTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	var req dto.SplitTopicRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	topic, err := h.topicService.SplitTopic(c.Context(), topicID, user.ID, &req)
+	if err != nil {
+		return topicModerationErrorResponse(c, err, "Failed to split topic")
+	}
+
+	return utils.SuccessResponse(c, "Topic split successfully", topic)
+}
+
+func topicModerationErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "you are not a moderator"):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied", err)
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, message, err)
+	}
+}
+
+func acceptedAnswerErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return utils.NotFoundResponse(c, err.Error())
+	case strings.HasPrefix(err.Error(), "you don't have permission"):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied", err)
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to update accepted answer", err)
+	}
+}
+
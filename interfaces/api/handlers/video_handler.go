@@ -71,7 +71,7 @@ func (h *VideoHandler) GetVideos(c *fiber.Ctx) error {
 		SortBy: sortBy,
 	}
 
-	videos, err := h.videoService.GetVideos(c.Context(), params)
+	videos, err := h.videoService.GetVideos(c.Context(), params, viewerIDFromContext(c))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve videos", err)
 	}
@@ -87,7 +87,7 @@ func (h *VideoHandler) GetVideoByID(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid video ID")
 	}
 
-	video, err := h.videoService.GetVideoByID(c.Context(), videoID)
+	video, err := h.videoService.GetVideoByID(c.Context(), videoID, viewerIDFromContext(c))
 	if err != nil {
 		return utils.NotFoundResponse(c, "Video not found")
 	}
@@ -95,6 +95,32 @@ func (h *VideoHandler) GetVideoByID(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "Video retrieved successfully", video)
 }
 
+// GetVideoMedia issues fresh (signed if needed) media URLs for the current viewer
+// GET /api/v1/videos/:id/media
+func (h *VideoHandler) GetVideoMedia(c *fiber.Ctx) error {
+	videoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid video ID")
+	}
+
+	var viewerID *uuid.UUID
+	isAdmin := false
+	if user, err := utils.GetUserFromContext(c); err == nil {
+		viewerID = &user.ID
+		isAdmin = user.Role == "admin"
+	}
+
+	media, err := h.videoService.GetVideoMedia(c.Context(), videoID, viewerID, isAdmin)
+	if err != nil {
+		if err.Error() == "video not found" {
+			return utils.NotFoundResponse(c, "Video not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied", err)
+	}
+
+	return utils.SuccessResponse(c, "Video media retrieved successfully", media)
+}
+
 // GetUserVideos handles getting videos by user
 // GET /api/v1/videos/user/:userId
 func (h *VideoHandler) GetUserVideos(c *fiber.Ctx) error {
@@ -121,7 +147,7 @@ func (h *VideoHandler) GetUserVideos(c *fiber.Ctx) error {
 		Limit: limit,
 	}
 
-	videos, err := h.videoService.GetUserVideos(c.Context(), userID, params)
+	videos, err := h.videoService.GetUserVideos(c.Context(), userID, params, viewerIDFromContext(c))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve user videos", err)
 	}
@@ -256,3 +282,12 @@ func (h *VideoHandler) DeleteVideoByAdmin(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, "Video deleted successfully", nil)
 }
+
+// viewerIDFromContext returns the authenticated user's ID when the optional auth middleware found one
+func viewerIDFromContext(c *fiber.Ctx) *uuid.UUID {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return nil
+	}
+	return &user.ID
+}
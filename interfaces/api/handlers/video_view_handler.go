@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type VideoViewHandler struct {
+	videoViewService services.VideoViewService
+}
+
+func NewVideoViewHandler(videoViewService services.VideoViewService) *VideoViewHandler {
+	return &VideoViewHandler{videoViewService: videoViewService}
+}
+
+// TrackViewEvent handles playback events (start, heartbeat, complete)
+// POST /api/v1/videos/:id/views
+func (h *VideoViewHandler) TrackViewEvent(c *fiber.Ctx) error {
+	videoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid video ID")
+	}
+
+	var req dto.VideoViewEventRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	result, err := h.videoViewService.TrackEvent(c.Context(), videoID, viewerIDFromContext(c), c.IP(), c.Get(fiber.HeaderUserAgent), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to track view event", err)
+	}
+
+	return utils.SuccessResponse(c, "View event recorded", result)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
+	"gofiber-social/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WatchHandler struct {
+	watchService services.WatchService
+}
+
+func NewWatchHandler(watchService services.WatchService) *WatchHandler {
+	return &WatchHandler{watchService: watchService}
+}
+
+// WatchTopic handles subscribing to new replies in a topic
+// POST /api/v1/topics/:id/watch
+func (h *WatchHandler) WatchTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	status, err := h.watchService.WatchTopic(c.Context(), user.ID, topicID)
+	if err != nil {
+		return forumAccessErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Topic watched successfully", status)
+}
+
+// UnwatchTopic handles unsubscribing from a topic
+// DELETE /api/v1/topics/:id/watch
+func (h *WatchHandler) UnwatchTopic(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	status, err := h.watchService.UnwatchTopic(c.Context(), user.ID, topicID)
+	if err != nil {
+		return utils.NotFoundResponse(c, "Topic not found")
+	}
+
+	return utils.SuccessResponse(c, "Topic unwatched successfully", status)
+}
+
+// GetTopicWatchStatus handles checking whether the user watches a topic
+// GET /api/v1/topics/:id/watch
+func (h *WatchHandler) GetTopicWatchStatus(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	topicID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid topic ID")
+	}
+
+	status, err := h.watchService.GetTopicWatchStatus(c.Context(), user.ID, topicID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get watch status", err)
+	}
+
+	return utils.SuccessResponse(c, "Watch status retrieved successfully", status)
+}
+
+// WatchForum handles subscribing to new topics in a forum
+// POST /api/v1/forums/:id/watch
+func (h *WatchHandler) WatchForum(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid forum ID")
+	}
+
+	status, err := h.watchService.WatchForum(c.Context(), user.ID, forumID)
+	if err != nil {
+		return forumAccessErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Forum watched successfully", status)
+}
+
+// UnwatchForum handles unsubscribing from a forum
+// DELETE /api/v1/forums/:id/watch
+func (h *WatchHandler) UnwatchForum(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid forum ID")
+	}
+
+	status, err := h.watchService.UnwatchForum(c.Context(), user.ID, forumID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to unwatch forum", err)
+	}
+
+	return utils.SuccessResponse(c, "Forum unwatched successfully", status)
+}
+
+// GetForumWatchStatus handles checking whether the user watches a forum
+// GET /api/v1/forums/:id/watch
+func (h *WatchHandler) GetForumWatchStatus(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid forum ID")
+	}
+
+	status, err := h.watchService.GetForumWatchStatus(c.Context(), user.ID, forumID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get watch status", err)
+	}
+
+	return utils.SuccessResponse(c, "Watch status retrieved successfully", status)
+}
+
+// GetWatchedTopics handles listing watched topics with their unread reply counts
+// GET /api/v1/watches/topics?unread=true&page=1&limit=20
+func (h *WatchHandler) GetWatchedTopics(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var params dto.WatchedTopicQueryParams
+	if err := c.QueryParser(&params); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	if err := utils.ValidateStruct(&params); err != nil {
+		return utils.ValidationErrorResponse(c, err.Error())
+	}
+
+	topics, err := h.watchService.GetWatchedTopics(c.Context(), user.ID, &params)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get watched topics", err)
+	}
+
+	return utils.SuccessResponse(c, "Watched topics retrieved successfully", topics)
+}
+
+// GetWatchedForums handles listing watched forums
+// GET /api/v1/watches/forums
+func (h *WatchHandler) GetWatchedForums(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	forums, err := h.watchService.GetWatchedForums(c.Context(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get watched forums", err)
+	}
+
+	return utils.SuccessResponse(c, "Watched forums retrieved successfully", forums)
+}
+
+// GetPreferences handles getting the user's auto-watch settings
+// GET /api/v1/watches/preferences
+func (h *WatchHandler) GetPreferences(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	preferences, err := h.watchService.GetPreferences(c.Context(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get watch preferences", err)
+	}
+
+	return utils.SuccessResponse(c, "Watch preferences retrieved successfully", preferences)
+}
+
+// UpdatePreferences handles changing the user's auto-watch settings
+// PUT /api/v1/watches/preferences
+func (h *WatchHandler) UpdatePreferences(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.UpdateWatchPreferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	preferences, err := h.watchService.UpdatePreferences(c.Context(), user.ID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update watch preferences", err)
+	}
+
+	return utils.SuccessResponse(c, "Watch preferences updated successfully", preferences)
+}
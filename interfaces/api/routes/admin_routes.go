@@ -14,6 +14,9 @@ func SetupAdminRoutes(api fiber.Router, h *handlers.Handlers) {
 	admin.Get("/dashboard/stats", h.AdminHandler.GetDashboardStats)     // GET /api/v1/admin/dashboard/stats
 	admin.Get("/dashboard/charts", h.AdminHandler.GetDashboardCharts)   // GET /api/v1/admin/dashboard/charts
 
+	// WebSocket delivery
+	admin.Get("/websocket/stats", h.WebSocketHandler.GetStats)          // GET /api/v1/admin/websocket/stats
+
 	// User Management
 	users := admin.Group("/users")
 	users.Get("/", h.AdminHandler.GetUsers)                  // GET /api/v1/admin/users
@@ -29,6 +32,14 @@ func SetupAdminRoutes(api fiber.Router, h *handlers.Handlers) {
 	reports.Get("/:id", h.AdminHandler.GetReportByID)        // GET /api/v1/admin/reports/:id
 	reports.Put("/:id/review", h.AdminHandler.ReviewReport)  // PUT /api/v1/admin/reports/:id/review
 
+	// Background Task Queue
+	queue := admin.Group("/queue")
+	queue.Get("/stats", h.QueueHandler.GetStats)               // GET /api/v1/admin/queue/stats
+	queue.Get("/tasks", h.QueueHandler.ListTasks)              // GET /api/v1/admin/queue/tasks
+	queue.Post("/tasks/replay", h.QueueHandler.ReplayTasks)    // POST /api/v1/admin/queue/tasks/replay
+	queue.Get("/tasks/:id", h.QueueHandler.GetTask)            // GET /api/v1/admin/queue/tasks/:id
+	queue.Post("/tasks/:id/replay", h.QueueHandler.ReplayTask) // POST /api/v1/admin/queue/tasks/:id/replay
+
 	// Activity Logs
 	admin.Get("/activity-logs", h.AdminHandler.GetActivityLogs) // GET /api/v1/admin/activity-logs
 }
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"gofiber-social/interfaces/api/handlers"
+	"gofiber-social/interfaces/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAnalyticsRoutes(api fiber.Router, h *handlers.Handlers) {
+	// Creator analytics (owner only)
+	analytics := api.Group("/analytics", middleware.Protected())
+	analytics.Get("/creator", h.AnalyticsHandler.GetCreatorAnalytics)   // GET /api/v1/analytics/creator
+	analytics.Get("/videos/:id", h.AnalyticsHandler.GetVideoAnalytics)  // GET /api/v1/analytics/videos/:id
+	analytics.Get("/topics/:id", h.AnalyticsHandler.GetTopicAnalytics)  // GET /api/v1/analytics/topics/:id
+
+	// Admin routes
+	adminAnalytics := api.Group("/admin/analytics")
+	adminAnalytics.Use(middleware.Protected(), middleware.AdminOnly())
+	adminAnalytics.Post("/rollup", h.AnalyticsHandler.RollupDay) // POST /api/v1/admin/analytics/rollup
+}
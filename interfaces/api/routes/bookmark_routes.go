@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"gofiber-social/interfaces/api/handlers"
+	"gofiber-social/interfaces/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupBookmarkRoutes(api fiber.Router, h *handlers.Handlers) {
+	bookmarks := api.Group("/bookmarks", middleware.Protected())
+
+	// Folders (static paths before /:type/:targetId)
+	bookmarks.Get("/folders", h.BookmarkHandler.GetFolders)          // GET /api/v1/bookmarks/folders
+	bookmarks.Post("/folders", h.BookmarkHandler.CreateFolder)       // POST /api/v1/bookmarks/folders
+	bookmarks.Put("/folders/:id", h.BookmarkHandler.UpdateFolder)    // PUT /api/v1/bookmarks/folders/:id
+	bookmarks.Delete("/folders/:id", h.BookmarkHandler.DeleteFolder) // DELETE /api/v1/bookmarks/folders/:id
+
+	// Saved items
+	bookmarks.Get("/", h.BookmarkHandler.GetBookmarks)                            // GET /api/v1/bookmarks
+	bookmarks.Post("/", h.BookmarkHandler.AddBookmark)                            // POST /api/v1/bookmarks
+	bookmarks.Put("/:id/folder", h.BookmarkHandler.MoveBookmark)                  // PUT /api/v1/bookmarks/:id/folder
+	bookmarks.Get("/:type/:targetId/status", h.BookmarkHandler.GetBookmarkStatus) // GET /api/v1/bookmarks/:type/:targetId/status
+	bookmarks.Delete("/:type/:targetId", h.BookmarkHandler.RemoveBookmark)        // DELETE /api/v1/bookmarks/:type/:targetId
+}
@@ -13,6 +13,7 @@ func SetupCommentRoutes(api fiber.Router, h *handlers.Handlers) {
 	comments.Post("/", middleware.Protected(), h.CommentHandler.CreateComment)       // POST /api/v1/comments
 	comments.Put("/:id", middleware.Protected(), h.CommentHandler.UpdateComment)     // PUT /api/v1/comments/:id
 	comments.Delete("/:id", middleware.Protected(), h.CommentHandler.DeleteComment)  // DELETE /api/v1/comments/:id
+	comments.Get("/:id/revisions", middleware.Protected(), h.RevisionHandler.GetCommentRevisions) // GET /api/v1/comments/:id/revisions
 
 	// Video comments route (public)
 	videos := api.Group("/videos")
@@ -22,4 +23,5 @@ func SetupCommentRoutes(api fiber.Router, h *handlers.Handlers) {
 	adminComments := api.Group("/admin/comments")
 	adminComments.Use(middleware.Protected(), middleware.AdminOnly())
 	adminComments.Delete("/:id", h.CommentHandler.DeleteCommentByAdmin)  // DELETE /api/v1/admin/comments/:id
+	adminComments.Post("/:id/revisions/:version/rollback", h.RevisionHandler.RollbackComment) // POST /api/v1/admin/comments/:id/revisions/:version/rollback
 }
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"gofiber-social/interfaces/api/handlers"
+	"gofiber-social/interfaces/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupContentRoutes(api fiber.Router, h *handlers.Handlers) {
+	content := api.Group("/content")
+	content.Use(middleware.Protected())
+	content.Post("/preview", h.ContentHandler.PreviewContent)
+}
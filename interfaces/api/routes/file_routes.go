@@ -12,6 +12,7 @@ func SetupFileRoutes(api fiber.Router, h *handlers.Handlers) {
 	files.Use(middleware.Protected())
 	files.Post("/upload", h.FileHandler.UploadFile)
 	files.Get("/", middleware.AdminOnly(), h.FileHandler.ListFiles)
+	files.Post("/cleanup", middleware.AdminOnly(), h.FileHandler.CleanupOrphanedFiles)
 	files.Get("/my", h.FileHandler.GetUserFiles)
 	files.Get("/:id", h.FileHandler.GetFile)
 	files.Delete("/:id", middleware.OwnerOnly(), h.FileHandler.DeleteFile)
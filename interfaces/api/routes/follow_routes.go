@@ -17,4 +17,9 @@ func SetupFollowRoutes(api fiber.Router, h *handlers.Handlers) {
 	api.Post("/users/:userId/follow", middleware.Protected(), h.FollowHandler.FollowUser)                 // POST /api/v1/users/:userId/follow
 	api.Delete("/users/:userId/follow", middleware.Protected(), h.FollowHandler.UnfollowUser)             // DELETE /api/v1/users/:userId/follow
 	api.Get("/users/:userId/follow/status", middleware.Protected(), h.FollowHandler.GetFollowStatus)      // GET /api/v1/users/:userId/follow/status
+
+	// Blocks
+	api.Post("/users/:userId/block", middleware.Protected(), h.FollowHandler.BlockUser)                   // POST /api/v1/users/:userId/block
+	api.Delete("/users/:userId/block", middleware.Protected(), h.FollowHandler.UnblockUser)               // DELETE /api/v1/users/:userId/block
+	api.Get("/blocks", middleware.Protected(), h.FollowHandler.GetBlockedUsers)                           // GET /api/v1/blocks
 }
@@ -20,11 +20,15 @@ func SetupForumRoutes(api fiber.Router, h *handlers.Handlers) {
 	adminForums.Delete("/:id", h.ForumHandler.DeleteForum)
 	adminForums.Post("/:id/sync-topic-count", h.ForumHandler.SyncTopicCount)
 	adminForums.Post("/sync-all-topic-counts", h.ForumHandler.SyncAllTopicCounts)
+	adminForums.Post("/:id/moderators", h.ForumHandler.AddModerator)
+	adminForums.Delete("/:id/moderators/:userId", h.ForumHandler.RemoveModerator)
 
 	// Public Routes
 	forums := api.Group("/forums")
+	forums.Use(middleware.Optional())
 
 	forums.Get("/", h.ForumHandler.GetActiveForums)
 	forums.Get("/:id", h.ForumHandler.GetForumByID)
+	forums.Get("/:id/moderators", h.ForumHandler.GetModerators)
 	forums.Get("/slug/:slug", h.ForumHandler.GetForumBySlug)
 }
@@ -13,9 +13,15 @@ func SetupJobRoutes(api fiber.Router, h *handlers.Handlers) {
 	jobs.Use(middleware.AdminOnly()) // All job operations require admin access
 	jobs.Post("/", h.JobHandler.CreateJob)
 	jobs.Get("/", h.JobHandler.ListJobs)
+	jobs.Get("/types", h.JobHandler.GetJobTypes)
+	jobs.Post("/cron/preview", h.JobHandler.PreviewCron)
 	jobs.Get("/:id", h.JobHandler.GetJob)
+	jobs.Get("/:id/runs", h.JobHandler.GetJobRuns)
 	jobs.Put("/:id", h.JobHandler.UpdateJob)
 	jobs.Delete("/:id", h.JobHandler.DeleteJob)
 	jobs.Post("/:id/start", h.JobHandler.StartJob)
 	jobs.Post("/:id/stop", h.JobHandler.StopJob)
+	jobs.Post("/:id/run", h.JobHandler.RunJob)
+	jobs.Post("/:id/pause", h.JobHandler.PauseJob)
+	jobs.Post("/:id/resume", h.JobHandler.ResumeJob)
 }
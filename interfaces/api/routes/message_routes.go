@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"gofiber-social/interfaces/api/handlers"
+	"gofiber-social/interfaces/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupMessageRoutes(api fiber.Router, h *handlers.Handlers) {
+	conversations := api.Group("/conversations", middleware.Protected())
+	conversations.Post("/", h.MessageHandler.CreateConversation)                          // POST /api/v1/conversations
+	conversations.Get("/", h.MessageHandler.GetConversations)                             // GET /api/v1/conversations?status=active|requests
+	conversations.Get("/unread-count", h.MessageHandler.GetUnreadCount)                   // GET /api/v1/conversations/unread-count
+	conversations.Get("/:id", h.MessageHandler.GetConversation)                           // GET /api/v1/conversations/:id
+	conversations.Post("/:id/accept", h.MessageHandler.AcceptRequest)                     // POST /api/v1/conversations/:id/accept
+	conversations.Post("/:id/decline", h.MessageHandler.DeclineRequest)                   // POST /api/v1/conversations/:id/decline
+	conversations.Post("/:id/participants", h.MessageHandler.AddParticipants)             // POST /api/v1/conversations/:id/participants
+	conversations.Delete("/:id/participants/:userId", h.MessageHandler.RemoveParticipant) // DELETE /api/v1/conversations/:id/participants/:userId
+	conversations.Get("/:id/messages", h.MessageHandler.GetMessages)                      // GET /api/v1/conversations/:id/messages
+	conversations.Post("/:id/messages", h.MessageHandler.SendMessage)                     // POST /api/v1/conversations/:id/messages
+	conversations.Put("/:id/read", h.MessageHandler.MarkRead)                             // PUT /api/v1/conversations/:id/read
+
+	messages := api.Group("/messages", middleware.Protected())
+	messages.Put("/:id", h.MessageHandler.UpdateMessage)    // PUT /api/v1/messages/:id
+	messages.Delete("/:id", h.MessageHandler.DeleteMessage) // DELETE /api/v1/messages/:id
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"gofiber-social/interfaces/api/handlers"
+	"gofiber-social/interfaces/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupPlaylistRoutes(api fiber.Router, h *handlers.Handlers) {
+	playlists := api.Group("/playlists")
+
+	// Static paths before /:id
+	playlists.Get("/my", middleware.Protected(), h.PlaylistHandler.GetMyPlaylists)                 // GET /api/v1/playlists/my
+	playlists.Get("/user/:userId", middleware.Optional(), h.PlaylistHandler.GetUserPlaylists)      // GET /api/v1/playlists/user/:userId
+
+	// Public (unlisted works by link, private only for the owner)
+	playlists.Get("/:id", middleware.Optional(), h.PlaylistHandler.GetPlaylist)                    // GET /api/v1/playlists/:id
+
+	// Protected owner routes
+	playlists.Post("/", middleware.Protected(), h.PlaylistHandler.CreatePlaylist)                  // POST /api/v1/playlists
+	playlists.Put("/:id", middleware.Protected(), h.PlaylistHandler.UpdatePlaylist)                // PUT /api/v1/playlists/:id
+	playlists.Delete("/:id", middleware.Protected(), h.PlaylistHandler.DeletePlaylist)             // DELETE /api/v1/playlists/:id
+	playlists.Post("/:id/items", middleware.Protected(), h.PlaylistHandler.AddVideo)               // POST /api/v1/playlists/:id/items
+	playlists.Put("/:id/items/order", middleware.Protected(), h.PlaylistHandler.ReorderVideos)     // PUT /api/v1/playlists/:id/items/order
+	playlists.Delete("/:id/items/:videoId", middleware.Protected(), h.PlaylistHandler.RemoveVideo) // DELETE /api/v1/playlists/:id/items/:videoId
+
+	// "Add to playlist" from the video detail
+	videos := api.Group("/videos")
+	videos.Get("/:id/playlists", middleware.Protected(), h.PlaylistHandler.GetPlaylistsForVideo) // GET /api/v1/videos/:id/playlists
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"gofiber-social/interfaces/api/handlers"
+	"gofiber-social/interfaces/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupReadRoutes(api fiber.Router, h *handlers.Handlers) {
+	api.Put("/topics/:id/read", middleware.Protected(), h.ReadHandler.MarkTopicRead)          // PUT /api/v1/topics/:id/read
+	api.Get("/topics/:id/first-unread", middleware.Protected(), h.ReadHandler.GetFirstUnread) // GET /api/v1/topics/:id/first-unread
+	api.Put("/forums/:id/read", middleware.Protected(), h.ReadHandler.MarkForumRead)          // PUT /api/v1/forums/:id/read
+}
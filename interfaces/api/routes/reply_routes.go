@@ -7,11 +7,16 @@ import (
 )
 
 func SetupReplyRoutes(api fiber.Router, h *handlers.Handlers) {
+	// Public routes (ต้องลงทะเบียนก่อน group ที่ใช้ Protected)
+	api.Get("/replies/:id/replies", middleware.Optional(), h.ReplyHandler.GetChildReplies)
+	api.Get("/replies/:id/context", middleware.Optional(), h.ReplyHandler.GetReplyContext)
+
 	replies := api.Group("/replies")
 	replies.Use(middleware.Protected())
 
 	replies.Put("/:id", h.ReplyHandler.UpdateReply)
 	replies.Delete("/:id", h.ReplyHandler.DeleteReply)
+	replies.Get("/:id/revisions", h.RevisionHandler.GetReplyRevisions)
 
 	// Admin routes
 	adminReplies := api.Group("/admin/replies")
@@ -19,4 +24,5 @@ func SetupReplyRoutes(api fiber.Router, h *handlers.Handlers) {
 	adminReplies.Use(middleware.AdminOnly())
 
 	adminReplies.Delete("/:id", h.ReplyHandler.DeleteReplyByAdmin)
+	adminReplies.Post("/:id/revisions/:version/rollback", h.RevisionHandler.RollbackReply)
 }
@@ -23,15 +23,22 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 	SetupForumRoutes(api, h)
 	SetupTopicRoutes(api, h)
 	SetupReplyRoutes(api, h)
+	SetupContentRoutes(api, h)
 	SetupTagRoutes(api, h)
 	SetupVideoRoutes(api, h)
 	SetupLikeRoutes(api, h)
 	SetupCommentRoutes(api, h)
 	SetupShareRoutes(api, h)
+	SetupAnalyticsRoutes(api, h)
+	SetupPlaylistRoutes(api, h)
+	SetupBookmarkRoutes(api, h)
+	SetupWatchRoutes(api, h)
+	SetupReadRoutes(api, h)
+	SetupMessageRoutes(api, h)
 	SetupNotificationRoutes(api, h)
 	SetupAdminRoutes(api, h)
 	SetupReportRoutes(api, h)
 
 	// Setup WebSocket routes (needs app, not api group)
-	SetupWebSocketRoutes(app)
+	SetupWebSocketRoutes(app, h)
 }
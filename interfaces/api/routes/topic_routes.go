@@ -9,10 +9,13 @@ import (
 func SetupTopicRoutes(api fiber.Router, h *handlers.Handlers) {
 	// Public routes
 	topics := api.Group("/topics")
-	topics.Get("/", h.TopicHandler.GetTopics)
-	topics.Get("/search", h.TopicHandler.SearchTopics)
-	topics.Get("/:id", h.TopicHandler.GetTopic)
-	topics.Get("/:id/replies", h.ReplyHandler.GetReplies)
+	topics.Get("/", middleware.Optional(), h.TopicHandler.GetTopics)
+	topics.Get("/search", middleware.Optional(), h.TopicHandler.SearchTopics)
+	topics.Get("/:id", middleware.Optional(), h.TopicHandler.GetTopic)
+	topics.Get("/:id/replies", middleware.Optional(), h.ReplyHandler.GetReplies)
+	topics.Get("/:id/thread", middleware.Optional(), h.ReplyHandler.GetThread)
+	topics.Get("/:id/poll", middleware.Optional(), h.PollHandler.GetPoll)
+	topics.Get("/:id/poll/options/:optionId/voters", middleware.Optional(), h.PollHandler.GetVoters)
 
 	// Protected routes
 	topicsProtected := api.Group("/topics")
@@ -21,6 +24,25 @@ func SetupTopicRoutes(api fiber.Router, h *handlers.Handlers) {
 	topicsProtected.Put("/:id", h.TopicHandler.UpdateTopic)
 	topicsProtected.Delete("/:id", h.TopicHandler.DeleteTopic)
 	topicsProtected.Post("/:id/replies", h.ReplyHandler.CreateReply)
+	topicsProtected.Get("/:id/revisions", h.RevisionHandler.GetTopicRevisions)
+
+	// Polls
+	topicsProtected.Post("/:id/poll/votes", h.PollHandler.Vote)
+	topicsProtected.Delete("/:id/poll/votes", h.PollHandler.RetractVote)
+	topicsProtected.Post("/:id/poll/close", h.PollHandler.ClosePoll)
+
+	// Moderator routes (admin หรือ moderator ของ forum นั้น)
+	topicsProtected.Put("/:id/pin", h.TopicHandler.PinTopic)
+	topicsProtected.Put("/:id/unpin", h.TopicHandler.UnpinTopic)
+	topicsProtected.Put("/:id/lock", h.TopicHandler.LockTopic)
+	topicsProtected.Put("/:id/unlock", h.TopicHandler.UnlockTopic)
+	topicsProtected.Post("/:id/move", h.TopicHandler.MoveTopic)
+	topicsProtected.Post("/:id/merge", h.TopicHandler.MergeTopic)
+	topicsProtected.Post("/:id/split", h.TopicHandler.SplitTopic)
+
+	// Q&A (topic author หรือ moderator)
+	topicsProtected.Put("/:id/accepted-answer", h.TopicHandler.AcceptAnswer)
+	topicsProtected.Delete("/:id/accepted-answer", h.TopicHandler.UnacceptAnswer)
 
 	// Forum topics
 	api.Get("/forums/:id/topics", h.TopicHandler.GetTopicsByForum)
@@ -40,4 +62,5 @@ func SetupTopicRoutes(api fiber.Router, h *handlers.Handlers) {
 	adminTopics.Put("/:id/lock", h.TopicHandler.LockTopic)
 	adminTopics.Put("/:id/unlock", h.TopicHandler.UnlockTopic)
 	adminTopics.Delete("/:id", h.TopicHandler.DeleteTopicByAdmin)
+	adminTopics.Post("/:id/revisions/:version/rollback", h.RevisionHandler.RollbackTopic)
 }
@@ -8,11 +8,13 @@ import (
 )
 
 func SetupVideoRoutes(api fiber.Router, h *handlers.Handlers) {
-	// Public routes
+	// Public routes (optional auth so private/hidden media can be signed for the viewer)
 	videos := api.Group("/videos")
-	videos.Get("/", h.VideoHandler.GetVideos)                   // GET /api/v1/videos
-	videos.Get("/:id", h.VideoHandler.GetVideoByID)             // GET /api/v1/videos/:id
-	videos.Get("/user/:userId", h.VideoHandler.GetUserVideos)   // GET /api/v1/videos/user/:userId
+	videos.Get("/", middleware.Optional(), h.VideoHandler.GetVideos)                   // GET /api/v1/videos
+	videos.Get("/:id", middleware.Optional(), h.VideoHandler.GetVideoByID)             // GET /api/v1/videos/:id
+	videos.Get("/:id/media", middleware.Optional(), h.VideoHandler.GetVideoMedia)      // GET /api/v1/videos/:id/media
+	videos.Get("/user/:userId", middleware.Optional(), h.VideoHandler.GetUserVideos)   // GET /api/v1/videos/user/:userId
+	videos.Post("/:id/views", middleware.Optional(), h.VideoViewHandler.TrackViewEvent) // POST /api/v1/videos/:id/views
 
 	// Protected user routes (requires authentication)
 	videos.Post("/", middleware.Protected(), h.VideoHandler.CreateVideo)            // POST /api/v1/videos
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"gofiber-social/interfaces/api/handlers"
+	"gofiber-social/interfaces/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupWatchRoutes(api fiber.Router, h *handlers.Handlers) {
+	// Watch / unwatch
+	api.Get("/topics/:id/watch", middleware.Protected(), h.WatchHandler.GetTopicWatchStatus) // GET /api/v1/topics/:id/watch
+	api.Post("/topics/:id/watch", middleware.Protected(), h.WatchHandler.WatchTopic)         // POST /api/v1/topics/:id/watch
+	api.Delete("/topics/:id/watch", middleware.Protected(), h.WatchHandler.UnwatchTopic)     // DELETE /api/v1/topics/:id/watch
+	api.Get("/forums/:id/watch", middleware.Protected(), h.WatchHandler.GetForumWatchStatus) // GET /api/v1/forums/:id/watch
+	api.Post("/forums/:id/watch", middleware.Protected(), h.WatchHandler.WatchForum)         // POST /api/v1/forums/:id/watch
+	api.Delete("/forums/:id/watch", middleware.Protected(), h.WatchHandler.UnwatchForum)     // DELETE /api/v1/forums/:id/watch
+
+	// My watches
+	watches := api.Group("/watches", middleware.Protected())
+	watches.Get("/topics", h.WatchHandler.GetWatchedTopics)       // GET /api/v1/watches/topics
+	watches.Get("/forums", h.WatchHandler.GetWatchedForums)       // GET /api/v1/watches/forums
+	watches.Get("/preferences", h.WatchHandler.GetPreferences)    // GET /api/v1/watches/preferences
+	watches.Put("/preferences", h.WatchHandler.UpdatePreferences) // PUT /api/v1/watches/preferences
+}
@@ -1,17 +1,14 @@
 package routes
 
 import (
-	"gofiber-social/interfaces/api/middleware"
-	websocketHandler "gofiber-social/interfaces/api/websocket"
+	"gofiber-social/interfaces/api/handlers"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 )
 
-func SetupWebSocketRoutes(app *fiber.App) {
-	wsHandler := websocketHandler.NewWebSocketHandler()
-
-	// WebSocket with optional authentication
-	app.Use("/ws", middleware.Optional(), wsHandler.WebSocketUpgrade)
-	app.Get("/ws", websocket.New(wsHandler.HandleWebSocket))
+func SetupWebSocketRoutes(app *fiber.App, h *handlers.Handlers) {
+	// Token from the Authorization header or ?token=, or an auth message after connecting
+	app.Use("/ws", h.WebSocketHandler.WebSocketUpgrade)
+	app.Get("/ws", websocket.New(h.WebSocketHandler.HandleWebSocket))
 }
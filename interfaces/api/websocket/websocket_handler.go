@@ -1,62 +1,490 @@
 package websocket
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gofiber-social/domain/dto"
+	"gofiber-social/domain/services"
 	websocketManager "gofiber-social/infrastructure/websocket"
 	"gofiber-social/pkg/utils"
 	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 	"github.com/google/uuid"
 )
 
-type WebSocketHandler struct{}
+const (
+	authorizeTimeout = 5 * time.Second
+	presenceDelay    = time.Second     // viewer counts of a room are sent at most once per this
+	typingInterval   = 3 * time.Second // a connection reports typing in a room at most once per this
+)
+
+// Error codes sent in "error" messages
+const (
+	ErrorCodeBadRequest   = "bad_request"
+	ErrorCodeUnauthorized = "unauthorized" // login required, or the token is invalid
+	ErrorCodeForbidden    = "forbidden"
+	ErrorCodeNotFound     = "not_found"
+	ErrorCodeInvalidRoom  = "invalid_room"
+	ErrorCodeTooManyRooms = "too_many_rooms"
+)
+
+type WebSocketHandler struct {
+	realtimeService services.RealtimeService
+	jwtSecret       string
 
-func NewWebSocketHandler() *WebSocketHandler {
-	return &WebSocketHandler{}
+	presenceMutex   sync.Mutex
+	presencePending map[string]bool // rooms with a presence update scheduled
 }
 
-func (h *WebSocketHandler) WebSocketUpgrade(c *fiber.Ctx) error {
-	if websocket.IsWebSocketUpgrade(c) {
-		return c.Next()
+// session is the state of one connection's read loop
+type session struct {
+	first    bool
+	typingAt map[string]time.Time // rooms the user is typing in, by when it was last sent
+}
+
+func NewWebSocketHandler(realtimeService services.RealtimeService) *WebSocketHandler {
+	return &WebSocketHandler{
+		realtimeService: realtimeService,
+		jwtSecret:       os.Getenv("JWT_SECRET"),
+		presencePending: make(map[string]bool),
 	}
-	return fiber.ErrUpgradeRequired
 }
 
-func (h *WebSocketHandler) HandleWebSocket(c *websocket.Conn) {
-	var userID uuid.UUID
-	var roomID string
+// WebSocketUpgrade authenticates with the Authorization header or ?token= before upgrading.
+// A bad token is refused here; without a token the connection starts as a guest and may send
+// an auth message first.
+func (h *WebSocketHandler) WebSocketUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
 
-	// Try to get user from context (set by Optional middleware)
-	if userContext := c.Locals("user"); userContext != nil {
-		if user, ok := userContext.(*utils.UserContext); ok {
-			userID = user.ID
+	token := utils.ExtractTokenFromHeader(c.Get("Authorization"))
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token != "" {
+		user, err := utils.ValidateTokenStringToUUID(token, h.jwtSecret)
+		if err != nil {
+			return utils.UnauthorizedResponse(c, "Invalid token")
 		}
+		c.Locals("user", user)
 	}
 
-	// If no user context, generate anonymous user ID
-	if userID == uuid.Nil {
-		userID = uuid.New()
-		log.Printf("WebSocket: Anonymous user connected with ID: %s", userID.String())
-	} else {
+	return c.Next()
+}
+
+func (h *WebSocketHandler) HandleWebSocket(c *websocket.Conn) {
+	var userID uuid.UUID
+	var role string
+	if user, ok := c.Locals("user").(*utils.UserContext); ok {
+		userID = user.ID
+		role = user.Role
 		log.Printf("WebSocket: Authenticated user connected: %s", userID.String())
+	} else {
+		log.Printf("WebSocket: Guest connected")
 	}
 
-	roomID = c.Query("room", "")
+	if err := websocketManager.Manager.RegisterClient(c, userID, role); err != nil {
+		deadline := time.Now().Add(time.Second)
+		_ = c.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error()), deadline)
+		return
+	}
 
-	websocketManager.Manager.RegisterClient(c, userID, roomID)
+	state := &session{first: true, typingAt: make(map[string]time.Time)}
 
 	defer func() {
+		client, ok := websocketManager.Manager.GetClient(c)
 		websocketManager.Manager.UnregisterClient(c)
+		if !ok {
+			return
+		}
+		for roomID := range state.typingAt {
+			h.broadcastTyping(roomID, client.UserID, false)
+		}
+		for roomID := range client.Rooms {
+			h.schedulePresence(roomID)
+		}
 	}()
 
+	// A room in the URL is checked like a join_room message
+	if roomID := c.Query("room", ""); roomID != "" {
+		h.joinRoom(c, roomID)
+	}
+
 	for {
-		messageType, message, err := c.ReadMessage()
+		_, data, err := c.ReadMessage()
 		if err != nil {
 			log.Printf("WebSocket read error: %v", err)
 			break
 		}
+		websocketManager.Manager.Touch(c)
+
+		var message websocketManager.Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			h.sendError(c, "", "", ErrorCodeBadRequest, "invalid message")
+			continue
+		}
+
+		isFirst := state.first
+		state.first = false
+
+		if message.Type == "auth" {
+			if !isFirst {
+				h.sendError(c, "auth", "", ErrorCodeBadRequest, "auth must be the first message")
+				continue
+			}
+			if !h.authenticate(c, message) {
+				return
+			}
+			continue
+		}
+
+		h.handleMessage(c, state, message)
+	}
+}
+
+func (h *WebSocketHandler) handleMessage(c *websocket.Conn, state *session, message websocketManager.Message) {
+	switch message.Type {
+	case "ping":
+		h.send(c, websocketManager.Message{
+			Type: "pong",
+			Data: "pong",
+		})
+
+	case "join_room":
+		var data struct {
+			RoomID string `json:"roomId"`
+		}
+		if err := decodeData(message.Data, &data); err != nil || data.RoomID == "" {
+			h.sendError(c, "join_room", "", ErrorCodeBadRequest, "roomId is required")
+			return
+		}
+		h.joinRoom(c, data.RoomID)
+
+	case "leave_room":
+		var data struct {
+			RoomID string `json:"roomId"`
+		}
+		_ = decodeData(message.Data, &data)
+		h.leaveRoom(c, data.RoomID)
+
+	case "resume":
+		var data struct {
+			LastSeq *int64 `json:"lastSeq"`
+		}
+		if err := decodeData(message.Data, &data); err != nil {
+			h.sendError(c, "resume", "", ErrorCodeBadRequest, "invalid lastSeq")
+			return
+		}
+		h.resume(c, data.LastSeq)
+
+	case "typing":
+		var data struct {
+			RoomID   string `json:"roomId"`
+			IsTyping *bool  `json:"isTyping"`
+		}
+		if err := decodeData(message.Data, &data); err != nil || data.RoomID == "" {
+			h.sendError(c, "typing", "", ErrorCodeBadRequest, "roomId is required")
+			return
+		}
+		isTyping := data.IsTyping == nil || *data.IsTyping
+		h.typing(c, state, data.RoomID, isTyping)
+
+	default:
+		h.sendError(c, message.Type, "", ErrorCodeBadRequest, fmt.Sprintf("unknown message type: %s", message.Type))
+	}
+}
+
+// authenticate handles {"type":"auth","data":{"token":"..."}}; an invalid token closes the
+// connection
+func (h *WebSocketHandler) authenticate(c *websocket.Conn, message websocketManager.Message) bool {
+	var data struct {
+		Token string `json:"token"`
+	}
+	_ = decodeData(message.Data, &data)
+
+	user, err := utils.ValidateTokenStringToUUID(data.Token, h.jwtSecret)
+	if err != nil {
+		h.sendError(c, "auth", "", ErrorCodeUnauthorized, err.Error())
+		return false
+	}
+
+	if err := websocketManager.Manager.Authenticate(c, user.ID, user.Role); err != nil {
+		h.sendError(c, "auth", "", ErrorCodeBadRequest, err.Error())
+		return true
+	}
+
+	h.send(c, websocketManager.Message{
+		Type: "authenticated",
+		Data: map[string]interface{}{
+			"userId": user.ID,
+		},
+	})
+	return true
+}
+
+func (h *WebSocketHandler) joinRoom(c *websocket.Conn, rawRoomID string) {
+	roomType, id, err := websocketManager.ParseRoom(rawRoomID)
+	if err != nil {
+		h.sendError(c, "join_room", rawRoomID, ErrorCodeInvalidRoom, err.Error())
+		return
+	}
+	roomID := websocketManager.RoomName(roomType, id)
+
+	client, ok := websocketManager.Manager.GetClient(c)
+	if !ok {
+		return
+	}
+	var userID *uuid.UUID
+	if !client.IsGuest() {
+		userID = &client.UserID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authorizeTimeout)
+	defer cancel()
+	if err := h.realtimeService.AuthorizeRoom(ctx, userID, client.Role, roomID); err != nil {
+		h.sendError(c, "join_room", roomID, joinErrorCode(err, userID == nil), err.Error())
+		return
+	}
+
+	if err := websocketManager.Manager.JoinRoom(c, roomID); err != nil {
+		code := ErrorCodeBadRequest
+		if errors.Is(err, websocketManager.ErrTooManyRooms) {
+			code = ErrorCodeTooManyRooms
+		}
+		h.sendError(c, "join_room", roomID, code, err.Error())
+		return
+	}
+
+	h.schedulePresence(roomID)
+
+	h.send(c, websocketManager.Message{
+		Type: "room_joined",
+		Data: map[string]interface{}{
+			"roomId":  roomID,
+			"message": fmt.Sprintf("Joined room %s", roomID),
+		},
+	})
+}
+
+// leaveRoom leaves one room, or every room when roomID is empty
+func (h *WebSocketHandler) leaveRoom(c *websocket.Conn, roomID string) {
+	client, ok := websocketManager.Manager.GetClient(c)
+	if !ok {
+		return
+	}
+
+	left := []string{}
+	if roomID == "" {
+		for joined := range client.Rooms {
+			websocketManager.Manager.LeaveRoom(c, joined)
+			left = append(left, joined)
+		}
+	} else if roomType, id, err := websocketManager.ParseRoom(roomID); err == nil {
+		roomID = websocketManager.RoomName(roomType, id)
+		websocketManager.Manager.LeaveRoom(c, roomID)
+		left = append(left, roomID)
+	} else {
+		h.sendError(c, "leave_room", roomID, ErrorCodeInvalidRoom, err.Error())
+		return
+	}
+
+	for _, roomID := range left {
+		h.schedulePresence(roomID)
+	}
+
+	h.send(c, websocketManager.Message{
+		Type: "room_left",
+		Data: map[string]interface{}{
+			"roomIds": left,
+		},
+	})
+}
+
+// resume replays the user-directed messages sent after lastSeq, or answers resync_required
+// when they are no longer all in the log. Without lastSeq it only reports the current sequence
+// so a fresh client knows where it starts.
+func (h *WebSocketHandler) resume(c *websocket.Conn, lastSeq *int64) {
+	client, ok := websocketManager.Manager.GetClient(c)
+	if !ok {
+		return
+	}
+	if client.IsGuest() {
+		h.sendError(c, "resume", "", ErrorCodeUnauthorized, "login required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authorizeTimeout)
+	defer cancel()
+
+	var result *websocketManager.ResumeResult
+	var err error
+	if lastSeq == nil {
+		var current int64
+		current, err = websocketManager.Manager.CurrentSeq(ctx, client.UserID)
+		result = &websocketManager.ResumeResult{LastSeq: current}
+	} else {
+		result, err = websocketManager.Manager.Resume(ctx, client.UserID, *lastSeq)
+	}
+	if err != nil {
+		log.Printf("WebSocket resume failed: %v", err)
+		h.send(c, websocketManager.Message{
+			Type: "resync_required",
+			Data: map[string]interface{}{"lastSeq": 0},
+		})
+		return
+	}
+
+	if result.Resync {
+		h.send(c, websocketManager.Message{
+			Type: "resync_required",
+			Data: map[string]interface{}{"lastSeq": result.LastSeq},
+		})
+		return
+	}
+
+	for _, missed := range result.Messages {
+		h.send(c, missed)
+	}
+	h.send(c, websocketManager.Message{
+		Type: "resumed",
+		Data: map[string]interface{}{
+			"lastSeq":  result.LastSeq,
+			"replayed": len(result.Messages),
+		},
+	})
+}
+
+// typing relays a typing indicator to a topic room the user has joined. Starting to type is
+// sent at most once per typingInterval; stopping always is.
+func (h *WebSocketHandler) typing(c *websocket.Conn, state *session, rawRoomID string, isTyping bool) {
+	roomType, id, err := websocketManager.ParseRoom(rawRoomID)
+	if err != nil || roomType != websocketManager.RoomTypeTopic {
+		h.sendError(c, "typing", rawRoomID, ErrorCodeInvalidRoom, "typing is only supported in topic rooms")
+		return
+	}
+	roomID := websocketManager.RoomName(roomType, id)
+
+	client, ok := websocketManager.Manager.GetClient(c)
+	if !ok {
+		return
+	}
+	if client.IsGuest() {
+		h.sendError(c, "typing", roomID, ErrorCodeUnauthorized, "login required")
+		return
+	}
+	if !client.Rooms[roomID] {
+		h.sendError(c, "typing", roomID, ErrorCodeForbidden, "join the room first")
+		return
+	}
+
+	if isTyping {
+		if last, ok := state.typingAt[roomID]; ok && time.Since(last) < typingInterval {
+			return
+		}
+		state.typingAt[roomID] = time.Now()
+	} else {
+		if _, ok := state.typingAt[roomID]; !ok {
+			return
+		}
+		delete(state.typingAt, roomID)
+	}
+
+	h.broadcastTyping(roomID, client.UserID, isTyping)
+}
+
+func (h *WebSocketHandler) broadcastTyping(roomID string, userID uuid.UUID, isTyping bool) {
+	websocketManager.Manager.BroadcastToRoom(roomID, websocketManager.EventTyping, dto.TypingEvent{
+		RoomID:   roomID,
+		UserID:   userID,
+		IsTyping: isTyping,
+	})
+}
+
+// schedulePresence sends a topic room its viewer count shortly after someone joins or leaves;
+// changes within presenceDelay are sent once
+func (h *WebSocketHandler) schedulePresence(roomID string) {
+	if roomType, _, err := websocketManager.ParseRoom(roomID); err != nil || roomType != websocketManager.RoomTypeTopic {
+		return
+	}
+
+	h.presenceMutex.Lock()
+	defer h.presenceMutex.Unlock()
+	if h.presencePending[roomID] {
+		return
+	}
+	h.presencePending[roomID] = true
+
+	time.AfterFunc(presenceDelay, func() {
+		h.presenceMutex.Lock()
+		delete(h.presencePending, roomID)
+		h.presenceMutex.Unlock()
+
+		websocketManager.Manager.BroadcastToRoom(roomID, websocketManager.EventPresence, dto.PresenceEvent{
+			RoomID:  roomID,
+			Viewers: websocketManager.Manager.GetRoomClients(roomID),
+		})
+	})
+}
+
+// send queues a reply on the connection's writer; the handler never writes to the socket itself
+func (h *WebSocketHandler) send(c *websocket.Conn, message websocketManager.Message) {
+	if err := websocketManager.Manager.Send(c, message); err != nil && !errors.Is(err, websocketManager.ErrNotConnected) {
+		log.Printf("WebSocket send failed: %v", err)
+	}
+}
+
+// GetStats reports connections, send queue depth and dropped messages of this replica, plus
+// the connection count of the whole cluster
+func (h *WebSocketHandler) GetStats(c *fiber.Ctx) error {
+	stats := websocketManager.Manager.Stats()
+	return utils.SuccessResponse(c, "WebSocket stats retrieved successfully", fiber.Map{
+		"node":               stats,
+		"clusterConnections": websocketManager.Manager.GetTotalClients(),
+	})
+}
+
+// sendError answers a request with {"type":"error","data":{"request","roomId","code","message"}}
+func (h *WebSocketHandler) sendError(c *websocket.Conn, request, roomID, code, message string) {
+	data := map[string]interface{}{
+		"request": request,
+		"code":    code,
+		"message": message,
+	}
+	if roomID != "" {
+		data["roomId"] = roomID
+	}
+	h.send(c, websocketManager.Message{Type: "error", Data: data})
+}
+
+// joinErrorCode maps the authorization errors of the services to error codes
+func joinErrorCode(err error, isGuest bool) string {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, websocketManager.ErrInvalidRoom):
+		return ErrorCodeInvalidRoom
+	case strings.HasSuffix(msg, "not found"):
+		return ErrorCodeNotFound
+	case strings.HasPrefix(msg, "login required"), isGuest:
+		return ErrorCodeUnauthorized
+	default:
+		return ErrorCodeForbidden
+	}
+}
 
-		websocketManager.HandleWebSocketMessage(c, messageType, message)
+// decodeData converts the untyped data of a message into dest
+func decodeData(data interface{}, dest interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
 	}
+	return json.Unmarshal(raw, dest)
 }
@@ -3,15 +3,26 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
+
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Bunny    BunnyConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	Bunny     BunnyConfig
+	Storage   StorageConfig
+	Views     ViewsConfig
+	Analytics AnalyticsConfig
+	Polls     PollsConfig
+	Watch     WatchConfig
+	Unfurl    UnfurlConfig
+	WebSocket WebSocketConfig
+	Jobs      JobsConfig
+	Queue     QueueConfig
 }
 
 type AppConfig struct {
@@ -41,10 +52,86 @@ type JWTConfig struct {
 }
 
 type BunnyConfig struct {
-	StorageZone string
-	AccessKey   string
-	BaseURL     string
-	CDNUrl      string
+	StorageZone  string
+	AccessKey    string
+	BaseURL      string
+	CDNUrl       string
+	SecureStorageZone string
+	SecureAccessKey   string
+	SecureCDNUrl      string
+	TokenKey          string
+}
+
+type StorageConfig struct {
+	DefaultQuota      int64         // bytes per user
+	OrphanGracePeriod time.Duration // how long an unattached upload is kept
+	OrphanCleanupCron string
+	SignedURLTTL      time.Duration // lifetime of signed URLs for protected media
+	ProtectMediaCron  string        // how often media uploaded before the secure zone is moved into it
+}
+
+type ViewsConfig struct {
+	DedupeWindow time.Duration // repeat views from the same user/session inside this window are not counted
+	FlushCron    string        // how often buffered view counts are written to the database
+}
+
+type AnalyticsConfig struct {
+	Timezone   string // day boundaries of the daily rollups, e.g. Asia/Bangkok
+	RollupCron string
+}
+
+type PollsConfig struct {
+	CloseCron string // how often polls past their close time are closed
+}
+
+type WatchConfig struct {
+	AutoWatchOnPost  bool // default for users without their own preference
+	AutoWatchOnReply bool
+	NotifyBatchSize  int // watchers notified per batch when a busy topic gets a reply
+}
+
+type UnfurlConfig struct {
+	Enabled      bool          // fetch previews of external links in topics and replies
+	Timeout      time.Duration // per request
+	MaxBodyBytes int64         // only the head of a page is read
+	UserAgent    string
+	RefreshAfter time.Duration // cached previews older than this are fetched again
+}
+
+type WebSocketConfig struct {
+	ClusterEnabled bool          // relay broadcasts and share presence between replicas through Redis
+	ClusterChannel string        // Redis pub/sub channel, the same on every replica
+	PresenceTTL    time.Duration // a replica that stops reporting drops out of the online counts after this
+
+	SendQueueSize    int    // messages buffered per connection
+	SlowClientPolicy string // "disconnect" or "drop" when a connection's queue is full
+	PingInterval     time.Duration
+	IdleTimeout      time.Duration // connections silent for this long (no pong either) are closed
+	WriteTimeout     time.Duration
+	ShutdownTimeout  time.Duration // how long shutdown waits for queues to flush
+
+	EventLogSize int           // user-directed messages kept per user for resume; keep below SendQueueSize
+	EventLogTTL  time.Duration // logs of users who receive nothing for this long are dropped
+}
+
+type JobsConfig struct {
+	NodeID    string        // this replica in the leader election; generated from the hostname when empty
+	LeaderTTL time.Duration // a leader that stops renewing is replaced after this
+	LockTTL   time.Duration // how long a firing's lock is kept so no other replica repeats it
+	SyncCron  string        // how often each replica reloads its schedule from the jobs table
+	Timezone  string        // cron expressions of system jobs and of jobs without their own timezone
+}
+
+type QueueConfig struct {
+	Workers      int           // background task workers per replica
+	PollInterval time.Duration // how often idle workers look for due tasks
+	MaxAttempts  int           // a task that fails this often is moved to the dead letters
+	BaseBackoff  time.Duration // delay before the first retry; doubled for every further attempt
+	MaxBackoff   time.Duration
+	TaskTimeout  time.Duration // per attempt
+	StaleAfter   time.Duration // a task processing for longer (its worker died) is claimed again
+	Retention    time.Duration // completed tasks, and their idempotency keys, are kept this long
+	CleanupCron  string
 }
 
 func LoadConfig() (*Config, error) {
@@ -52,6 +139,36 @@ func LoadConfig() (*Config, error) {
 	_ = godotenv.Load()
 
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	storageQuotaMB, _ := strconv.ParseInt(getEnv("STORAGE_DEFAULT_QUOTA_MB", "1024"), 10, 64)
+	orphanGraceHours, _ := strconv.Atoi(getEnv("STORAGE_ORPHAN_GRACE_HOURS", "24"))
+	signedURLTTLMinutes, _ := strconv.Atoi(getEnv("STORAGE_SIGNED_URL_TTL_MINUTES", "60"))
+	viewDedupeMinutes, _ := strconv.Atoi(getEnv("VIEW_DEDUPE_WINDOW_MINUTES", "30"))
+	watchOnPost, _ := strconv.ParseBool(getEnv("WATCH_AUTO_ON_POST", "true"))
+	watchOnReply, _ := strconv.ParseBool(getEnv("WATCH_AUTO_ON_REPLY", "true"))
+	watchBatchSize, _ := strconv.Atoi(getEnv("WATCH_NOTIFY_BATCH_SIZE", "500"))
+	unfurlEnabled, _ := strconv.ParseBool(getEnv("UNFURL_ENABLED", "true"))
+	unfurlTimeoutSeconds, _ := strconv.Atoi(getEnv("UNFURL_TIMEOUT_SECONDS", "5"))
+	unfurlMaxBodyKB, _ := strconv.ParseInt(getEnv("UNFURL_MAX_BODY_KB", "512"), 10, 64)
+	unfurlRefreshHours, _ := strconv.Atoi(getEnv("UNFURL_REFRESH_HOURS", "168"))
+	wsClusterEnabled, _ := strconv.ParseBool(getEnv("WS_CLUSTER_ENABLED", "true"))
+	wsPresenceTTLSeconds, _ := strconv.Atoi(getEnv("WS_PRESENCE_TTL_SECONDS", "30"))
+	wsSendQueueSize, _ := strconv.Atoi(getEnv("WS_SEND_QUEUE_SIZE", "256"))
+	wsPingIntervalSeconds, _ := strconv.Atoi(getEnv("WS_PING_INTERVAL_SECONDS", "25"))
+	wsIdleTimeoutSeconds, _ := strconv.Atoi(getEnv("WS_IDLE_TIMEOUT_SECONDS", "60"))
+	wsWriteTimeoutSeconds, _ := strconv.Atoi(getEnv("WS_WRITE_TIMEOUT_SECONDS", "10"))
+	wsShutdownTimeoutSeconds, _ := strconv.Atoi(getEnv("WS_SHUTDOWN_TIMEOUT_SECONDS", "10"))
+	wsEventLogSize, _ := strconv.Atoi(getEnv("WS_EVENT_LOG_SIZE", "100"))
+	wsEventLogTTLHours, _ := strconv.Atoi(getEnv("WS_EVENT_LOG_TTL_HOURS", "24"))
+	jobLeaderTTLSeconds, _ := strconv.Atoi(getEnv("JOB_LEADER_TTL_SECONDS", "15"))
+	jobLockTTLSeconds, _ := strconv.Atoi(getEnv("JOB_LOCK_TTL_SECONDS", "300"))
+	queueWorkers, _ := strconv.Atoi(getEnv("QUEUE_WORKERS", "4"))
+	queuePollSeconds, _ := strconv.Atoi(getEnv("QUEUE_POLL_INTERVAL_SECONDS", "2"))
+	queueMaxAttempts, _ := strconv.Atoi(getEnv("QUEUE_MAX_ATTEMPTS", "8"))
+	queueBaseBackoffSeconds, _ := strconv.Atoi(getEnv("QUEUE_BASE_BACKOFF_SECONDS", "5"))
+	queueMaxBackoffMinutes, _ := strconv.Atoi(getEnv("QUEUE_MAX_BACKOFF_MINUTES", "60"))
+	queueTaskTimeoutSeconds, _ := strconv.Atoi(getEnv("QUEUE_TASK_TIMEOUT_SECONDS", "30"))
+	queueStaleMinutes, _ := strconv.Atoi(getEnv("QUEUE_STALE_AFTER_MINUTES", "5"))
+	queueRetentionDays, _ := strconv.Atoi(getEnv("QUEUE_RETENTION_DAYS", "7"))
 
 	config := &Config{
 		App: AppConfig{
@@ -77,10 +194,77 @@ func LoadConfig() (*Config, error) {
 			Secret: getEnv("JWT_SECRET", "your-secret-key"),
 		},
 		Bunny: BunnyConfig{
-			StorageZone: getEnv("BUNNY_STORAGE_ZONE", ""),
-			AccessKey:   getEnv("BUNNY_ACCESS_KEY", ""),
-			BaseURL:     getEnv("BUNNY_BASE_URL", "https://storage.bunnycdn.com"),
-			CDNUrl:      getEnv("BUNNY_CDN_URL", ""),
+			StorageZone:  getEnv("BUNNY_STORAGE_ZONE", ""),
+			AccessKey:    getEnv("BUNNY_ACCESS_KEY", ""),
+			BaseURL:      getEnv("BUNNY_BASE_URL", "https://storage.bunnycdn.com"),
+			CDNUrl:       getEnv("BUNNY_CDN_URL", ""),
+			SecureStorageZone: getEnv("BUNNY_SECURE_STORAGE_ZONE", ""),
+			SecureAccessKey:   getEnv("BUNNY_SECURE_ACCESS_KEY", ""),
+			SecureCDNUrl:      getEnv("BUNNY_SECURE_CDN_URL", ""),
+			TokenKey:          getEnv("BUNNY_TOKEN_KEY", ""),
+		},
+		Storage: StorageConfig{
+			DefaultQuota:      storageQuotaMB * 1024 * 1024,
+			OrphanGracePeriod: time.Duration(orphanGraceHours) * time.Hour,
+			OrphanCleanupCron: getEnv("STORAGE_ORPHAN_CLEANUP_CRON", "0 * * * *"),
+			SignedURLTTL:      time.Duration(signedURLTTLMinutes) * time.Minute,
+			ProtectMediaCron:  getEnv("STORAGE_PROTECT_MEDIA_CRON", "*/10 * * * *"),
+		},
+		Views: ViewsConfig{
+			DedupeWindow: time.Duration(viewDedupeMinutes) * time.Minute,
+			FlushCron:    getEnv("VIEW_FLUSH_CRON", "* * * * *"),
+		},
+		Analytics: AnalyticsConfig{
+			Timezone:   getEnv("ANALYTICS_TIMEZONE", "UTC"),
+			RollupCron: getEnv("ANALYTICS_ROLLUP_CRON", "10 * * * *"),
+		},
+		Polls: PollsConfig{
+			CloseCron: getEnv("POLL_CLOSE_CRON", "* * * * *"),
+		},
+		Watch: WatchConfig{
+			AutoWatchOnPost:  watchOnPost,
+			AutoWatchOnReply: watchOnReply,
+			NotifyBatchSize:  watchBatchSize,
+		},
+		Unfurl: UnfurlConfig{
+			Enabled:      unfurlEnabled,
+			Timeout:      time.Duration(unfurlTimeoutSeconds) * time.Second,
+			MaxBodyBytes: unfurlMaxBodyKB * 1024,
+			UserAgent:    getEnv("UNFURL_USER_AGENT", "GoFiberSocialBot/1.0 (+link preview)"),
+			RefreshAfter: time.Duration(unfurlRefreshHours) * time.Hour,
+		},
+		WebSocket: WebSocketConfig{
+			ClusterEnabled: wsClusterEnabled,
+			ClusterChannel: getEnv("WS_CLUSTER_CHANNEL", "ws:broadcast"),
+			PresenceTTL:    time.Duration(wsPresenceTTLSeconds) * time.Second,
+
+			SendQueueSize:    wsSendQueueSize,
+			SlowClientPolicy: getEnv("WS_SLOW_CLIENT_POLICY", "disconnect"),
+			PingInterval:     time.Duration(wsPingIntervalSeconds) * time.Second,
+			IdleTimeout:      time.Duration(wsIdleTimeoutSeconds) * time.Second,
+			WriteTimeout:     time.Duration(wsWriteTimeoutSeconds) * time.Second,
+			ShutdownTimeout:  time.Duration(wsShutdownTimeoutSeconds) * time.Second,
+
+			EventLogSize: wsEventLogSize,
+			EventLogTTL:  time.Duration(wsEventLogTTLHours) * time.Hour,
+		},
+		Jobs: JobsConfig{
+			NodeID:    getEnv("JOB_NODE_ID", ""),
+			LeaderTTL: time.Duration(jobLeaderTTLSeconds) * time.Second,
+			LockTTL:   time.Duration(jobLockTTLSeconds) * time.Second,
+			SyncCron:  getEnv("JOB_SYNC_CRON", "* * * * *"),
+			Timezone:  getEnv("JOB_TIMEZONE", "UTC"),
+		},
+		Queue: QueueConfig{
+			Workers:      queueWorkers,
+			PollInterval: time.Duration(queuePollSeconds) * time.Second,
+			MaxAttempts:  queueMaxAttempts,
+			BaseBackoff:  time.Duration(queueBaseBackoffSeconds) * time.Second,
+			MaxBackoff:   time.Duration(queueMaxBackoffMinutes) * time.Minute,
+			TaskTimeout:  time.Duration(queueTaskTimeoutSeconds) * time.Second,
+			StaleAfter:   time.Duration(queueStaleMinutes) * time.Minute,
+			Retention:    time.Duration(queueRetentionDays) * 24 * time.Hour,
+			CleanupCron:  getEnv("QUEUE_CLEANUP_CRON", "30 3 * * *"),
 		},
 	}
 
@@ -8,10 +8,13 @@ import (
 	"gofiber-social/infrastructure/postgres"
 	"gofiber-social/infrastructure/redis"
 	"gofiber-social/infrastructure/storage"
+	"gofiber-social/infrastructure/unfurl"
+	"gofiber-social/infrastructure/websocket"
 	"gofiber-social/interfaces/api/handlers"
 	"gofiber-social/pkg/config"
 	"gofiber-social/pkg/scheduler"
 	"log"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -24,25 +27,40 @@ type Container struct {
 	DB             *gorm.DB
 	RedisClient    *redis.RedisClient
 	BunnyStorage   storage.BunnyStorage
+	LinkResolver   unfurl.LinkResolver // nil when link previews are disabled
 	EventScheduler scheduler.EventScheduler
+	JobLeader      *redis.LeaderElector
 
 	// Repositories
-	UserRepository         repositories.UserRepository
-	TaskRepository         repositories.TaskRepository
-	FileRepository         repositories.FileRepository
-	JobRepository          repositories.JobRepository
-	ForumRepository        repositories.ForumRepository
-	TopicRepository        repositories.TopicRepository
-	ReplyRepository        repositories.ReplyRepository
-	TagRepository          repositories.TagRepository
-	VideoRepository        repositories.VideoRepository
-	LikeRepository         repositories.LikeRepository
-	CommentRepository      repositories.CommentRepository
-	ShareRepository        repositories.ShareRepository
-	FollowRepository       repositories.FollowRepository
-	NotificationRepository repositories.NotificationRepository
-	ReportRepository       repositories.ReportRepository
-	ActivityLogRepository  repositories.ActivityLogRepository
+	UserRepository           repositories.UserRepository
+	TaskRepository           repositories.TaskRepository
+	FileRepository           repositories.FileRepository
+	JobRepository            repositories.JobRepository
+	ForumRepository          repositories.ForumRepository
+	TopicRepository          repositories.TopicRepository
+	ReplyRepository          repositories.ReplyRepository
+	PollRepository           repositories.PollRepository
+	TagRepository            repositories.TagRepository
+	VideoRepository          repositories.VideoRepository
+	VideoViewRepository      repositories.VideoViewRepository
+	AnalyticsRepository      repositories.AnalyticsRepository
+	PlaylistRepository       repositories.PlaylistRepository
+	LikeRepository           repositories.LikeRepository
+	CommentRepository        repositories.CommentRepository
+	ShareRepository          repositories.ShareRepository
+	FollowRepository         repositories.FollowRepository
+	NotificationRepository   repositories.NotificationRepository
+	ReportRepository         repositories.ReportRepository
+	ActivityLogRepository    repositories.ActivityLogRepository
+	RevisionRepository       repositories.RevisionRepository
+	LinkPreviewRepository    repositories.LinkPreviewRepository
+	BookmarkRepository       repositories.BookmarkRepository
+	WatchRepository          repositories.WatchRepository
+	ReadRepository           repositories.ReadRepository
+	BlockRepository          repositories.BlockRepository
+	ConversationRepository   repositories.ConversationRepository
+	MessageRepository        repositories.MessageRepository
+	BackgroundTaskRepository repositories.BackgroundTaskRepository
 
 	// Services
 	UserService         services.UserService
@@ -52,8 +70,12 @@ type Container struct {
 	ForumService        services.ForumService
 	TopicService        services.TopicService
 	ReplyService        services.ReplyService
+	PollService         services.PollService
 	TagService          services.TagService
 	VideoService        services.VideoService
+	VideoViewService    services.VideoViewService
+	AnalyticsService    services.AnalyticsService
+	PlaylistService     services.PlaylistService
 	LikeService         services.LikeService
 	CommentService      services.CommentService
 	ShareService        services.ShareService
@@ -61,6 +83,14 @@ type Container struct {
 	NotificationService services.NotificationService
 	AdminService        services.AdminService
 	ReportService       services.ReportService
+	RevisionService     services.RevisionService
+	ContentService      services.ContentService
+	BookmarkService     services.BookmarkService
+	WatchService        services.WatchService
+	ReadService         services.ReadService
+	RealtimeService     services.RealtimeService
+	MessageService      services.MessageService
+	QueueService        services.QueueService
 }
 
 func NewContainer() *Container {
@@ -134,23 +164,73 @@ func (c *Container) initInfrastructure() error {
 	}
 	c.RedisClient = redis.NewRedisClient(redisConfig)
 
+	wsOptions := websocket.Options{
+		QueueSize:        c.Config.WebSocket.SendQueueSize,
+		SlowClientPolicy: c.Config.WebSocket.SlowClientPolicy,
+		PingInterval:     c.Config.WebSocket.PingInterval,
+		IdleTimeout:      c.Config.WebSocket.IdleTimeout,
+		WriteTimeout:     c.Config.WebSocket.WriteTimeout,
+	}
+	if err := websocket.Manager.Configure(wsOptions); err != nil {
+		log.Printf("Warning: Invalid WebSocket settings, using defaults: %v", err)
+	}
+
 	// Test Redis connection
 	if err := c.RedisClient.Ping(context.Background()); err != nil {
 		log.Printf("Warning: Redis connection failed: %v", err)
 	} else {
 		log.Println("✓ Redis connected")
+
+		// Messages to a user are numbered so reconnecting clients can resume
+		replayConfig := websocket.ReplayConfig{
+			LogSize: c.Config.WebSocket.EventLogSize,
+			TTL:     c.Config.WebSocket.EventLogTTL,
+		}
+		if err := websocket.Manager.EnableReplay(c.RedisClient, replayConfig); err != nil {
+			log.Printf("Warning: WebSocket replay disabled: %v", err)
+		}
+
+		// WebSocket broadcasts reach clients connected to other replicas
+		if c.Config.WebSocket.ClusterEnabled {
+			clusterConfig := websocket.ClusterConfig{
+				Channel:     c.Config.WebSocket.ClusterChannel,
+				PresenceTTL: c.Config.WebSocket.PresenceTTL,
+			}
+			if err := websocket.Manager.EnableCluster(c.RedisClient, clusterConfig); err != nil {
+				log.Printf("Warning: WebSocket cluster disabled: %v", err)
+			} else {
+				log.Println("✓ WebSocket cluster enabled")
+			}
+		}
 	}
 
 	// Initialize Bunny Storage
 	bunnyConfig := storage.BunnyConfig{
-		StorageZone: c.Config.Bunny.StorageZone,
-		AccessKey:   c.Config.Bunny.AccessKey,
-		BaseURL:     c.Config.Bunny.BaseURL,
-		CDNUrl:      c.Config.Bunny.CDNUrl,
+		StorageZone:       c.Config.Bunny.StorageZone,
+		AccessKey:         c.Config.Bunny.AccessKey,
+		BaseURL:           c.Config.Bunny.BaseURL,
+		CDNUrl:            c.Config.Bunny.CDNUrl,
+		SecureStorageZone: c.Config.Bunny.SecureStorageZone,
+		SecureAccessKey:   c.Config.Bunny.SecureAccessKey,
+		SecureCDNUrl:      c.Config.Bunny.SecureCDNUrl,
+		TokenKey:          c.Config.Bunny.TokenKey,
+	}
+	c.BunnyStorage, err = storage.NewBunnyStorage(bunnyConfig)
+	if err != nil {
+		return err
 	}
-	c.BunnyStorage = storage.NewBunnyStorage(bunnyConfig)
 	log.Println("✓ Bunny Storage initialized")
 
+	// Link preview resolver for external links in topics and replies
+	if c.Config.Unfurl.Enabled {
+		c.LinkResolver = unfurl.NewHTTPLinkResolver(unfurl.Config{
+			Timeout:      c.Config.Unfurl.Timeout,
+			MaxBodyBytes: c.Config.Unfurl.MaxBodyBytes,
+			UserAgent:    c.Config.Unfurl.UserAgent,
+		})
+		log.Println("✓ Link preview resolver initialized")
+	}
+
 	return nil
 }
 
@@ -162,8 +242,12 @@ func (c *Container) initRepositories() error {
 	c.ForumRepository = postgres.NewForumRepository(c.DB)
 	c.TopicRepository = postgres.NewTopicRepository(c.DB)
 	c.ReplyRepository = postgres.NewReplyRepository(c.DB)
+	c.PollRepository = postgres.NewPollRepository(c.DB)
 	c.TagRepository = postgres.NewTagRepository(c.DB)
 	c.VideoRepository = postgres.NewVideoRepository(c.DB)
+	c.VideoViewRepository = postgres.NewVideoViewRepository(c.DB)
+	c.AnalyticsRepository = postgres.NewAnalyticsRepository(c.DB)
+	c.PlaylistRepository = postgres.NewPlaylistRepository(c.DB)
 	c.LikeRepository = postgres.NewLikeRepository(c.DB)
 	c.CommentRepository = postgres.NewCommentRepository(c.DB)
 	c.ShareRepository = postgres.NewShareRepository(c.DB)
@@ -171,6 +255,15 @@ func (c *Container) initRepositories() error {
 	c.NotificationRepository = postgres.NewNotificationRepository(c.DB)
 	c.ReportRepository = postgres.NewReportRepository(c.DB)
 	c.ActivityLogRepository = postgres.NewActivityLogRepository(c.DB)
+	c.RevisionRepository = postgres.NewRevisionRepository(c.DB)
+	c.LinkPreviewRepository = postgres.NewLinkPreviewRepository(c.DB)
+	c.BookmarkRepository = postgres.NewBookmarkRepository(c.DB)
+	c.WatchRepository = postgres.NewWatchRepository(c.DB)
+	c.ReadRepository = postgres.NewReadRepository(c.DB)
+	c.BlockRepository = postgres.NewBlockRepository(c.DB)
+	c.ConversationRepository = postgres.NewConversationRepository(c.DB)
+	c.MessageRepository = postgres.NewMessageRepository(c.DB)
+	c.BackgroundTaskRepository = postgres.NewBackgroundTaskRepository(c.DB)
 	log.Println("✓ Repositories initialized")
 	return nil
 }
@@ -186,19 +279,104 @@ func (c *Container) initServices() error {
 		c.ReplyRepository,
 	)
 
+	// Background task queue - side effects of likes, replies and comments run through it
+	c.QueueService = serviceimpl.NewQueueService(c.BackgroundTaskRepository, serviceimpl.QueueOptions{
+		Workers:      c.Config.Queue.Workers,
+		PollInterval: c.Config.Queue.PollInterval,
+		MaxAttempts:  c.Config.Queue.MaxAttempts,
+		BaseBackoff:  c.Config.Queue.BaseBackoff,
+		MaxBackoff:   c.Config.Queue.MaxBackoff,
+		TaskTimeout:  c.Config.Queue.TaskTimeout,
+		StaleAfter:   c.Config.Queue.StaleAfter,
+	})
+
+	// File service - used by services that attach uploaded files
+	c.FileService = serviceimpl.NewFileService(
+		c.FileRepository,
+		c.UserRepository,
+		c.BunnyStorage,
+		c.Config.Storage.DefaultQuota,
+		c.Config.Storage.OrphanGracePeriod,
+		c.Config.Storage.SignedURLTTL,
+	)
+
 	// Initialize other services with notification service where needed
-	c.UserService = serviceimpl.NewUserService(c.UserRepository, c.TopicRepository, c.VideoRepository, c.FollowRepository, c.Config.JWT.Secret)
+	c.UserService = serviceimpl.NewUserService(c.UserRepository, c.TopicRepository, c.VideoRepository, c.FollowRepository, c.FileService, c.Config.JWT.Secret)
 	c.TaskService = serviceimpl.NewTaskService(c.TaskRepository, c.UserRepository)
-	c.FileService = serviceimpl.NewFileService(c.FileRepository, c.UserRepository, c.BunnyStorage)
-	c.ForumService = serviceimpl.NewForumService(c.ForumRepository)
+	c.ForumService = serviceimpl.NewForumService(c.ForumRepository, c.UserRepository, c.ReadRepository)
 	c.TagService = serviceimpl.NewTagService(c.TagRepository, c.DB)
-	c.TopicService = serviceimpl.NewTopicService(c.TopicRepository, c.ForumRepository, c.ReplyRepository, c.TagService)
-	c.ReplyService = serviceimpl.NewReplyService(c.ReplyRepository, c.TopicRepository, c.NotificationService)
-	c.VideoService = serviceimpl.NewVideoService(c.VideoRepository, c.FileRepository, c.UserRepository)
-	c.LikeService = serviceimpl.NewLikeService(c.LikeRepository, c.TopicRepository, c.VideoRepository, c.ReplyRepository, c.CommentRepository, c.NotificationService)
-	c.CommentService = serviceimpl.NewCommentService(c.CommentRepository, c.VideoRepository, c.UserRepository, c.NotificationService)
-	c.FollowService = serviceimpl.NewFollowService(c.FollowRepository, c.UserRepository, c.NotificationService)
+	c.ContentService = serviceimpl.NewContentService(
+		c.FileRepository,
+		c.VideoRepository,
+		c.LinkPreviewRepository,
+		c.LinkResolver,
+		c.Config.Unfurl.RefreshAfter,
+	)
+	c.RevisionService = serviceimpl.NewRevisionService(
+		c.RevisionRepository,
+		c.TopicRepository,
+		c.ReplyRepository,
+		c.CommentRepository,
+		c.ActivityLogRepository,
+		c.TagService,
+		c.ContentService,
+	)
+	c.WatchService = serviceimpl.NewWatchService(
+		c.WatchRepository,
+		c.ReadRepository,
+		c.TopicRepository,
+		c.ForumService,
+		c.NotificationService,
+		c.Config.Watch.AutoWatchOnPost,
+		c.Config.Watch.AutoWatchOnReply,
+		c.Config.Watch.NotifyBatchSize,
+	)
+	c.ReadService = serviceimpl.NewReadService(c.ReadRepository, c.TopicRepository, c.ReplyRepository, c.UserRepository, c.ForumService)
+	c.TopicService = serviceimpl.NewTopicService(c.TopicRepository, c.ForumRepository, c.ReplyRepository, c.PollRepository, c.BookmarkRepository, c.ActivityLogRepository, c.ForumService, c.TagService, c.FileService, c.ContentService, c.NotificationService, c.WatchService, c.ReadService, c.QueueService)
+	c.ReplyService = serviceimpl.NewReplyService(c.ReplyRepository, c.TopicRepository, c.ForumService, c.ContentService, c.QueueService)
+	c.PollService = serviceimpl.NewPollService(c.PollRepository, c.TopicRepository, c.ForumService)
+	c.VideoService = serviceimpl.NewVideoService(
+		c.VideoRepository,
+		c.FileRepository,
+		c.UserRepository,
+		c.FollowRepository,
+		c.PlaylistRepository,
+		c.BookmarkRepository,
+		c.FileService,
+		c.BunnyStorage,
+		c.Config.Storage.SignedURLTTL,
+	)
+	c.RealtimeService = serviceimpl.NewRealtimeService(c.TopicRepository, c.ForumService, c.VideoService)
+	c.PlaylistService = serviceimpl.NewPlaylistService(c.PlaylistRepository, c.VideoRepository, c.VideoService)
+	c.VideoViewService = serviceimpl.NewVideoViewService(c.VideoViewRepository, c.VideoRepository, c.RedisClient, c.Config.Views.DedupeWindow)
+
+	analyticsLocation, err := time.LoadLocation(c.Config.Analytics.Timezone)
+	if err != nil {
+		log.Printf("Warning: Unknown analytics timezone %q, using UTC: %v", c.Config.Analytics.Timezone, err)
+		analyticsLocation = time.UTC
+	}
+	c.AnalyticsService = serviceimpl.NewAnalyticsService(c.AnalyticsRepository, c.VideoRepository, c.TopicRepository, analyticsLocation)
+	c.LikeService = serviceimpl.NewLikeService(c.LikeRepository, c.TopicRepository, c.VideoRepository, c.ReplyRepository, c.CommentRepository, c.BookmarkRepository, c.QueueService)
+	c.CommentService = serviceimpl.NewCommentService(c.CommentRepository, c.VideoRepository, c.UserRepository, c.QueueService)
+	c.FollowService = serviceimpl.NewFollowService(c.FollowRepository, c.BlockRepository, c.UserRepository, c.NotificationService)
 	c.ShareService = serviceimpl.NewShareService(c.ShareRepository, c.VideoRepository)
+	c.MessageService = serviceimpl.NewMessageService(
+		c.ConversationRepository,
+		c.MessageRepository,
+		c.UserRepository,
+		c.FollowRepository,
+		c.BlockRepository,
+		c.FileService,
+	)
+	c.BookmarkService = serviceimpl.NewBookmarkService(
+		c.BookmarkRepository,
+		c.TopicRepository,
+		c.ReplyRepository,
+		c.VideoRepository,
+		c.CommentRepository,
+		c.ForumService,
+		c.VideoService,
+	)
 
 	// Admin services
 	c.ReportService = serviceimpl.NewReportService(c.ReportRepository)
@@ -212,42 +390,138 @@ func (c *Container) initServices() error {
 		c.ForumRepository,
 	)
 
+	// The task handlers need the services above; workers start once they are registered
+	serviceimpl.RegisterTaskHandlers(
+		c.QueueService,
+		c.NotificationService,
+		c.WatchService,
+		c.LikeRepository,
+		c.TopicRepository,
+		c.VideoRepository,
+		c.ReplyRepository,
+		c.CommentRepository,
+	)
+	c.QueueService.Start()
+
 	log.Println("✓ Services initialized")
 	return nil
 }
 
 func (c *Container) initScheduler() error {
-	c.EventScheduler = scheduler.NewEventScheduler()
-	c.JobService = serviceimpl.NewJobService(c.JobRepository, c.EventScheduler)
+	jobLocation, err := time.LoadLocation(c.Config.Jobs.Timezone)
+	if err != nil {
+		log.Printf("Warning: Unknown job timezone %q, using UTC: %v", c.Config.Jobs.Timezone, err)
+		jobLocation = time.UTC
+	}
+	c.EventScheduler = scheduler.NewEventScheduler(jobLocation)
+	c.JobLeader = redis.NewLeaderElector(c.RedisClient, "jobs:leader", c.Config.Jobs.NodeID, c.Config.Jobs.LeaderTTL)
+	c.JobService = serviceimpl.NewJobService(c.JobRepository, c.EventScheduler, c.RedisClient, c.JobLeader, c.Config.Jobs.LockTTL)
+	serviceimpl.RegisterJobHandlers(c.JobService, c.ForumService, c.LikeService, c.NotificationService)
 
 	// Start the scheduler
 	c.EventScheduler.Start()
 	log.Println("✓ Event scheduler started")
 
-	// Load and schedule existing active jobs
-	ctx := context.Background()
-	jobs, _, err := c.JobService.ListJobs(ctx, 0, 1000)
+	// System job: remove uploads that were never attached to anything
+	err = c.EventScheduler.AddJob("system:file-orphan-cleanup", c.Config.Storage.OrphanCleanupCron, func() {
+		deleted, err := c.FileService.CleanupOrphanedFiles(context.Background())
+		if err != nil {
+			log.Printf("Warning: Orphaned file cleanup failed: %v", err)
+			return
+		}
+		if deleted > 0 {
+			log.Printf("✓ Removed %d orphaned files", deleted)
+		}
+	})
 	if err != nil {
-		log.Printf("Warning: Failed to load existing jobs: %v", err)
-		return nil
+		log.Printf("Warning: Failed to schedule orphaned file cleanup: %v", err)
 	}
 
-	activeJobCount := 0
-	for _, job := range jobs {
-		if job.IsActive {
-			err := c.EventScheduler.AddJob(job.ID.String(), job.CronExpr, func() {
-				c.JobService.ExecuteJob(ctx, job)
-			})
-			if err != nil {
-				log.Printf("Warning: Failed to schedule job %s: %v", job.Name, err)
-			} else {
-				activeJobCount++
-			}
+	// System job: move video media and attachments uploaded before the secure zone into it
+	err = c.EventScheduler.AddJob("system:media-protect", c.Config.Storage.ProtectMediaCron, func() {
+		moved, err := c.FileService.ProtectLegacyMedia(context.Background())
+		if err != nil {
+			log.Printf("Warning: Moving media to the secure zone failed: %v", err)
+			return
+		}
+		if moved > 0 {
+			log.Printf("✓ Moved %d files to the secure zone", moved)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to schedule media protection: %v", err)
+	}
+
+	// System job: write view counts buffered in Redis to the database
+	err = c.EventScheduler.AddJob("system:video-view-flush", c.Config.Views.FlushCron, func() {
+		if _, err := c.VideoViewService.FlushViewCounts(context.Background()); err != nil {
+			log.Printf("Warning: Video view flush failed: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to schedule video view flush: %v", err)
+	}
+
+	// System job: refresh today's and yesterday's analytics rollups
+	err = c.EventScheduler.AddJob("system:analytics-rollup", c.Config.Analytics.RollupCron, func() {
+		if err := c.AnalyticsService.RollupRecent(context.Background()); err != nil {
+			log.Printf("Warning: Analytics rollup failed: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to schedule analytics rollup: %v", err)
+	}
+
+	// System job: close polls whose closesAt has passed and announce the final results
+	err = c.EventScheduler.AddJob("system:poll-close", c.Config.Polls.CloseCron, func() {
+		closed, err := c.PollService.CloseDuePolls(context.Background())
+		if err != nil {
+			log.Printf("Warning: Closing due polls failed: %v", err)
+			return
+		}
+		if closed > 0 {
+			log.Printf("✓ Closed %d polls", closed)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to schedule poll close: %v", err)
+	}
+
+	// System job: drop completed background tasks past their retention
+	err = c.EventScheduler.AddJob("system:task-cleanup", c.Config.Queue.CleanupCron, func() {
+		deleted, err := c.QueueService.PurgeCompleted(context.Background(), time.Now().Add(-c.Config.Queue.Retention))
+		if err != nil {
+			log.Printf("Warning: Background task cleanup failed: %v", err)
+			return
 		}
+		if deleted > 0 {
+			log.Printf("✓ Removed %d completed background tasks", deleted)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to schedule background task cleanup: %v", err)
+	}
+
+	// System job: pick up jobs created, changed or stopped through other replicas
+	err = c.EventScheduler.AddJob("system:job-sync", c.Config.Jobs.SyncCron, func() {
+		if _, _, err := c.JobService.SyncScheduledJobs(context.Background()); err != nil {
+			log.Printf("Warning: Job schedule sync failed: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to schedule job sync: %v", err)
 	}
 
-	if activeJobCount > 0 {
-		log.Printf("✓ Scheduled %d active jobs", activeJobCount)
+	// Load and schedule existing active jobs; only the leader replica runs them
+	c.JobLeader.Start()
+	scheduled, _, err := c.JobService.SyncScheduledJobs(context.Background())
+	if err != nil {
+		log.Printf("Warning: Failed to load existing jobs: %v", err)
+		return nil
+	}
+
+	if scheduled > 0 {
+		log.Printf("✓ Scheduled %d active jobs (node %s, leader: %t)", scheduled, c.JobLeader.NodeID(), c.JobLeader.IsLeader())
 	}
 
 	return nil
@@ -256,6 +530,15 @@ func (c *Container) initScheduler() error {
 func (c *Container) Cleanup() error {
 	log.Println("Starting cleanup...")
 
+	// Flush and close WebSocket connections first so clients reconnect elsewhere
+	wsCtx, cancelWS := context.WithTimeout(context.Background(), c.Config.WebSocket.ShutdownTimeout)
+	if err := websocket.Manager.Shutdown(wsCtx); err != nil {
+		log.Printf("Warning: WebSocket connections did not close in time: %v", err)
+	} else {
+		log.Println("✓ WebSocket connections closed")
+	}
+	cancelWS()
+
 	// Stop scheduler
 	if c.EventScheduler != nil {
 		if c.EventScheduler.IsRunning() {
@@ -266,6 +549,32 @@ func (c *Container) Cleanup() error {
 		}
 	}
 
+	// Hand over job leadership so another replica takes over without waiting for the lease
+	if c.JobLeader != nil {
+		c.JobLeader.Stop()
+	}
+
+	// Let background tasks in progress finish; unfinished ones are picked up again after restart
+	if c.QueueService != nil {
+		queueCtx, cancelQueue := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := c.QueueService.Stop(queueCtx); err != nil {
+			log.Printf("Warning: Background tasks did not finish in time: %v", err)
+		} else {
+			log.Println("✓ Background task workers stopped")
+		}
+		cancelQueue()
+	}
+
+	// Flush buffered view counts before Redis goes away
+	if c.VideoViewService != nil {
+		if _, err := c.VideoViewService.FlushViewCounts(context.Background()); err != nil {
+			log.Printf("Warning: Failed to flush video views: %v", err)
+		}
+	}
+
+	// Leave the WebSocket cluster while Redis is still reachable
+	websocket.Manager.Close()
+
 	// Close Redis connection
 	if c.RedisClient != nil {
 		if err := c.RedisClient.Close(); err != nil {
@@ -308,8 +617,12 @@ func (c *Container) GetHandlerServices() *handlers.Services {
 		ForumService:        c.ForumService,
 		TopicService:        c.TopicService,
 		ReplyService:        c.ReplyService,
+		PollService:         c.PollService,
 		TagService:          c.TagService,
 		VideoService:        c.VideoService,
+		VideoViewService:    c.VideoViewService,
+		AnalyticsService:    c.AnalyticsService,
+		PlaylistService:     c.PlaylistService,
 		LikeService:         c.LikeService,
 		CommentService:      c.CommentService,
 		ShareService:        c.ShareService,
@@ -317,5 +630,13 @@ func (c *Container) GetHandlerServices() *handlers.Services {
 		NotificationService: c.NotificationService,
 		AdminService:        c.AdminService,
 		ReportService:       c.ReportService,
+		RevisionService:     c.RevisionService,
+		ContentService:      c.ContentService,
+		BookmarkService:     c.BookmarkService,
+		WatchService:        c.WatchService,
+		ReadService:         c.ReadService,
+		RealtimeService:     c.RealtimeService,
+		MessageService:      c.MessageService,
+		QueueService:        c.QueueService,
 	}
 }
@@ -0,0 +1,594 @@
+// Package markdown implements the Markdown subset used by topics and replies.
+//
+// Supported syntax:
+//
+//	# Heading .. ###### Heading
+//	- item / * item / 1. item      (flat lists)
+//	> quote
+//	```lang ... ```                (fenced code) and `inline code`
+//	**bold**, *italic*, _italic_
+//	[text](https://example.com)    (http, https, mailto and site-relative links)
+//	bare https:// URLs             (autolinked)
+//	![alt](file:<file id>)         (images uploaded through the file API only)
+//	@[video](<video id>)           (video embed, on its own line)
+//	---                            (horizontal rule)
+//
+// Raw HTML is never passed through: every piece of text is escaped and the
+// output only contains the tags generated here, so it is safe to serve as-is.
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxQuoteDepth  = 3
+	maxInlineDepth = 5
+	maxImages      = 20
+	maxVideos      = 5
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletPattern      = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^\s{0,3}(\d{1,9})[.)]\s+(.*)$`)
+	videoPattern       = regexp.MustCompile(`^@\[video\]\(\s*([^)\s]*)\s*\)$`)
+	codeLangPattern    = regexp.MustCompile(`^[A-Za-z0-9_+#.-]{1,20}$`)
+	horizontalRules    = map[string]bool{"---": true, "***": true, "___": true}
+	allowedLinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+// Refs lists what a document points to; the caller resolves them before rendering
+type Refs struct {
+	FileIDs  []uuid.UUID
+	VideoIDs []uuid.UUID
+	Links    []string // external http(s) URLs, in order of appearance
+}
+
+// Video is the data shown for an embedded video
+type Video struct {
+	Title string
+}
+
+// Embeds holds the resolved images and videos of a document. Anything missing is
+// rendered as unavailable instead of failing, so stored content survives deletes.
+type Embeds struct {
+	Images map[uuid.UUID]string // file ID -> URL
+	Videos map[uuid.UUID]Video
+}
+
+// Document is parsed Markdown ready to be rendered
+type Document struct {
+	blocks   []block
+	refs     Refs
+	problems []string
+	seen     map[string]bool
+}
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockQuote
+	blockBulletList
+	blockOrderedList
+	blockRule
+	blockVideo
+)
+
+type block struct {
+	kind     blockKind
+	level    int // heading level or first number of an ordered list
+	lang     string
+	text     string // code block content
+	inlines  []inline
+	items    [][]inline
+	children []block
+	videoID  uuid.UUID
+}
+
+type inlineKind int
+
+const (
+	inlineText inlineKind = iota
+	inlineCode
+	inlineStrong
+	inlineEmphasis
+	inlineLink
+	inlineImage
+	inlineBreak
+)
+
+type inline struct {
+	kind     inlineKind
+	text     string // text, code, image alt
+	href     string
+	fileID   uuid.UUID
+	children []inline
+}
+
+// Parse parses source into a document. It never fails; problems such as
+// disallowed link schemes are reported by Validate and rendered as plain text.
+func Parse(source string) *Document {
+	doc := &Document{seen: make(map[string]bool)}
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	doc.blocks = doc.parseBlocks(strings.Split(source, "\n"), 0)
+	return doc
+}
+
+// Links returns the external links of source, used to look up unfurl previews
+func Links(source string) []string {
+	return Parse(source).refs.Links
+}
+
+// Refs returns the files, videos and external links the document references
+func (d *Document) Refs() Refs {
+	return d.refs
+}
+
+// Validate reports content that is not allowed by the supported subset
+func (d *Document) Validate() error {
+	if len(d.problems) > 0 {
+		return errors.New(d.problems[0])
+	}
+	if len(d.refs.FileIDs) > maxImages {
+		return fmt.Errorf("content can embed at most %d images", maxImages)
+	}
+	if len(d.refs.VideoIDs) > maxVideos {
+		return fmt.Errorf("content can embed at most %d videos", maxVideos)
+	}
+	return nil
+}
+
+// HTML renders the document with the given embeds
+func (d *Document) HTML(embeds Embeds) string {
+	var sb strings.Builder
+	renderBlocks(&sb, d.blocks, embeds)
+	return sb.String()
+}
+
+// Block parsing
+
+func (d *Document) parseBlocks(lines []string, depth int) []block {
+	var blocks []block
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```"):
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			if !codeLangPattern.MatchString(lang) {
+				lang = ""
+			}
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence (an unclosed fence runs to the end)
+			blocks = append(blocks, block{kind: blockCode, lang: lang, text: strings.Join(code, "\n")})
+
+		case headingPattern.MatchString(trimmed):
+			m := headingPattern.FindStringSubmatch(trimmed)
+			blocks = append(blocks, block{kind: blockHeading, level: len(m[1]), inlines: d.parseInlines(m[2], 0)})
+			i++
+
+		case horizontalRules[strings.ReplaceAll(trimmed, " ", "")]:
+			blocks = append(blocks, block{kind: blockRule})
+			i++
+
+		case videoPattern.MatchString(trimmed):
+			m := videoPattern.FindStringSubmatch(trimmed)
+			videoID, err := uuid.Parse(m[1])
+			if err != nil {
+				d.problem("video embeds must use a video ID: @[video](<id>)")
+				blocks = append(blocks, block{kind: blockParagraph, inlines: []inline{{kind: inlineText, text: trimmed}}})
+			} else {
+				d.addVideo(videoID)
+				blocks = append(blocks, block{kind: blockVideo, videoID: videoID})
+			}
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				content := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(content, " "))
+				i++
+			}
+			if depth >= maxQuoteDepth {
+				blocks = append(blocks, block{kind: blockParagraph, inlines: d.parseInlines(strings.Join(quoted, "\n"), 0)})
+				continue
+			}
+			blocks = append(blocks, block{kind: blockQuote, children: d.parseBlocks(quoted, depth+1)})
+
+		case bulletPattern.MatchString(line):
+			var items [][]inline
+			for i < len(lines) && bulletPattern.MatchString(lines[i]) {
+				text := bulletPattern.FindStringSubmatch(lines[i])[1]
+				text, i = continuation(lines, i+1, text)
+				items = append(items, d.parseInlines(text, 0))
+			}
+			blocks = append(blocks, block{kind: blockBulletList, items: items})
+
+		case orderedPattern.MatchString(line):
+			start, _ := strconv.Atoi(orderedPattern.FindStringSubmatch(line)[1])
+			var items [][]inline
+			for i < len(lines) && orderedPattern.MatchString(lines[i]) {
+				text := orderedPattern.FindStringSubmatch(lines[i])[2]
+				text, i = continuation(lines, i+1, text)
+				items = append(items, d.parseInlines(text, 0))
+			}
+			blocks = append(blocks, block{kind: blockOrderedList, level: start, items: items})
+
+		default:
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !startsBlock(lines[i])) {
+				para = append(para, strings.TrimSpace(lines[i]))
+				i++
+			}
+			blocks = append(blocks, block{kind: blockParagraph, inlines: d.parseInlines(strings.Join(para, "\n"), 0)})
+		}
+	}
+	return blocks
+}
+
+// continuation appends indented lines that belong to a list item
+func continuation(lines []string, i int, text string) (string, int) {
+	for i < len(lines) {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || !(strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")) ||
+			bulletPattern.MatchString(line) || orderedPattern.MatchString(line) {
+			break
+		}
+		text += "\n" + strings.TrimSpace(line)
+		i++
+	}
+	return text, i
+}
+
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, ">") ||
+		headingPattern.MatchString(trimmed) || horizontalRules[strings.ReplaceAll(trimmed, " ", "")] ||
+		videoPattern.MatchString(trimmed) || bulletPattern.MatchString(line) || orderedPattern.MatchString(line)
+}
+
+// Inline parsing
+
+func (d *Document) parseInlines(text string, depth int) []inline {
+	var nodes []inline
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = append(nodes, inline{kind: inlineText, text: buf.String()})
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_[]()!#>-@", text[i+1]) >= 0:
+			buf.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '\n':
+			flush()
+			nodes = append(nodes, inline{kind: inlineBreak})
+			i++
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				flush()
+				nodes = append(nodes, inline{kind: inlineCode, text: text[i+1 : i+1+end]})
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") && depth < maxInlineDepth:
+			if end := strings.Index(text[i+2:], "**"); end > 0 {
+				flush()
+				nodes = append(nodes, inline{kind: inlineStrong, children: d.parseInlines(text[i+2:i+2+end], depth+1)})
+				i += end + 4
+				continue
+			}
+
+		case (c == '*' || c == '_') && depth < maxInlineDepth && (c == '*' || i == 0 || !isWordByte(text[i-1])):
+			if end := closingEmphasis(text, i+1, c); end > i+1 {
+				flush()
+				nodes = append(nodes, inline{kind: inlineEmphasis, children: d.parseInlines(text[i+1:end], depth+1)})
+				i = end + 1
+				continue
+			}
+
+		case strings.HasPrefix(rest, "!["):
+			if label, target, n, ok := bracketPair(rest[1:]); ok {
+				flush()
+				nodes = append(nodes, d.image(label, target, rest[:n+1]))
+				i += n + 1
+				continue
+			}
+
+		case c == '[':
+			if label, target, n, ok := bracketPair(rest); ok {
+				flush()
+				if href, ok := d.link(target); ok {
+					var children []inline
+					if depth < maxInlineDepth {
+						children = d.parseInlines(label, depth+1)
+					} else {
+						children = []inline{{kind: inlineText, text: label}}
+					}
+					nodes = append(nodes, inline{kind: inlineLink, href: href, children: children})
+				} else {
+					nodes = append(nodes, inline{kind: inlineText, text: rest[:n]})
+				}
+				i += n
+				continue
+			}
+
+		case (strings.HasPrefix(rest, "https://") || strings.HasPrefix(rest, "http://")) && (i == 0 || !isWordByte(text[i-1])):
+			end := strings.IndexAny(rest, " \t\n<>\"")
+			if end < 0 {
+				end = len(rest)
+			}
+			url := strings.TrimRight(rest[:end], ".,;:!?)'")
+			if href, ok := d.link(url); ok && len(url) > len("https://") {
+				flush()
+				nodes = append(nodes, inline{kind: inlineLink, href: href, children: []inline{{kind: inlineText, text: url}}})
+				i += len(url)
+				continue
+			}
+		}
+
+		buf.WriteByte(c)
+		i++
+	}
+
+	flush()
+	return nodes
+}
+
+// closingEmphasis finds the single marker closing an emphasis that opened before start
+func closingEmphasis(text string, start int, marker byte) int {
+	for j := start; j < len(text); j++ {
+		if text[j] == '\n' {
+			return -1
+		}
+		if text[j] != marker {
+			continue
+		}
+		if marker == '*' && j+1 < len(text) && text[j+1] == '*' {
+			j++
+			continue
+		}
+		if marker == '_' && j+1 < len(text) && isWordByte(text[j+1]) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// bracketPair parses "[label](target)" at the start of s and returns its length
+func bracketPair(s string) (label, target string, n int, ok bool) {
+	depth := 0
+	closeLabel := -1
+	for j := 0; j < len(s); j++ {
+		if s[j] == '\n' {
+			return "", "", 0, false
+		}
+		if s[j] == '[' {
+			depth++
+		} else if s[j] == ']' {
+			depth--
+			if depth == 0 {
+				closeLabel = j
+				break
+			}
+		}
+	}
+	if closeLabel < 0 || closeLabel+1 >= len(s) || s[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+
+	depth = 0
+	for j := closeLabel + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\n':
+			return "", "", 0, false
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:closeLabel], strings.TrimSpace(s[closeLabel+2 : j]), j + 1, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+func (d *Document) image(alt, target, source string) inline {
+	if !strings.HasPrefix(target, "file:") {
+		d.problem("images must reference an uploaded file: ![alt](file:<id>)")
+		return inline{kind: inlineText, text: source}
+	}
+	fileID, err := uuid.Parse(strings.TrimPrefix(target, "file:"))
+	if err != nil {
+		d.problem("images must reference an uploaded file: ![alt](file:<id>)")
+		return inline{kind: inlineText, text: source}
+	}
+
+	if !d.seen["file:"+fileID.String()] {
+		d.seen["file:"+fileID.String()] = true
+		d.refs.FileIDs = append(d.refs.FileIDs, fileID)
+	}
+	return inline{kind: inlineImage, text: alt, fileID: fileID}
+}
+
+// link checks a link target and records external URLs for unfurling
+func (d *Document) link(target string) (string, bool) {
+	if siteRelative(target) {
+		return target, true
+	}
+
+	scheme, _, found := strings.Cut(target, ":")
+	if !found || !allowedLinkSchemes[strings.ToLower(scheme)] {
+		d.problem("links must use http, https or mailto")
+		return "", false
+	}
+
+	if strings.ToLower(scheme) != "mailto" && !d.seen["link:"+target] {
+		d.seen["link:"+target] = true
+		d.refs.Links = append(d.refs.Links, target)
+	}
+	return target, true
+}
+
+// siteRelative accepts paths on this site. Browsers read "//host" and "/\host" as another
+// host and drop tabs from URLs, so none of those may follow the leading slash.
+func siteRelative(target string) bool {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") {
+		return false
+	}
+	for _, c := range target {
+		if c == '\\' || c < 0x20 || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *Document) addVideo(videoID uuid.UUID) {
+	if !d.seen["video:"+videoID.String()] {
+		d.seen["video:"+videoID.String()] = true
+		d.refs.VideoIDs = append(d.refs.VideoIDs, videoID)
+	}
+}
+
+func (d *Document) problem(message string) {
+	d.problems = append(d.problems, message)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// Rendering
+
+func renderBlocks(sb *strings.Builder, blocks []block, embeds Embeds) {
+	for _, b := range blocks {
+		switch b.kind {
+		case blockParagraph:
+			sb.WriteString("<p>")
+			renderInlines(sb, b.inlines, embeds)
+			sb.WriteString("</p>\n")
+
+		case blockHeading:
+			fmt.Fprintf(sb, "<h%d>", b.level)
+			renderInlines(sb, b.inlines, embeds)
+			fmt.Fprintf(sb, "</h%d>\n", b.level)
+
+		case blockCode:
+			if b.lang != "" {
+				fmt.Fprintf(sb, `<pre><code class="language-%s">`, html.EscapeString(b.lang))
+			} else {
+				sb.WriteString("<pre><code>")
+			}
+			sb.WriteString(html.EscapeString(b.text))
+			sb.WriteString("</code></pre>\n")
+
+		case blockQuote:
+			sb.WriteString("<blockquote>\n")
+			renderBlocks(sb, b.children, embeds)
+			sb.WriteString("</blockquote>\n")
+
+		case blockBulletList, blockOrderedList:
+			tag := "ul"
+			if b.kind == blockOrderedList {
+				tag = "ol"
+			}
+			if b.kind == blockOrderedList && b.level != 1 {
+				fmt.Fprintf(sb, "<ol start=\"%d\">\n", b.level)
+			} else {
+				fmt.Fprintf(sb, "<%s>\n", tag)
+			}
+			for _, item := range b.items {
+				sb.WriteString("<li>")
+				renderInlines(sb, item, embeds)
+				sb.WriteString("</li>\n")
+			}
+			fmt.Fprintf(sb, "</%s>\n", tag)
+
+		case blockRule:
+			sb.WriteString("<hr>\n")
+
+		case blockVideo:
+			video, ok := embeds.Videos[b.videoID]
+			if !ok {
+				sb.WriteString("<p class=\"embed-unavailable\">Video unavailable</p>\n")
+				continue
+			}
+			id := b.videoID.String()
+			fmt.Fprintf(sb, "<div class=\"video-embed\" data-video-id=\"%s\"><a href=\"/videos/%s\">%s</a></div>\n",
+				id, id, html.EscapeString(video.Title))
+		}
+	}
+}
+
+func renderInlines(sb *strings.Builder, nodes []inline, embeds Embeds) {
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineText:
+			sb.WriteString(html.EscapeString(n.text))
+		case inlineBreak:
+			sb.WriteString("<br>\n")
+		case inlineCode:
+			sb.WriteString("<code>" + html.EscapeString(n.text) + "</code>")
+		case inlineStrong:
+			sb.WriteString("<strong>")
+			renderInlines(sb, n.children, embeds)
+			sb.WriteString("</strong>")
+		case inlineEmphasis:
+			sb.WriteString("<em>")
+			renderInlines(sb, n.children, embeds)
+			sb.WriteString("</em>")
+		case inlineLink:
+			if strings.HasPrefix(n.href, "/") {
+				fmt.Fprintf(sb, `<a href="%s">`, html.EscapeString(n.href))
+			} else {
+				fmt.Fprintf(sb, `<a href="%s" rel="nofollow noopener ugc" target="_blank">`, html.EscapeString(n.href))
+			}
+			renderInlines(sb, n.children, embeds)
+			sb.WriteString("</a>")
+		case inlineImage:
+			url, ok := embeds.Images[n.fileID]
+			if !ok {
+				sb.WriteString(html.EscapeString(n.text))
+				continue
+			}
+			fmt.Fprintf(sb, `<img src="%s" alt="%s" loading="lazy">`, html.EscapeString(url), html.EscapeString(n.text))
+		}
+	}
+}
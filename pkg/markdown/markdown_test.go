@@ -0,0 +1,97 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLEscapesMarkup(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		absent []string
+	}{
+		{"script tag", `<script>alert(1)</script>`, []string{"<script"}},
+		{"inline handler", `<img src=x onerror=alert(1)>`, []string{"<img"}},
+		{"link label", `[<b onmouseover=alert(1)>hi</b>](https://example.com)`, []string{"<b "}},
+		{"quote in link target", `[x](https://example.com/"onmouseover="alert(1))`, []string{`"onmouseover`}},
+		{"image alt", `![<svg onload=alert(1)>](file:6f1c1f7e-3c1b-4c55-9a43-7f3ef6a1e0a1)`, []string{"<svg"}},
+		{"code block", "```\n</code><script>alert(1)</script>\n```", []string{"<script"}},
+		{"inline code", "`<iframe src=x>`", []string{"<iframe"}},
+		{"heading", `# <style>body{}</style>`, []string{"<style"}},
+		{"bare url", `https://example.com/"><script>alert(1)</script>`, []string{"<script"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := Parse(tt.source).HTML(Embeds{})
+			for _, s := range tt.absent {
+				if strings.Contains(out, s) {
+					t.Errorf("HTML(%q) = %q, must not contain %q", tt.source, out, s)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		allowed bool
+		href    string // rendered href when allowed
+	}{
+		{"https", `[x](https://example.com/a)`, true, `href="https://example.com/a" rel="nofollow noopener ugc" target="_blank"`},
+		{"http", `[x](http://example.com)`, true, `href="http://example.com" rel="nofollow noopener ugc"`},
+		{"mailto", `[x](mailto:someone@example.com)`, true, `href="mailto:someone@example.com"`},
+		{"site relative", `[x](/topics/123)`, true, `<a href="/topics/123">`},
+		{"javascript", `[x](javascript:alert(1))`, false, ""},
+		{"javascript mixed case", `[x](JavaScript:alert(1))`, false, ""},
+		{"data", `[x](data:text/html;base64,PHNjcmlwdD4=)`, false, ""},
+		{"vbscript", `[x](vbscript:msgbox(1))`, false, ""},
+		{"scheme relative", `[x](//evil.com)`, false, ""},
+		{"backslash after slash", `[x](/\evil.com)`, false, ""},
+		{"backslash later", `[x](/a\b)`, false, ""},
+		{"tab after slash", "[x](/\t/evil.com)", false, ""},
+		{"relative without slash", `[x](evil.com)`, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Parse(tt.source)
+			out := doc.HTML(Embeds{})
+			err := doc.Validate()
+
+			if !tt.allowed {
+				if err == nil {
+					t.Errorf("Validate(%q) = nil, want an error", tt.source)
+				}
+				if strings.Contains(out, "<a ") {
+					t.Errorf("HTML(%q) = %q, must not render a link", tt.source, out)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Validate(%q) = %v, want nil", tt.source, err)
+			}
+			if !strings.Contains(out, tt.href) {
+				t.Errorf("HTML(%q) = %q, want it to contain %q", tt.source, out, tt.href)
+			}
+		})
+	}
+}
+
+func TestLinksListsExternalURLsOnly(t *testing.T) {
+	got := Links("[a](https://example.com) [b](/local) [c](mailto:x@example.com) https://example.org/page. [d](/\\evil.com)")
+	want := []string{"https://example.com", "https://example.org/page"}
+
+	if len(got) != len(want) {
+		t.Fatalf("Links() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Links()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -1,46 +1,101 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron"
+	"github.com/robfig/cron/v3"
 )
 
+// ErrMaxConcurrency is returned by RunNow when the job already runs as often as it may at once
+var ErrMaxConcurrency = errors.New("job is already running at its maximum concurrency")
+
 type EventScheduler interface {
 	Start()
 	Stop()
 	AddJob(id, cronExpr string, task func()) error
+	// AddJobWithOptions schedules task in a timezone, with a timeout and a concurrency limit.
+	// The task's context is cancelled when the timeout passes.
+	AddJobWithOptions(id, cronExpr string, opts JobOptions, task func(ctx context.Context)) error
 	RemoveJob(id string) error
+	// RunNow starts a run right away, within the job's limits, even while it is paused
+	RunNow(id string) error
+	// SetPaused skips the job's firings until it is resumed; it stays scheduled meanwhile
+	SetPaused(id string, paused bool) error
 	GetJob(id string) (*JobInfo, bool)
 	ListJobs() map[string]*JobInfo
 	IsRunning() bool
+	Location() *time.Location
+}
+
+type JobOptions struct {
+	Location       *time.Location // the scheduler's location when nil
+	Timeout        time.Duration  // no limit when 0
+	MaxConcurrency int            // runs at once on this scheduler; 1 when 0
 }
 
 type JobInfo struct {
-	ID       string
-	CronExpr string
-	Job      *gocron.Job
-	IsActive bool
-	LastRun  *time.Time
-	NextRun  *time.Time
+	ID             string
+	CronExpr       string
+	Location       *time.Location
+	Timeout        time.Duration
+	MaxConcurrency int
+	Job            *gocron.Job
+	IsActive       bool // false while paused
+	Running        int  // runs in progress
+	LastRun        *time.Time
+	NextRun        *time.Time
+
+	task func(ctx context.Context)
 }
 
 type GocronScheduler struct {
 	scheduler *gocron.Scheduler
+	location  *time.Location
 	jobs      map[string]*JobInfo
 	mu        sync.RWMutex
 	running   bool
 }
 
-func NewEventScheduler() EventScheduler {
-	scheduler := gocron.NewScheduler(time.UTC)
-	scheduler.SingletonModeAll()
+type manualRunKey struct{}
+
+type runSlotKey struct{}
+
+// IsManualRun reports whether a task was started by RunNow rather than by its schedule
+func IsManualRun(ctx context.Context) bool {
+	manual, _ := ctx.Value(manualRunKey{}).(bool)
+	return manual
+}
+
+// HoldRunSlot keeps the run's concurrency slot taken until release is called, even after the
+// task has returned. Tasks that leave work running past their timeout hold the slot for that
+// work, so it still counts against MaxConcurrency. Outside a scheduled task it does nothing.
+func HoldRunSlot(ctx context.Context) (release func()) {
+	slot, ok := ctx.Value(runSlotKey{}).(*sync.WaitGroup)
+	if !ok {
+		return func() {}
+	}
+	slot.Add(1)
+	var once sync.Once
+	return func() { once.Do(slot.Done) }
+}
+
+// NewEventScheduler interprets cron expressions in location unless a job has its own.
+// Overlapping runs are limited per job by JobOptions.MaxConcurrency.
+func NewEventScheduler(location *time.Location) EventScheduler {
+	if location == nil {
+		location = time.UTC
+	}
 
 	return &GocronScheduler{
-		scheduler: scheduler,
+		scheduler: gocron.NewScheduler(location),
+		location:  location,
 		jobs:      make(map[string]*JobInfo),
 		running:   false,
 	}
@@ -80,7 +135,17 @@ func (s *GocronScheduler) IsRunning() bool {
 	return s.running
 }
 
+func (s *GocronScheduler) Location() *time.Location {
+	return s.location
+}
+
 func (s *GocronScheduler) AddJob(id, cronExpr string, task func()) error {
+	return s.AddJobWithOptions(id, cronExpr, JobOptions{}, func(ctx context.Context) {
+		task()
+	})
+}
+
+func (s *GocronScheduler) AddJobWithOptions(id, cronExpr string, opts JobOptions, task func(ctx context.Context)) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -88,23 +153,19 @@ func (s *GocronScheduler) AddJob(id, cronExpr string, task func()) error {
 		return fmt.Errorf("job with ID %s already exists", id)
 	}
 
-	job, err := s.scheduler.Cron(cronExpr).Do(func() {
-		now := time.Now()
-		log.Printf("Executing job: %s at %s", id, now.Format(time.RFC3339))
+	location := opts.Location
+	if location == nil {
+		location = s.location
+	}
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
 
-		// Update last run time
-		s.mu.Lock()
-		if jobInfo, exists := s.jobs[id]; exists {
-			jobInfo.LastRun = &now
-			if jobInfo.Job != nil {
-				nextRun := jobInfo.Job.NextRun()
-				jobInfo.NextRun = &nextRun
-			}
+	job, err := s.scheduler.Cron(fmt.Sprintf("CRON_TZ=%s %s", location, cronExpr)).Do(func() {
+		if err := s.run(id, false); err != nil && !errors.Is(err, ErrMaxConcurrency) {
+			log.Printf("Warning: Job %s did not run: %v", id, err)
 		}
-		s.mu.Unlock()
-
-		// Execute the task
-		task()
 	})
 
 	if err != nil {
@@ -113,15 +174,99 @@ func (s *GocronScheduler) AddJob(id, cronExpr string, task func()) error {
 
 	nextRun := job.NextRun()
 	s.jobs[id] = &JobInfo{
-		ID:       id,
-		CronExpr: cronExpr,
-		Job:      job,
-		IsActive: true,
-		LastRun:  nil,
-		NextRun:  &nextRun,
+		ID:             id,
+		CronExpr:       cronExpr,
+		Location:       location,
+		Timeout:        opts.Timeout,
+		MaxConcurrency: maxConcurrency,
+		Job:            job,
+		IsActive:       true,
+		LastRun:        nil,
+		NextRun:        &nextRun,
+		task:           task,
 	}
 
-	log.Printf("Job added: ID=%s, CronExpr=%s, NextRun=%s", id, cronExpr, nextRun.Format(time.RFC3339))
+	log.Printf("Job added: ID=%s, CronExpr=%s, Location=%s, NextRun=%s", id, cronExpr, location, nextRun.Format(time.RFC3339))
+	return nil
+}
+
+func (s *GocronScheduler) RunNow(id string) error {
+	return s.run(id, true)
+}
+
+// run executes one run of a job within its limits; manual runs happen in the background
+func (s *GocronScheduler) run(id string, manual bool) error {
+	s.mu.Lock()
+	jobInfo, exists := s.jobs[id]
+	if !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("job with ID %s not found", id)
+	}
+	if !manual && !jobInfo.IsActive {
+		s.mu.Unlock()
+		return nil
+	}
+	if jobInfo.Running >= jobInfo.MaxConcurrency {
+		s.mu.Unlock()
+		log.Printf("Skipping job %s: %d runs still in progress", id, jobInfo.Running)
+		return ErrMaxConcurrency
+	}
+
+	now := time.Now()
+	log.Printf("Executing job: %s at %s (manual: %t)", id, now.Format(time.RFC3339), manual)
+
+	// Update last run time
+	jobInfo.Running++
+	jobInfo.LastRun = &now
+	if jobInfo.Job != nil {
+		nextRun := jobInfo.Job.NextRun()
+		jobInfo.NextRun = &nextRun
+	}
+	task, timeout := jobInfo.task, jobInfo.Timeout
+	s.mu.Unlock()
+
+	execute := func() {
+		// The slot is freed once the task and all work holding the slot are done
+		var slot sync.WaitGroup
+		slot.Add(1)
+		go func() {
+			slot.Wait()
+			s.mu.Lock()
+			jobInfo.Running--
+			s.mu.Unlock()
+		}()
+		defer slot.Done()
+
+		ctx := context.WithValue(context.Background(), manualRunKey{}, manual)
+		ctx = context.WithValue(ctx, runSlotKey{}, &slot)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		// Execute the task
+		task(ctx)
+	}
+
+	if manual {
+		go execute()
+	} else {
+		execute()
+	}
+	return nil
+}
+
+func (s *GocronScheduler) SetPaused(id string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobInfo, exists := s.jobs[id]
+	if !exists {
+		return fmt.Errorf("job with ID %s not found", id)
+	}
+
+	jobInfo.IsActive = !paused
 	return nil
 }
 
@@ -152,12 +297,32 @@ func (s *GocronScheduler) GetJob(id string) (*JobInfo, bool) {
 		return nil, false
 	}
 
-	// Create a copy to avoid race conditions
+	return copyJobInfo(jobInfo), true
+}
+
+func (s *GocronScheduler) ListJobs() map[string]*JobInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make(map[string]*JobInfo)
+	for id, jobInfo := range s.jobs {
+		jobs[id] = copyJobInfo(jobInfo)
+	}
+
+	return jobs
+}
+
+// copyJobInfo copies a job's info to avoid race conditions; the caller holds the lock
+func copyJobInfo(jobInfo *JobInfo) *JobInfo {
 	info := &JobInfo{
-		ID:       jobInfo.ID,
-		CronExpr: jobInfo.CronExpr,
-		Job:      jobInfo.Job,
-		IsActive: jobInfo.IsActive,
+		ID:             jobInfo.ID,
+		CronExpr:       jobInfo.CronExpr,
+		Location:       jobInfo.Location,
+		Timeout:        jobInfo.Timeout,
+		MaxConcurrency: jobInfo.MaxConcurrency,
+		Job:            jobInfo.Job,
+		IsActive:       jobInfo.IsActive,
+		Running:        jobInfo.Running,
 	}
 
 	if jobInfo.LastRun != nil {
@@ -176,49 +341,25 @@ func (s *GocronScheduler) GetJob(id string) (*JobInfo, bool) {
 		info.NextRun = &nextRun
 	}
 
-	return info, true
+	return info
 }
 
-func (s *GocronScheduler) ListJobs() map[string]*JobInfo {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	jobs := make(map[string]*JobInfo)
-	for id, jobInfo := range s.jobs {
-		info := &JobInfo{
-			ID:       jobInfo.ID,
-			CronExpr: jobInfo.CronExpr,
-			Job:      jobInfo.Job,
-			IsActive: jobInfo.IsActive,
-		}
-
-		if jobInfo.LastRun != nil {
-			lastRun := *jobInfo.LastRun
-			info.LastRun = &lastRun
-		}
-
-		if jobInfo.NextRun != nil {
-			nextRun := *jobInfo.NextRun
-			info.NextRun = &nextRun
-		}
-
-		// Update next run if job exists
-		if jobInfo.Job != nil {
-			nextRun := jobInfo.Job.NextRun()
-			info.NextRun = &nextRun
-		}
-
-		jobs[id] = info
+// parseCron parses a standard 5-field expression (or a descriptor such as @daily). Timezones
+// are set per job, not with a TZ= prefix.
+func parseCron(cronExpr string, location *time.Location) (cron.Schedule, error) {
+	trimmed := strings.TrimSpace(cronExpr)
+	if strings.HasPrefix(trimmed, "TZ=") || strings.HasPrefix(trimmed, "CRON_TZ=") {
+		return nil, errors.New("set the timezone separately instead of a TZ= prefix")
 	}
-
-	return jobs
+	if location == nil {
+		location = time.UTC
+	}
+	return cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", location, trimmed))
 }
 
 // Helper function to validate cron expression
 func ValidateCronExpression(cronExpr string) error {
-	scheduler := gocron.NewScheduler(time.UTC)
-	_, err := scheduler.Cron(cronExpr).Do(func() {})
-	if err != nil {
+	if _, err := parseCron(cronExpr, time.UTC); err != nil {
 		return fmt.Errorf("invalid cron expression: %v", err)
 	}
 	return nil
@@ -226,12 +367,35 @@ func ValidateCronExpression(cronExpr string) error {
 
 // Helper function to get next run time from cron expression
 func GetNextRunTime(cronExpr string) (*time.Time, error) {
-	scheduler := gocron.NewScheduler(time.UTC)
-	job, err := scheduler.Cron(cronExpr).Do(func() {})
+	nextRuns, err := NextRunTimes(cronExpr, time.UTC, time.Now(), 1)
+	if err != nil {
+		return nil, err
+	}
+	return &nextRuns[0], nil
+}
+
+// NextRunTimes lists the next count firings after from, in location
+func NextRunTimes(cronExpr string, location *time.Location, from time.Time, count int) ([]time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	schedule, err := parseCron(cronExpr, location)
 	if err != nil {
 		return nil, fmt.Errorf("invalid cron expression: %v", err)
 	}
 
-	nextRun := job.NextRun()
-	return &nextRun, nil
+	nextRuns := make([]time.Time, 0, count)
+	next := from
+	for i := 0; i < count; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		nextRuns = append(nextRuns, next.In(location))
+	}
+	if len(nextRuns) == 0 {
+		return nil, errors.New("invalid cron expression: it never fires")
+	}
+	return nextRuns, nil
 }